@@ -39,6 +39,21 @@ type PhasePowers interface {
 	Powers() (float64, float64, float64, error)
 }
 
+// MeterReactivePower provides total reactive power in var
+type MeterReactivePower interface {
+	ReactivePower() (float64, error)
+}
+
+// MeterPowerFactor provides total power factor between -1 and 1
+type MeterPowerFactor interface {
+	PowerFactor() (float64, error)
+}
+
+// MeterFrequency provides grid frequency in Hz
+type MeterFrequency interface {
+	Frequency() (float64, error)
+}
+
 // Battery provides battery Soc in %
 type Battery interface {
 	Soc() (float64, error)
@@ -101,6 +116,13 @@ type ChargerEx interface {
 	MaxCurrentMillis(current float64) error
 }
 
+// Watchdog makes the charger fall back to a safe current if evcc loses control.
+// Calling Watchdog arms and refreshes the watchdog, the charger falls back to the
+// fallback current if it is not refreshed within the timeout.
+type Watchdog interface {
+	Watchdog(timeout time.Duration, fallback float64) error
+}
+
 // PhaseSwitcher provides 1p3p switching
 type PhaseSwitcher interface {
 	Phases1p3p(phases int) error
@@ -264,6 +286,14 @@ type CircuitLoad interface {
 	GetCircuit() Circuit
 }
 
+// CircuitCurtailer is a load that can be curtailed immediately by its circuits' overload protection.
+// Each circuit curtails and releases independently, the lowest limit applies.
+type CircuitCurtailer interface {
+	CircuitLoad
+	Curtail(circuit Circuit, current float64) error
+	Release(circuit Circuit)
+}
+
 // Circuit defines the load control domain
 type Circuit interface {
 	CircuitMeasurements
@@ -47,6 +47,7 @@ type All struct {
 	Levels          map[string]string
 	Interval        time.Duration
 	Database        DB
+	Templates       Templates
 	Mqtt            Mqtt
 	ModbusProxy     []ModbusProxy
 	Javascript      []Javascript
@@ -64,6 +65,23 @@ type All struct {
 	Site            map[string]any
 	Loadpoints      []config.Named
 	Circuits        []config.Named
+	Sites           []Site // additional sites
+}
+
+// Site configures an additional site with its own loadpoints and tariffs.
+// Devices are referenced by name from the global device configuration.
+type Site struct {
+	ID         string
+	Site       map[string]any
+	Loadpoints []config.Named
+	Tariffs    Tariffs // roles not configured are inherited from the default site
+}
+
+// Templates configures device templates in addition to the embedded ones
+type Templates struct {
+	Dir       string // directory with class subdirectories
+	Bundle    string // zip bundle, signed by <bundle>.sig
+	PublicKey string // base64 ed25519 key for verifying the bundle
 }
 
 type Javascript struct {
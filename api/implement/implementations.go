@@ -273,6 +273,51 @@ func (i *iMeterEnergy) TotalEnergy() (float64, error) {
 	return i.meterEnergy0()
 }
 
+func MeterFrequency(meterFrequency0 func() (float64, error)) api.MeterFrequency {
+	if meterFrequency0 == nil {
+		return nil
+	}
+	return &iMeterFrequency{meterFrequency0}
+}
+
+type iMeterFrequency struct {
+	meterFrequency0 func() (float64, error)
+}
+
+func (i *iMeterFrequency) Frequency() (float64, error) {
+	return i.meterFrequency0()
+}
+
+func MeterPowerFactor(meterPowerFactor0 func() (float64, error)) api.MeterPowerFactor {
+	if meterPowerFactor0 == nil {
+		return nil
+	}
+	return &iMeterPowerFactor{meterPowerFactor0}
+}
+
+type iMeterPowerFactor struct {
+	meterPowerFactor0 func() (float64, error)
+}
+
+func (i *iMeterPowerFactor) PowerFactor() (float64, error) {
+	return i.meterPowerFactor0()
+}
+
+func MeterReactivePower(meterReactivePower0 func() (float64, error)) api.MeterReactivePower {
+	if meterReactivePower0 == nil {
+		return nil
+	}
+	return &iMeterReactivePower{meterReactivePower0}
+}
+
+type iMeterReactivePower struct {
+	meterReactivePower0 func() (float64, error)
+}
+
+func (i *iMeterReactivePower) ReactivePower() (float64, error) {
+	return i.meterReactivePower0()
+}
+
 func MeterReturnEnergy(meterReturnEnergy0 func() (float64, error)) api.MeterReturnEnergy {
 	if meterReturnEnergy0 == nil {
 		return nil
@@ -482,3 +527,18 @@ type iVehicleRange struct {
 func (i *iVehicleRange) Range() (int64, error) {
 	return i.vehicleRange0()
 }
+
+func Watchdog(watchdog0 func(time.Duration, float64) error) api.Watchdog {
+	if watchdog0 == nil {
+		return nil
+	}
+	return &iWatchdog{watchdog0}
+}
+
+type iWatchdog struct {
+	watchdog0 func(time.Duration, float64) error
+}
+
+func (i *iWatchdog) Watchdog(p0 time.Duration, p1 float64) error {
+	return i.watchdog0(p0, p1)
+}
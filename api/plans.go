@@ -13,6 +13,20 @@ type RepeatingPlan struct {
 	Active   bool   `json:"active"`   // active flag
 }
 
+// TripCalendar is an iCalendar feed whose upcoming trips are converted into charge plans
+type TripCalendar struct {
+	Uri         string  `json:"uri"`                   // http(s) url, file path or file:// url
+	Consumption float64 `json:"consumption,omitempty"` // kWh/100km
+	Reserve     int     `json:"reserve,omitempty"`     // soc reserve on return in %
+	Geocoder    string  `json:"geocoder,omitempty"`    // Nominatim compatible search url for locating events, disabled if empty
+}
+
+// DeparturePrediction creates plans for departures predicted from session history
+type DeparturePrediction struct {
+	Soc        int     `json:"soc"`                  // target soc in %
+	Confidence float64 `json:"confidence,omitempty"` // minimum prediction confidence 0..1
+}
+
 type PlanStrategy struct {
 	Continuous   bool          `json:"continuous"`   // force continuous planning
 	Precondition time.Duration `json:"precondition"` // precondition duration in seconds
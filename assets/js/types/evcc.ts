@@ -217,6 +217,10 @@ export interface State {
   residualPower?: number;
   /** Static grid export power limit in W used as optimizer constraint, 0 = disabled. An active HEMS curtailment takes precedence. */
   gridExportLimit?: number;
+  /** Grid outage detected, loadpoints are limited to the backup power budget. */
+  gridOutage?: boolean;
+  /** Load shedding configuration. */
+  loadShedding?: LoadShedding;
   /** Share of green energy in home consumption, between 0 and 1. */
   greenShareHome?: number;
   /** Share of green energy used for charging, between 0 and 1. */
@@ -513,6 +517,15 @@ export interface ConfigLoadpoint {
     estimate: boolean;
   };
   ui: LoadpointUi;
+  watchdog: LoadpointWatchdog;
+}
+
+/** Charger fallback if evcc loses control. */
+export interface LoadpointWatchdog {
+  /** Fallback without refresh in ns, disabled if zero. */
+  timeout: number;
+  /** Current the charger falls back to, in A. */
+  fallbackCurrent: number;
 }
 
 /** Display-only UI settings of a loadpoint. */
@@ -562,6 +575,8 @@ export interface Loadpoint {
   chargeVoltages?: number[];
   /** Energy charged in the current charging session in kWh. */
   chargedEnergy: number;
+  /** Charger has fallen back to its fail-safe current because evcc lost control. */
+  chargerFallback: boolean;
   /** The connected vehicle is not identified automatically by its status. */
   chargerFeatureAutodetectDisabled: boolean;
   /** Values are averaged. */
@@ -690,6 +705,8 @@ export interface Loadpoint {
   pvAction: PV_ACTION;
   /** Remaining time until the pending charge start or stop action executes, in seconds. */
   pvRemaining: number;
+  /** Time window schedules setting mode, current, limits or priority when a window starts. */
+  schedules: LoadpointSchedules;
   /** Energy used in the last 24 hours in kWh. */
   last24hEnergy?: number;
   /** Energy used in the last 7 days in kWh. */
@@ -704,6 +721,8 @@ export interface Loadpoint {
   sessionPricePerKWh: number | null;
   /** Share of solar energy in the current charging session in %. */
   sessionSolarPercentage: number;
+  /** Loadpoint is switched off by load shedding, regardless of charge mode. */
+  shed: boolean;
   /** Fast charging with cheap or clean grid energy is currently active. */
   smartCostActive: boolean;
   /** Price or emission limit for fast charging with grid energy. */
@@ -728,6 +747,8 @@ export interface Loadpoint {
   title: string;
   /** Energy used since midnight in kWh. */
   todayEnergy?: number;
+  /** Vehicle proposed by charging fingerprint. */
+  vehicleCandidate?: VehicleCandidate | null;
   /** Climater of the connected vehicle is active. */
   vehicleClimaterActive: boolean | null;
   /** Automatic vehicle detection is running. */
@@ -1038,6 +1059,7 @@ export enum MESSAGING_EVENTS {
   ASLEEP = "asleep",
   PLANOVERRUN = "planoverrun",
   SUGGESTION = "suggestion",
+  ARRIVING = "arriving",
 }
 
 /** A configured notification message. */
@@ -1090,6 +1112,12 @@ export interface Meter {
   powers?: number[];
   /** Current per phase in A. */
   currents?: number[];
+  /** Total reactive power in var. */
+  reactivePower?: number;
+  /** Total power factor between -1 and 1. */
+  powerFactor?: number;
+  /** Grid frequency in Hz. */
+  frequency?: number;
 }
 
 /** Projected home battery charge levels based on the solar and price forecast. */
@@ -1141,6 +1169,14 @@ export interface BatterySuggestion {
   actionable?: boolean;
 }
 
+/** Vehicle identified by its charging fingerprint. */
+export interface VehicleCandidate {
+  /** Unique name of the proposed vehicle. */
+  vehicle: string;
+  /** Match confidence between 0 and 1. */
+  confidence: number;
+}
+
 /** Battery optimizer suggestion for a loadpoint. */
 export interface LoadpointSuggestion {
   /** Suggested charging action. */
@@ -1179,6 +1215,37 @@ export interface RepeatingPlan {
   active: boolean;
 }
 
+/** Loadpoint settings applied when a time window starts. Manual changes are kept until the next window. */
+export interface LoadpointSchedule {
+  /** Weekdays the schedule is active. 0 is Sunday, 6 is Saturday. */
+  weekdays: number[];
+  /** Schedule is active on holidays instead of weekdays. */
+  holidays?: boolean;
+  /** Window start in HH:MM format. */
+  start: string;
+  /** Window end in HH:MM format. Spans midnight if not after start. */
+  end: string;
+  /** Charge mode. */
+  mode?: CHARGE_MODE;
+  /** Minimum current in A. */
+  minCurrent?: number;
+  /** Session SoC limit in %. */
+  limitSoc?: number;
+  /** Session energy limit in kWh. */
+  limitEnergy?: number;
+  /** Loadpoint priority. */
+  priority?: number;
+  /** Schedule is active. */
+  active: boolean;
+}
+
+/** Loadpoint schedules. The first matching window wins. */
+export interface LoadpointSchedules {
+  windows: LoadpointSchedule[] | null;
+  /** Holiday dates in YYYY-MM-DD format. */
+  holidays?: string[];
+}
+
 export interface PlanWrapper {
   planId: number;
   planTime: Date;
@@ -1201,6 +1268,8 @@ export interface StaticSocPlan {
   soc: number;
   /** Target time. */
   time: Date;
+  /** Plan was created for a predicted departure. */
+  predicted?: boolean;
 }
 
 /** Charging plan with an energy goal. */
@@ -1219,6 +1288,38 @@ export interface PlanStrategy {
   precondition: number;
 }
 
+/** Loadpoints switched off in order when grid import, §14a or circuit limits are exceeded. */
+export interface LoadShedding {
+  /** Loadpoint names, shed first to last and restored in reverse order. */
+  devices: string[];
+  /** Maximum grid import in W, 0 = no limit. */
+  gridLimit?: number;
+  /** Headroom in W required in addition to the device power before restoring. */
+  hysteresis?: number;
+  /** Minimum time between restoring devices in seconds. */
+  delay?: number;
+}
+
+/** Calendar feed whose upcoming trips are converted into charging plans. */
+export interface TripCalendar {
+  /** iCalendar http(s) url, file path or file:// url. */
+  uri: string;
+  /** Vehicle consumption in kWh/100km. */
+  consumption?: number;
+  /** SoC reserve on return in %. */
+  reserve?: number;
+  /** Nominatim compatible search url for locating events, disabled if empty. */
+  geocoder?: string;
+}
+
+/** Automatic charging plans for departures predicted from session history. */
+export interface DeparturePrediction {
+  /** Target SoC in % for predicted departures. */
+  soc: number;
+  /** Minimum prediction confidence between 0 and 1. */
+  confidence?: number;
+}
+
 /** A configured vehicle. */
 export interface Vehicle {
   /** Unique vehicle name used in API routes and configuration. */
@@ -1235,6 +1336,10 @@ export interface Vehicle {
   repeatingPlans: RepeatingPlan[] | null;
   /** Charging plan strategy. */
   planStrategy: PlanStrategy;
+  /** Calendar feed creating charging plans from upcoming trips. */
+  tripCalendar?: TripCalendar;
+  /** Departure prediction creating charging plans from session history. */
+  departurePrediction?: DeparturePrediction;
   /** Vehicle title for UI display. */
   title: string;
   /** Feature flags of the vehicle implementation. */
@@ -90,7 +90,13 @@ func NewABB(ctx context.Context, settings modbus.Settings) (api.Charger, error)
 
 	// keep-alive
 	go func() {
-		for range time.Tick(30 * time.Second) {
+		for tick := time.Tick(30 * time.Second); ; {
+			select {
+			case <-tick:
+			case <-ctx.Done():
+				return
+			}
+
 			if _, err := wb.status(); err != nil {
 				log.ERROR.Println("heartbeat:", err)
 			}
@@ -22,6 +22,8 @@ import (
 	"encoding/binary"
 	"encoding/hex"
 	"fmt"
+	"math"
+	"sync/atomic"
 	"time"
 
 	"github.com/evcc-io/evcc/api"
@@ -34,11 +36,12 @@ import (
 // Amperfied charger implementation
 type Amperfied struct {
 	implement.Caps
-	log     *util.Logger
-	conn    *modbus.Connection
-	current uint16
-	phases  int
-	wakeup  bool
+	log      *util.Logger
+	conn     *modbus.Connection
+	current  uint16
+	phases   int
+	wakeup   bool
+	watchdog atomic.Bool // heartbeat is left to the watchdog
 }
 
 const (
@@ -48,10 +51,10 @@ const (
 	ampRegVoltages           = 10   // Input 10,11,12
 	ampRegPower              = 14   // Input
 	ampRegEnergy             = 17   // Input
-	ampRegTimeoutConfig      = 257  // Holding
+	ampRegTimeoutConfig      = 257  // Holding, ms
 	ampRegRemoteLock         = 259  // Holding
 	ampRegAmpsConfig         = 261  // Holding
-	ampRegFailSafeConfig     = 262  // Holding
+	ampRegFailSafeConfig     = 262  // Holding, 0.1A
 	ampRegPhaseSwitchControl = 501  // Holding
 	ampRegPhaseSwitchState   = 5001 // Input
 	ampRegRfidUID            = 2002 // Input
@@ -125,12 +128,39 @@ func (wb *Amperfied) heartbeat(ctx context.Context, timeout time.Duration) {
 			return
 		}
 
+		if wb.watchdog.Load() {
+			return
+		}
+
 		if _, err := wb.Status(); err != nil {
 			wb.log.ERROR.Println("heartbeat:", err)
 		}
 	}
 }
 
+var _ api.Watchdog = (*Amperfied)(nil)
+
+// Watchdog implements the api.Watchdog interface
+func (wb *Amperfied) Watchdog(timeout time.Duration, fallback float64) error {
+	ms := timeout.Milliseconds()
+	if ms > math.MaxUint16 {
+		return fmt.Errorf("failsafe timeout exceeds %v", time.Duration(math.MaxUint16)*time.Millisecond)
+	}
+
+	if err := wb.set(ampRegFailSafeConfig, uint16(10*fallback)); err != nil {
+		return fmt.Errorf("failsafe current: %w", err)
+	}
+
+	if err := wb.set(ampRegTimeoutConfig, uint16(max(1, ms))); err != nil {
+		return fmt.Errorf("failsafe timeout: %w", err)
+	}
+
+	// stop heartbeat to fall back if the loadpoint stops refreshing
+	wb.watchdog.Store(true)
+
+	return nil
+}
+
 func (wb *Amperfied) set(reg, val uint16) error {
 	b := make([]byte, 2)
 	binary.BigEndian.PutUint16(b, val)
@@ -21,6 +21,7 @@ import (
 	"context"
 	"encoding/binary"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/evcc-io/evcc/api"
@@ -32,16 +33,18 @@ import (
 
 // Compleo charger implementation
 type Compleo struct {
-	lp     loadpoint.API
-	conn   *modbus.Connection
-	offset uint16
-	power  uint16
+	lp       loadpoint.API
+	conn     *modbus.Connection
+	offset   uint16
+	power    uint16
+	watchdog atomic.Bool // heartbeat is left to the watchdog
 }
 
 const (
 	// global
-	compleoRegFallback   = 0x5 // holding
-	compleoRegConnectors = 0x8 // input
+	compleoRegFallbackPower = 0x4 // holding, W
+	compleoRegFallback      = 0x5 // holding, s
+	compleoRegConnectors    = 0x8 // input
 
 	// per connector
 	compleoRegBase           = 0x0100 // input
@@ -128,12 +131,34 @@ func (wb *Compleo) heartbeat(ctx context.Context, log *util.Logger, timeout time
 			return
 		}
 
+		if wb.watchdog.Load() {
+			return
+		}
+
 		if _, err := wb.status(); err != nil {
 			log.ERROR.Println("heartbeat:", err)
 		}
 	}
 }
 
+var _ api.Watchdog = (*Compleo)(nil)
+
+// Watchdog implements the api.Watchdog interface
+func (wb *Compleo) Watchdog(timeout time.Duration, fallback float64) error {
+	if _, err := wb.conn.WriteSingleRegister(compleoRegFallbackPower, uint16(3*230*fallback)); err != nil {
+		return fmt.Errorf("fallback power: %w", err)
+	}
+
+	if _, err := wb.conn.WriteSingleRegister(compleoRegFallback, uint16(max(1, timeout/time.Second))); err != nil {
+		return fmt.Errorf("failsafe timeout: %w", err)
+	}
+
+	// stop heartbeat to fall back if the loadpoint stops refreshing
+	wb.watchdog.Store(true)
+
+	return nil
+}
+
 func (wb *Compleo) reg(addr uint16) uint16 {
 	return compleoRegBase + wb.offset + addr
 }
@@ -21,6 +21,7 @@ import (
 	"context"
 	"encoding/binary"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/evcc-io/evcc/api"
@@ -30,7 +31,8 @@ import (
 )
 
 const (
-	dadapowerRegFailsafeTimeout     = 102
+	dadapowerRegFailsafeCurrent     = 101 // 0.01A
+	dadapowerRegFailsafeTimeout     = 102 // s
 	dadapowerRegModel               = 105
 	dadapowerRegSerial              = 106 // 6
 	dadapowerRegFirmware            = 112 // 6
@@ -52,6 +54,7 @@ type Dadapower struct {
 	log       *util.Logger
 	conn      *modbus.Connection
 	regOffset uint16
+	watchdog  atomic.Bool // heartbeat is left to the watchdog
 }
 
 func init() {
@@ -111,12 +114,34 @@ func (wb *Dadapower) heartbeat(ctx context.Context) {
 			return
 		}
 
+		if wb.watchdog.Load() {
+			return
+		}
+
 		if _, err := wb.conn.ReadInputRegisters(dadapowerRegFailsafeTimeout, 1); err != nil {
 			wb.log.ERROR.Println("heartbeat:", err)
 		}
 	}
 }
 
+var _ api.Watchdog = (*Dadapower)(nil)
+
+// Watchdog implements the api.Watchdog interface
+func (wb *Dadapower) Watchdog(timeout time.Duration, fallback float64) error {
+	if _, err := wb.conn.WriteSingleRegister(dadapowerRegFailsafeCurrent, uint16(100*fallback)); err != nil {
+		return fmt.Errorf("failsafe current: %w", err)
+	}
+
+	if _, err := wb.conn.WriteSingleRegister(dadapowerRegFailsafeTimeout, uint16(max(1, timeout/time.Second))); err != nil {
+		return fmt.Errorf("failsafe timeout: %w", err)
+	}
+
+	// stop heartbeat to fall back if the loadpoint stops refreshing
+	wb.watchdog.Store(true)
+
+	return nil
+}
+
 // Status implements the api.Charger interface
 func (wb *Dadapower) Status() (api.ChargeStatus, error) {
 	b, err := wb.conn.ReadInputRegisters(dadapowerRegPlugState+wb.regOffset, 1)
@@ -22,6 +22,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/evcc-io/evcc/api"
@@ -34,10 +35,11 @@ import (
 // DaheimLaden charger implementation
 type DaheimLaden struct {
 	implement.Caps
-	log    *util.Logger
-	conn   *modbus.Connection
-	curr   uint16
-	phases uint16
+	log      *util.Logger
+	conn     *modbus.Connection
+	curr     uint16
+	phases   uint16
+	watchdog atomic.Bool // heartbeat is left to the watchdog
 }
 
 const (
@@ -148,12 +150,38 @@ func (wb *DaheimLaden) heartbeat(ctx context.Context, timeout time.Duration) {
 			return
 		}
 
+		if wb.watchdog.Load() {
+			return
+		}
+
 		if _, err := wb.conn.ReadHoldingRegisters(dlRegSafeCurrent, 1); err != nil {
 			wb.log.ERROR.Println("heartbeat:", err)
 		}
 	}
 }
 
+var _ api.Watchdog = (*DaheimLaden)(nil)
+
+// Watchdog implements the api.Watchdog interface
+func (wb *DaheimLaden) Watchdog(timeout time.Duration, fallback float64) error {
+	b := make([]byte, 2)
+
+	binary.BigEndian.PutUint16(b, uint16(10*fallback))
+	if _, err := wb.conn.WriteMultipleRegisters(dlRegSafeCurrent, 1, b); err != nil {
+		return fmt.Errorf("safe current: %w", err)
+	}
+
+	binary.BigEndian.PutUint16(b, uint16(max(1, timeout/time.Second)))
+	if _, err := wb.conn.WriteMultipleRegisters(dlRegCommTimeout, 1, b); err != nil {
+		return fmt.Errorf("failsafe timeout: %w", err)
+	}
+
+	// stop heartbeat to fall back if the loadpoint stops refreshing
+	wb.watchdog.Store(true)
+
+	return nil
+}
+
 func (wb *DaheimLaden) setCurrent(current uint16) error {
 	b := make([]byte, 2)
 	binary.BigEndian.PutUint16(b, current)
@@ -10,6 +10,7 @@ import (
 	ucapi "github.com/enbility/eebus-go/usecases/api"
 	"github.com/enbility/eebus-go/usecases/cem/evcc"
 	"github.com/enbility/eebus-go/usecases/cem/evcem"
+	"github.com/enbility/eebus-go/usecases/eg/lpc"
 	spineapi "github.com/enbility/spine-go/api"
 	"github.com/enbility/spine-go/model"
 	"github.com/evcc-io/evcc/api"
@@ -29,11 +30,20 @@ type minMax struct {
 	min, max float64
 }
 
+type failsafe struct {
+	power    float64
+	duration time.Duration
+}
+
 type EEBus struct {
 	implement.Caps
 	cem *eebus.CustomerEnergyManagement
+	eg  *eebus.EnergyGuard
 	ev  spineapi.EntityRemoteInterface
 
+	egLpcEntity spineapi.EntityRemoteInterface
+	failsafe    failsafe // last failsafe limit written
+
 	mux     sync.RWMutex
 	log     *util.Logger
 	lp      loadpoint.API
@@ -84,6 +94,7 @@ func newEEBus(ctx context.Context, ski, ip string) (*EEBus, error) {
 		log:     util.NewLogger("eebus"),
 		current: 6,
 		cem:     inst.CustomerEnergyManagement(),
+		eg:      inst.EnergyGuard(),
 	}
 
 	c.connector = eebus.NewConnector()
@@ -143,6 +154,8 @@ func (c *EEBus) Connect(connected bool) {
 	defer c.mux.Unlock()
 
 	c.ev = nil
+	c.egLpcEntity = nil
+	c.failsafe = failsafe{}
 }
 
 // UseCaseEvent implements the eebus.Device interface
@@ -162,6 +175,18 @@ func (c *EEBus) UseCaseEvent(device spineapi.DeviceRemoteInterface, entity spine
 	case evcem.DataUpdateCurrentPerPhase:
 		// acknowledge limit change
 		c.limitUpdated = time.Time{}
+
+	// Energy Guard LPC carries the failsafe limit
+	case lpc.UseCaseSupportUpdate:
+		// device/entity removal fires the use case update event with a nil entity
+		if entity == nil {
+			return
+		}
+		// use most specific selector
+		if c.egLpcEntity == nil || len(entity.Address().Entity) < len(c.egLpcEntity.Address().Entity) {
+			c.egLpcEntity = entity
+			c.failsafe = failsafe{}
+		}
 	}
 }
 
@@ -599,3 +624,42 @@ var _ loadpoint.Controller = (*EEBus)(nil)
 func (c *EEBus) LoadpointControl(lp loadpoint.API) {
 	c.lp = lp
 }
+
+var _ api.Watchdog = (*EEBus)(nil)
+
+// Watchdog implements the api.Watchdog interface. It writes the LPC failsafe
+// consumption limit and duration the EVSE falls back to when the heartbeat is lost.
+// The failsafe duration is bounded to the 2h..24h range mandated by LPC.
+func (c *EEBus) Watchdog(timeout time.Duration, fallback float64) error {
+	c.mux.RLock()
+	entity, written := c.egLpcEntity, c.failsafe
+	c.mux.RUnlock()
+
+	if entity == nil || !c.eg.EgLPCInterface.IsScenarioAvailableAtEntity(entity, eebus.LPCFailsafe) {
+		return api.ErrNotAvailable
+	}
+
+	fs := failsafe{
+		power:    3 * voltage * fallback,
+		duration: min(max(timeout, 2*time.Hour), 24*time.Hour),
+	}
+
+	// failsafe values persist on the EVSE, only write on change
+	if fs == written {
+		return nil
+	}
+
+	if _, err := c.eg.EgLPCInterface.WriteFailsafeConsumptionActivePowerLimit(entity, fs.power); err != nil {
+		return fmt.Errorf("failsafe limit: %w", err)
+	}
+
+	if _, err := c.eg.EgLPCInterface.WriteFailsafeDurationMinimum(entity, fs.duration); err != nil {
+		return fmt.Errorf("failsafe duration: %w", err)
+	}
+
+	c.mux.Lock()
+	c.failsafe = fs
+	c.mux.Unlock()
+
+	return nil
+}
@@ -48,6 +48,9 @@ type OCPP struct {
 
 	stackLevelZero      bool
 	profileKindRelative bool
+
+	watchdog time.Duration // validity of evcc's charging profile
+	fallback float64       // fallback profile current
 }
 
 const defaultIdTag = "evcc" // RemoteStartTransaction only
@@ -129,6 +132,11 @@ func NewOCPPFromConfig(ctx context.Context, other map[string]any) (api.Charger,
 		implement.Has(c, implement.PhaseSwitcher(c.phases1p3p))
 	}
 
+	// fallback profile requires a lower stack level and a second profile
+	if !stackLevelZero && c.cp.StackLevel > 0 && c.cp.ChargingProfileId > 1 {
+		implement.Has(c, implement.Watchdog(c.setWatchdog))
+	}
+
 	return c, nil
 }
 
@@ -357,9 +365,41 @@ func (c *OCPP) createTxDefaultChargingProfile(current float64) *types.ChargingPr
 		res.StackLevel = c.cp.StackLevel
 	}
 
+	// profile expires unless refreshed by the watchdog
+	if c.watchdog > 0 {
+		res.ValidTo = types.NewDateTime(time.Now().Add(c.watchdog))
+	}
+
 	return res
 }
 
+// setWatchdog implements the api.Watchdog interface.
+// The fallback is a TxDefaultProfile on stack level zero which applies once evcc's profile has expired.
+func (c *OCPP) setWatchdog(timeout time.Duration, fallback float64) error {
+	if c.watchdog == 0 || c.fallback != fallback {
+		profile := c.createTxDefaultChargingProfile(fallback)
+		profile.ChargingProfileId = c.cp.ChargingProfileId - 1
+		profile.StackLevel = 0
+		profile.ValidTo = nil
+
+		if err := c.conn.SetChargingProfileRequest(profile); err != nil {
+			return fmt.Errorf("set fallback charging profile: %w", err)
+		}
+
+		c.fallback = fallback
+	}
+
+	c.watchdog = timeout
+
+	// refresh profile validity
+	var current float64
+	if c.enabled {
+		current = c.current
+	}
+
+	return c.setCurrent(current)
+}
+
 var _ api.CurrentGetter = (*OCPP)(nil)
 
 // GetMaxCurrent returns the current the charge point is set to offer.
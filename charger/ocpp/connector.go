@@ -16,6 +16,7 @@ import (
 )
 
 type Connector struct {
+	ctx   context.Context // notifies pushed values
 	log   *util.Logger
 	mu    sync.Mutex
 	clock clock.Clock // mockable time
@@ -37,7 +38,24 @@ type Connector struct {
 }
 
 func NewConnector(ctx context.Context, log *util.Logger, id int, cp *CP, idTag string, meterInterval time.Duration) (*Connector, error) {
+	// share the connector state with the charger instance being replaced after a configuration update
+	if conn := cp.acquireConnector(id); conn != nil {
+		conn.mu.Lock()
+		conn.ctx = ctx
+		conn.remoteIdTag = idTag
+		conn.meterInterval = meterInterval
+		conn.mu.Unlock()
+
+		go func() {
+			<-ctx.Done()
+			cp.deregisterConnector(id)
+		}()
+
+		return conn, nil
+	}
+
 	conn := &Connector{
+		ctx:          ctx,
 		log:          log,
 		cp:           cp,
 		id:           id,
@@ -4,6 +4,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/evcc-io/evcc/util"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
 )
@@ -40,6 +41,10 @@ func (conn *Connector) OnStatusNotification(request *core.StatusNotificationRequ
 		conn.log.TRACE.Printf("ignoring status: %s < %s", request.Timestamp.Time, conn.status.Timestamp)
 	}
 
+	if applied {
+		util.Notify(conn.ctx)
+	}
+
 	// Available means cable unplugged and any prior transaction is stale
 	if applied && request.Status == core.ChargePointStatusAvailable && conn.txnId != 0 {
 		conn.log.DEBUG.Printf("clearing stale transaction %d on Available status", conn.txnId)
@@ -104,6 +109,8 @@ func (conn *Connector) OnMeterValues(request *core.MeterValuesRequest) (*core.Me
 		}
 	}
 
+	util.Notify(conn.ctx)
+
 	return new(core.MeterValuesConfirmation), nil
 }
 
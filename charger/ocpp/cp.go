@@ -42,6 +42,7 @@ type CP struct {
 	BootNotificationResult   *core.BootNotificationRequest
 
 	connectors map[int]*Connector
+	users      map[int]int // charger instances sharing a connector
 }
 
 func NewChargePoint(log *util.Logger, cs *CS, id string) *CP {
@@ -51,6 +52,7 @@ func NewChargePoint(log *util.Logger, cs *CS, id string) *CP {
 		id:  id,
 
 		connectors: make(map[int]*Connector),
+		users:      make(map[int]int),
 
 		connectC:                 make(chan struct{}, 1),
 		meterC:                   make(chan struct{}, 1),
@@ -70,14 +72,32 @@ func (cp *CP) registerConnector(id int, conn *Connector) error {
 	}
 
 	cp.connectors[id] = conn
+	cp.users[id] = 1
 	return nil
 }
 
+// acquireConnector returns the registered connector and adds a user to it
+func (cp *CP) acquireConnector(id int) *Connector {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	conn := cp.connectors[id]
+	if conn != nil {
+		cp.users[id]++
+	}
+
+	return conn
+}
+
+// deregisterConnector removes a user of the connector and the connector once it is unused
 func (cp *CP) deregisterConnector(id int) {
 	cp.mu.Lock()
 	defer cp.mu.Unlock()
 
-	delete(cp.connectors, id)
+	if cp.users[id]--; cp.users[id] <= 0 {
+		delete(cp.connectors, id)
+		delete(cp.users, id)
+	}
 }
 
 func (cp *CP) connectorByID(id int) *Connector {
@@ -1,6 +1,7 @@
 package charger
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
@@ -15,7 +16,7 @@ import (
 )
 
 func init() {
-	registry.Add("openwb", NewOpenWBFromConfig)
+	registry.AddCtx("openwb", NewOpenWBFromConfig)
 }
 
 // OpenWB configures generic charger and charge meter for an openWB loadpoint
@@ -33,7 +34,7 @@ type OpenWB struct {
 }
 
 // NewOpenWBFromConfig creates a new configurable charger
-func NewOpenWBFromConfig(other map[string]any) (api.Charger, error) {
+func NewOpenWBFromConfig(ctx context.Context, other map[string]any) (api.Charger, error) {
 	cc := struct {
 		mqtt.Config    `mapstructure:",squash"`
 		Topic          string
@@ -52,29 +53,29 @@ func NewOpenWBFromConfig(other map[string]any) (api.Charger, error) {
 
 	log := util.NewLogger("openwb")
 
-	return NewOpenWB(log, cc.Config, cc.ID, cc.Topic, cc.Phases1p3p, cc.DC, cc.Timeout)
+	return NewOpenWB(ctx, log, cc.Config, cc.ID, cc.Topic, cc.Phases1p3p, cc.DC, cc.Timeout)
 }
 
 // NewOpenWB creates a new configurable charger
-func NewOpenWB(log *util.Logger, mqttconf mqtt.Config, id int, topic string, p1p3, dc bool, timeout time.Duration) (api.Charger, error) {
+func NewOpenWB(ctx context.Context, log *util.Logger, mqttconf mqtt.Config, id int, topic string, p1p3, dc bool, timeout time.Duration) (api.Charger, error) {
 	client, err := mqtt.RegisteredClientOrDefault(log, mqttconf)
 	if err != nil {
 		return nil, err
 	}
 
 	// timeout handler
-	h, err := plugin.NewMqtt(log, client, fmt.Sprintf("%s/system/%s", topic, openwb.TimestampTopic), timeout).StringGetter()
+	h, err := plugin.NewMqtt(log, client, fmt.Sprintf("%s/system/%s", topic, openwb.TimestampTopic), timeout).WithContext(ctx).StringGetter()
 	if err != nil {
 		return nil, err
 	}
 	to := plugin.NewTimeoutHandler(h)
 
 	mq := func(subtopic string) *plugin.Mqtt {
-		return plugin.NewMqtt(log, client, fmt.Sprintf("%s/lp/%d/%s", topic, id, subtopic), 0)
+		return plugin.NewMqtt(log, client, fmt.Sprintf("%s/lp/%d/%s", topic, id, subtopic), 0).WithContext(ctx)
 	}
 
 	// check if loadpoint configured
-	configured, err := plugin.NewMqtt(log, client, fmt.Sprintf("%s/lp/%d/%s", topic, id, openwb.ConfiguredTopic), timeout).BoolGetter()
+	configured, err := plugin.NewMqtt(log, client, fmt.Sprintf("%s/lp/%d/%s", topic, id, openwb.ConfiguredTopic), timeout).WithContext(ctx).BoolGetter()
 	if err != nil {
 		return nil, err
 	}
@@ -163,7 +164,13 @@ func NewOpenWB(log *util.Logger, mqttconf mqtt.Config, id int, topic string, p1p
 	}
 
 	go func() {
-		for range time.Tick(openwb.HeartbeatInterval) {
+		for tick := time.Tick(openwb.HeartbeatInterval); ; {
+			select {
+			case <-tick:
+			case <-ctx.Done():
+				return
+			}
+
 			if err := heartbeatS(1); err != nil {
 				log.ERROR.Printf("heartbeat: %v", err)
 			}
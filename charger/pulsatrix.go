@@ -43,6 +43,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sync"
 	"sync/atomic"
@@ -80,6 +81,7 @@ type Pulsatrix struct {
 	wg                         sync.WaitGroup     // for goroutine synchronization
 	consecutiveReadErrors      int32              // atomic counter
 	consecutiveHeartbeatErrors int32              // atomic counter
+	closed                     atomic.Bool        // stops reconnecting after shutdown
 }
 
 type pulsatrixData struct {
@@ -195,6 +197,10 @@ func (c *Pulsatrix) reconnect() {
 
 	var lastErrorLog time.Time
 	operation := func() error {
+		if c.closed.Load() {
+			return backoff.Permanent(errors.New("closed"))
+		}
+
 		err := c.connect()
 		if err != nil {
 			// log error every errorLogInterval
@@ -206,7 +212,7 @@ func (c *Pulsatrix) reconnect() {
 		return err
 	}
 
-	if err := backoff.Retry(operation, bo); err != nil {
+	if err := backoff.Retry(operation, bo); err != nil && !c.closed.Load() {
 		// should never be reached with MaxElapsedTime = 0
 		c.log.ERROR.Printf("unexpected backoff failure: %v", err)
 	}
@@ -351,10 +357,21 @@ func (c *Pulsatrix) heartbeat(ctx context.Context) {
 	}
 }
 
+// Close implements the io.Closer interface
+func (c *Pulsatrix) Close() error {
+	return c.Shutdown()
+}
+
 // Shutdown gracefully closes the connection and stops all goroutines
 func (c *Pulsatrix) Shutdown() error {
-	if c.cancel != nil {
-		c.cancel()
+	c.closed.Store(true)
+
+	c.mu.RLock()
+	cancel := c.cancel
+	c.mu.RUnlock()
+
+	if cancel != nil {
+		cancel()
 	}
 
 	// wait for all goroutines to finish
@@ -1,9 +1,11 @@
 package charger
 
 import (
+	"context"
 	"testing"
 
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util/fixture"
 	"github.com/evcc-io/evcc/util/templates"
 	"github.com/evcc-io/evcc/util/test"
 )
@@ -47,3 +49,9 @@ func TestTemplates(t *testing.T) {
 		}
 	})
 }
+
+func TestFixtures(t *testing.T) {
+	fixture.TestClass(t, templates.Charger, "../templates/fixtures", func(ctx context.Context, other map[string]any) (any, error) {
+		return NewFromConfig(ctx, "template", other)
+	})
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/evcc-io/evcc/util/config"
+	"github.com/evcc-io/evcc/util/templates"
 	"github.com/spf13/cobra"
 )
 
@@ -12,8 +14,8 @@ var checkconfig = &cobra.Command{
 	Use:   "checkconfig",
 	Short: "Check config file for errors",
 	Long: `Check the (specified or default) config file for errors. Note that
-checkconfig only checks the config file for parsing errors and does
-not check that individual device configurations are valid.`,
+checkconfig only checks the config file for parsing errors and missing
+templates and does not check that individual device configurations are valid.`,
 	Run: runConfigCheck,
 }
 
@@ -24,6 +26,14 @@ func init() {
 func runConfigCheck(cmd *cobra.Command, args []string) {
 	err := loadConfigFile(&conf, !cmd.Flag(flagIgnoreDatabase).Changed)
 
+	if err == nil {
+		err = configureTemplates(conf.Templates)
+	}
+
+	if err == nil {
+		err = checkTemplates()
+	}
+
 	if err != nil {
 		log.FATAL.Println("config invalid:", err)
 		os.Exit(1)
@@ -31,3 +41,25 @@ func runConfigCheck(cmd *cobra.Command, args []string) {
 		fmt.Println("config valid")
 	}
 }
+
+// checkTemplates checks that all referenced device templates exist
+func checkTemplates() error {
+	for class, devs := range map[templates.Class][]config.Named{
+		templates.Meter:   conf.Meters,
+		templates.Charger: conf.Chargers,
+		templates.Vehicle: conf.Vehicles,
+	} {
+		for _, dev := range devs {
+			if dev.Type != "template" {
+				continue
+			}
+
+			name, _ := dev.Other["template"].(string)
+			if _, err := templates.ByName(class, name); err != nil {
+				return fmt.Errorf("%s %s: %w", class, dev.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/evcc-io/evcc/server/configdoc"
+	"github.com/spf13/cobra"
+)
+
+// configApplyCmd represents the config apply command
+var configApplyCmd = &cobra.Command{
+	Use:   "apply <file>",
+	Short: "Reconcile database configuration to yaml document",
+	Long:  "Reconcile database configuration to yaml document. Devices and settings not contained in the document are deleted.",
+	Run:   runConfigApply,
+	Args:  cobra.ExactArgs(1),
+}
+
+func init() {
+	configCmd.AddCommand(configApplyCmd)
+	configApplyCmd.Flags().Bool("dry-run", false, "Show changes without applying them")
+}
+
+func runConfigApply(cmd *cobra.Command, args []string) {
+	// load config
+	if err := loadConfigFile(&conf, !cmd.Flag(flagIgnoreDatabase).Changed); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	// setup persistence
+	if err := configureDatabase(conf.Database); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	b, err := os.ReadFile(args[0])
+	if err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	doc, err := configdoc.Unmarshal(b)
+	if err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	dryRun := cmd.Flag("dry-run").Changed
+
+	changes, err := configdoc.Apply(doc, "cli", dryRun)
+	for _, c := range changes {
+		fmt.Println(c.Op, c.Class, c.Key)
+	}
+	if err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	if len(changes) == 0 {
+		fmt.Println("configuration is up to date")
+	} else if !dryRun {
+		fmt.Println("restart evcc to apply the configuration")
+	}
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/evcc-io/evcc/server/configdoc"
+	"github.com/spf13/cobra"
+)
+
+// configExportCmd represents the config export command
+var configExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export database configuration as yaml document",
+	Run:   runConfigExport,
+}
+
+func init() {
+	configCmd.AddCommand(configExportCmd)
+	configExportCmd.Flags().StringP("output", "o", "", "Output file (default stdout)")
+}
+
+func runConfigExport(cmd *cobra.Command, args []string) {
+	// load config
+	if err := loadConfigFile(&conf, !cmd.Flag(flagIgnoreDatabase).Changed); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	// setup persistence
+	if err := configureDatabase(conf.Database); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	doc, err := configdoc.Export()
+	if err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	b, err := configdoc.Marshal(doc)
+	if err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	if file := cmd.Flag("output").Value.String(); file != "" {
+		if err := os.WriteFile(file, b, 0o600); err != nil {
+			log.FATAL.Fatal(err)
+		}
+		return
+	}
+
+	os.Stdout.Write(b)
+}
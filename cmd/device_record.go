@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/evcc-io/evcc/util/config"
+	"github.com/evcc-io/evcc/util/fixture"
+	"github.com/evcc-io/evcc/util/templates"
+	"github.com/spf13/cast"
+	"github.com/spf13/cobra"
+)
+
+// deviceRecordCmd represents the device record command
+var deviceRecordCmd = &cobra.Command{
+	Use:   "record <meter|charger|vehicle> <name>",
+	Short: "Record device traffic as template test fixture",
+	Long: `Record the modbus, http and mqtt traffic of a configured template device
+together with its current readings as template test fixture. Fixtures are
+replayed by the template tests from templates/fixtures/<class>/.
+Masked and private template parameters like credentials are replaced in the
+configuration and traffic. Other personal data like serial numbers must be
+removed before publishing.`,
+	Args: cobra.ExactArgs(2),
+	Run:  runDeviceRecord,
+}
+
+func init() {
+	deviceCmd.AddCommand(deviceRecordCmd)
+	deviceRecordCmd.Flags().StringP("output", "o", "", "Output file (default <template>.yaml)")
+}
+
+func deviceByName[T any](h config.Handler[T], name string) (config.Named, any, error) {
+	dev, err := h.ByName(name)
+	if err != nil {
+		return config.Named{}, nil, err
+	}
+
+	return dev.Config(), dev.Instance(), nil
+}
+
+func runDeviceRecord(cmd *cobra.Command, args []string) {
+	class, err := templates.ClassString(args[0])
+	if err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	name := args[1]
+
+	// load config
+	if err := loadConfigFile(&conf, !cmd.Flag(flagIgnoreDatabase).Changed); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	// record including shared mqtt connection
+	rec := fixture.Record()
+
+	// setup environment
+	if err := configureEnvironment(cmd, &conf); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	var (
+		cc  config.Named
+		dev any
+	)
+
+	switch class {
+	case templates.Meter:
+		if err = configureMeters(conf.Meters, name); err == nil {
+			cc, dev, err = deviceByName(config.Meters(), name)
+		}
+	case templates.Charger:
+		if err = configureChargers(conf.Chargers, name); err == nil {
+			cc, dev, err = deviceByName(config.Chargers(), name)
+		}
+	case templates.Vehicle:
+		if err = configureVehicles(conf.Vehicles, name); err == nil {
+			cc, dev, err = deviceByName(config.Vehicles(), name)
+		}
+	default:
+		err = fmt.Errorf("unsupported class: %s", class)
+	}
+	if err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	if cc.Type != "template" {
+		log.FATAL.Fatalf("%s is not a template device", name)
+	}
+
+	values, err := fixture.Read(dev)
+	if err != nil {
+		log.WARN.Println(err)
+	}
+
+	f := rec.Stop()
+	f.Config = maps.Clone(cc.Other)
+	f.Expect = values
+
+	tmpl, err := templates.ByName(class, cast.ToString(cc.Other["template"]))
+	if err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	var secrets []string
+	for _, p := range tmpl.Params {
+		if p.IsMasked() || p.IsPrivate() {
+			secrets = append(secrets, p.Name)
+		}
+	}
+
+	f.Mask(secrets...)
+
+	file := cmd.Flag("output").Value.String()
+	if file == "" {
+		file = fmt.Sprintf("%v.yaml", cc.Other["template"])
+	}
+
+	if err := f.Save(file); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	fmt.Println("fixture written to", file)
+}
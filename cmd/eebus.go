@@ -0,0 +1,15 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// eebusCmd represents the eebus command
+var eebusCmd = &cobra.Command{
+	Use:   "eebus",
+	Short: "EEBUS tools",
+}
+
+func init() {
+	rootCmd.AddCommand(eebusCmd)
+}
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io/fs"
+	"math"
+	"os"
+	"time"
+
+	ucapi "github.com/enbility/eebus-go/usecases/api"
+	"github.com/enbility/ship-go/cert"
+	"github.com/evcc-io/evcc/server/eebus"
+	"github.com/evcc-io/evcc/server/eebus/simulator"
+	"github.com/evcc-io/evcc/util"
+	"github.com/spf13/cobra"
+)
+
+// eebusSimulateCmd represents the eebus simulate command
+var eebusSimulateCmd = &cobra.Command{
+	Use:   "simulate <ski>",
+	Short: "Simulate a controlbox stating grid operator limits to evcc",
+	Long: `Simulate a controlbox (Energy Guard) stating LPC/LPP limits to the evcc instance identified by its SKI.
+Configure the printed controlbox SKI as eebus hems ski in evcc. Use --certificate to keep the SKI stable across runs.`,
+	Args: cobra.ExactArgs(1),
+	Run:  runEEBusSimulate,
+}
+
+func init() {
+	eebusCmd.AddCommand(eebusSimulateCmd)
+
+	eebusSimulateCmd.Flags().Int("port", 4713, "Controlbox port")
+	eebusSimulateCmd.Flags().String("certificate", "", "Controlbox certificate file (created if missing)")
+	eebusSimulateCmd.Flags().Float64("limit", 0, "Consumption limit (W, 0 releases the limit)")
+	eebusSimulateCmd.Flags().Duration("duration", 0, "Consumption limit duration (0 = unlimited)")
+	eebusSimulateCmd.Flags().Float64("production-limit", 0, "Production limit (W, 0 releases the limit)")
+	eebusSimulateCmd.Flags().Float64("failsafe", 0, "Failsafe consumption limit (W)")
+	eebusSimulateCmd.Flags().Float64("failsafe-production", 0, "Failsafe production limit (W)")
+	eebusSimulateCmd.Flags().Duration("failsafe-duration", 0, "Minimum failsafe duration")
+	eebusSimulateCmd.Flags().Duration("heartbeat-loss", 0, "Stop sending heartbeats for the given duration")
+}
+
+// simulatorCertificate loads the controlbox certificate from file or creates it
+func simulatorCertificate(file string) (tls.Certificate, error) {
+	if file == "" {
+		return cert.CreateCertificate("Demo", "Demo", "DE", "Demo-ControlBox-01")
+	}
+
+	if certificate, err := tls.LoadX509KeyPair(file, file); !errors.Is(err, fs.ErrNotExist) {
+		return certificate, err
+	}
+
+	certificate, err := cert.CreateCertificate("Demo", "Demo", "DE", "Demo-ControlBox-01")
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	public, private, err := eebus.GetX509KeyPair(certificate)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	return certificate, os.WriteFile(file, []byte(public+private), 0o600)
+}
+
+func runEEBusSimulate(cmd *cobra.Command, args []string) {
+	util.LogLevel(viper.GetString("log"), nil)
+
+	flags := cmd.Flags()
+
+	file, _ := flags.GetString("certificate")
+	certificate, err := simulatorCertificate(file)
+	if err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	port, _ := flags.GetInt("port")
+	box, err := simulator.New(simulator.Config{
+		Port:        port,
+		Certificate: certificate,
+	})
+	if err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	log.INFO.Println("controlbox ski:", box.Ski())
+
+	ctx := context.Background()
+	box.Start(ctx, args[0])
+
+	log.INFO.Println("waiting for evcc to connect")
+	if err := box.Wait(ctx); err != nil {
+		log.FATAL.Fatal(err)
+	}
+	log.INFO.Println("connected")
+
+	if flags.Changed("failsafe") {
+		limit, _ := flags.GetFloat64("failsafe")
+		log.INFO.Printf("failsafe consumption limit: %.0fW", limit)
+
+		if err := box.WriteFailsafeConsumptionLimit(limit); err != nil {
+			log.FATAL.Fatal(err)
+		}
+	}
+
+	if flags.Changed("failsafe-production") {
+		limit, _ := flags.GetFloat64("failsafe-production")
+		log.INFO.Printf("failsafe production limit: %.0fW", limit)
+
+		if err := box.WriteFailsafeProductionLimit(math.Abs(limit)); err != nil {
+			log.FATAL.Fatal(err)
+		}
+	}
+
+	if flags.Changed("failsafe-duration") {
+		duration, _ := flags.GetDuration("failsafe-duration")
+		log.INFO.Println("failsafe duration:", duration)
+
+		if err := box.WriteFailsafeDuration(duration); err != nil {
+			log.FATAL.Fatal(err)
+		}
+	}
+
+	if flags.Changed("limit") {
+		limit, _ := flags.GetFloat64("limit")
+		duration, _ := flags.GetDuration("duration")
+		log.INFO.Printf("consumption limit: %.0fW (duration: %v)", limit, duration)
+
+		if err := box.WriteConsumptionLimit(ucapi.LoadLimit{
+			IsActive: limit > 0,
+			Value:    limit,
+			Duration: duration,
+		}); err != nil {
+			log.FATAL.Fatal(err)
+		}
+	}
+
+	if flags.Changed("production-limit") {
+		limit, _ := flags.GetFloat64("production-limit")
+		log.INFO.Printf("production limit: %.0fW", limit)
+
+		// production limits are negative watts
+		if err := box.WriteProductionLimit(ucapi.LoadLimit{
+			IsActive: limit != 0,
+			Value:    -math.Abs(limit),
+		}); err != nil {
+			log.FATAL.Fatal(err)
+		}
+	}
+
+	if loss, _ := flags.GetDuration("heartbeat-loss"); loss > 0 {
+		log.INFO.Println("heartbeat stopped for", loss)
+		box.Heartbeat(false)
+
+		time.Sleep(loss)
+
+		log.INFO.Println("heartbeat resumed")
+		box.Heartbeat(true)
+	}
+
+	log.INFO.Println("running, press Ctrl-C to exit")
+	select {}
+}
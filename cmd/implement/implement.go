@@ -75,6 +75,9 @@ func generate(out io.Writer) error {
 		reflect.TypeFor[api.MaxACPowerGetter](),
 		reflect.TypeFor[api.Meter](),
 		reflect.TypeFor[api.MeterEnergy](),
+		reflect.TypeFor[api.MeterFrequency](),
+		reflect.TypeFor[api.MeterPowerFactor](),
+		reflect.TypeFor[api.MeterReactivePower](),
 		reflect.TypeFor[api.MeterReturnEnergy](),
 		reflect.TypeFor[api.PhaseCurrents](),
 		reflect.TypeFor[api.PhaseGetter](),
@@ -89,6 +92,7 @@ func generate(out io.Writer) error {
 		reflect.TypeFor[api.VehicleOdometer](),
 		reflect.TypeFor[api.VehiclePosition](),
 		reflect.TypeFor[api.VehicleRange](),
+		reflect.TypeFor[api.Watchdog](),
 	} {
 		lastPart := typ.Name()
 		var functions []funcStruct
@@ -39,6 +39,16 @@ func collectRefs(conf globalconfig.All) error {
 		return err
 	}
 
+	// additional sites
+	for _, sc := range conf.Sites {
+		if err := collectSiteMeterRefs(sc.Site); err != nil {
+			return err
+		}
+		if err := collectLoadpointRefs(slices.Values(sc.Loadpoints)); err != nil {
+			return err
+		}
+	}
+
 	// append devices from database
 	configurable, err := config.ConfigurationsByClass(templates.Loadpoint)
 	if err != nil {
@@ -55,12 +65,31 @@ func collectRefs(conf globalconfig.All) error {
 }
 
 func collectSiteRefs(conf globalconfig.All) error {
+	if err := collectSiteMeterRefs(conf.Site); err != nil {
+		return err
+	}
+
+	// append devices from settings
+	if v, err := settings.String(keys.GridMeter); err == nil && v != "" {
+		references.meter = append(references.meter, v)
+	}
+
+	for _, key := range []string{keys.PvMeters, keys.BatteryMeters, keys.ExtMeters, keys.AuxMeters, keys.ConsumerMeters} {
+		if v, err := settings.String(key); err == nil && v != "" {
+			references.meter = append(references.meter, strings.Split(v, ",")...)
+		}
+	}
+
+	return nil
+}
+
+func collectSiteMeterRefs(other map[string]any) error {
 	var refs struct {
 		Meters core.MetersConfig `mapstructure:"meters"` // Meter references
 		Other  map[string]any    `mapstructure:",remain"`
 	}
 
-	if err := util.DecodeOther(conf.Site, &refs); err != nil {
+	if err := util.DecodeOther(other, &refs); err != nil {
 		return err
 	}
 
@@ -71,17 +100,6 @@ func collectSiteRefs(conf globalconfig.All) error {
 	references.meter = append(references.meter, refs.Meters.AuxMetersRef...)
 	references.meter = append(references.meter, refs.Meters.ConsumerMetersRef...)
 
-	// append devices from settings
-	if v, err := settings.String(keys.GridMeter); err == nil && v != "" {
-		references.meter = append(references.meter, v)
-	}
-
-	for _, key := range []string{keys.PvMeters, keys.BatteryMeters, keys.ExtMeters, keys.AuxMeters, keys.ConsumerMeters} {
-		if v, err := settings.String(key); err == nil && v != "" {
-			references.meter = append(references.meter, strings.Split(v, ",")...)
-		}
-	}
-
 	return nil
 }
 
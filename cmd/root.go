@@ -15,7 +15,9 @@ import (
 	"github.com/evcc-io/evcc/api/globalconfig"
 	"github.com/evcc-io/evcc/charger/ocpp"
 	"github.com/evcc-io/evcc/core"
+	"github.com/evcc-io/evcc/core/automation"
 	"github.com/evcc-io/evcc/core/keys"
+	siteapi "github.com/evcc-io/evcc/core/site"
 	"github.com/evcc-io/evcc/hems/hems"
 	"github.com/evcc-io/evcc/messenger"
 	"github.com/evcc-io/evcc/server"
@@ -302,8 +304,15 @@ func runRoot(cmd *cobra.Command, args []string) {
 
 	// setup site and loadpoints
 	var site *core.Site
+	var namedSites map[string]*core.Site
 	if err == nil {
-		site, err = configureSiteAndLoadpoints(&conf)
+		site, namedSites, err = configureSitesAndLoadpoints(&conf)
+	}
+
+	// sites keyed by id, the default site has an empty id
+	sites := map[string]siteapi.API{"": site}
+	for id, s := range namedSites {
+		sites[id] = s
 	}
 
 	// setup influx
@@ -329,7 +338,7 @@ func runRoot(cmd *cobra.Command, args []string) {
 				keys.TariffSolar,
 				keys.ChargedEnergy,
 				keys.ChargeRemainingEnergy)
-			go influx.Run(site, dedupe.Pipe(
+			go influx.Run(sites, dedupe.Pipe(
 				pipe.NewDropper(append(ignoreLogs, ignoreEmpty, keys.Forecast)...).Pipe(tee.Attach()),
 			))
 		}
@@ -343,12 +352,19 @@ func runRoot(cmd *cobra.Command, args []string) {
 	// setup mqtt publisher
 	if err == nil && conf.Mqtt.Broker != "" && conf.Mqtt.Topic != "" {
 		var mqtt *server.MQTT
-		mqtt, err = server.NewMQTT(strings.Trim(conf.Mqtt.Topic, "/"), site)
+		mqtt, err = server.NewMQTT(strings.Trim(conf.Mqtt.Topic, "/"), sites)
 		if err == nil {
-			go mqtt.Run(site, pipe.NewDropper(append(ignoreMqtt, ignoreEmpty)...).Pipe(tee.Attach()))
+			go mqtt.Run(sites, pipe.NewDropper(append(ignoreMqtt, ignoreEmpty)...).Pipe(tee.Attach()))
 		}
 	}
 
+	// setup automation scripts
+	var automationRunner *automation.Runner
+	if err == nil {
+		automationRunner = configureAutomation(site)
+		go automationRunner.Run(pipe.NewDropper(append(ignoreLogs, ignoreEmpty)...).Pipe(tee.Attach()))
+	}
+
 	// announce on mDNS
 	if err == nil {
 		if err := configureMDNS(conf.Network); err != nil {
@@ -512,11 +528,22 @@ func runRoot(cmd *cobra.Command, args []string) {
 		site.DumpConfig()
 		site.Prepare(valueChan, pushChan)
 
-		httpd.RegisterSiteHandlers(site)
+		httpd.RegisterSiteHandlers(site, authObject)
+		httpd.RegisterAutomationHandlers(automationRunner, authObject)
 
 		go func() {
 			site.Run(stopC, conf.Interval)
 		}()
+
+		// setup additional sites
+		for _, s := range namedSites {
+			s.DumpConfig()
+			s.Prepare(valueChan, pushChan)
+
+			go s.Run(stopC, conf.Interval)
+		}
+
+		httpd.RegisterNamedSiteHandlers(sites, cache, authObject)
 	}
 
 	// signal HTTP API ready
@@ -20,6 +20,7 @@ import (
 	"github.com/evcc-io/evcc/charger/ocpp"
 	"github.com/evcc-io/evcc/cmd/shutdown"
 	"github.com/evcc-io/evcc/core"
+	"github.com/evcc-io/evcc/core/automation"
 	"github.com/evcc-io/evcc/core/circuit"
 	"github.com/evcc-io/evcc/core/keys"
 	"github.com/evcc-io/evcc/core/loadpoint"
@@ -34,7 +35,9 @@ import (
 	"github.com/evcc-io/evcc/plugin/javascript"
 	"github.com/evcc-io/evcc/plugin/mqtt"
 	"github.com/evcc-io/evcc/server"
+	"github.com/evcc-io/evcc/server/configdoc"
 	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/server/db/history"
 	"github.com/evcc-io/evcc/server/db/settings"
 	"github.com/evcc-io/evcc/server/eebus"
 	"github.com/evcc-io/evcc/server/modbus"
@@ -44,6 +47,7 @@ import (
 	"github.com/evcc-io/evcc/util/config"
 	"github.com/evcc-io/evcc/util/locale"
 	"github.com/evcc-io/evcc/util/machine"
+	"github.com/evcc-io/evcc/util/redact"
 	"github.com/evcc-io/evcc/util/request"
 	_ "github.com/evcc-io/evcc/util/service"
 	"github.com/evcc-io/evcc/util/sponsor"
@@ -93,6 +97,12 @@ var yamlSource struct {
 
 var nameRE = regexp.MustCompile(`^[a-zA-Z0-9_.:-]+$`)
 
+// site ids are used as api path, mqtt topic and settings key segment
+var siteIDRegex = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// reservedSiteIDs collide with top-level mqtt topics
+var reservedSiteIDs = []string{"site", "sites", "loadpoints", "vehicles", "circuits", "updated", "forecast", "battery", "pv", "aux", "ext"}
+
 func nameValid(name string) error {
 	if !nameRE.MatchString(name) {
 		return fmt.Errorf("name must not contain special characters or spaces: %s", name)
@@ -228,13 +238,17 @@ NEXT:
 			return fmt.Errorf("cannot decode custom circuit '%s': %w", cc.Name, err)
 		}
 
-		ctx := util.WithLogger(context.TODO(), log)
+		ctx, cancel := context.WithCancel(util.WithLogger(context.TODO(), log))
 
 		instance, err := circuit.NewFromConfig(ctx, typ, other)
 		if err != nil {
+			cancel()
 			return fmt.Errorf("cannot create circuit '%s': %w", cc.Name, err)
 		}
 
+		// cancel ctx once the instance is replaced or deleted
+		config.Own(instance, cancel)
+
 		// ensure config has title
 		if instance.GetTitle() == "" {
 			//lint:ignore SA1019 as Title is safe on ascii
@@ -296,7 +310,7 @@ func validateConfigurableCircuits(children []config.Config) error {
 type newFromConfFunc[T any] func(context.Context, string, map[string]any) (T, error)
 
 func staticInstance[T any](typ string, cc config.Named, newFromConf newFromConfFunc[T], h config.Handler[T]) error {
-	ctx, cancel := context.WithCancel(util.WithLogger(context.TODO(), util.NewLogger(cc.Name)))
+	ctx, cancel := context.WithCancel(util.WithNotifier(util.WithLogger(context.TODO(), util.NewLogger(cc.Name)), cc.Name))
 
 	instance, err := newFromConf(ctx, cc.Type, cc.Other)
 	if err != nil {
@@ -328,7 +342,7 @@ func loggerForConfig(conf *config.Config) *util.Logger {
 
 func configurableInstance[T any](typ string, conf *config.Config, newFromConf newFromConfFunc[T], h config.Handler[T]) error {
 	cc := conf.Named()
-	ctx, cancel := context.WithCancel(util.WithLogger(context.TODO(), loggerForConfig(conf)))
+	ctx, cancel := context.WithCancel(util.WithNotifier(util.WithLogger(context.TODO(), loggerForConfig(conf)), cc.Name))
 
 	typ, other, err := config.CustomDevice(cc.Type, cc.Other)
 	if err != nil {
@@ -354,6 +368,11 @@ func configurableInstance[T any](typ string, conf *config.Config, newFromConf ne
 		err = &DeviceError{cc.Name, e}
 	}
 
+	// cancel ctx once the instance is replaced or deleted
+	if err == nil {
+		config.Own(instance, cancel)
+	}
+
 	return err
 }
 
@@ -591,6 +610,21 @@ func configureSponsorship(token string) (err error) {
 	return sponsor.ConfigureSponsorship(token)
 }
 
+// configureTemplates merges external templates. Local templates take precedence over the bundle.
+func configureTemplates(conf globalconfig.Templates) error {
+	if conf.Bundle != "" {
+		if err := templates.LoadBundle(conf.Bundle, conf.PublicKey); err != nil {
+			return err
+		}
+	}
+
+	if conf.Dir != "" {
+		return templates.LoadDir(conf.Dir)
+	}
+
+	return nil
+}
+
 func configureEnvironment(cmd *cobra.Command, conf *globalconfig.All) error {
 	// full http request log
 	if cmd.Flag(flagHeaders).Changed {
@@ -619,6 +653,11 @@ func configureEnvironment(cmd *cobra.Command, conf *globalconfig.All) error {
 		}
 	}
 
+	// setup external templates
+	if err == nil {
+		err = configureTemplates(conf.Templates)
+	}
+
 	// setup translations
 	if err == nil {
 		// TODO decide wrapping
@@ -674,6 +713,10 @@ func configureDatabase(conf globalconfig.DB) error {
 		return err
 	}
 
+	// keep revisions of configuration settings only, not of runtime state or tokens
+	history.Track(history.Setting, configdoc.IsConfigSetting)
+	history.Secrets(redact.IsSecret)
+
 	persistSettings := func() {
 		if err := settings.Persist(); err != nil {
 			log.ERROR.Println("cannot save settings:", err)
@@ -907,6 +950,20 @@ func configureMDNS(conf globalconfig.Network) error {
 	return nil
 }
 
+// setup automation scripts
+func configureAutomation(site *core.Site) *automation.Runner {
+	runner := automation.New(site)
+
+	var scripts []automation.Script
+	if err := settings.Json(keys.Automation, &scripts); err == nil {
+		if err := runner.Apply(scripts); err != nil {
+			log.ERROR.Printf("automation: %v", err)
+		}
+	}
+
+	return runner
+}
+
 // setup OCPP
 func configureOCPP(cfg *ocpp.Config, externalUrl string) {
 	if settings.Exists(keys.Ocpp) {
@@ -1056,30 +1113,56 @@ func configureMessengers(confMessaging *globalconfig.Messaging, confEvents *glob
 		}
 	}
 
+	// hot-reload ui-configured messengers
+	config.Messengers().Subscribe(func(op config.Operation, dev config.Device[api.Messenger]) {
+		inst := dev.Instance()
+
+		switch op {
+		case config.OpAdd:
+			if inst != nil {
+				messageHub.Add(inst)
+			}
+		case config.OpUpdate:
+			previous, _ := config.Previous(dev)
+			if inst == nil {
+				messageHub.Delete(previous)
+			} else {
+				messageHub.Replace(previous, inst)
+			}
+		case config.OpDelete:
+			messageHub.Delete(inst)
+		}
+	})
+
 	go messageHub.Run(messageChan)
 
 	return messageChan, nil
 }
 
 func tariffInstance(name string, conf config.Typed) (api.Tariff, error) {
-	ctx := util.WithLogger(context.TODO(), util.NewLogger(name))
-
 	typ, other, err := config.CustomDevice(conf.Type, conf.Other)
 	if err != nil {
 		return nil, fmt.Errorf("cannot decode custom tariff '%s': %w", name, err)
 	}
 
+	ctx, cancel := context.WithCancel(util.WithLogger(context.TODO(), util.NewLogger(name)))
+
 	instance, err := tariff.NewFromConfig(ctx, typ, other)
 	if err != nil {
+		cancel()
+
 		if _, ok := errors.AsType[*util.ConfigError](err); ok {
 			return nil, err
 		}
 
 		// wrap non-config tariff errors to prevent fatals
 		log.ERROR.Printf("creating tariff %s failed: %v", name, err)
-		instance = tariff.NewWrapper(conf.Type, conf.Other, err)
+		return tariff.NewWrapper(conf.Type, conf.Other, err), nil
 	}
 
+	// cancel ctx once the instance is replaced or deleted
+	config.Own(instance, cancel)
+
 	return instance, nil
 }
 
@@ -1305,11 +1388,17 @@ func configureModbusProxy(conf *[]globalconfig.ModbusProxy) error {
 }
 
 func configureSiteAndLoadpoints(conf *globalconfig.All) (*core.Site, error) {
+	site, _, err := configureSitesAndLoadpoints(conf)
+	return site, err
+}
+
+// configureSitesAndLoadpoints configures the default site and all additional sites keyed by site id
+func configureSitesAndLoadpoints(conf *globalconfig.All) (*core.Site, map[string]*core.Site, error) {
 	// migrate settings
 	if settings.Exists(keys.Interval) {
 		d, err := settings.Int(keys.Interval)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		conf.Interval = time.Duration(d)
 	}
@@ -1339,20 +1428,34 @@ func configureSiteAndLoadpoints(conf *globalconfig.All) (*core.Site, error) {
 	}
 
 	if len(errs) > 0 {
-		return site, joinErrors(errs...)
+		return site, nil, joinErrors(errs...)
+	}
+
+	sites, err := configureNamedSites(conf.Sites, site, tariffs)
+	if err != nil {
+		return site, sites, err
 	}
 
 	if len(config.Circuits().Devices()) > 0 {
-		if err := validateCircuits(loadpoints); err != nil {
-			return site, &ClassError{ClassCircuit, err}
+		siteCircuits := []string{site.CircuitRef}
+		for _, s := range sites {
+			for _, lp := range s.Loadpoints() {
+				loadpoints = append(loadpoints, lp.(*core.Loadpoint))
+			}
+			siteCircuits = append(siteCircuits, s.CircuitRef)
+		}
+
+		if err := validateCircuits(loadpoints, siteCircuits...); err != nil {
+			return site, sites, &ClassError{ClassCircuit, err}
 		}
 	}
 
-	return site, nil
+	return site, sites, nil
 }
 
-func validateCircuits(loadpoints []*core.Loadpoint) error {
-	var hasRoot bool
+// validateCircuits validates the circuit tree. Multiple root circuits are only allowed if each site references its root circuit.
+func validateCircuits(loadpoints []*core.Loadpoint, siteCircuits ...string) error {
+	var roots []string
 
 CONTINUE:
 	for _, dev := range config.Circuits().Devices() {
@@ -1360,11 +1463,12 @@ CONTINUE:
 
 		isRoot := instance.GetParent() == nil
 		if isRoot {
-			if hasRoot {
+			name := dev.Config().Name
+			if len(roots) > 0 && (slices.Contains(siteCircuits, "") || !slices.Contains(siteCircuits, name) || !slices.Contains(siteCircuits, roots[0])) {
 				return errors.New("multiple root circuits")
 			}
 
-			hasRoot = true
+			roots = append(roots, name)
 		}
 
 		if slices.ContainsFunc(loadpoints, func(lp *core.Loadpoint) bool {
@@ -1378,7 +1482,7 @@ CONTINUE:
 		}
 	}
 
-	if !hasRoot {
+	if len(roots) == 0 {
 		return errors.New("missing root circuit")
 	}
 
@@ -1398,6 +1502,89 @@ func configureSite(conf map[string]any, loadpoints []*core.Loadpoint, tariffs *t
 	return site, nil
 }
 
+// configureNamedSites configures additional sites sharing vehicles with the primary site
+func configureNamedSites(conf []globalconfig.Site, primary *core.Site, tariffs *tariff.Tariffs) (map[string]*core.Site, error) {
+	res := make(map[string]*core.Site, len(conf))
+
+	for _, sc := range conf {
+		if !siteIDRegex.MatchString(sc.ID) || slices.Contains(reservedSiteIDs, sc.ID) {
+			return res, fmt.Errorf("invalid site id: %q", sc.ID)
+		}
+		if _, ok := res[sc.ID]; ok {
+			return res, fmt.Errorf("duplicate site id: %s", sc.ID)
+		}
+
+		site, err := configureNamedSite(sc, primary, tariffs)
+		if err != nil {
+			return res, &ClassError{ClassSite, fmt.Errorf("site %s: %w", sc.ID, err)}
+		}
+
+		res[sc.ID] = site
+	}
+
+	return res, nil
+}
+
+func configureNamedSite(conf globalconfig.Site, primary *core.Site, shared *tariff.Tariffs) (*core.Site, error) {
+	loadpoints := make([]*core.Loadpoint, 0, len(conf.Loadpoints))
+
+	for id, cc := range conf.Loadpoints {
+		idx := id + 1
+		name := conf.ID + ".lp-" + strconv.Itoa(idx)
+
+		lp, err := newLoadpoint(idx, name, cc.Other, func(*util.Logger) coresettings.Settings {
+			return coresettings.NewDatabaseSettingsAdapter(fmt.Sprintf("site.%s.lp%d.", conf.ID, idx))
+		})
+		if err != nil {
+			return nil, &DeviceError{name, err}
+		}
+
+		loadpoints = append(loadpoints, lp)
+	}
+
+	tariffs, err := configureSiteTariffs(conf.Tariffs, shared)
+	if err != nil {
+		return nil, &ClassError{ClassTariff, err}
+	}
+
+	site, err := core.NewNamedSiteFromConfig(conf.ID, primary, conf.Site)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := site.Boot(log, loadpoints, tariffs); err != nil {
+		return nil, fmt.Errorf("failed booting site: %w", err)
+	}
+
+	return site, nil
+}
+
+// configureSiteTariffs configures the tariffs of an additional site, inheriting roles not configured from the default site
+func configureSiteTariffs(conf globalconfig.Tariffs, shared *tariff.Tariffs) (*tariff.Tariffs, error) {
+	tariffs := *shared
+
+	var eg errgroup.Group
+	eg.Go(func() error { return configureTariff(conf.Grid, "", &tariffs.Grid) })
+	eg.Go(func() error { return configureTariff(conf.FeedIn, "", &tariffs.FeedIn) })
+	eg.Go(func() error { return configureTariff(conf.Co2, "", &tariffs.Co2) })
+	eg.Go(func() error { return configureTariff(conf.Planner, "", &tariffs.Planner) })
+	eg.Go(func() error { return configureSolarTariffs(conf.Solar, nil, &tariffs.Solar) })
+	eg.Go(func() error { return configureTariff(conf.Temperature, "", &tariffs.Temperature) })
+	if err := eg.Wait(); err != nil {
+		return &tariffs, err
+	}
+
+	if conf.Currency != "" {
+		cur, err := currency.ParseISO(conf.Currency)
+		if err != nil {
+			return &tariffs, err
+		}
+		tariffs.Currency = cur
+	}
+
+	return &tariffs, nil
+}
+
 func newLoadpoint(idx int, name string, other map[string]any, settingsFn func(*util.Logger) coresettings.Settings) (*core.Loadpoint, error) {
 	log := util.NewLoggerWithLoadpoint("lp-"+strconv.Itoa(idx), idx)
 
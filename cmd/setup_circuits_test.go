@@ -160,3 +160,32 @@ loadpoints:
 	// lp using root circuit is valid
 	suite.Require().NoError(validateCircuits(lps))
 }
+
+func (suite *circuitsTestSuite) TestMultipleRootCircuits() {
+	var conf globalconfig.All
+	viper.SetConfigType("yaml")
+
+	suite.Require().NoError(viper.ReadConfig(strings.NewReader(`
+circuits:
+- name: main
+  maxPower: 10000
+- name: garage
+  maxPower: 10000
+`)))
+
+	suite.Require().NoError(viper.UnmarshalExact(&conf))
+
+	suite.Require().NoError(configureCircuits(&conf.Circuits))
+	suite.Require().Len(config.Circuits().Devices(), 2)
+
+	// roots not referenced by sites
+	err := validateCircuits(nil, "")
+	suite.Require().Error(err)
+	suite.Require().Equal("multiple root circuits", err.Error())
+
+	// default site without root reference
+	suite.Require().Error(validateCircuits(nil, "", "garage"))
+
+	// each site references its root
+	suite.Require().NoError(validateCircuits(nil, "main", "garage"))
+}
@@ -0,0 +1,163 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/export"
+	"gorm.io/gorm"
+)
+
+// Kind is the channel a change arrived from
+type Kind string
+
+const (
+	HTTP     Kind = "http"
+	MQTT     Kind = "mqtt"
+	MCP      Kind = "mcp"
+	HEMS     Kind = "hems"
+	Internal Kind = "internal"
+)
+
+// Source identifies who initiated a change
+type Source struct {
+	Kind  Kind
+	Actor string // authenticated user, api key, mqtt topic etc.
+}
+
+// Entry is a single recorded setter invocation
+type Entry struct {
+	ID      uint      `json:"id" csv:"-" gorm:"primarykey"`
+	Created time.Time `json:"created" gorm:"index"`
+	Source  Kind      `json:"source"`
+	Actor   string    `json:"actor,omitempty"`
+	Target  string    `json:"target"` // site, loadpoint title or vehicle name
+	Key     string    `json:"key"`
+	Old     string    `json:"old"`
+	New     string    `json:"new"`
+	Error   string    `json:"error,omitempty"`
+}
+
+type Entries []Entry
+
+var _ export.Writer = (*Entries)(nil)
+
+// Write implements the export.Writer interface
+func (t *Entries) Write(ww export.RowWriter) error {
+	return export.WriteStructSlice(ww, t, export.Config{
+		I18nPrefix: "audit.csv",
+	})
+}
+
+var log = util.NewLogger("audit")
+
+func init() {
+	db.Register(func(db *gorm.DB) error {
+		return db.AutoMigrate(new(Entry))
+	})
+}
+
+type ctxKey struct{}
+
+// WithSource returns a context carrying the change source
+func WithSource(ctx context.Context, src Source) context.Context {
+	return context.WithValue(ctx, ctxKey{}, src)
+}
+
+// SourceFromContext returns the change source attached to the context
+func SourceFromContext(ctx context.Context) (Source, bool) {
+	src, ok := ctx.Value(ctxKey{}).(Source)
+	return src, ok
+}
+
+// encode converts a value into its json representation for storage
+func encode(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err.Error()
+	}
+	return string(b)
+}
+
+// record persists a setter invocation
+func record(src Source, target, key string, old, new any, err error) {
+	if db.Instance == nil {
+		return
+	}
+
+	e := Entry{
+		Created: time.Now(),
+		Source:  src.Kind,
+		Actor:   src.Actor,
+		Target:  target,
+		Key:     key,
+		Old:     encode(old),
+		New:     encode(new),
+	}
+
+	if err != nil {
+		e.Error = err.Error()
+	}
+
+	if err := db.Instance.Create(&e).Error; err != nil {
+		log.ERROR.Printf("persist: %v", err)
+	}
+}
+
+// Record persists a change that was not made through a wrapped setter, e.g. by a HEMS
+func Record(src Source, target, key string, old, new any) {
+	record(src, target, key, old, new, nil)
+}
+
+// Query selects audit entries
+type Query struct {
+	Source Kind
+	Target string
+	Key    string
+	From   time.Time
+	To     time.Time
+	Offset int
+	Limit  int
+}
+
+// Find returns the audit entries matching the query, newest first, and the total number of matches
+func Find(q Query) (Entries, int64, error) {
+	res := make(Entries, 0)
+	if db.Instance == nil {
+		return res, 0, nil
+	}
+
+	tx := db.Instance.Model(new(Entry))
+
+	if q.Source != "" {
+		tx = tx.Where("source = ?", q.Source)
+	}
+	if q.Target != "" {
+		tx = tx.Where("target = ?", q.Target)
+	}
+	if q.Key != "" {
+		tx = tx.Where("key = ?", q.Key)
+	}
+	if !q.From.IsZero() {
+		tx = tx.Where("created >= ?", q.From)
+	}
+	if !q.To.IsZero() {
+		tx = tx.Where("created < ?", q.To)
+	}
+
+	var total int64
+	if err := tx.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if q.Limit > 0 {
+		tx = tx.Limit(q.Limit)
+	}
+
+	err := tx.Order("id DESC").Offset(q.Offset).Find(&res).Error
+
+	return res, total, err
+}
@@ -0,0 +1,71 @@
+package audit
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestLoadpointSetters(t *testing.T) {
+	require.NoError(t, db.NewInstance("sqlite", ":memory:"))
+
+	ctrl := gomock.NewController(t)
+	lp := loadpoint.NewMockAPI(ctrl)
+
+	lp.EXPECT().GetMode().Return(api.ModePV)
+	lp.EXPECT().SetMode(api.ModeNow)
+	lp.EXPECT().GetMaxCurrent().Return(16.0)
+	lp.EXPECT().SetMaxCurrent(32.0).Return(errors.New("invalid"))
+
+	src := Source{Kind: MQTT, Actor: "evcc/loadpoints/1"}
+	alp := Loadpoint(lp, LoadpointTarget(0), src)
+
+	alp.SetMode(api.ModeNow)
+	assert.Error(t, alp.SetMaxCurrent(32))
+
+	res, total, err := Find(Query{})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), total)
+
+	// newest first
+	assert.Equal(t, Entry{
+		ID:      2,
+		Created: res[0].Created,
+		Source:  MQTT,
+		Actor:   "evcc/loadpoints/1",
+		Target:  "loadpoint-1",
+		Key:     "maxCurrent",
+		Old:     "16",
+		New:     "32",
+		Error:   "invalid",
+	}, res[0])
+
+	assert.Equal(t, "mode", res[1].Key)
+	assert.Equal(t, `"pv"`, res[1].Old)
+	assert.Equal(t, `"now"`, res[1].New)
+
+	res, total, err = Find(Query{Key: "mode", Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Len(t, res, 1)
+}
+
+func TestRecord(t *testing.T) {
+	require.NoError(t, db.NewInstance("sqlite", ":memory:"))
+
+	Record(Source{Kind: HEMS}, SiteTarget, "dimmed", nil, true)
+
+	res, total, err := Find(Query{Source: HEMS})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), total)
+
+	assert.Equal(t, "site", res[0].Target)
+	assert.Equal(t, "null", res[0].Old)
+	assert.Equal(t, "true", res[0].New)
+}
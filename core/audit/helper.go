@@ -0,0 +1,29 @@
+package audit
+
+import "github.com/evcc-io/evcc/server/db/settings"
+
+// target records changes of a single site, loadpoint or vehicle
+type target struct {
+	src  Source
+	name string
+}
+
+// set applies a setter without error result and records old and new value
+func set[T any](t target, key string, get func() T, set func(T), val T) {
+	old := get()
+	settings.Attribute(t.src.Actor, func() {
+		set(val)
+	})
+	record(t.src, t.name, key, old, val, nil)
+}
+
+// setE applies a setter with error result and records old and new value
+func setE[T any](t target, key string, get func() T, set func(T) error, val T) error {
+	old := get()
+	var err error
+	settings.Attribute(t.src.Actor, func() {
+		err = set(val)
+	})
+	record(t.src, t.name, key, old, val, err)
+	return err
+}
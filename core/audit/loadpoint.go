@@ -0,0 +1,164 @@
+package audit
+
+import (
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/core/loadpoint"
+)
+
+type loadpointAPI struct {
+	loadpoint.API
+	target
+}
+
+// Loadpoint wraps the loadpoint api recording all setter invocations
+func Loadpoint(lp loadpoint.API, name string, src Source) loadpoint.API {
+	return &loadpointAPI{
+		API:    lp,
+		target: target{src: src, name: name},
+	}
+}
+
+func (lp *loadpointAPI) SetChargerRef(ref string) {
+	set(lp.target, keys.Charger, lp.API.GetChargerRef, lp.API.SetChargerRef, ref)
+}
+
+func (lp *loadpointAPI) SetMeterRef(ref string) {
+	set(lp.target, keys.Meter, lp.API.GetMeterRef, lp.API.SetMeterRef, ref)
+}
+
+func (lp *loadpointAPI) SetCircuitRef(ref string) {
+	set(lp.target, keys.Circuit, lp.API.GetCircuitRef, lp.API.SetCircuitRef, ref)
+}
+
+func (lp *loadpointAPI) SetDefaultVehicleRef(ref string) {
+	set(lp.target, keys.DefaultVehicle, lp.API.GetDefaultVehicleRef, lp.API.SetDefaultVehicleRef, ref)
+}
+
+func (lp *loadpointAPI) SetTitle(title string) {
+	set(lp.target, keys.Title, lp.API.GetTitle, lp.API.SetTitle, title)
+}
+
+func (lp *loadpointAPI) SetPriority(prio int) {
+	set(lp.target, keys.Priority, lp.API.GetPriority, lp.API.SetPriority, prio)
+}
+
+func (lp *loadpointAPI) SetMinCurrent(current float64) error {
+	return setE(lp.target, keys.MinCurrent, lp.API.GetMinCurrent, lp.API.SetMinCurrent, current)
+}
+
+func (lp *loadpointAPI) SetMaxCurrent(current float64) error {
+	return setE(lp.target, keys.MaxCurrent, lp.API.GetMaxCurrent, lp.API.SetMaxCurrent, current)
+}
+
+func (lp *loadpointAPI) SetMode(mode api.ChargeMode) {
+	set(lp.target, keys.Mode, lp.API.GetMode, lp.API.SetMode, mode)
+}
+
+func (lp *loadpointAPI) SetDefaultMode(mode api.ChargeMode) {
+	set(lp.target, keys.DefaultMode, lp.API.GetDefaultMode, lp.API.SetDefaultMode, mode)
+}
+
+func (lp *loadpointAPI) SetPhasesConfigured(phases int) error {
+	return setE(lp.target, keys.PhasesConfigured, lp.API.GetPhasesConfigured, lp.API.SetPhasesConfigured, phases)
+}
+
+func (lp *loadpointAPI) SetLimitSoc(soc int) {
+	set(lp.target, keys.LimitSoc, lp.API.GetLimitSoc, lp.API.SetLimitSoc, soc)
+}
+
+func (lp *loadpointAPI) SetLimitEnergy(energy float64) {
+	set(lp.target, keys.LimitEnergy, lp.API.GetLimitEnergy, lp.API.SetLimitEnergy, energy)
+}
+
+func (lp *loadpointAPI) SetMinSoc(soc int) {
+	set(lp.target, keys.MinSoc, lp.API.GetMinSoc, lp.API.SetMinSoc, soc)
+}
+
+func (lp *loadpointAPI) SetPlanEnergy(ts time.Time, energy float64) error {
+	type plan struct {
+		Time   time.Time `json:"time"`
+		Energy float64   `json:"energy"`
+	}
+
+	get := func() plan {
+		ts, energy := lp.API.GetPlanEnergy()
+		return plan{ts, energy}
+	}
+
+	return setE(lp.target, keys.PlanEnergy, get, func(p plan) error {
+		return lp.API.SetPlanEnergy(p.Time, p.Energy)
+	}, plan{ts, energy})
+}
+
+func (lp *loadpointAPI) SetPlanStrategy(strategy api.PlanStrategy) error {
+	return setE(lp.target, keys.PlanStrategy, lp.API.GetPlanStrategy, lp.API.SetPlanStrategy, strategy)
+}
+
+func (lp *loadpointAPI) SetSchedules(schedules loadpoint.Schedules) error {
+	return setE(lp.target, keys.Schedules, lp.API.GetSchedules, lp.API.SetSchedules, schedules)
+}
+
+func (lp *loadpointAPI) SetSocConfig(soc loadpoint.SocConfig) {
+	set(lp.target, keys.Soc, lp.API.GetSocConfig, lp.API.SetSocConfig, soc)
+}
+
+func (lp *loadpointAPI) SetUI(ui loadpoint.UIConfig) {
+	set(lp.target, keys.UI, lp.API.GetUI, lp.API.SetUI, ui)
+}
+
+func (lp *loadpointAPI) SetWatchdog(watchdog loadpoint.WatchdogConfig) error {
+	return setE(lp.target, keys.Watchdog, lp.API.GetWatchdog, lp.API.SetWatchdog, watchdog)
+}
+
+func (lp *loadpointAPI) SetThresholds(thresholds loadpoint.ThresholdsConfig) {
+	set(lp.target, keys.Thresholds, lp.API.GetThresholds, lp.API.SetThresholds, thresholds)
+}
+
+func (lp *loadpointAPI) SetEnableThreshold(threshold float64) {
+	set(lp.target, keys.EnableThreshold, lp.API.GetEnableThreshold, lp.API.SetEnableThreshold, threshold)
+}
+
+func (lp *loadpointAPI) SetDisableThreshold(threshold float64) {
+	set(lp.target, keys.DisableThreshold, lp.API.GetDisableThreshold, lp.API.SetDisableThreshold, threshold)
+}
+
+func (lp *loadpointAPI) SetEnableDelay(delay time.Duration) {
+	set(lp.target, keys.EnableDelay, lp.API.GetEnableDelay, lp.API.SetEnableDelay, delay)
+}
+
+func (lp *loadpointAPI) SetDisableDelay(delay time.Duration) {
+	set(lp.target, keys.DisableDelay, lp.API.GetDisableDelay, lp.API.SetDisableDelay, delay)
+}
+
+func (lp *loadpointAPI) SetBatteryBoost(enable bool) error {
+	get := func() bool { return lp.API.GetBatteryBoost() > 0 }
+	return setE(lp.target, keys.BatteryBoost, get, lp.API.SetBatteryBoost, enable)
+}
+
+func (lp *loadpointAPI) SetBatteryBoostLimit(soc int) {
+	set(lp.target, keys.BatteryBoostLimit, lp.API.GetBatteryBoostLimit, lp.API.SetBatteryBoostLimit, soc)
+}
+
+func (lp *loadpointAPI) SetSmartCostLimit(limit *float64) {
+	set(lp.target, keys.SmartCostLimit, lp.API.GetSmartCostLimit, lp.API.SetSmartCostLimit, limit)
+}
+
+func (lp *loadpointAPI) SetSmartFeedInPriorityLimit(limit *float64) {
+	set(lp.target, keys.SmartFeedInPriorityLimit, lp.API.GetSmartFeedInPriorityLimit, lp.API.SetSmartFeedInPriorityLimit, limit)
+}
+
+func (lp *loadpointAPI) SetVehicle(vehicle api.Vehicle) {
+	title := func(v api.Vehicle) string {
+		if v == nil {
+			return ""
+		}
+		return v.GetTitle()
+	}
+
+	old := lp.API.GetVehicle()
+	lp.API.SetVehicle(vehicle)
+	record(lp.src, lp.name, keys.VehicleTitle, title(old), title(vehicle), nil)
+}
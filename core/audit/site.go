@@ -0,0 +1,140 @@
+package audit
+
+import (
+	"fmt"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/shedding"
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/util/geo"
+)
+
+// SiteTarget is the audit target name of the site
+const SiteTarget = "site"
+
+type siteAPI struct {
+	site.API
+	target
+	lpPrefix string
+}
+
+// Site wraps the site api recording all setter invocations including those of its loadpoints and vehicles
+func Site(s site.API, src Source) site.API {
+	return &siteAPI{
+		API:    s,
+		target: target{src: src, name: SiteTarget},
+	}
+}
+
+// NamedSite wraps the api of an additional site, prefixing all target names with the site id
+func NamedSite(s site.API, id string, src Source) site.API {
+	return &siteAPI{
+		API:      s,
+		target:   target{src: src, name: SiteTarget + "-" + id},
+		lpPrefix: SiteTarget + "-" + id + "-",
+	}
+}
+
+// LoadpointTarget returns the audit target name of the loadpoint with given index
+func LoadpointTarget(id int) string {
+	return fmt.Sprintf("loadpoint-%d", id+1)
+}
+
+// Loadpoints implements the site.API interface
+func (s *siteAPI) Loadpoints() []loadpoint.API {
+	lps := s.API.Loadpoints()
+
+	res := make([]loadpoint.API, 0, len(lps))
+	for id, lp := range lps {
+		res = append(res, Loadpoint(lp, s.lpPrefix+LoadpointTarget(id), s.src))
+	}
+
+	return res
+}
+
+// Vehicles implements the site.API interface
+func (s *siteAPI) Vehicles() site.Vehicles {
+	return &vehicles{Vehicles: s.API.Vehicles(), src: s.src}
+}
+
+func (s *siteAPI) SetTitle(title string) {
+	set(s.target, keys.SiteTitle, s.API.GetTitle, s.API.SetTitle, title)
+}
+
+func (s *siteAPI) SetHome(home geo.Home) error {
+	return setE(s.target, keys.Home, s.API.GetHome, s.API.SetHome, home)
+}
+
+func (s *siteAPI) SetLoadShedding(cfg shedding.Config) error {
+	return setE(s.target, keys.LoadShedding, s.API.GetLoadShedding, s.API.SetLoadShedding, cfg)
+}
+
+func (s *siteAPI) SetGridMeterRef(ref string) {
+	set(s.target, keys.GridMeter, s.API.GetGridMeterRef, s.API.SetGridMeterRef, ref)
+}
+
+func (s *siteAPI) SetPVMeterRefs(refs []string) {
+	set(s.target, keys.PvMeters, s.API.GetPVMeterRefs, s.API.SetPVMeterRefs, refs)
+}
+
+func (s *siteAPI) SetBatteryMeterRefs(refs []string) {
+	set(s.target, keys.BatteryMeters, s.API.GetBatteryMeterRefs, s.API.SetBatteryMeterRefs, refs)
+}
+
+func (s *siteAPI) SetAuxMeterRefs(refs []string) {
+	set(s.target, keys.AuxMeters, s.API.GetAuxMeterRefs, s.API.SetAuxMeterRefs, refs)
+}
+
+func (s *siteAPI) SetExtMeterRefs(refs []string) {
+	set(s.target, keys.ExtMeters, s.API.GetExtMeterRefs, s.API.SetExtMeterRefs, refs)
+}
+
+func (s *siteAPI) SetConsumerMeterRefs(refs []string) {
+	set(s.target, keys.ConsumerMeters, s.API.GetConsumerMeterRefs, s.API.SetConsumerMeterRefs, refs)
+}
+
+func (s *siteAPI) SetPrioritySoc(soc float64) error {
+	return setE(s.target, keys.PrioritySoc, s.API.GetPrioritySoc, s.API.SetPrioritySoc, soc)
+}
+
+func (s *siteAPI) SetBufferSoc(soc float64) error {
+	return setE(s.target, keys.BufferSoc, s.API.GetBufferSoc, s.API.SetBufferSoc, soc)
+}
+
+func (s *siteAPI) SetBufferStartSoc(soc float64) error {
+	return setE(s.target, keys.BufferStartSoc, s.API.GetBufferStartSoc, s.API.SetBufferStartSoc, soc)
+}
+
+func (s *siteAPI) SetBatteryGridChargeLimit(limit *float64) error {
+	return setE(s.target, keys.BatteryGridChargeLimit, s.API.GetBatteryGridChargeLimit, s.API.SetBatteryGridChargeLimit, limit)
+}
+
+func (s *siteAPI) SetOptimizerChargingStrategy(strategy string) error {
+	return setE(s.target, keys.OptimizerChargingStrategy, s.API.GetOptimizerChargingStrategy, s.API.SetOptimizerChargingStrategy, strategy)
+}
+
+func (s *siteAPI) SetResidualPower(power float64) error {
+	return setE(s.target, keys.ResidualPower, s.API.GetResidualPower, s.API.SetResidualPower, power)
+}
+
+func (s *siteAPI) SetGridExportLimit(power float64) error {
+	return setE(s.target, keys.GridExportLimit, s.API.GetGridExportLimit, s.API.SetGridExportLimit, power)
+}
+
+func (s *siteAPI) SetSolarAdjusted(enable bool) {
+	set(s.target, keys.SolarAdjusted, s.API.GetSolarAdjusted, s.API.SetSolarAdjusted, enable)
+}
+
+func (s *siteAPI) SetBatteryDischargeControl(enable bool) error {
+	return setE(s.target, keys.BatteryDischargeControl, s.API.GetBatteryDischargeControl, s.API.SetBatteryDischargeControl, enable)
+}
+
+func (s *siteAPI) SetBatteryGridDischarge(enable bool) error {
+	return setE(s.target, keys.BatteryGridDischarge, s.API.GetBatteryGridDischarge, s.API.SetBatteryGridDischarge, enable)
+}
+
+func (s *siteAPI) SetBatteryModeExternal(mode api.BatteryMode) error {
+	return setE(s.target, keys.BatteryModeExternal, s.API.GetBatteryModeExternal, s.API.SetBatteryModeExternal, mode)
+}
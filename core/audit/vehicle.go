@@ -0,0 +1,90 @@
+package audit
+
+import (
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/core/vehicle"
+)
+
+type vehicleAPI struct {
+	vehicle.API
+	target
+}
+
+// Vehicle wraps the vehicle api recording all setter invocations
+func Vehicle(v vehicle.API, src Source) vehicle.API {
+	return &vehicleAPI{
+		API:    v,
+		target: target{src: src, name: v.Name()},
+	}
+}
+
+func (v *vehicleAPI) SetMode(mode api.ChargeMode) {
+	set(v.target, keys.Mode, v.API.GetMode, v.API.SetMode, mode)
+}
+
+func (v *vehicleAPI) SetMinSoc(soc int) {
+	set(v.target, keys.MinSoc, v.API.GetMinSoc, v.API.SetMinSoc, soc)
+}
+
+func (v *vehicleAPI) SetLimitSoc(soc int) {
+	set(v.target, keys.LimitSoc, v.API.GetLimitSoc, v.API.SetLimitSoc, soc)
+}
+
+func (v *vehicleAPI) SetPlanSoc(ts time.Time, soc int) error {
+	type plan struct {
+		Time time.Time `json:"time"`
+		Soc  int       `json:"soc"`
+	}
+
+	get := func() plan {
+		ts, soc := v.API.GetPlanSoc()
+		return plan{ts, soc}
+	}
+
+	return setE(v.target, keys.PlanSoc, get, func(p plan) error {
+		return v.API.SetPlanSoc(p.Time, p.Soc)
+	}, plan{ts, soc})
+}
+
+func (v *vehicleAPI) SetRepeatingPlans(plans []api.RepeatingPlan) error {
+	return setE(v.target, keys.RepeatingPlans, v.API.GetRepeatingPlans, v.API.SetRepeatingPlans, plans)
+}
+
+func (v *vehicleAPI) SetTripCalendar(cal *api.TripCalendar) error {
+	return setE(v.target, keys.TripCalendar, v.API.GetTripCalendar, v.API.SetTripCalendar, cal)
+}
+
+func (v *vehicleAPI) SetDeparturePrediction(prediction *api.DeparturePrediction) error {
+	return setE(v.target, keys.Prediction, v.API.GetDeparturePrediction, v.API.SetDeparturePrediction, prediction)
+}
+
+func (v *vehicleAPI) SetPlanStrategy(strategy api.PlanStrategy) error {
+	return setE(v.target, keys.PlanStrategy, v.API.GetPlanStrategy, v.API.SetPlanStrategy, strategy)
+}
+
+type vehicles struct {
+	site.Vehicles
+	src Source
+}
+
+// Settings implements the site.Vehicles interface
+func (vv *vehicles) Settings() []vehicle.API {
+	res := vv.Vehicles.Settings()
+	for i, v := range res {
+		res[i] = Vehicle(v, vv.src)
+	}
+	return res
+}
+
+// ByName implements the site.Vehicles interface
+func (vv *vehicles) ByName(name string) (vehicle.API, error) {
+	v, err := vv.Vehicles.ByName(name)
+	if err != nil {
+		return nil, err
+	}
+	return Vehicle(v, vv.src), nil
+}
@@ -0,0 +1,111 @@
+package automation
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/plugin/golang/stdlib"
+	"github.com/evcc-io/evcc/util"
+	"github.com/traefik/yaegi/interp"
+)
+
+// errRunning is returned while a previous run exceeding the timeout has not terminated
+var errRunning = errors.New("previous run still running")
+
+// goExecutor runs a script in a new interpreter for each event.
+// The evcc package exposes Site, Loadpoints, Vehicle(name) and the triggering Event.
+// Interpreted code cannot be interrupted. A run exceeding the timeout keeps running in
+// the background and the script is not run again until it has terminated.
+type goExecutor struct {
+	log     *util.Logger
+	site    site.API
+	src     string
+	timeout time.Duration
+	done    chan struct{} // closed when the last run has terminated
+}
+
+func newGo(site site.API, log *util.Logger, src string, timeout time.Duration) (*goExecutor, error) {
+	e := &goExecutor{
+		log:     log,
+		site:    site,
+		src:     src,
+		timeout: timeout,
+	}
+
+	vm, err := e.vm(Event{})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := vm.Compile(src); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+func (e *goExecutor) vm(ev Event) (*interp.Interpreter, error) {
+	vm := interp.New(interp.Options{
+		Stdout: e.log.INFO.Writer(),
+		Stderr: e.log.ERROR.Writer(),
+	})
+
+	site := e.site
+	loadpoints := site.Loadpoints()
+
+	for _, symbols := range []interp.Exports{stdlib.Symbols, {
+		"evcc/evcc": {
+			"Site":       reflect.ValueOf(&site).Elem(),
+			"Loadpoints": reflect.ValueOf(&loadpoints).Elem(),
+			"Vehicle":    reflect.ValueOf(vehicleByName(site)),
+			"Event":      reflect.ValueOf(&ev).Elem(),
+		},
+	}} {
+		if err := vm.Use(symbols); err != nil {
+			return nil, err
+		}
+	}
+	vm.ImportUsed()
+
+	return vm, nil
+}
+
+func (e *goExecutor) Run(ev Event) error {
+	if e.done != nil {
+		select {
+		case <-e.done:
+		default:
+			return errRunning
+		}
+	}
+
+	vm, err := e.vm(ev)
+	if err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	e.done = done
+
+	go func() {
+		defer close(done)
+
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic: %v", r)
+			}
+		}()
+
+		_, err = vm.Eval(e.src)
+	}()
+
+	select {
+	case <-done:
+		return err
+	case <-time.After(e.timeout):
+		return errTimeout
+	}
+}
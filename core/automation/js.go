@@ -0,0 +1,127 @@
+package automation
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/core/vehicle"
+	"github.com/evcc-io/evcc/plugin/javascript"
+	"github.com/evcc-io/evcc/util"
+	"github.com/robertkrimen/otto"
+	"github.com/samber/lo"
+)
+
+var errTimeout = errors.New("timeout")
+
+// jsLoadpoint accepts charge modes as strings since otto does not convert to named string types
+type jsLoadpoint struct {
+	loadpoint.API
+}
+
+func (lp jsLoadpoint) SetMode(mode string) {
+	lp.API.SetMode(chargeMode(mode))
+}
+
+func (lp jsLoadpoint) SetDefaultMode(mode string) {
+	lp.API.SetDefaultMode(chargeMode(mode))
+}
+
+// jsVehicle accepts charge modes as strings since otto does not convert to named string types
+type jsVehicle struct {
+	vehicle.API
+}
+
+func (v jsVehicle) SetMode(mode string) {
+	v.API.SetMode(chargeMode(mode))
+}
+
+// chargeMode parses the charge mode and panics into the script on error
+func chargeMode(s string) api.ChargeMode {
+	mode, err := api.ChargeModeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return mode
+}
+
+// jsExecutor runs a script in its own VM. Global state is kept between runs.
+type jsExecutor struct {
+	vm      *otto.Otto
+	site    site.API
+	script  *otto.Script
+	timeout time.Duration
+}
+
+func newJS(site site.API, log *util.Logger, src string, timeout time.Duration) (*jsExecutor, error) {
+	vm := otto.New()
+	if err := javascript.SetConsole(vm, log); err != nil {
+		return nil, err
+	}
+
+	script, err := vm.Compile("", src)
+	if err != nil {
+		return nil, err
+	}
+
+	vehicle := func(name string) any {
+		if v := vehicleByName(site)(name); v != nil {
+			return jsVehicle{v}
+		}
+		return nil
+	}
+
+	if err := vm.Set("vehicle", vehicle); err != nil {
+		return nil, err
+	}
+
+	return &jsExecutor{
+		vm:      vm,
+		site:    site,
+		script:  script,
+		timeout: timeout,
+	}, nil
+}
+
+func (e *jsExecutor) Run(ev Event) (err error) {
+	event := map[string]any{
+		"key":   ev.Key,
+		"value": ev.Value,
+	}
+	if ev.Loadpoint != nil {
+		event["loadpoint"] = *ev.Loadpoint
+	}
+
+	for k, v := range map[string]any{
+		"site":       e.site,
+		"loadpoints": lo.Map(e.site.Loadpoints(), func(lp loadpoint.API, _ int) jsLoadpoint { return jsLoadpoint{lp} }),
+		"event":      event,
+	} {
+		if err := e.vm.Set(k, v); err != nil {
+			return err
+		}
+	}
+
+	e.vm.Interrupt = make(chan func(), 1)
+	timer := time.AfterFunc(e.timeout, func() {
+		e.vm.Interrupt <- func() { panic(errTimeout) }
+	})
+	defer timer.Stop()
+
+	defer func() {
+		if r := recover(); r != nil {
+			if r == errTimeout {
+				err = errTimeout
+			} else {
+				err = fmt.Errorf("panic: %v", r)
+			}
+		}
+	}()
+
+	_, err = e.vm.Run(e.script)
+
+	return err
+}
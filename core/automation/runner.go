@@ -0,0 +1,247 @@
+package automation
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/core/vehicle"
+	"github.com/evcc-io/evcc/util"
+)
+
+// Event is a change of a site or loadpoint parameter
+type Event struct {
+	Loadpoint *int   `json:"loadpoint,omitempty"` // loadpoint index
+	Key       string `json:"key"`
+	Value     any    `json:"value"`
+}
+
+// Status is the script's execution status
+type Status struct {
+	Script
+	Runs    int       `json:"runs"`
+	LastRun time.Time `json:"lastRun,omitzero"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// executor executes a compiled script for an event
+type executor interface {
+	Run(ev Event) error
+}
+
+type instance struct {
+	Script
+	log     *util.Logger
+	exec    executor // nil if disabled
+	runs    int
+	lastRun time.Time
+	err     error
+}
+
+// Runner executes the enabled scripts when subscribed parameters change.
+// Scripts are executed one at a time, events are dropped if the queue is full.
+type Runner struct {
+	mu      sync.Mutex
+	log     *util.Logger
+	site    site.API
+	timeout time.Duration
+	scripts []*instance
+	values  map[string]any // last value by parameter id
+	queue   chan Event
+}
+
+// New creates an automation runner
+func New(site site.API) *Runner {
+	return &Runner{
+		log:     util.NewLogger("automation"),
+		site:    site,
+		timeout: Timeout,
+		values:  make(map[string]any),
+		queue:   make(chan Event, 64),
+	}
+}
+
+func (r *Runner) executor(s Script, log *util.Logger) (executor, error) {
+	if s.Type == TypeGo {
+		return newGo(r.site, log, s.Script, r.timeout)
+	}
+	return newJS(r.site, log, s.Script, r.timeout)
+}
+
+func (r *Runner) instance(s Script) (*instance, error) {
+	res := &instance{
+		Script: s,
+		log:    util.NewLogger("automation-" + s.Name),
+	}
+
+	if s.Enabled {
+		exec, err := r.executor(s, res.log)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", s.Name, err)
+		}
+		res.exec = exec
+	}
+
+	return res, nil
+}
+
+// Apply replaces the scripts. Enabled scripts must compile.
+func (r *Runner) Apply(scripts []Script) error {
+	if err := Validate(scripts); err != nil {
+		return err
+	}
+
+	res := make([]*instance, 0, len(scripts))
+	for _, s := range scripts {
+		inst, err := r.instance(s)
+		if err != nil {
+			return err
+		}
+		res = append(res, inst)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.scripts = res
+
+	return nil
+}
+
+// Enable enables or disables a script and returns the updated scripts
+func (r *Runner) Enable(name string, enable bool) ([]Script, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	idx := slices.IndexFunc(r.scripts, func(s *instance) bool { return s.Name == name })
+	if idx < 0 {
+		return nil, fmt.Errorf("script not found: %s", name)
+	}
+
+	s := r.scripts[idx].Script
+	s.Enabled = enable
+
+	inst, err := r.instance(s)
+	if err != nil {
+		return nil, err
+	}
+
+	r.scripts[idx] = inst
+
+	return r.list(), nil
+}
+
+func (r *Runner) list() []Script {
+	res := make([]Script, 0, len(r.scripts))
+	for _, s := range r.scripts {
+		res = append(res, s.Script)
+	}
+	return res
+}
+
+// Scripts returns the scripts
+func (r *Runner) Scripts() []Script {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.list()
+}
+
+// Status returns the scripts' execution status
+func (r *Runner) Status() []Status {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	res := make([]Status, 0, len(r.scripts))
+	for _, s := range r.scripts {
+		status := Status{
+			Script:  s.Script,
+			Runs:    s.runs,
+			LastRun: s.lastRun,
+		}
+		if s.err != nil {
+			status.Error = s.err.Error()
+		}
+		res = append(res, status)
+	}
+
+	return res
+}
+
+// subscribed returns true if any enabled script is subscribed to the event
+func (r *Runner) subscribed(ev Event) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.ContainsFunc(r.scripts, func(s *instance) bool {
+		return s.exec != nil && s.matches(ev)
+	})
+}
+
+// Run dispatches changed parameters to the subscribed scripts. It must not block the parameter stream.
+func (r *Runner) Run(in <-chan util.Param) {
+	go r.worker()
+	defer close(r.queue)
+
+	for p := range in {
+		// scripts address the default site
+		if p.Site != "" {
+			continue
+		}
+
+		ev := Event{Loadpoint: p.Loadpoint, Key: p.Key, Value: p.Val}
+		if !r.subscribed(ev) {
+			continue
+		}
+
+		id := p.UniqueID()
+		if v, ok := r.values[id]; ok && reflect.DeepEqual(v, p.Val) {
+			continue
+		}
+		r.values[id] = p.Val
+
+		select {
+		case r.queue <- ev:
+		default:
+			r.log.WARN.Printf("queue full, dropping %s", id)
+		}
+	}
+}
+
+func (r *Runner) worker() {
+	for ev := range r.queue {
+		r.mu.Lock()
+		scripts := slices.Clone(r.scripts)
+		r.mu.Unlock()
+
+		for _, s := range scripts {
+			if s.exec != nil && s.matches(ev) {
+				r.run(s, ev)
+			}
+		}
+	}
+}
+
+func (r *Runner) run(s *instance, ev Event) {
+	err := s.exec.Run(ev)
+	if err != nil {
+		s.log.ERROR.Printf("%s: %v", ev.Key, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s.runs++
+	s.lastRun = time.Now()
+	s.err = err
+}
+
+// vehicleByName returns a vehicle lookup for scripts. Unknown vehicles are nil.
+func vehicleByName(site site.API) func(string) vehicle.API {
+	return func(name string) vehicle.API {
+		v, _ := site.Vehicles().ByName(name)
+		return v
+	}
+}
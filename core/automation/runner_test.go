@@ -0,0 +1,196 @@
+package automation
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type testSite struct {
+	site.API
+	title      string
+	loadpoints []loadpoint.API
+}
+
+func (s *testSite) Loadpoints() []loadpoint.API {
+	return s.loadpoints
+}
+
+func (s *testSite) GetTitle() string {
+	return s.title
+}
+
+func (s *testSite) SetTitle(title string) {
+	s.title = title
+}
+
+type testExecutor chan Event
+
+func (e testExecutor) Run(ev Event) error {
+	e <- ev
+	return nil
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate([]Script{{Name: "surplus", Type: TypeJS, Events: []string{"pvPower"}}}))
+	assert.Error(t, Validate([]Script{{Name: "surplus", Type: TypeJS}}), "events")
+	assert.Error(t, Validate([]Script{{Name: "surplus", Type: "lua", Events: []string{"pvPower"}}}), "type")
+	assert.Error(t, Validate([]Script{{Name: "pv surplus", Type: TypeJS, Events: []string{"pvPower"}}}), "name")
+	assert.Error(t, Validate([]Script{
+		{Name: "surplus", Type: TypeJS, Events: []string{"pvPower"}},
+		{Name: "surplus", Type: TypeGo, Events: []string{"pvPower"}},
+	}), "duplicate")
+}
+
+func TestMatches(t *testing.T) {
+	s := Script{Events: []string{"tariffGrid", "lp2.connected"}}
+
+	assert.True(t, s.matches(Event{Key: "tariffGrid"}))
+	assert.True(t, s.matches(Event{Loadpoint: new(1), Key: "connected"}))
+	assert.False(t, s.matches(Event{Loadpoint: new(0), Key: "connected"}))
+	assert.False(t, s.matches(Event{Key: "pvPower"}))
+}
+
+func TestJS(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	lp := loadpoint.NewMockAPI(ctrl)
+	lp.EXPECT().SetMode(api.ModePV)
+
+	s := &testSite{loadpoints: []loadpoint.API{lp}}
+	r := New(s)
+
+	require.NoError(t, r.Apply([]Script{{
+		Name:    "connected",
+		Type:    TypeJS,
+		Events:  []string{"connected"},
+		Enabled: true,
+		Script: `
+			if (event.value) {
+				loadpoints[event.loadpoint].SetMode("pv");
+				site.SetTitle("connected " + event.loadpoint);
+			}`,
+	}}))
+
+	require.NoError(t, r.scripts[0].exec.Run(Event{Loadpoint: new(0), Key: "connected", Value: true}))
+	assert.Equal(t, "connected 0", s.title)
+
+	// invalid charge modes fail the script
+	require.NoError(t, r.Apply([]Script{{
+		Name:    "invalid",
+		Type:    TypeJS,
+		Events:  []string{"connected"},
+		Enabled: true,
+		Script:  `loadpoints[0].SetMode("turbo")`,
+	}}))
+
+	assert.Error(t, r.scripts[0].exec.Run(Event{Key: "connected"}))
+}
+
+func TestGo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	lp := loadpoint.NewMockAPI(ctrl)
+	lp.EXPECT().SetMode(api.ModeOff)
+
+	s := &testSite{loadpoints: []loadpoint.API{lp}}
+	r := New(s)
+
+	require.NoError(t, r.Apply([]Script{{
+		Name:    "expensive",
+		Type:    TypeGo,
+		Events:  []string{"tariffGrid"},
+		Enabled: true,
+		Script: `
+			if evcc.Event.Value.(float64) > 0.3 {
+				evcc.Loadpoints[0].SetMode("off")
+				evcc.Site.SetTitle(fmt.Sprintf("%s %.2f", evcc.Event.Key, evcc.Event.Value))
+			}`,
+	}}))
+
+	require.NoError(t, r.scripts[0].exec.Run(Event{Key: "tariffGrid", Value: 0.4}))
+	assert.Equal(t, "tariffGrid 0.40", s.title)
+}
+
+func TestCompileError(t *testing.T) {
+	r := New(new(testSite))
+
+	for _, typ := range []string{TypeJS, TypeGo} {
+		s := Script{Name: "broken", Type: typ, Events: []string{"pvPower"}, Script: "if (", Enabled: true}
+		assert.Error(t, r.Apply([]Script{s}), typ)
+
+		// disabled scripts are not compiled
+		s.Enabled = false
+		assert.NoError(t, r.Apply([]Script{s}), typ)
+
+		_, err := r.Enable("broken", true)
+		assert.Error(t, err, typ)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	r := New(new(testSite))
+	r.timeout = 10 * time.Millisecond
+
+	require.NoError(t, r.Apply([]Script{
+		{Name: "js", Type: TypeJS, Events: []string{"pvPower"}, Script: "while (true) {}", Enabled: true},
+		{Name: "go", Type: TypeGo, Events: []string{"pvPower"}, Script: "time.Sleep(100 * time.Millisecond)", Enabled: true},
+	}))
+
+	for _, s := range r.scripts {
+		assert.ErrorIs(t, s.exec.Run(Event{Key: "pvPower"}), errTimeout, s.Name)
+	}
+
+	// go scripts are not interrupted and not run again before terminating
+	exec := r.scripts[1].exec
+	assert.ErrorIs(t, exec.Run(Event{Key: "pvPower"}), errRunning)
+
+	assert.Eventually(t, func() bool {
+		return errors.Is(exec.Run(Event{Key: "pvPower"}), errTimeout)
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestRun(t *testing.T) {
+	r := New(new(testSite))
+
+	exec := make(testExecutor, 10)
+	r.scripts = []*instance{{
+		Script: Script{Name: "surplus", Events: []string{"pvPower"}},
+		exec:   exec,
+	}}
+
+	in := make(chan util.Param)
+	go r.Run(in)
+
+	for _, p := range []util.Param{
+		{Key: "pvPower", Val: 1000.0},
+		{Key: "gridPower", Val: 0.0},
+		{Key: "pvPower", Val: 1000.0}, // unchanged
+		{Key: "pvPower", Val: 2000.0},
+	} {
+		in <- p
+	}
+	close(in)
+
+	for _, expected := range []float64{1000, 2000} {
+		select {
+		case ev := <-exec:
+			assert.Equal(t, Event{Key: "pvPower", Value: expected}, ev)
+		case <-time.After(time.Second):
+			require.Fail(t, "timeout")
+		}
+	}
+
+	assert.Eventually(t, func() bool {
+		return r.Status()[0].Runs == 2
+	}, time.Second, 10*time.Millisecond)
+	assert.Empty(t, exec)
+}
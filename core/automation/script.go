@@ -0,0 +1,66 @@
+// Package automation runs user scripts on site events with access to the site, loadpoint and vehicle APIs.
+package automation
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Timeout is the maximum execution time of a single script run
+const Timeout = 5 * time.Second
+
+const (
+	TypeJS = "js"
+	TypeGo = "go"
+)
+
+var nameRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// Script is an automation script triggered by site events
+type Script struct {
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`    // js or go
+	Events  []string `json:"events"`  // event keys, optionally loadpoint-scoped as lp1.connected
+	Script  string   `json:"script"`  // source code
+	Enabled bool     `json:"enabled"` // enabled flag
+}
+
+// Validate validates the scripts
+func Validate(scripts []Script) error {
+	for i, s := range scripts {
+		if !nameRegex.MatchString(s.Name) {
+			return fmt.Errorf("invalid name: %q", s.Name)
+		}
+		if slices.ContainsFunc(scripts[:i], func(o Script) bool { return o.Name == s.Name }) {
+			return fmt.Errorf("duplicate script: %s", s.Name)
+		}
+		if s.Type != TypeJS && s.Type != TypeGo {
+			return fmt.Errorf("%s: invalid type: %q", s.Name, s.Type)
+		}
+		if len(s.Events) == 0 {
+			return fmt.Errorf("%s: missing events", s.Name)
+		}
+		if slices.Contains(s.Events, "") {
+			return fmt.Errorf("%s: empty event", s.Name)
+		}
+	}
+
+	return nil
+}
+
+// matches returns true if the script is subscribed to the event
+func (s Script) matches(ev Event) bool {
+	for _, key := range s.Events {
+		if key == ev.Key {
+			return true
+		}
+		if ev.Loadpoint != nil && key == "lp"+strconv.Itoa(*ev.Loadpoint+1)+"."+ev.Key {
+			return true
+		}
+	}
+
+	return false
+}
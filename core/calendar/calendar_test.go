@@ -0,0 +1,163 @@
+package calendar
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/vehicle"
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/util/geo"
+	"github.com/evcc-io/evcc/util/ical"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var berlin = geo.Home{Fence: geo.Fence{Latitude: 52.5163, Longitude: 13.3777}}
+
+func TestNextTrip(t *testing.T) {
+	now := time.Now()
+
+	events := []ical.Event{
+		{UID: "past", Start: now.Add(-time.Hour), Description: "soc: 80"},
+		{UID: "meeting", Start: now.Add(time.Hour)},
+		{UID: "cancelled", Start: now.Add(2 * time.Hour), Description: "soc=80", Status: "CANCELLED"},
+		{UID: "hamburg", Start: now.Add(4 * time.Hour), Geo: &ical.Position{Latitude: 53.5503, Longitude: 9.9923}},
+		{UID: "distance", Start: now.Add(3 * time.Hour), Summary: "Trip km=120,5"},
+	}
+
+	trip, ok := NextTrip(events, berlin, now)
+	require.True(t, ok)
+	assert.Equal(t, "distance", trip.UID)
+	assert.Equal(t, 120.5, trip.Distance)
+
+	events = events[:4]
+	trip, ok = NextTrip(events, berlin, now)
+	require.True(t, ok)
+	assert.Equal(t, "hamburg", trip.UID)
+	assert.InDelta(t, 660, trip.Distance, 5)
+
+	_, ok = NextTrip(events, geo.Home{}, now)
+	assert.False(t, ok)
+}
+
+func TestTargetSoc(t *testing.T) {
+	assert.Equal(t, 90, Trip{Soc: 90, Distance: 100}.TargetSoc(50, 20, 20))
+	assert.Equal(t, 60, Trip{Distance: 100}.TargetSoc(50, 20, 20))
+	assert.Equal(t, 100, Trip{Distance: 500}.TargetSoc(50, 20, 20))
+	assert.Equal(t, 0, Trip{Distance: 100}.TargetSoc(0, 20, 20))
+}
+
+type vehicles []vehicle.API
+
+func (vv vehicles) Settings() []vehicle.API {
+	return vv
+}
+
+func TestPlanner(t *testing.T) {
+	require.NoError(t, db.NewInstance("sqlite", ":memory:"))
+
+	ctrl := gomock.NewController(t)
+
+	instance := api.NewMockVehicle(ctrl)
+	instance.EXPECT().Capacity().Return(50.0).AnyTimes()
+
+	departure := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+
+	var calendar string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(calendar))
+	}))
+	defer srv.Close()
+
+	write := func(ts time.Time) {
+		calendar = "BEGIN:VCALENDAR\nBEGIN:VEVENT\nUID:trip\nDTSTART:" +
+			ts.UTC().Format("20060102T150405Z") + "\nSUMMARY:Trip\nDESCRIPTION:distance: 100\nEND:VEVENT\nEND:VCALENDAR\n"
+	}
+
+	var (
+		planTime time.Time
+		planSoc  int
+	)
+
+	v := vehicle.NewMockAPI(ctrl)
+	v.EXPECT().Name().Return("ev").AnyTimes()
+	v.EXPECT().Instance().Return(instance).AnyTimes()
+	v.EXPECT().GetTripCalendar().Return(&api.TripCalendar{Uri: srv.URL}).AnyTimes()
+	v.EXPECT().GetPlanSoc().DoAndReturn(func() (time.Time, int) { return planTime, planSoc }).AnyTimes()
+	v.EXPECT().SetPlanSoc(gomock.Any(), gomock.Any()).DoAndReturn(func(ts time.Time, soc int) error {
+		planTime, planSoc = ts, soc
+		return nil
+	}).AnyTimes()
+
+	p := New(vehicles{v}, func() geo.Home { return berlin })
+
+	write(departure)
+	p.Update()
+	assert.True(t, departure.Equal(planTime))
+	assert.Equal(t, 60, planSoc)
+
+	// moved event
+	write(departure.Add(time.Hour))
+	p.Update()
+	assert.True(t, departure.Add(time.Hour).Equal(planTime))
+
+	// manual plan is kept
+	planTime, planSoc = departure, 80
+	write(departure.Add(2 * time.Hour))
+	p.Update()
+	assert.True(t, departure.Equal(planTime))
+	assert.Equal(t, 80, planSoc)
+}
+
+type geocoder map[string]ical.Position
+
+func (g geocoder) Locate(address string) (float64, float64, error) {
+	if p, ok := g[address]; ok {
+		return p.Latitude, p.Longitude, nil
+	}
+	return 0, 0, geo.ErrNotFound
+}
+
+func TestLocate(t *testing.T) {
+	now := time.Now()
+
+	events := []ical.Event{
+		{UID: "meeting", Start: now.Add(time.Hour), Location: "Room 4"},
+		{UID: "hamburg", Start: now.Add(2 * time.Hour), Location: "Rathausmarkt 1, Hamburg"},
+	}
+
+	p := New(nil, nil)
+
+	// geocoding is opt-in
+	p.locate(p.geocoder(""), events, berlin, now)
+	_, ok := NextTrip(events, berlin, now)
+	assert.False(t, ok)
+
+	p.locate(geocoder{"Rathausmarkt 1, Hamburg": {Latitude: 53.5503, Longitude: 9.9923}}, events, berlin, now)
+
+	trip, ok := NextTrip(events, berlin, now)
+	require.True(t, ok)
+	assert.Equal(t, "hamburg", trip.UID)
+	assert.InDelta(t, 660, trip.Distance, 5)
+}
+
+func TestFileUri(t *testing.T) {
+	now := time.Now()
+
+	file := filepath.Join(t.TempDir(), "trips.ics")
+	require.NoError(t, os.WriteFile(file, []byte("BEGIN:VCALENDAR\nBEGIN:VEVENT\nUID:trip\nDTSTART:"+
+		now.Add(time.Hour).UTC().Format("20060102T150405Z")+"\nEND:VEVENT\nEND:VCALENDAR\n"), 0o600))
+
+	for _, uri := range []string{file, "file://" + file} {
+		events, err := New(nil, nil).events(uri, now)
+		require.NoError(t, err, uri)
+		require.Len(t, events, 1, uri)
+		assert.Equal(t, "trip", events[0].UID)
+	}
+}
@@ -0,0 +1,222 @@
+// Package calendar converts trips from vehicle calendar feeds into charge plans.
+package calendar
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/core/vehicle"
+	"github.com/evcc-io/evcc/server/db/settings"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/geo"
+	"github.com/evcc-io/evcc/util/ical"
+	"github.com/evcc-io/evcc/util/request"
+)
+
+// horizon limits the trips considered for planning
+const horizon = 7 * 24 * time.Hour
+
+// Vehicles provides the vehicle settings
+type Vehicles interface {
+	Settings() []vehicle.API
+}
+
+// Geocoder resolves event locations to positions
+type Geocoder interface {
+	Locate(address string) (float64, float64, error)
+}
+
+// Planner keeps the vehicles' charge plans in sync with their trip calendars
+type Planner struct {
+	log       *util.Logger
+	helper    *request.Helper
+	vehicles  Vehicles
+	home      func() geo.Home
+	geocoders map[string]Geocoder
+}
+
+// plan is the charge plan created from a trip
+type plan struct {
+	UID  string    `json:"uid"`
+	Time time.Time `json:"time"`
+	Soc  int       `json:"soc"`
+}
+
+// New creates a trip calendar planner
+func New(vehicles Vehicles, home func() geo.Home) *Planner {
+	log := util.NewLogger("calendar")
+
+	return &Planner{
+		log:       log,
+		helper:    request.NewHelper(log),
+		vehicles:  vehicles,
+		home:      home,
+		geocoders: make(map[string]Geocoder),
+	}
+}
+
+// geocoder returns the geocoder for the search uri, keeping its cache and rate limit
+// across updates. Geocoding is disabled for an empty uri.
+func (p *Planner) geocoder(uri string) Geocoder {
+	if uri == "" {
+		return nil
+	}
+
+	g, ok := p.geocoders[uri]
+	if !ok {
+		g = geo.NewGeocoder(p.log, uri)
+		p.geocoders[uri] = g
+	}
+
+	return g
+}
+
+// Run updates the plans at the given interval until stopped
+func (p *Planner) Run(stopC <-chan struct{}, interval time.Duration) {
+	p.Update()
+
+	for tick := time.Tick(interval); ; {
+		select {
+		case <-tick:
+			p.Update()
+		case <-stopC:
+			return
+		}
+	}
+}
+
+// Update updates the plans of all vehicles with trip calendar
+func (p *Planner) Update() {
+	for _, v := range p.vehicles.Settings() {
+		if c := v.GetTripCalendar(); c != nil {
+			if err := p.update(v, *c); err != nil {
+				p.log.ERROR.Printf("%s: %v", v.Name(), err)
+			}
+		}
+	}
+}
+
+// read reads the calendar from http(s) url, file:// url or file path
+func (p *Planner) read(uri string) ([]byte, error) {
+	if strings.HasPrefix(uri, "http://") || strings.HasPrefix(uri, "https://") {
+		return p.helper.GetBody(uri)
+	}
+
+	return os.ReadFile(strings.TrimPrefix(uri, "file://"))
+}
+
+// events reads the calendar's events starting within the planning horizon
+func (p *Planner) events(uri string, now time.Time) ([]ical.Event, error) {
+	b, err := p.read(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := ical.Parse(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+
+	events = ical.Expand(events, now, now.Add(horizon))
+
+	return slices.DeleteFunc(events, func(ev ical.Event) bool {
+		return ev.Start.After(now.Add(horizon))
+	}), nil
+}
+
+// locate resolves the positions of upcoming events that are trips by location only
+func (p *Planner) locate(geocoder Geocoder, events []ical.Event, home geo.Home, now time.Time) {
+	if geocoder == nil || !home.Configured() {
+		return
+	}
+
+	for i, ev := range events {
+		if ev.Geo != nil || ev.Location == "" || !ev.Start.After(now) {
+			continue
+		}
+
+		if _, ok := NewTrip(ev, home); ok || ev.Cancelled() || ev.AllDay {
+			continue
+		}
+
+		lat, lon, err := geocoder.Locate(ev.Location)
+		if err != nil {
+			if !errors.Is(err, geo.ErrNotFound) {
+				p.log.WARN.Printf("locate %s: %v", ev.Location, err)
+			}
+			continue
+		}
+
+		events[i].Geo = &ical.Position{Latitude: lat, Longitude: lon}
+	}
+}
+
+func planKey(v vehicle.API) string {
+	return fmt.Sprintf("vehicle.%s.%s", v.Name(), keys.CalendarPlan)
+}
+
+// update sets the plan for the next trip. Plans not created from the calendar are left untouched.
+func (p *Planner) update(v vehicle.API, c api.TripCalendar) error {
+	now := time.Now()
+
+	events, err := p.events(c.Uri, now)
+	if err != nil {
+		return err
+	}
+
+	var prev plan
+	_ = settings.Json(planKey(v), &prev)
+
+	ts, soc := v.GetPlanSoc()
+	if !ts.IsZero() && (!ts.Equal(prev.Time) || soc != prev.Soc) {
+		p.log.DEBUG.Printf("%s: keeping manual plan", v.Name())
+		return nil
+	}
+
+	home := p.home()
+	p.locate(p.geocoder(c.Geocoder), events, home, now)
+
+	trip, ok := NextTrip(events, home, now)
+	if !ok {
+		if !ts.IsZero() {
+			p.log.INFO.Printf("%s: remove plan for cancelled trip %s", v.Name(), prev.UID)
+			settings.SetString(planKey(v), "")
+			return v.SetPlanSoc(time.Time{}, 0)
+		}
+		return nil
+	}
+
+	consumption := c.Consumption
+	if consumption == 0 {
+		consumption = DefaultConsumption
+	}
+
+	reserve := c.Reserve
+	if reserve == 0 {
+		reserve = DefaultReserve
+	}
+
+	target := trip.TargetSoc(v.Instance().Capacity(), consumption, reserve)
+	if target == 0 {
+		return fmt.Errorf("%s: cannot determine soc without vehicle capacity", trip.Title)
+	}
+
+	if ts.Equal(trip.Departure) && soc == target {
+		return nil
+	}
+
+	p.log.INFO.Printf("%s: plan %d%% @ %v for %s (%.0fkm)", v.Name(), target, trip.Departure.Round(time.Second).Local(), trip.Title, trip.Distance)
+
+	if err := v.SetPlanSoc(trip.Departure, target); err != nil {
+		return err
+	}
+
+	return settings.SetJson(planKey(v), plan{UID: trip.UID, Time: trip.Departure, Soc: target})
+}
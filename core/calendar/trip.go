@@ -0,0 +1,105 @@
+package calendar
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/util/geo"
+	"github.com/evcc-io/evcc/util/ical"
+)
+
+const (
+	DefaultConsumption = 20 // kWh/100km
+	DefaultReserve     = 20 // %
+
+	roadFactor = 1.3 // road distance relative to great-circle distance
+)
+
+var (
+	socRegex      = regexp.MustCompile(`(?i)\bsoc\s*[:=]\s*(\d{1,3})\s*%?`)
+	distanceRegex = regexp.MustCompile(`(?i)\b(?:distance|km)\s*[:=]\s*(\d+(?:[.,]\d+)?)`)
+)
+
+// Trip is an upcoming trip
+type Trip struct {
+	UID       string
+	Title     string
+	Departure time.Time
+	Distance  float64 // km, total distance
+	Soc       int     // explicit target soc in %, 0 if derived from distance
+}
+
+// TargetSoc returns the soc required for the trip including the reserve
+func (t Trip) TargetSoc(capacity, consumption float64, reserve int) int {
+	if t.Soc > 0 {
+		return min(t.Soc, 100)
+	}
+
+	if capacity <= 0 || t.Distance <= 0 {
+		return 0
+	}
+
+	energy := t.Distance * consumption / 100
+	soc := int(math.Ceil(100*energy/capacity)) + reserve
+
+	return min(soc, 100)
+}
+
+// NewTrip converts an event into a trip. Events are trips if they have a soc or distance tag
+// in their summary or description or, given a home location, a geo position.
+// Event locations are resolved to geo positions by the planner.
+// The distance to a geo position is estimated as round trip by road.
+func NewTrip(ev ical.Event, home geo.Home) (Trip, bool) {
+	if ev.Cancelled() || ev.AllDay {
+		return Trip{}, false
+	}
+
+	res := Trip{
+		UID:       ev.UID,
+		Title:     ev.Summary,
+		Departure: ev.Start,
+	}
+
+	text := ev.Summary + "\n" + ev.Description
+
+	if m := socRegex.FindStringSubmatch(text); m != nil {
+		if soc, err := strconv.Atoi(m[1]); err == nil && soc > 0 {
+			res.Soc = soc
+		}
+	}
+
+	if m := distanceRegex.FindStringSubmatch(text); m != nil {
+		if d, err := strconv.ParseFloat(strings.Replace(m[1], ",", ".", 1), 64); err == nil {
+			res.Distance = d
+		}
+	}
+
+	if res.Distance == 0 && ev.Geo != nil && home.Configured() {
+		res.Distance = 2 * roadFactor * home.Distance(ev.Geo.Latitude, ev.Geo.Longitude) / 1e3
+	}
+
+	return res, res.Soc > 0 || res.Distance > 0
+}
+
+// NextTrip returns the first trip departing after now
+func NextTrip(events []ical.Event, home geo.Home, now time.Time) (Trip, bool) {
+	var (
+		res Trip
+		ok  bool
+	)
+
+	for _, ev := range events {
+		if !ev.Start.After(now) || ok && !ev.Start.Before(res.Departure) {
+			continue
+		}
+
+		if trip, isTrip := NewTrip(ev, home); isTrip {
+			res, ok = trip, true
+		}
+	}
+
+	return res, ok
+}
@@ -41,6 +41,8 @@ type Circuit struct {
 
 	currentUpdated time.Time
 	powerUpdated   time.Time
+
+	protection protection // fast overload protection
 }
 
 func init() {
@@ -58,9 +60,14 @@ func NewConfigurableFromConfig(ctx context.Context, other map[string]any) (api.C
 		GetMaxCurrent *plugin.Config // dynamic max allowed current
 		GetMaxPower   *plugin.Config // dynamic max allowed power
 		Timeout       time.Duration  // timeout between meter updates
+		Protection    struct {
+			Interval time.Duration // fast overload protection meter interval, disabled if zero
+			Hold     time.Duration // time without overload before releasing curtailed loads
+		}
 	}{
 		Timeout: time.Minute,
 	}
+	cc.Protection.Hold = time.Minute
 
 	// drop circuit type- all circuits are custom
 	delete(other, "type")
@@ -87,6 +94,15 @@ func NewConfigurableFromConfig(ctx context.Context, other map[string]any) (api.C
 		return nil, err
 	}
 
+	if cc.Protection.Interval > 0 {
+		if meter == nil {
+			return nil, errors.New("overload protection requires meter")
+		}
+
+		circuit.protection.interval = cc.Protection.Interval
+		circuit.protection.hold = cc.Protection.Hold
+	}
+
 	circuit.getMaxPower, err = cc.GetMaxPower.FloatGetter(ctx)
 	if err != nil {
 		return nil, err
@@ -3,6 +3,7 @@ package circuit
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/evcc-io/evcc/api"
@@ -41,3 +42,35 @@ func Root() api.Circuit {
 	}
 	return nil
 }
+
+// Remove unregisters a circuit from its parent
+func Remove(circuit api.Circuit) {
+	p, ok := circuit.GetParent().(*Circuit)
+	if !ok {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.children = slices.DeleteFunc(p.children, func(c api.Circuit) bool { return c == circuit })
+}
+
+// Reparent moves the children of a replaced circuit to its successor
+func Reparent(previous, parent api.Circuit) {
+	Remove(previous)
+
+	for _, dev := range config.Circuits().Devices() {
+		c, ok := dev.Instance().(*Circuit)
+		if !ok || c.GetParent() != previous {
+			continue
+		}
+
+		c.mu.Lock()
+		c.parent = parent
+		c.mu.Unlock()
+
+		if parent != nil {
+			parent.RegisterChild(c)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package circuit
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// protectSettle is the time loads are given to follow a curtailment before curtailing further
+const protectSettle = 3 * time.Second
+
+// protection is the state of the fast overload protection loop
+type protection struct {
+	interval time.Duration // meter polling interval, disabled if zero
+	hold     time.Duration // time without overload before releasing curtailed loads
+
+	updated     time.Time                        // last meter reading
+	overloaded  time.Time                        // last overload
+	curtailedAt time.Time                        // last curtailment
+	curtailed   map[api.CircuitCurtailer]float64 // curtailed loads and their limits
+}
+
+// Protect reads the circuit meter and immediately curtails the circuit's loads on overload.
+// Curtailed loads are released once the circuit has stayed within its limits for the hold duration.
+// It is called frequently from a single goroutine and polls the meter at the protection interval.
+func (c *Circuit) Protect(loads []api.CircuitLoad) {
+	p := &c.protection
+	if p.interval == 0 || c.meter == nil || time.Since(p.updated) < p.interval {
+		return
+	}
+	p.updated = time.Now()
+
+	excessPower, excessCurrent, err := c.excess()
+	if err != nil {
+		c.log.DEBUG.Printf("protection: %v", err)
+		return
+	}
+
+	if excessPower <= 0 && excessCurrent <= 0 {
+		if len(p.curtailed) > 0 && time.Since(p.overloaded) >= p.hold {
+			c.release()
+		}
+		return
+	}
+
+	p.overloaded = time.Now()
+
+	// give loads time to follow the previous curtailment
+	if time.Since(p.curtailedAt) >= protectSettle {
+		c.curtail(loads, excessPower, excessCurrent)
+		p.curtailedAt = time.Now()
+	}
+}
+
+// excess returns the measured power and current exceeding the circuit limits
+func (c *Circuit) excess() (float64, float64, error) {
+	var excessPower, excessCurrent float64
+
+	if maxPower := c.effectiveMaxPower(); maxPower != 0 {
+		power, err := c.meter.CurrentPower()
+		if err != nil {
+			return 0, 0, err
+		}
+
+		excessPower = power - maxPower
+	}
+
+	if maxCurrent := c.GetMaxCurrent(); maxCurrent != 0 {
+		phaseMeter, ok := api.Cap[api.PhaseCurrents](c.meter)
+		if !ok {
+			return 0, 0, errors.New("meter does not support phase currents")
+		}
+
+		i1, i2, i3, err := phaseMeter.Currents()
+		if err != nil {
+			return 0, 0, err
+		}
+
+		excessCurrent = max(i1, i2, i3) - maxCurrent
+	}
+
+	return excessPower, excessCurrent, nil
+}
+
+// affects returns true if the load is attached to the circuit or one of its children
+func (c *Circuit) affects(load api.CircuitLoad) bool {
+	for lc := load.GetCircuit(); lc != nil; lc = lc.GetParent() {
+		if lc == c {
+			return true
+		}
+	}
+	return false
+}
+
+// curtail reduces the current of the circuit's loads, largest first, until the excess is covered
+func (c *Circuit) curtail(loads []api.CircuitLoad, excessPower, excessCurrent float64) {
+	p := &c.protection
+	if p.curtailed == nil {
+		p.curtailed = make(map[api.CircuitCurtailer]float64)
+	}
+
+	c.log.WARN.Printf("protection: overload detected (%.0fW, %.3gA excess)", max(0, excessPower), max(0, excessCurrent))
+
+	type candidate struct {
+		load           api.CircuitCurtailer
+		current, power float64
+	}
+
+	var candidates []candidate
+	for _, load := range loads {
+		cl, ok := load.(api.CircuitCurtailer)
+		if !ok || !c.affects(load) {
+			continue
+		}
+
+		current, power := load.GetMaxPhaseCurrent(), load.GetChargePower()
+		// measurements may still lag behind the curtailment
+		if limit, ok := p.curtailed[cl]; ok && current > limit {
+			power *= limit / current
+			current = limit
+		}
+
+		if current > 0 && power > 0 {
+			candidates = append(candidates, candidate{cl, current, power})
+		}
+	}
+
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		return cmp.Compare(b.current, a.current)
+	})
+
+	for _, cd := range candidates {
+		if excessPower <= 0 && excessCurrent <= 0 {
+			break
+		}
+
+		// power per ampere depends on the load's active phases
+		reduction := min(cd.current, max(excessCurrent, excessPower*cd.current/cd.power))
+		limit := cd.current - reduction
+
+		if err := cd.load.Curtail(c, limit); err != nil {
+			c.log.ERROR.Printf("protection: curtail: %v", err)
+			continue
+		}
+
+		p.curtailed[cd.load] = limit
+		excessCurrent -= reduction
+		excessPower -= reduction * cd.power / cd.current
+	}
+
+	if excessPower > 0 || excessCurrent > 0 {
+		c.log.WARN.Printf("protection: insufficient loads to curtail")
+	}
+}
+
+// release hands the curtailed loads back to the regular loop
+func (c *Circuit) release() {
+	p := &c.protection
+
+	c.log.INFO.Printf("protection: releasing %d loads", len(p.curtailed))
+
+	for load := range p.curtailed {
+		load.Release(c)
+	}
+
+	clear(p.curtailed)
+}
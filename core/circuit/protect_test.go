@@ -0,0 +1,99 @@
+package circuit
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type curtailLoad struct {
+	circuit        api.Circuit
+	current, power float64
+	limit          *float64
+}
+
+func (l *curtailLoad) GetChargePower() float64     { return l.power }
+func (l *curtailLoad) GetMaxPhaseCurrent() float64 { return l.current }
+func (l *curtailLoad) GetCircuit() api.Circuit     { return l.circuit }
+func (l *curtailLoad) Release(api.Circuit)         { l.limit = nil }
+
+func (l *curtailLoad) Curtail(_ api.Circuit, current float64) error {
+	l.limit = &current
+	return nil
+}
+
+func TestProtect(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	type combined struct {
+		*api.MockMeter
+		*api.MockPhaseCurrents
+	}
+	m := combined{
+		api.NewMockMeter(ctrl),
+		api.NewMockPhaseCurrents(ctrl),
+	}
+
+	c, err := New(util.NewLogger("foo"), "foo", 32, 0, m, 0)
+	require.NoError(t, err)
+
+	c.protection.interval = 1
+	c.protection.hold = 0
+
+	other, err := New(util.NewLogger("bar"), "bar", 0, 0, nil, 0)
+	require.NoError(t, err)
+
+	lp1 := &curtailLoad{circuit: c, current: 16, power: 11000}
+	lp2 := &curtailLoad{circuit: c, current: 10, power: 2300}
+	lp3 := &curtailLoad{circuit: other, current: 32, power: 22000}
+	loads := []api.CircuitLoad{lp1, lp2, lp3}
+
+	// within limits
+	m.MockPhaseCurrents.EXPECT().Currents().Return(30.0, 20.0, 20.0, nil)
+	c.Protect(loads)
+	assert.Nil(t, lp1.limit)
+
+	// overload curtails the largest load first
+	m.MockPhaseCurrents.EXPECT().Currents().Return(36.0, 20.0, 20.0, nil)
+	c.Protect(loads)
+	require.NotNil(t, lp1.limit)
+	assert.Equal(t, 12.0, *lp1.limit)
+	assert.Nil(t, lp2.limit)
+	assert.Nil(t, lp3.limit)
+
+	// further overload waits for loads to settle
+	m.MockPhaseCurrents.EXPECT().Currents().Return(36.0, 20.0, 20.0, nil)
+	c.Protect(loads)
+	assert.Equal(t, 12.0, *lp1.limit)
+
+	// release once stable
+	m.MockPhaseCurrents.EXPECT().Currents().Return(28.0, 20.0, 20.0, nil)
+	c.Protect(loads)
+	assert.Nil(t, lp1.limit)
+}
+
+func TestProtectMultipleLoads(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	m := api.NewMockMeter(ctrl)
+	c, err := New(util.NewLogger("foo"), "foo", 0, 10000, m, 0)
+	require.NoError(t, err)
+
+	c.protection.interval = 1
+
+	lp1 := &curtailLoad{circuit: c, current: 16, power: 11000}
+	lp2 := &curtailLoad{circuit: c, current: 15, power: 3450}
+
+	// excess exceeds the first load's power
+	m.EXPECT().CurrentPower().Return(23300.0, nil)
+	c.Protect([]api.CircuitLoad{lp1, lp2})
+
+	require.NotNil(t, lp1.limit)
+	require.NotNil(t, lp2.limit)
+	assert.Equal(t, 0.0, *lp1.limit)
+	assert.InDelta(t, 5.0, *lp2.limit, 1e-9)
+}
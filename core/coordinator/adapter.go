@@ -40,3 +40,7 @@ func (a *adapter) IdentifyVehicleByStatus() api.Vehicle {
 	status := a.lp.GetStatus()
 	return a.c.identifyVehicleByStatus(available, status)
 }
+
+func (a *adapter) Away(v api.Vehicle) bool {
+	return a.c.Away(v)
+}
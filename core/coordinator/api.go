@@ -21,4 +21,7 @@ type API interface {
 
 	// IdentifyVehicleByStatus returns an available vehicle that is currently connected or charging
 	IdentifyVehicleByStatus() api.Vehicle
+
+	// Away returns true if the vehicle reports a position outside the home geofence
+	Away(api.Vehicle) bool
 }
@@ -7,22 +7,26 @@ import (
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/core/loadpoint"
 	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/geo"
 )
 
 // Coordinator coordinates vehicle access between loadpoints
 type Coordinator struct {
-	mu       sync.RWMutex
-	log      *util.Logger
-	vehicles []api.Vehicle
-	tracked  map[api.Vehicle]loadpoint.API
+	mu        sync.RWMutex
+	log       *util.Logger
+	vehicles  []api.Vehicle
+	tracked   map[api.Vehicle]loadpoint.API
+	home      geo.Fence
+	positions map[api.Vehicle]position
 }
 
 // New creates a coordinator for a set of vehicles
 func New(log *util.Logger, vehicles []api.Vehicle) *Coordinator {
 	return &Coordinator{
-		log:      log,
-		vehicles: vehicles,
-		tracked:  make(map[api.Vehicle]loadpoint.API),
+		log:       log,
+		vehicles:  vehicles,
+		tracked:   make(map[api.Vehicle]loadpoint.API),
+		positions: make(map[api.Vehicle]position),
 	}
 }
 
@@ -76,6 +80,7 @@ func (c *Coordinator) Delete(vehicle api.Vehicle) {
 				}(o)
 			}
 			delete(c.tracked, vehicle)
+			delete(c.positions, vehicle)
 
 			break
 		}
@@ -85,6 +90,29 @@ func (c *Coordinator) Delete(vehicle api.Vehicle) {
 	c.mu.Unlock()
 }
 
+// SetHome sets the home geofence for position-aware vehicle detection
+func (c *Coordinator) SetHome(home geo.Fence) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.home = home
+}
+
+// disambiguate removes vehicles that are away from multiple candidates. The caller must hold the lock.
+func (c *Coordinator) disambiguate(candidates []api.Vehicle) []api.Vehicle {
+	if len(candidates) < 2 {
+		return candidates
+	}
+
+	return slices.DeleteFunc(candidates, func(v api.Vehicle) bool {
+		away := c.away(c.home, v)
+		if away {
+			c.log.DEBUG.Printf("vehicle position: away (%s)", v.GetTitle())
+		}
+		return away
+	})
+}
+
 func (c *Coordinator) acquire(owner loadpoint.API, vehicle api.Vehicle) {
 	c.mu.Lock()
 
@@ -128,10 +156,10 @@ func (c *Coordinator) availableDetectibleVehicles(owner loadpoint.API) []api.Veh
 	return res
 }
 
-// identifyVehicleByStatus finds active vehicle by charge state
+// identifyVehicleByStatus finds active vehicle by charge state.
+// Multiple candidates are disambiguated by excluding vehicles positioned away from home.
 func (c *Coordinator) identifyVehicleByStatus(available []api.Vehicle, lpStatus api.ChargeStatus) api.Vehicle {
-	var exactMatch api.Vehicle
-	var approximateMatches []api.Vehicle
+	var exactMatches, approximateMatches []api.Vehicle
 
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -151,12 +179,7 @@ func (c *Coordinator) identifyVehicleByStatus(available []api.Vehicle, lpStatus
 			// vehicle is plugged or charging and has the same state as the charger, so it should be the right one
 			if status == api.StatusB || status == api.StatusC {
 				if status == lpStatus {
-					if exactMatch != nil {
-						c.log.WARN.Println("vehicle status: >1 matches, giving up")
-						return nil
-					}
-
-					exactMatch = vehicle
+					exactMatches = append(exactMatches, vehicle)
 				} else {
 					// vehicle is plugged or charging, so it should be the right one if there is no exact match
 					approximateMatches = append(approximateMatches, vehicle)
@@ -165,13 +188,17 @@ func (c *Coordinator) identifyVehicleByStatus(available []api.Vehicle, lpStatus
 		}
 	}
 
-	if exactMatch != nil {
-		return exactMatch
+	matches := exactMatches
+	if len(matches) == 0 {
+		matches = approximateMatches
 	}
-	if len(approximateMatches) == 1 {
-		return approximateMatches[0]
+
+	matches = c.disambiguate(matches)
+
+	if len(matches) == 1 {
+		return matches[0]
 	}
-	if len(approximateMatches) > 1 {
+	if len(matches) > 1 {
 		c.log.WARN.Println("vehicle status: >1 matches, giving up")
 	}
 
@@ -6,6 +6,7 @@ import (
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/core/loadpoint"
 	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/geo"
 	"go.uber.org/mock/gomock"
 )
 
@@ -73,3 +74,57 @@ func TestVehicleDetectByStatus(t *testing.T) {
 		}
 	}
 }
+
+type vehiclePosition struct {
+	lat, lon float64
+}
+
+func (p vehiclePosition) Position() (float64, float64, error) {
+	return p.lat, p.lon, nil
+}
+
+func TestVehicleDetectByPosition(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	type vehicle struct {
+		*api.MockVehicle
+		*api.MockChargeState
+		vehiclePosition
+	}
+
+	home := geo.Fence{Latitude: 52.5163, Longitude: 13.3777}
+
+	v1 := &vehicle{api.NewMockVehicle(ctrl), api.NewMockChargeState(ctrl), vehiclePosition{home.Latitude, home.Longitude}}
+	v2 := &vehicle{api.NewMockVehicle(ctrl), api.NewMockChargeState(ctrl), vehiclePosition{53.5503, 9.9923}}
+
+	for _, v := range []*vehicle{v1, v2} {
+		v.MockVehicle.EXPECT().GetTitle().Return("").AnyTimes()
+		v.MockVehicle.EXPECT().Features().Return(nil).AnyTimes()
+		v.MockChargeState.EXPECT().Status().Return(api.StatusB, nil).AnyTimes()
+	}
+
+	var lp loadpoint.API
+	c := New(util.NewLogger("foo"), []api.Vehicle{v1, v2})
+
+	// ambiguous without home
+	if res := c.identifyVehicleByStatus(c.availableDetectibleVehicles(lp), api.StatusB); res != nil {
+		t.Errorf("expected nil, got %v", res)
+	}
+
+	c.SetHome(home)
+
+	// positions are not queried synchronously
+	if c.Away(v2) {
+		t.Error("unexpected away status without cached position")
+	}
+
+	c.UpdatePositions()
+
+	if c.Away(v1) || !c.Away(v2) {
+		t.Error("unexpected away status")
+	}
+
+	if res := c.identifyVehicleByStatus(c.availableDetectibleVehicles(lp), api.StatusB); res != v1 {
+		t.Errorf("expected %v, got %v", v1, res)
+	}
+}
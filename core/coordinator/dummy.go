@@ -27,3 +27,7 @@ func (a *dummy) Release(api.Vehicle) {}
 func (a *dummy) IdentifyVehicleByStatus() api.Vehicle {
 	return nil
 }
+
+func (a *dummy) Away(api.Vehicle) bool {
+	return false
+}
@@ -0,0 +1,97 @@
+package coordinator
+
+import (
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/util/geo"
+)
+
+// positionTimeout is the maximum age of a cached vehicle position
+const positionTimeout = 15 * time.Minute
+
+type position struct {
+	lat, lon float64
+	updated  time.Time
+}
+
+// RunPositions refreshes the cached vehicle positions until stopC is closed.
+// Positions are only queried while a home location is configured.
+func (c *Coordinator) RunPositions(stopC <-chan struct{}, interval time.Duration) {
+	c.UpdatePositions()
+
+	for tick := time.Tick(interval); ; {
+		select {
+		case <-tick:
+			c.UpdatePositions()
+		case <-stopC:
+			return
+		}
+	}
+}
+
+// UpdatePositions queries the positions of all vehicles outside the coordinator's lock
+func (c *Coordinator) UpdatePositions() {
+	c.mu.RLock()
+	vehicles := c.vehicles
+	configured := c.home.Configured()
+	c.mu.RUnlock()
+
+	if !configured {
+		return
+	}
+
+	for _, v := range vehicles {
+		vp, ok := api.Cap[api.VehiclePosition](v)
+		if !ok {
+			continue
+		}
+
+		lat, lon, err := vp.Position()
+		if err != nil {
+			if !loadpoint.AcceptableError(err) {
+				c.log.ERROR.Printf("vehicle position: %v (%s)", err, v.GetTitle())
+			}
+			continue
+		}
+
+		c.mu.Lock()
+		c.positions[v] = position{lat: lat, lon: lon, updated: time.Now()}
+		c.mu.Unlock()
+	}
+}
+
+// Position returns the cached vehicle position
+func (c *Coordinator) Position(vehicle api.Vehicle) (float64, float64, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	p, ok := c.position(vehicle)
+	return p.lat, p.lon, ok
+}
+
+// position returns the cached vehicle position unless outdated. The caller must hold the lock.
+func (c *Coordinator) position(vehicle api.Vehicle) (position, bool) {
+	p, ok := c.positions[vehicle]
+	return p, ok && time.Since(p.updated) < positionTimeout
+}
+
+// Away returns true if the vehicle's cached position is outside the home geofence.
+// Vehicles without position or without configured home are never away.
+func (c *Coordinator) Away(vehicle api.Vehicle) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.away(c.home, vehicle)
+}
+
+// away evaluates the cached vehicle position. The caller must hold the lock.
+func (c *Coordinator) away(home geo.Fence, vehicle api.Vehicle) bool {
+	p, ok := c.position(vehicle)
+	if !ok || !home.Configured() {
+		return false
+	}
+
+	return !home.Contains(p.lat, p.lon)
+}
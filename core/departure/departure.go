@@ -0,0 +1,107 @@
+// Package departure predicts vehicle departures from session history.
+package departure
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	// Weeks is the session history used for prediction
+	Weeks = 8
+
+	// Tolerance is the maximum deviation of a departure from the typical departure time
+	Tolerance = 30 * time.Minute
+
+	minSamples = 3 // minimum departures per weekday
+)
+
+// Prediction is a predicted departure
+type Prediction struct {
+	Time       time.Time
+	Confidence float64 // share of weekdays with departure close to the predicted time
+}
+
+// firstDepartures returns the earliest departure per day in [since, until) in minutes since midnight, by weekday
+func firstDepartures(departures []time.Time, since, until time.Time, loc *time.Location) map[time.Weekday][]int {
+	first := make(map[time.Time]time.Time)
+
+	for _, d := range departures {
+		d = d.In(loc)
+		if d.Before(since) || !d.Before(until) {
+			continue
+		}
+
+		date := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, loc)
+		if f, ok := first[date]; !ok || d.Before(f) {
+			first[date] = d
+		}
+	}
+
+	res := make(map[time.Weekday][]int)
+	for date, d := range first {
+		res[date.Weekday()] = append(res[date.Weekday()], d.Hour()*60+d.Minute())
+	}
+
+	return res
+}
+
+// occurrences counts the days with given weekday in [from, to)
+func occurrences(wd time.Weekday, from, to time.Time) int {
+	var res int
+	for d := from; d.Before(to); d = d.AddDate(0, 0, 1) {
+		if d.Weekday() == wd {
+			res++
+		}
+	}
+	return res
+}
+
+// Predict returns the next departure after now with at least the given confidence.
+// The typical departure time per weekday is the median of the first departure of each day.
+func Predict(departures []time.Time, now time.Time, confidence float64) (Prediction, bool) {
+	if len(departures) == 0 {
+		return Prediction{}, false
+	}
+
+	loc := now.Location()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	// observed history starts with the first departure
+	since := today.AddDate(0, 0, -7*Weeks)
+	if first := slices.MinFunc(departures, func(a, b time.Time) int { return a.Compare(b) }).In(loc); first.After(since) {
+		since = time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, loc)
+	}
+
+	byWeekday := firstDepartures(departures, since, today, loc)
+
+	for day := range 8 {
+		date := today.AddDate(0, 0, day)
+
+		minutes := byWeekday[date.Weekday()]
+		if len(minutes) < minSamples {
+			continue
+		}
+
+		slices.Sort(minutes)
+		median := minutes[len(minutes)/2]
+
+		var within int
+		for _, m := range minutes {
+			if time.Duration(max(m-median, median-m))*time.Minute <= Tolerance {
+				within++
+			}
+		}
+
+		res := Prediction{
+			Time:       time.Date(date.Year(), date.Month(), date.Day(), median/60, median%60, 0, 0, loc),
+			Confidence: min(1, float64(within)/float64(occurrences(date.Weekday(), since, today))),
+		}
+
+		if res.Time.After(now) && res.Confidence >= confidence {
+			return res, true
+		}
+	}
+
+	return Prediction{}, false
+}
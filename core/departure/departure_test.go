@@ -0,0 +1,137 @@
+package departure
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/session"
+	"github.com/evcc-io/evcc/core/vehicle"
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/server/db/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPredict(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	// Wednesday evening
+	now := time.Date(2026, 10, 21, 20, 0, 0, 0, loc)
+
+	var departures []time.Time
+	for week := 1; week <= Weeks; week++ {
+		// weekday commute around 7:15
+		for day := range 5 {
+			monday := now.AddDate(0, 0, -2-7*week)
+			d := time.Date(monday.Year(), monday.Month(), monday.Day()+day, 7, 10+week%3*5, 0, 0, loc)
+			departures = append(departures, d, d.Add(10*time.Hour))
+		}
+	}
+
+	// thursday morning
+	res, ok := Predict(departures, now, 0.7)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2026, 10, 22, 7, 15, 0, 0, loc), res.Time)
+	assert.Equal(t, 1.0, res.Confidence)
+
+	// irregular thursdays
+	var irregular []time.Time
+	for _, d := range departures {
+		if d.Weekday() == time.Thursday && d.Hour() < 12 && d.Day()%2 == 0 {
+			d = d.Add(3 * time.Hour)
+		}
+		irregular = append(irregular, d)
+	}
+
+	res, ok = Predict(irregular, now, 0.7)
+	require.True(t, ok)
+	assert.Equal(t, time.Friday, res.Time.Weekday())
+
+	// not enough history
+	_, ok = Predict(departures[len(departures)-10:], now, 0.7)
+	assert.False(t, ok)
+
+	_, ok = Predict(nil, now, 0)
+	assert.False(t, ok)
+}
+
+type vehicles []vehicle.API
+
+func (vv vehicles) Settings() []vehicle.API {
+	return vv
+}
+
+func TestPlanner(t *testing.T) {
+	require.NoError(t, db.NewInstance("sqlite", ":memory:"))
+	require.NoError(t, db.Instance.AutoMigrate(new(session.Session)))
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	// daily departure at 7:00
+	for day := 1; day <= 7*Weeks; day++ {
+		d := today.AddDate(0, 0, -day).Add(7 * time.Hour)
+		require.NoError(t, db.Instance.Create(&session.Session{
+			Vehicle:      "Car",
+			Created:      d.Add(-10 * time.Hour),
+			Finished:     d.Add(-5 * time.Hour),
+			Disconnected: d,
+		}).Error)
+	}
+
+	ctrl := gomock.NewController(t)
+
+	instance := api.NewMockVehicle(ctrl)
+	instance.EXPECT().GetTitle().Return("Car").AnyTimes()
+
+	var (
+		planTime  time.Time
+		planSoc   int
+		predicted bool
+	)
+
+	v := vehicle.NewMockAPI(ctrl)
+	v.EXPECT().Name().Return("ev").AnyTimes()
+	v.EXPECT().Instance().Return(instance).AnyTimes()
+	v.EXPECT().GetTripCalendar().Return(nil).AnyTimes()
+	v.EXPECT().GetDeparturePrediction().Return(&api.DeparturePrediction{Soc: 80}).AnyTimes()
+	v.EXPECT().GetPlanSoc().DoAndReturn(func() (time.Time, int) { return planTime, planSoc }).AnyTimes()
+	v.EXPECT().GetPlanPredicted().DoAndReturn(func() bool { return predicted }).AnyTimes()
+	v.EXPECT().SetPredictedPlanSoc(gomock.Any(), gomock.Any()).DoAndReturn(func(ts time.Time, soc int) error {
+		planTime, planSoc, predicted = ts, soc, soc > 0
+		return nil
+	}).AnyTimes()
+
+	p := New(vehicles{v})
+
+	p.Update()
+	assert.Equal(t, 7, planTime.Hour())
+	assert.True(t, planTime.After(now))
+	assert.Equal(t, 80, planSoc)
+	assert.True(t, predicted)
+
+	// cleared plan is not recreated
+	planTime, planSoc, predicted = time.Time{}, 0, false
+	p.Update()
+	assert.True(t, planTime.IsZero())
+
+	// manual plan is kept, even if identical to the predicted one
+	p = New(vehicles{v})
+	settings.SetString(planKey(v), "")
+	p.Update()
+	require.True(t, predicted)
+
+	predicted = false
+	manual := planTime
+	p.Update()
+	assert.True(t, manual.Equal(planTime))
+	assert.False(t, predicted)
+
+	planTime, planSoc = manual.Add(time.Hour), 90
+	p.Update()
+	assert.True(t, manual.Add(time.Hour).Equal(planTime))
+	assert.Equal(t, 90, planSoc)
+}
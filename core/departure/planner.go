@@ -0,0 +1,135 @@
+package departure
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/core/session"
+	"github.com/evcc-io/evcc/core/vehicle"
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/server/db/settings"
+	"github.com/evcc-io/evcc/util"
+)
+
+// DefaultConfidence is the minimum prediction confidence if not configured
+const DefaultConfidence = 0.7
+
+// Vehicles provides the vehicle settings
+type Vehicles interface {
+	Settings() []vehicle.API
+}
+
+// Planner creates soft plans for predicted departures of vehicles with enabled prediction.
+// Soft plans are flagged as predicted. They never replace manual or calendar plans, follow the prediction
+// until departure and are not recreated for the same day once cleared.
+type Planner struct {
+	log      *util.Logger
+	vehicles Vehicles
+}
+
+// plan is the charge plan created from a prediction
+type plan struct {
+	Time       time.Time `json:"time"`
+	Soc        int       `json:"soc"`
+	Confidence float64   `json:"confidence"`
+}
+
+// New creates a departure planner
+func New(vehicles Vehicles) *Planner {
+	return &Planner{
+		log:      util.NewLogger("departure"),
+		vehicles: vehicles,
+	}
+}
+
+// Run updates the plans at the given interval until stopped
+func (p *Planner) Run(stopC <-chan struct{}, interval time.Duration) {
+	p.Update()
+
+	for tick := time.Tick(interval); ; {
+		select {
+		case <-tick:
+			p.Update()
+		case <-stopC:
+			return
+		}
+	}
+}
+
+// Update updates the plans of all vehicles with enabled prediction
+func (p *Planner) Update() {
+	if db.Instance == nil {
+		return
+	}
+
+	for _, v := range p.vehicles.Settings() {
+		// calendar trips take precedence
+		if pp := v.GetDeparturePrediction(); pp != nil && v.GetTripCalendar() == nil {
+			if err := p.update(v, pp.Soc, pp.Confidence); err != nil {
+				p.log.ERROR.Printf("%s: %v", v.Name(), err)
+			}
+		}
+	}
+}
+
+func planKey(v vehicle.API) string {
+	return fmt.Sprintf("vehicle.%s.%s", v.Name(), keys.PredictedPlan)
+}
+
+// update sets the plan for the predicted departure
+func (p *Planner) update(v vehicle.API, soc int, confidence float64) error {
+	var prev plan
+	_ = settings.Json(planKey(v), &prev)
+
+	ts, planSoc := v.GetPlanSoc()
+	if !ts.IsZero() && !v.GetPlanPredicted() {
+		p.log.DEBUG.Printf("%s: keeping existing plan", v.Name())
+		return nil
+	}
+
+	if confidence == 0 {
+		confidence = DefaultConfidence
+	}
+
+	now := time.Now()
+
+	// sessions refer to vehicles by title
+	departures, err := session.Departures(db.Instance, v.Instance().GetTitle(), now.AddDate(0, 0, -7*Weeks))
+	if err != nil {
+		return err
+	}
+
+	res, ok := Predict(departures, now, confidence)
+	if !ok {
+		if !ts.IsZero() {
+			p.log.INFO.Printf("%s: remove plan, no departure predicted", v.Name())
+			settings.SetString(planKey(v), "")
+			return v.SetPredictedPlanSoc(time.Time{}, 0)
+		}
+		return nil
+	}
+
+	if ts.Equal(res.Time) && planSoc == soc {
+		return nil
+	}
+
+	// cleared plans stay cleared for the predicted day
+	if ts.IsZero() && sameDay(prev.Time, res.Time) {
+		return nil
+	}
+
+	p.log.INFO.Printf("%s: plan %d%% @ %v for predicted departure (confidence %.0f%%)", v.Name(), soc, res.Time.Local(), 100*res.Confidence)
+
+	if err := v.SetPredictedPlanSoc(res.Time, soc); err != nil {
+		return err
+	}
+
+	return settings.SetJson(planKey(v), plan{Time: res.Time, Soc: soc, Confidence: res.Confidence})
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Local().Date()
+	by, bm, bd := b.Local().Date()
+	return ay == by && am == bm && ad == bd
+}
@@ -1,14 +1,21 @@
 package core
 
+import (
+	"time"
+
+	"github.com/evcc-io/evcc/core/session"
+)
+
 // EnergyMetrics calculates stats about the charged energy and gives you details about price or co2s
 type EnergyMetrics struct {
-	totalKWh          float64  // Total amount of energy used (kWh)
-	solarKWh          float64  // Self-produced energy (kWh)
-	price             *float64 // Total cost (Currency)
-	co2               *float64 // Amount of emitted CO2 (gCO2eq)
-	currentGreenShare float64  // Current share of solar energy of site (0-1)
-	currentPrice      *float64 // Current price per kWh
-	currentCo2        *float64 // Current co2 emissions
+	totalKWh          float64         // Total amount of energy used (kWh)
+	solarKWh          float64         // Self-produced energy (kWh)
+	price             *float64        // Total cost (Currency)
+	co2               *float64        // Amount of emitted CO2 (gCO2eq)
+	currentGreenShare float64         // Current share of solar energy of site (0-1)
+	currentPrice      *float64        // Current price per kWh
+	currentCo2        *float64        // Current co2 emissions
+	tariffs           session.Tariffs // Charged energy by applied price
 }
 
 // SetEnvironment updates site information like solar share, price, co2 for use in later calculations
@@ -37,6 +44,7 @@ func (em *EnergyMetrics) Update(chargedKWh float64) (float64, float64) {
 			newPrice = *em.price + newPrice
 		}
 		em.price = &newPrice
+		em.tariffs = em.tariffs.Add(time.Now(), *em.currentPrice, added)
 	}
 	if em.currentCo2 != nil {
 		addedCo2 := *em.currentCo2 * added
@@ -55,6 +63,7 @@ func (em *EnergyMetrics) Reset() {
 	em.solarKWh = 0
 	em.price = nil
 	em.co2 = nil
+	em.tariffs = nil
 }
 
 // TotalWh returns the total energy in Wh
@@ -87,6 +96,11 @@ func (em *EnergyMetrics) PricePerKWh() *float64 {
 	return &price
 }
 
+// Tariffs returns the charged energy by applied price
+func (em *EnergyMetrics) Tariffs() session.Tariffs {
+	return em.tariffs
+}
+
 // Co2PerKWh returns the average co2 emissions per kWh
 func (em *EnergyMetrics) Co2PerKWh() *float64 {
 	if em.totalKWh == 0 || em.co2 == nil {
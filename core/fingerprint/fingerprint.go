@@ -0,0 +1,208 @@
+// Package fingerprint identifies vehicles by their charging behavior.
+package fingerprint
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/benbjohnson/clock"
+)
+
+const (
+	// RampUpWindow is the time after charging starts until the fingerprint is complete
+	RampUpWindow = 3 * time.Minute
+
+	// MinSimilarity is the minimum similarity of a vehicle's learned fingerprints to be a candidate
+	MinSimilarity = 0.75
+
+	minActiveCurrent  = 1.0 // A, minimum current at which a phase is treated as active
+	maxStandbyPower   = 500 // W, maximum power treated as standby consumption
+	minStandbySamples = 3   // standby measurements required for a partial fingerprint
+	limitedCurrent    = 1.0 // A, distance to the offered current at which the vehicle is treated as limited by the charger
+)
+
+// Fingerprint describes the charging behavior of a vehicle
+type Fingerprint struct {
+	Phases       int           // active phases while charging, 0 if unknown
+	MaxCurrent   float64       // A, maximum phase current, 0 if unknown or limited by the offered current
+	RampUp       time.Duration // time to reach 90% of the maximum current, 0 if unknown
+	StandbyPower float64       // W, power drawn while connected and not charging
+}
+
+// Recorder collects the fingerprint of a charging session
+type Recorder struct {
+	clock clock.Clock
+
+	started  time.Time // charging start
+	samples  []sample  // currents during ramp-up window
+	phases   int
+	standby  float64
+	nStandby int
+}
+
+type sample struct {
+	ts      time.Time
+	current float64
+	offered float64
+}
+
+// NewRecorder creates a fingerprint recorder
+func NewRecorder(clock clock.Clock) *Recorder {
+	return &Recorder{clock: clock}
+}
+
+// Reset clears all measurements
+func (r *Recorder) Reset() {
+	*r = Recorder{clock: r.clock}
+}
+
+// Update adds a measurement. Currents are nil if not available, offered is the current offered by the charger.
+func (r *Recorder) Update(charging bool, power float64, currents []float64, offered float64) {
+	if !charging {
+		// standby consumption before charging starts
+		if r.started.IsZero() && power >= 0 && power <= maxStandbyPower {
+			r.standby += power
+			r.nStandby++
+		}
+		return
+	}
+
+	now := r.clock.Now()
+	if r.started.IsZero() {
+		r.started = now
+	}
+
+	if currents == nil || now.Sub(r.started) > RampUpWindow {
+		return
+	}
+
+	var phases int
+	for _, i := range currents {
+		if i > minActiveCurrent {
+			phases++
+		}
+	}
+	r.phases = max(r.phases, phases)
+
+	r.samples = append(r.samples, sample{ts: now, current: slices.Max(currents), offered: offered})
+}
+
+// Partial returns the fingerprint known before the ramp-up window has elapsed, i.e. standby
+// power and active phases. It is available once standby power has been measured.
+func (r *Recorder) Partial() (Fingerprint, bool) {
+	if r.nStandby < minStandbySamples {
+		return Fingerprint{}, false
+	}
+
+	return Fingerprint{
+		Phases:       r.phases,
+		StandbyPower: r.standby / float64(r.nStandby),
+	}, true
+}
+
+// Fingerprint returns the fingerprint once the ramp-up window has elapsed
+func (r *Recorder) Fingerprint() (Fingerprint, bool) {
+	if r.started.IsZero() || r.clock.Since(r.started) < RampUpWindow {
+		return Fingerprint{}, false
+	}
+
+	res := Fingerprint{
+		Phases: r.phases,
+	}
+
+	if r.nStandby > 0 {
+		res.StandbyPower = r.standby / float64(r.nStandby)
+	}
+
+	var limited bool
+	for _, s := range r.samples {
+		res.MaxCurrent = max(res.MaxCurrent, s.current)
+		limited = limited || s.offered > 0 && s.current >= s.offered-limitedCurrent
+	}
+
+	for _, s := range r.samples {
+		if res.MaxCurrent > 0 && s.current >= 0.9*res.MaxCurrent {
+			res.RampUp = s.ts.Sub(r.started)
+			break
+		}
+	}
+
+	// current limited by the charger does not describe the vehicle
+	if limited {
+		res.MaxCurrent = 0
+	}
+
+	return res, true
+}
+
+// Candidate is a vehicle matching a fingerprint
+type Candidate struct {
+	Vehicle    string  `json:"vehicle"`
+	Confidence float64 `json:"confidence"` // 0..1
+}
+
+// similarity returns a value between 0 and 1 for the difference of two measurements given a tolerance
+func similarity(a, b, tolerance float64) float64 {
+	d := (a - b) / tolerance
+	return math.Exp(-d * d)
+}
+
+// Similarity compares two fingerprints, ignoring unknown values. Returns a value between 0 and 1.
+func Similarity(a, b Fingerprint) float64 {
+	var sum, weights float64
+
+	add := func(weight, s float64) {
+		sum += weight * s
+		weights += weight
+	}
+
+	if a.Phases > 0 && b.Phases > 0 {
+		add(3, map[bool]float64{false: 0, true: 1}[a.Phases == b.Phases])
+	}
+	if a.MaxCurrent > 0 && b.MaxCurrent > 0 {
+		add(2, similarity(a.MaxCurrent, b.MaxCurrent, 2))
+	}
+	if a.RampUp > 0 && b.RampUp > 0 {
+		add(1, similarity(a.RampUp.Seconds(), b.RampUp.Seconds(), 20))
+	}
+	add(1, similarity(a.StandbyPower, b.StandbyPower, 20))
+
+	return sum / weights
+}
+
+// Match rates the learned fingerprints of each vehicle against the fingerprint.
+// The confidence is reduced if other vehicles are similar. Candidates are sorted by decreasing confidence.
+// Vehicles below MinSimilarity are no candidates, an empty result denotes an unknown vehicle.
+func Match(fp Fingerprint, learned map[string][]Fingerprint) []Candidate {
+	scores := make(map[string]float64, len(learned))
+
+	var total float64
+	for vehicle, fps := range learned {
+		if len(fps) == 0 {
+			continue
+		}
+
+		var sum float64
+		for _, l := range fps {
+			sum += Similarity(fp, l)
+		}
+
+		scores[vehicle] = sum / float64(len(fps))
+		total += scores[vehicle]
+	}
+
+	res := make([]Candidate, 0, len(scores))
+	for vehicle, score := range scores {
+		if score >= MinSimilarity {
+			res = append(res, Candidate{Vehicle: vehicle, Confidence: score * score / total})
+		}
+	}
+
+	slices.SortFunc(res, func(a, b Candidate) int {
+		return cmp.Or(cmp.Compare(b.Confidence, a.Confidence), cmp.Compare(a.Vehicle, b.Vehicle))
+	})
+
+	return res
+}
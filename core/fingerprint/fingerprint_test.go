@@ -0,0 +1,98 @@
+package fingerprint
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	clock := clock.NewMock()
+	r := NewRecorder(clock)
+
+	// standby
+	r.Update(false, 20, nil, 0)
+	r.Update(false, 30, nil, 0)
+
+	_, ok := r.Fingerprint()
+	assert.False(t, ok)
+
+	// partial fingerprint requires standby measurements
+	_, ok = r.Partial()
+	assert.False(t, ok)
+
+	r.Update(false, 25, nil, 0)
+
+	partial, ok := r.Partial()
+	require.True(t, ok)
+	assert.Equal(t, Fingerprint{StandbyPower: 25}, partial)
+
+	// ramp-up
+	for _, i := range []float64{4, 8, 12, 15, 16, 16, 16} {
+		r.Update(true, 3*230*i, []float64{i, i, 0}, 32)
+		clock.Add(30 * time.Second)
+	}
+
+	partial, ok = r.Partial()
+	require.True(t, ok)
+	assert.Equal(t, 2, partial.Phases)
+
+	fp, ok := r.Fingerprint()
+	require.True(t, ok)
+	assert.Equal(t, Fingerprint{
+		Phases:       2,
+		MaxCurrent:   16,
+		RampUp:       90 * time.Second,
+		StandbyPower: 25,
+	}, fp)
+
+	r.Reset()
+	_, ok = r.Fingerprint()
+	assert.False(t, ok)
+}
+
+func TestRecorderLimited(t *testing.T) {
+	clock := clock.NewMock()
+	r := NewRecorder(clock)
+
+	// vehicle draws the offered current
+	for _, i := range []float64{4, 8, 10, 10} {
+		r.Update(true, 3*230*i, []float64{i, i, i}, 10)
+		clock.Add(time.Minute)
+	}
+
+	fp, ok := r.Fingerprint()
+	require.True(t, ok)
+	assert.Equal(t, 3, fp.Phases)
+	assert.Zero(t, fp.MaxCurrent)
+}
+
+func TestMatch(t *testing.T) {
+	zoe := Fingerprint{Phases: 3, MaxCurrent: 32, RampUp: 30 * time.Second, StandbyPower: 0}
+	egolf := Fingerprint{Phases: 2, MaxCurrent: 16, RampUp: 90 * time.Second, StandbyPower: 25}
+
+	learned := map[string][]Fingerprint{
+		"zoe":   {zoe, zoe},
+		"egolf": {egolf},
+	}
+
+	res := Match(Fingerprint{Phases: 2, MaxCurrent: 15.8, RampUp: 80 * time.Second, StandbyPower: 22}, learned)
+	require.Len(t, res, 1)
+	assert.Equal(t, "egolf", res[0].Vehicle)
+	assert.Greater(t, res[0].Confidence, 0.9)
+
+	// ambiguous vehicles
+	learned["egolf2"] = []Fingerprint{egolf}
+	res = Match(egolf, learned)
+	assert.InDelta(t, res[0].Confidence, res[1].Confidence, 1e-6)
+	assert.Less(t, res[0].Confidence, 0.6)
+
+	assert.Empty(t, Match(egolf, nil))
+
+	// unknown vehicle does not match a single learned vehicle
+	guest := Fingerprint{Phases: 1, MaxCurrent: 20, RampUp: 10 * time.Second, StandbyPower: 5}
+	assert.Empty(t, Match(guest, map[string][]Fingerprint{"egolf": {egolf}}))
+}
@@ -19,7 +19,8 @@ const (
 	LimitEnergy       = "limitEnergy"      // limit energy
 	Soc               = "soc"
 	Thresholds        = "thresholds"
-	UI                = "ui" // display-only ui settings (json)
+	UI                = "ui"       // display-only ui settings (json)
+	Watchdog          = "watchdog" // charger watchdog (json)
 	EnableThreshold   = "enableThreshold"
 	DisableThreshold  = "disableThreshold"
 	EnableDelay       = "enableDelay"
@@ -35,12 +36,14 @@ const (
 	ChargerSinglePhase  = "chargerSinglePhase"  // api.PhaseDescriber: charger physical phases, sockets only
 	ChargerPhases1p3p   = "chargerPhases1p3p"   // api.PhaseSwitcher: 1p3p chargers
 	ChargerStatusReason = "chargerStatusReason" // either awaiting authorization or disconnect required
+	ChargerFallback     = "chargerFallback"     // api.Watchdog: charger has fallen back to fallback current
 
 	// loadpoint status
 	Enabled   = "enabled"   // loadpoint enabled
 	Connected = "connected" // connected
 	Charging  = "charging"  // charging
 	Dimmed    = "dimmed"    // dimmed pseudo-status
+	Shed      = "shed"      // switched off by load shedding
 
 	// loadpoint setpoint
 	OfferedCurrent = "offeredCurrent" // offered current
@@ -96,8 +99,17 @@ const (
 	PlanOverrun        = "planOverrun"        // charge plan goal not reachable in time
 	PlanStrategy       = "planStrategy"       // charge plan strategy (precondition, continuous)
 
+	// schedules
+	Schedules      = "schedules"      // time window schedules
+	ScheduleWindow = "scheduleWindow" // last applied schedule window
+
 	// repeating plans
 	RepeatingPlans = "repeatingPlans" // key to access all repeating plans in db
+	TripCalendar   = "tripCalendar"   // key to access the trip calendar in db
+	CalendarPlan   = "calendarPlan"   // key to access the plan created from the trip calendar in db
+	Prediction     = "prediction"     // key to access the departure prediction in db
+	PredictedPlan  = "predictedPlan"  // key to access the plan created from the departure prediction in db
+	PlanPredicted  = "planPredicted"  // key to access the flag marking the current plan as predicted in db
 
 	// remote control
 	RemoteDisabled       = "remoteDisabled"       // remote disabled
@@ -107,6 +119,7 @@ const (
 	VehicleName            = "vehicleName"            // vehicle name
 	VehicleTitle           = "vehicleTitle"           // vehicle title
 	VehicleIdentity        = "vehicleIdentity"        // vehicle identity
+	VehicleCandidate       = "vehicleCandidate"       // vehicle proposed by charging fingerprint
 	VehicleDetectionActive = "vehicleDetectionActive" // vehicle detection active
 	VehicleOdometer        = "vehicleOdometer"        // vehicle odometer
 	VehicleRange           = "vehicleRange"           // vehicle range
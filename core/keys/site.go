@@ -10,6 +10,7 @@ const (
 	GreenShareHome        = "greenShareHome"
 	GreenShareLoadpoints  = "greenShareLoadpoints"
 	GridConfigured        = "gridConfigured"
+	Home                  = "home"
 	Grid                  = "grid"
 	HistoryUpdated        = "historyUpdated"
 	HomePower             = "homePower"
@@ -52,6 +53,15 @@ const (
 	// grid settings
 	GridExportLimit = "gridExportLimit"
 
+	// hems
+	CurtailPercent = "curtailPercent"
+
+	// backup mode
+	GridOutage = "gridOutage"
+
+	// load shedding
+	LoadShedding = "loadShedding"
+
 	// forecast settings
 	SolarAdjusted = "solarAdjusted"
 
@@ -69,4 +79,8 @@ const (
 	// smart charging
 	SmartCostAvailable           = "smartCostAvailable"           // smart cost available
 	SmartFeedInPriorityAvailable = "smartFeedInPriorityAvailable" // smart feed-in priority available
+
+	// reimbursement
+	ReimbursementKey       = "reimbursementKey"       // reimbursement signing key seed
+	ReimbursementPublicKey = "reimbursementPublicKey" // reimbursement public key, detects a lost signing key
 )
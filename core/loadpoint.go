@@ -16,6 +16,7 @@ import (
 	"github.com/cenkalti/backoff/v4"
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/core/coordinator"
+	"github.com/evcc-io/evcc/core/fingerprint"
 	"github.com/evcc-io/evcc/core/keys"
 	"github.com/evcc-io/evcc/core/loadpoint"
 	"github.com/evcc-io/evcc/core/metrics"
@@ -91,6 +92,7 @@ type Loadpoint struct {
 	rwMutex      atomic.Int64 // count reentrant RWMutex
 	sync.RWMutex              // guard status
 	vmu          sync.RWMutex // guard vehicle
+	reload       atomic.Bool  // devices need to be re-resolved from their refs
 
 	// exposed public configuration
 	CircuitRef string `mapstructure:"circuit"` // Circuit reference
@@ -99,6 +101,7 @@ type Loadpoint struct {
 	MeterRef   string `mapstructure:"meter"`   // Charge meter reference
 
 	Soc             loadpoint.SocConfig
+	Watchdog        loadpoint.WatchdogConfig
 	Enable, Disable loadpoint.ThresholdConfig
 	Ui              loadpoint.UIConfig // display-only, not used in control logic
 
@@ -128,6 +131,7 @@ type Loadpoint struct {
 
 	mode                api.ChargeMode
 	enabled             bool      // Charger enabled state
+	shed                bool      // Switched off by site load shedding, guarded by mutex
 	phases              int       // Charger enabled phases, guarded by mutex
 	measuredPhases      int       // Charger physically measured phases
 	offeredCurrent      float64   // Charger current limit
@@ -138,18 +142,38 @@ type Loadpoint struct {
 	vehicleDetectTicker *clock.Ticker
 	vehicleIdentifier   string
 
+	fingerprint        *fingerprint.Recorder    // charging fingerprint of the connected vehicle
+	fingerprinted      bool                     // fingerprint completed for the current connection
+	fingerprintPartial *fingerprint.Fingerprint // partial fingerprint matched for the current connection
+	vehicleCandidate   *fingerprint.Candidate   // vehicle proposed by charging fingerprint
+
 	charger          api.Charger
 	chargeTimer      api.ChargeTimer
 	chargeRater      api.ChargeRater
 	chargedAtStartup float64 // session energy at startup
 
-	circuit        api.Circuit        // Circuit
-	chargeMeter    api.Meter          // Charger usage meter
-	chargeEnergy   *metrics.Collector // Charger usage collector
-	vehicle        api.Vehicle        // Currently active vehicle
-	defaultVehicle api.Vehicle        // Default vehicle (disables detection)
-	coordinator    coordinator.API
-	socEstimator   *soc.Estimator
+	circuit         api.Circuit        // Circuit
+	chargeMeter     api.Meter          // Charger usage meter
+	configuredMeter api.Meter          // Charge meter resolved from MeterRef, nil if integrated
+	chargeEnergy    *metrics.Collector // Charger usage collector
+	vehicle         api.Vehicle        // Currently active vehicle
+	defaultVehicle  api.Vehicle        // Default vehicle (disables detection)
+	coordinator     coordinator.API
+	socEstimator    *soc.Estimator
+
+	// site backup mode
+	backupPower *float64 // power budget during grid outage, guarded by mutex
+
+	// circuit overload protection
+	curtailMu   sync.Mutex              // serializes charger updates of concurrent circuits
+	curtailed   map[api.Circuit]float64 // current limits imposed by circuit overload protection, guarded by mutex
+	curtailSync atomic.Bool             // charger has been set by circuit overload protection
+	curtailOff  atomic.Bool             // charger has been disabled by circuit overload protection
+
+	// charger watchdog
+	watchdogUpdated  time.Time     // last successful watchdog refresh
+	watchdogFallback bool          // charger has fallen back to the watchdog fallback current
+	watchdogCycle    time.Duration // update cycle the watchdog timeout must exceed
 
 	// charge planning
 	planner          *planner.Planner
@@ -162,6 +186,11 @@ type Loadpoint struct {
 	planOverrunSent  bool             // notification has been sent already
 	planLocked       PlanLock         // locked plan
 
+	// schedules
+	schedules      loadpoint.Schedules // time window schedules
+	scheduleWindow string              // last applied schedule window
+	auditTarget    string              // audit target name of schedule changes
+
 	// cached state
 	status         api.ChargeStatus // Charger status
 	chargePower    float64          // Charging power
@@ -232,6 +261,7 @@ func NewLoadpointFromConfig(log *util.Logger, settings settings.Settings, collec
 		if lp.circuit == nil {
 			return lp, errors.New("missing circuit instance")
 		}
+		config.Retain(lp.circuit)
 	}
 
 	if lp.MeterRef != "" {
@@ -243,6 +273,8 @@ func NewLoadpointFromConfig(log *util.Logger, settings settings.Settings, collec
 		if lp.chargeMeter == nil {
 			return lp, errors.New("missing charge meter instance")
 		}
+		lp.configuredMeter = lp.chargeMeter
+		config.Retain(lp.configuredMeter)
 	}
 
 	// default vehicle
@@ -269,6 +301,7 @@ func NewLoadpointFromConfig(log *util.Logger, settings settings.Settings, collec
 	if lp.charger == nil {
 		return lp, errors.New("missing charger instance")
 	}
+	config.Retain(lp.charger)
 
 	lp.configureChargerType(lp.charger)
 	// add collector
@@ -396,6 +429,11 @@ func (lp *Loadpoint) restoreSettings() {
 		lp.Ui = ui
 	}
 
+	var watchdog loadpoint.WatchdogConfig
+	if err := lp.settings.Json(keys.Watchdog, &watchdog); err == nil {
+		lp.Watchdog = watchdog
+	}
+
 	t, err1 := lp.settings.Time(keys.PlanTime)
 	v, err2 := lp.settings.Float(keys.PlanEnergy)
 	if err1 == nil && err2 == nil {
@@ -407,6 +445,15 @@ func (lp *Loadpoint) restoreSettings() {
 	if err := lp.settings.Json(keys.PlanStrategy, &planStrategy); err == nil {
 		lp.setPlanStrategy(planStrategy)
 	}
+
+	// restore schedules without re-applying the current window
+	var schedules loadpoint.Schedules
+	if err := lp.settings.Json(keys.Schedules, &schedules); err == nil {
+		lp.setSchedules(schedules)
+	}
+	if v, err := lp.settings.String(keys.ScheduleWindow); err == nil {
+		lp.scheduleWindow = v
+	}
 }
 
 // requestUpdate requests site to update this loadpoint
@@ -571,6 +618,9 @@ func (lp *Loadpoint) evVehicleConnectHandler() {
 	// create charging session
 	lp.createSession()
 
+	// record charging fingerprint
+	lp.resetFingerprint()
+
 	// reset energy-based charging plan offset
 	lp.planEnergyOffset = 0
 }
@@ -585,6 +635,11 @@ func (lp *Loadpoint) evVehicleDisconnectHandler() {
 	// re-read odometer to catch delayed update (#30225)
 	lp.vehicleOdometer()
 
+	// record departure time for departure prediction
+	lp.updateSession(func(session *session.Session) {
+		session.Disconnected = lp.clock.Now()
+	})
+
 	// session is persisted during evChargeStopHandler which runs before
 	lp.clearSession()
 
@@ -608,6 +663,7 @@ func (lp *Loadpoint) evVehicleDisconnectHandler() {
 	// remove charger vehicle id and stop potential detection
 	lp.setVehicleIdentifier("")
 	lp.stopVehicleDetection()
+	lp.resetFingerprint()
 
 	// set default mode on disconnect
 	// skip for integrated devices: the "disconnect" here is just the socket
@@ -636,6 +692,9 @@ func (lp *Loadpoint) evVehicleDisconnectHandler() {
 	// mark plan slot as inactive
 	// this will force a deletion of an outdated plan once plan time is expired in GetPlan()
 	lp.setPlanActive(false)
+
+	// active schedule takes precedence over the default mode
+	lp.reapplySchedules()
 }
 
 // evVehicleSocProgressHandler sends external start event
@@ -681,6 +740,16 @@ func (lp *Loadpoint) defaultMode() {
 	}
 }
 
+// subscribeEvents registers the loadpoint's event handlers
+func (lp *Loadpoint) subscribeEvents() {
+	_ = lp.bus.Subscribe(evChargeStart, lp.evChargeStartHandler)
+	_ = lp.bus.Subscribe(evChargeStop, lp.evChargeStopHandler)
+	_ = lp.bus.Subscribe(evVehicleConnect, lp.evVehicleConnectHandler)
+	_ = lp.bus.Subscribe(evVehicleDisconnect, lp.evVehicleDisconnectHandler)
+	_ = lp.bus.Subscribe(evChargeCurrent, lp.evChargeCurrentHandler)
+	_ = lp.bus.Subscribe(evVehicleSoc, lp.evVehicleSocProgressHandler)
+}
+
 // Prepare loadpoint configuration by adding missing helper elements
 func (lp *Loadpoint) Prepare(site site.API, uiChan chan<- util.Param, pushChan chan<- messenger.Event, lpChan chan<- *Loadpoint) {
 	lp.site = site
@@ -689,12 +758,7 @@ func (lp *Loadpoint) Prepare(site site.API, uiChan chan<- util.Param, pushChan c
 	lp.lpChan = lpChan
 
 	// event handlers
-	_ = lp.bus.Subscribe(evChargeStart, lp.evChargeStartHandler)
-	_ = lp.bus.Subscribe(evChargeStop, lp.evChargeStopHandler)
-	_ = lp.bus.Subscribe(evVehicleConnect, lp.evVehicleConnectHandler)
-	_ = lp.bus.Subscribe(evVehicleDisconnect, lp.evVehicleDisconnectHandler)
-	_ = lp.bus.Subscribe(evChargeCurrent, lp.evChargeCurrentHandler)
-	_ = lp.bus.Subscribe(evVehicleSoc, lp.evVehicleSocProgressHandler)
+	lp.subscribeEvents()
 
 	// restore settings
 	lp.restoreSettings()
@@ -759,6 +823,7 @@ func (lp *Loadpoint) Prepare(site site.API, uiChan chan<- util.Param, pushChan c
 	lp.publish(keys.PlanTime, lp.planTime)
 	lp.publish(keys.PlanEnergy, lp.planEnergy)
 	lp.publish(keys.PlanStrategy, lp.planStrategy)
+	lp.publish(keys.Schedules, lp.schedules)
 	lp.publish(keys.LimitSoc, lp.limitSoc)
 	lp.publish(keys.LimitEnergy, lp.limitEnergy)
 	lp.publish(keys.MinSoc, lp.minSoc)
@@ -770,6 +835,15 @@ func (lp *Loadpoint) Prepare(site site.API, uiChan chan<- util.Param, pushChan c
 	lp.publish(keys.BatteryBoost, lp.batteryBoost != boostDisabled)
 	lp.publish(keys.BatteryBoostLimit, lp.batteryBoostLimit)
 
+	// load shedding
+	lp.publish(keys.Shed, lp.shed)
+
+	// charger watchdog
+	lp.publish(keys.ChargerFallback, false)
+	if lp.Watchdog.Timeout > 0 && !api.HasCap[api.Watchdog](lp.charger) {
+		lp.log.WARN.Println("watchdog: charger does not support fallback current")
+	}
+
 	// read initial charger state to prevent immediately disabling charger
 	if enabled, err := lp.charger.Enabled(); err == nil {
 		if lp.enabled = enabled; enabled {
@@ -961,6 +1035,12 @@ func (lp *Loadpoint) setLimit(current float64) error {
 		current = lp.roundedCurrent(min(currentLimit, currentLimitViaPower))
 	}
 
+	// apply backup mode power budget
+	current = lp.backupCurrent(current)
+
+	// apply circuit overload protection
+	current = lp.curtailedCurrent(current)
+
 	// https://github.com/evcc-io/evcc/issues/16309
 	effMinCurrent := lp.effectiveMinCurrent()
 	if effMaxCurrent := lp.effectiveMaxCurrent(); effMinCurrent > effMaxCurrent {
@@ -2093,6 +2173,14 @@ func (lp *Loadpoint) phaseSwitchCompleted() bool {
 
 // Update is the main control function. It reevaluates meters and charger state
 func (lp *Loadpoint) Update(sitePower, batteryPower float64, consumption, feedin api.Rates, batteryBuffered, batteryStart bool, greenShare float64, effPrice, effCo2 *float64, dim *bool) {
+	// swap reconfigured devices before using them
+	if lp.reload.CompareAndSwap(true, false) {
+		lp.reloadDevices()
+	}
+
+	// apply schedule settings before evaluating mode and limits
+	lp.applySchedules()
+
 	// hold battery boost when SOC drops below the limit: stop draining the battery, but
 	// keep the vehicle prioritised over recharging it (via sitePower priorityAdjustment)
 	// until the vehicle disconnects. This holds the battery at the configured level
@@ -2187,6 +2275,9 @@ func (lp *Loadpoint) Update(sitePower, batteryPower float64, consumption, feedin
 		if lp.vehicleUnidentified() {
 			lp.identifyVehicleByStatus()
 		}
+
+		// learn charging fingerprint and find vehicle by it if still unidentified
+		lp.identifyVehicleByFingerprint()
 	}
 
 	// release deferred connect notification once detection has settled
@@ -2196,6 +2287,9 @@ func (lp *Loadpoint) Update(sitePower, batteryPower float64, consumption, feedin
 	// initial update of connected state matches charger status
 	lp.publishSocAndRange()
 
+	// keep charger from falling back
+	lp.refreshWatchdog()
+
 	// sync settings with charger
 	if err := lp.syncCharger(); err != nil {
 		lp.log.ERROR.Println(err)
@@ -2219,6 +2313,10 @@ func (lp *Loadpoint) Update(sitePower, batteryPower float64, consumption, feedin
 		// https://github.com/evcc-io/evcc/issues/105
 		err = lp.setLimit(0)
 
+	// load shedding overrides all charge modes
+	case lp.isShed():
+		err = lp.setLimit(0)
+
 	case lp.scalePhasesRequired():
 		if err = lp.scalePhases(lp.phasesConfigured); errors.Is(err, api.ErrNotAvailable) {
 			// the charger cannot switch phases right now (e.g. EEBus charger
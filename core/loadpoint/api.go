@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/fingerprint"
 )
 
 //go:generate go tool mockgen -package loadpoint -destination mock.go -mock_names API=MockAPI github.com/evcc-io/evcc/core/loadpoint API
@@ -142,6 +143,11 @@ type API interface {
 	// GetPlan creates a charging plan
 	GetPlan(targetTime time.Time, requiredDuration, precondition time.Duration, continuous bool) api.Rates
 
+	// GetSchedules returns the loadpoint schedules
+	GetSchedules() Schedules
+	// SetSchedules sets the loadpoint schedules
+	SetSchedules(Schedules) error
+
 	// GetSocConfig returns the soc poll settings
 	GetSocConfig() SocConfig
 	// SetSocConfig sets the soc poll settings
@@ -152,6 +158,11 @@ type API interface {
 	// SetUI sets the display-only ui settings
 	SetUI(ui UIConfig)
 
+	// GetWatchdog returns the charger watchdog settings
+	GetWatchdog() WatchdogConfig
+	// SetWatchdog sets the charger watchdog settings
+	SetWatchdog(watchdog WatchdogConfig) error
+
 	// GetThresholds returns the PV mode threshold settings
 	GetThresholds() ThresholdsConfig
 	// SetThresholds sets the PV mode threshold settings
@@ -230,6 +241,8 @@ type API interface {
 	SetVehicle(vehicle api.Vehicle)
 	// StartVehicleDetection allows triggering vehicle detection for debugging purposes
 	StartVehicleDetection()
+	// GetVehicleCandidate returns the vehicle proposed by charging fingerprint
+	GetVehicleCandidate() *fingerprint.Candidate
 	// GetSoc returns the last vehicle or charger soc in %
 	GetSoc() float64
 }
@@ -38,6 +38,7 @@ type DynamicConfig struct {
 	Thresholds ThresholdsConfig `json:"thresholds"`
 	Soc        SocConfig        `json:"soc"`
 	UI         UIConfig         `json:"ui"`
+	Watchdog   WatchdogConfig   `json:"watchdog"`
 }
 
 // UIConfig holds display-only settings. Not used in control logic.
@@ -91,6 +92,10 @@ func (payload DynamicConfig) Apply(lp API) error {
 		err = lp.SetPhasesConfigured(payload.PhasesConfigured)
 	}
 
+	if err == nil {
+		err = lp.SetWatchdog(payload.Watchdog)
+	}
+
 	if err == nil {
 		// In case both min and max current are set, we need to set them in the correct order to avoid validation errors
 		switch {
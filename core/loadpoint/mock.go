@@ -14,6 +14,7 @@ import (
 	time "time"
 
 	api "github.com/evcc-io/evcc/api"
+	fingerprint "github.com/evcc-io/evcc/core/fingerprint"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -601,6 +602,20 @@ func (mr *MockAPIMockRecorder) GetRemainingEnergy() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRemainingEnergy", reflect.TypeOf((*MockAPI)(nil).GetRemainingEnergy))
 }
 
+// GetSchedules mocks base method.
+func (m *MockAPI) GetSchedules() Schedules {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSchedules")
+	ret0, _ := ret[0].(Schedules)
+	return ret0
+}
+
+// GetSchedules indicates an expected call of GetSchedules.
+func (mr *MockAPIMockRecorder) GetSchedules() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSchedules", reflect.TypeOf((*MockAPI)(nil).GetSchedules))
+}
+
 // GetSmartCostLimit mocks base method.
 func (m *MockAPI) GetSmartCostLimit() *float64 {
 	m.ctrl.T.Helper()
@@ -727,6 +742,34 @@ func (mr *MockAPIMockRecorder) GetVehicle() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVehicle", reflect.TypeOf((*MockAPI)(nil).GetVehicle))
 }
 
+// GetVehicleCandidate mocks base method.
+func (m *MockAPI) GetVehicleCandidate() *fingerprint.Candidate {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVehicleCandidate")
+	ret0, _ := ret[0].(*fingerprint.Candidate)
+	return ret0
+}
+
+// GetVehicleCandidate indicates an expected call of GetVehicleCandidate.
+func (mr *MockAPIMockRecorder) GetVehicleCandidate() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVehicleCandidate", reflect.TypeOf((*MockAPI)(nil).GetVehicleCandidate))
+}
+
+// GetWatchdog mocks base method.
+func (m *MockAPI) GetWatchdog() WatchdogConfig {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWatchdog")
+	ret0, _ := ret[0].(WatchdogConfig)
+	return ret0
+}
+
+// GetWatchdog indicates an expected call of GetWatchdog.
+func (mr *MockAPIMockRecorder) GetWatchdog() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWatchdog", reflect.TypeOf((*MockAPI)(nil).GetWatchdog))
+}
+
 // HasChargeMeter mocks base method.
 func (m *MockAPI) HasChargeMeter() bool {
 	m.ctrl.T.Helper()
@@ -1045,6 +1088,20 @@ func (mr *MockAPIMockRecorder) SetPriority(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPriority", reflect.TypeOf((*MockAPI)(nil).SetPriority), arg0)
 }
 
+// SetSchedules mocks base method.
+func (m *MockAPI) SetSchedules(arg0 Schedules) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSchedules", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetSchedules indicates an expected call of SetSchedules.
+func (mr *MockAPIMockRecorder) SetSchedules(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSchedules", reflect.TypeOf((*MockAPI)(nil).SetSchedules), arg0)
+}
+
 // SetSmartCostLimit mocks base method.
 func (m *MockAPI) SetSmartCostLimit(limit *float64) {
 	m.ctrl.T.Helper()
@@ -1129,6 +1186,20 @@ func (mr *MockAPIMockRecorder) SetVehicle(vehicle any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVehicle", reflect.TypeOf((*MockAPI)(nil).SetVehicle), vehicle)
 }
 
+// SetWatchdog mocks base method.
+func (m *MockAPI) SetWatchdog(watchdog WatchdogConfig) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetWatchdog", watchdog)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetWatchdog indicates an expected call of SetWatchdog.
+func (mr *MockAPIMockRecorder) SetWatchdog(watchdog any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWatchdog", reflect.TypeOf((*MockAPI)(nil).SetWatchdog), watchdog)
+}
+
 // SocBasedPlanning mocks base method.
 func (m *MockAPI) SocBasedPlanning() bool {
 	m.ctrl.T.Helper()
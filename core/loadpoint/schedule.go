@@ -0,0 +1,125 @@
+package loadpoint
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// Schedule applies loadpoint settings when its time window starts.
+// Settings can be changed manually until the next window starts.
+type Schedule struct {
+	Weekdays    []int          `json:"weekdays"`              // 0-6 (Sunday-Saturday)
+	Holidays    bool           `json:"holidays,omitempty"`    // active on holidays instead of weekdays
+	Start       string         `json:"start"`                 // HH:MM
+	End         string         `json:"end"`                   // HH:MM, window spans midnight if not after start
+	Mode        api.ChargeMode `json:"mode,omitempty"`        // charge mode
+	MinCurrent  float64        `json:"minCurrent,omitempty"`  // min current in A
+	LimitSoc    int            `json:"limitSoc,omitempty"`    // session limit soc in %
+	LimitEnergy float64        `json:"limitEnergy,omitempty"` // session limit energy in kWh
+	Priority    *int           `json:"priority,omitempty"`    // priority
+	Active      bool           `json:"active"`                // active flag
+}
+
+// Schedules are the loadpoint's schedules. The first matching window wins.
+type Schedules struct {
+	Windows  []Schedule `json:"windows"`
+	Holidays []string   `json:"holidays,omitempty"` // YYYY-MM-DD
+}
+
+// Window is an occurrence of a schedule
+type Window struct {
+	Index      int
+	Start, End time.Time
+}
+
+// Validate validates the schedules
+func (s Schedules) Validate() error {
+	for _, day := range s.Holidays {
+		if _, err := time.Parse(time.DateOnly, day); err != nil {
+			return fmt.Errorf("invalid holiday: %v", err)
+		}
+	}
+
+	for _, w := range s.Windows {
+		for _, day := range w.Weekdays {
+			if day < 0 || day > 6 {
+				return fmt.Errorf("weekday out of range: %v", day)
+			}
+		}
+
+		start, err := time.Parse("15:04", w.Start)
+		if err != nil {
+			return fmt.Errorf("invalid start: %v", err)
+		}
+
+		end, err := time.Parse("15:04", w.End)
+		if err != nil {
+			return fmt.Errorf("invalid end: %v", err)
+		}
+
+		if start.Equal(end) {
+			return errors.New("empty window")
+		}
+
+		if w.Mode != api.ModeEmpty {
+			if _, err := api.ChargeModeString(w.Mode.String()); err != nil {
+				return err
+			}
+		}
+
+		if w.MinCurrent < 0 || w.LimitSoc < 0 || w.LimitSoc > 100 || w.LimitEnergy < 0 {
+			return errors.New("invalid limits")
+		}
+	}
+
+	return nil
+}
+
+// matches returns true if the schedule applies to the given date
+func (s Schedules) matches(w Schedule, date time.Time) bool {
+	if slices.Contains(s.Holidays, date.Format(time.DateOnly)) {
+		return w.Holidays
+	}
+
+	return slices.Contains(w.Weekdays, int(date.Weekday()))
+}
+
+// Active returns the window of the first active schedule at the given time
+func (s Schedules) Active(now time.Time) (Window, bool) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	for i, w := range s.Windows {
+		if !w.Active {
+			continue
+		}
+
+		start, err1 := time.Parse("15:04", w.Start)
+		end, err2 := time.Parse("15:04", w.End)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+
+		// windows spanning midnight may have started yesterday
+		for _, date := range []time.Time{today, today.AddDate(0, 0, -1)} {
+			if !s.matches(w, date) {
+				continue
+			}
+
+			from := time.Date(date.Year(), date.Month(), date.Day(), start.Hour(), start.Minute(), 0, 0, now.Location())
+			to := time.Date(date.Year(), date.Month(), date.Day(), end.Hour(), end.Minute(), 0, 0, now.Location())
+			if !to.After(from) {
+				to = to.AddDate(0, 0, 1)
+			}
+
+			if !now.Before(from) && now.Before(to) {
+				return Window{Index: i, Start: from, End: to}, true
+			}
+		}
+	}
+
+	return Window{}, false
+}
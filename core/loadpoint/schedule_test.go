@@ -0,0 +1,53 @@
+package loadpoint
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedulesValidate(t *testing.T) {
+	assert.NoError(t, Schedules{Windows: []Schedule{{Weekdays: []int{1}, Start: "08:00", End: "17:00", Mode: api.ModePV}}}.Validate())
+	assert.Error(t, Schedules{Windows: []Schedule{{Weekdays: []int{7}, Start: "08:00", End: "17:00"}}}.Validate())
+	assert.Error(t, Schedules{Windows: []Schedule{{Start: "08:00", End: "08:00"}}}.Validate())
+	assert.Error(t, Schedules{Windows: []Schedule{{Start: "08:00", End: "17:00", Mode: "foo"}}}.Validate())
+	assert.Error(t, Schedules{Holidays: []string{"24.12.2026"}}.Validate())
+}
+
+func TestSchedulesActive(t *testing.T) {
+	s := Schedules{
+		Windows: []Schedule{
+			{Weekdays: []int{1, 2, 3, 4, 5}, Start: "08:00", End: "17:00", Mode: api.ModePV, Active: true},
+			{Weekdays: []int{0, 6}, Holidays: true, Start: "00:00", End: "00:00", Mode: api.ModeNow, Active: false},
+			{Weekdays: []int{5, 6}, Holidays: true, Start: "22:00", End: "06:00", Mode: api.ModeNow, Active: true},
+		},
+		Holidays: []string{"2026-12-25"},
+	}
+
+	// monday
+	w, ok := s.Active(time.Date(2026, 10, 19, 9, 0, 0, 0, time.Local))
+	require.True(t, ok)
+	assert.Equal(t, 0, w.Index)
+	assert.Equal(t, time.Date(2026, 10, 19, 8, 0, 0, 0, time.Local), w.Start)
+	assert.Equal(t, time.Date(2026, 10, 19, 17, 0, 0, 0, time.Local), w.End)
+
+	_, ok = s.Active(time.Date(2026, 10, 19, 17, 0, 0, 0, time.Local))
+	assert.False(t, ok)
+
+	// friday night spanning midnight
+	w, ok = s.Active(time.Date(2026, 10, 24, 2, 0, 0, 0, time.Local))
+	require.True(t, ok)
+	assert.Equal(t, 2, w.Index)
+	assert.Equal(t, time.Date(2026, 10, 23, 22, 0, 0, 0, time.Local), w.Start)
+
+	// holiday friday
+	_, ok = s.Active(time.Date(2026, 12, 25, 9, 0, 0, 0, time.Local))
+	assert.False(t, ok)
+
+	w, ok = s.Active(time.Date(2026, 12, 25, 23, 0, 0, 0, time.Local))
+	require.True(t, ok)
+	assert.Equal(t, 2, w.Index)
+}
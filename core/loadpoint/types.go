@@ -16,6 +16,12 @@ type ThresholdConfig struct {
 	Threshold float64       `json:"threshold"`
 }
 
+// WatchdogConfig defines the charger fallback if evcc loses control
+type WatchdogConfig struct {
+	Timeout         time.Duration `json:"timeout"`         // fallback without refresh, disabled if zero
+	FallbackCurrent float64       `json:"fallbackCurrent"` // current the charger falls back to
+}
+
 // SocConfig defines soc settings, estimation and update behavior
 type SocConfig struct {
 	Poll     PollConfig `json:"poll"`
@@ -8,6 +8,7 @@ import (
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/core/keys"
 	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/session"
 	"github.com/evcc-io/evcc/core/settings"
 	"github.com/evcc-io/evcc/core/wrapper"
 )
@@ -37,6 +38,8 @@ func (lp *Loadpoint) SetChargerRef(ref string) {
 	defer lp.Unlock()
 	lp.ChargerRef = ref
 	lp.settings.SetString(keys.Charger, ref)
+
+	lp.requestReload()
 }
 
 // GetMeter returns the loadpoint meter
@@ -57,6 +60,8 @@ func (lp *Loadpoint) SetMeterRef(ref string) {
 	defer lp.Unlock()
 	lp.MeterRef = ref
 	lp.settings.SetString(keys.Meter, ref)
+
+	lp.requestReload()
 }
 
 // GetCircuitName returns the loadpoint circuit
@@ -79,6 +84,8 @@ func (lp *Loadpoint) SetCircuitRef(ref string) {
 	defer lp.Unlock()
 	lp.CircuitRef = ref
 	lp.settings.SetString(keys.Circuit, ref)
+
+	lp.requestReload()
 }
 
 // GetDefaultVehicleRef returns the loadpoint default vehicle
@@ -635,6 +642,25 @@ func (lp *Loadpoint) SetDisableDelay(delay time.Duration) {
 	}
 }
 
+// isShed returns true if the loadpoint is switched off by load shedding
+func (lp *Loadpoint) isShed() bool {
+	lp.RLock()
+	defer lp.RUnlock()
+	return lp.shed
+}
+
+// setShed switches the loadpoint off or restores it for load shedding
+func (lp *Loadpoint) setShed(shed bool) {
+	lp.Lock()
+	defer lp.Unlock()
+
+	if shed != lp.shed {
+		lp.log.INFO.Println("load shedding:", shed)
+		lp.shed = shed
+		lp.publish(keys.Shed, shed)
+	}
+}
+
 // GetBatteryBoost returns the battery boost
 func (lp *Loadpoint) GetBatteryBoost() int {
 	lp.RLock()
@@ -874,6 +900,14 @@ func (lp *Loadpoint) SetVehicle(vehicle api.Vehicle) {
 	// set desired vehicle (protected by lock, no locking here)
 	lp.setActiveVehicle(vehicle)
 
+	// manual selection overrides fingerprint detection
+	if vehicle != nil {
+		lp.updateSession(func(session *session.Session) {
+			session.Detection = ""
+			session.DetectionConfidence = nil
+		})
+	}
+
 	lp.vmu.Lock()
 	defer lp.vmu.Unlock()
 
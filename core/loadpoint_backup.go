@@ -0,0 +1,40 @@
+package core
+
+// setBackupPower sets the power budget while the site runs in backup mode, nil if not limited
+func (lp *Loadpoint) setBackupPower(power *float64) {
+	lp.Lock()
+	defer lp.Unlock()
+
+	switch {
+	case power == nil && lp.backupPower != nil:
+		lp.log.INFO.Println("backup mode: released")
+	case power != nil && lp.backupPower == nil:
+		lp.log.WARN.Printf("backup mode: limiting power to %.0fW", *power)
+	}
+
+	lp.backupPower = power
+}
+
+// backupCurrent applies the backup mode power budget to the current
+func (lp *Loadpoint) backupCurrent(current float64) float64 {
+	lp.RLock()
+	power := lp.backupPower
+	lp.RUnlock()
+
+	if power == nil {
+		return current
+	}
+
+	return min(current, lp.roundedCurrent(powerToCurrent(*power, lp.ActivePhases())))
+}
+
+// enforceBackupPower applies the backup mode power budget to the offered current if the loadpoint cannot be updated
+func (lp *Loadpoint) enforceBackupPower() {
+	if !lp.enabled {
+		return
+	}
+
+	if err := lp.setLimit(lp.offeredCurrent); err != nil {
+		lp.log.ERROR.Println(err)
+	}
+}
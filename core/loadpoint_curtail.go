@@ -0,0 +1,91 @@
+package core
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+var _ api.CircuitCurtailer = (*Loadpoint)(nil)
+
+// Curtail immediately limits the charge current on behalf of the circuit's overload protection,
+// bypassing ramp and timer logic. The lowest limit of all curtailing circuits is applied by the
+// regular loop until released.
+func (lp *Loadpoint) Curtail(circuit api.Circuit, current float64) error {
+	// circuits protect concurrently, keep the charger at the lowest limit
+	lp.curtailMu.Lock()
+	defer lp.curtailMu.Unlock()
+
+	lp.Lock()
+	if lp.curtailed == nil {
+		lp.curtailed = make(map[api.Circuit]float64)
+	}
+	lp.curtailed[circuit] = current
+	limit, _ := lp.curtailLimit()
+	charger := lp.charger
+	minCurrent := lp.getMinCurrent()
+	lp.Unlock()
+
+	lp.curtailSync.Store(true)
+
+	if limit < minCurrent {
+		lp.log.WARN.Printf("circuit overload: disabling charger")
+		lp.curtailOff.Store(true)
+		return charger.Enable(false)
+	}
+
+	lp.log.WARN.Printf("circuit overload: limiting current to %.3gA", limit)
+
+	if c, ok := api.Cap[api.ChargerEx](charger); ok {
+		return c.MaxCurrentMillis(limit)
+	}
+
+	return charger.MaxCurrent(int64(limit))
+}
+
+// Release removes the circuit's limit. The charge current is handed back to the regular loop
+// once all circuits have released.
+func (lp *Loadpoint) Release(circuit api.Circuit) {
+	lp.Lock()
+	delete(lp.curtailed, circuit)
+	_, curtailed := lp.curtailLimit()
+	lp.Unlock()
+
+	if !curtailed {
+		lp.log.INFO.Printf("circuit overload: released")
+	}
+
+	lp.requestUpdate()
+}
+
+// curtailLimit returns the lowest current limit of all curtailing circuits
+func (lp *Loadpoint) curtailLimit() (float64, bool) {
+	if len(lp.curtailed) == 0 {
+		return 0, false
+	}
+
+	return slices.Min(slices.Collect(maps.Values(lp.curtailed))), true
+}
+
+// curtailedCurrent applies the circuit overload protection limit to the current.
+// It resyncs the charger state if the protection has bypassed the regular loop.
+func (lp *Loadpoint) curtailedCurrent(current float64) float64 {
+	if lp.curtailSync.CompareAndSwap(true, false) {
+		// ensure current is re-set
+		lp.offeredCurrent = 0
+
+		if lp.curtailOff.CompareAndSwap(true, false) && lp.enabled {
+			lp.setAndPublishEnabled(false)
+		}
+	}
+
+	lp.RLock()
+	defer lp.RUnlock()
+
+	if limit, ok := lp.curtailLimit(); ok {
+		return min(current, limit)
+	}
+
+	return current
+}
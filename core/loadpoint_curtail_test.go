@@ -0,0 +1,50 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/circuit"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCurtailNestedCircuits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	charger := api.NewMockCharger(ctrl)
+
+	lp := &Loadpoint{
+		log:        util.NewLogger("foo"),
+		charger:    charger,
+		minCurrent: 6,
+	}
+
+	parent, err := circuit.New(util.NewLogger("parent"), "parent", 32, 0, nil, 0)
+	require.NoError(t, err)
+	child, err := circuit.New(util.NewLogger("child"), "child", 16, 0, nil, 0)
+	require.NoError(t, err)
+
+	charger.EXPECT().MaxCurrent(int64(12))
+	require.NoError(t, lp.Curtail(parent, 12))
+	assert.Equal(t, 12.0, lp.curtailedCurrent(16))
+
+	// lowest limit applies
+	charger.EXPECT().MaxCurrent(int64(8))
+	require.NoError(t, lp.Curtail(child, 8))
+	assert.Equal(t, 8.0, lp.curtailedCurrent(16))
+
+	// higher limit does not override lower limit
+	charger.EXPECT().MaxCurrent(int64(8))
+	require.NoError(t, lp.Curtail(parent, 10))
+	assert.Equal(t, 8.0, lp.curtailedCurrent(16))
+
+	// release per circuit
+	lp.Release(child)
+	assert.Equal(t, 10.0, lp.curtailedCurrent(16))
+
+	lp.Release(parent)
+	assert.Equal(t, 16.0, lp.curtailedCurrent(16))
+}
@@ -0,0 +1,136 @@
+package core
+
+import (
+	"errors"
+
+	evbus "github.com/asaskevich/EventBus"
+	"github.com/evcc-io/evcc/util/config"
+)
+
+// requestReload schedules re-resolving charger, charge meter and circuit on the next update
+func (lp *Loadpoint) requestReload() {
+	lp.reload.Store(true)
+	lp.requestUpdate()
+}
+
+// usesDevice checks if the loadpoint references the named device of the given class
+func (lp *Loadpoint) usesDevice(class string, name string) bool {
+	lp.RLock()
+	defer lp.RUnlock()
+
+	switch class {
+	case "charger":
+		return lp.ChargerRef == name
+	case "meter":
+		return lp.MeterRef == name
+	case "circuit":
+		return lp.CircuitRef == name
+	}
+
+	return false
+}
+
+// deviceInstance resolves a device reference, returning the zero value for an empty ref
+func deviceInstance[T comparable](h config.Handler[T], ref string) (T, error) {
+	var zero T
+	if ref == "" {
+		return zero, nil
+	}
+
+	dev, err := h.ByName(ref)
+	if err != nil {
+		return zero, err
+	}
+
+	res := dev.Instance()
+	if res == zero {
+		return zero, errors.New("missing instance")
+	}
+
+	return res, nil
+}
+
+// swapDevice retains the current device instance and releases the replaced one,
+// shutting it down if it has been retired by its handler
+func swapDevice[T comparable](prev, current T) {
+	if prev == current {
+		return
+	}
+
+	config.Retain(current)
+	config.Release(prev)
+}
+
+// reloadDevices re-resolves charger, charge meter and circuit from their refs
+// and swaps the instances in place. The previous devices are kept on error.
+// Charger and charge meter carry the session accounting (charge rater, timer and
+// energy offset) and are only swapped while no vehicle is connected.
+func (lp *Loadpoint) reloadDevices() {
+	lp.RLock()
+	chargerRef, meterRef, circuitRef := lp.ChargerRef, lp.MeterRef, lp.CircuitRef
+	lp.RUnlock()
+
+	circuit, err := deviceInstance(config.Circuits(), circuitRef)
+	if err != nil {
+		lp.log.ERROR.Printf("reload: circuit: %v", err)
+		return
+	}
+
+	lp.Lock()
+	prevCircuit := lp.circuit
+	lp.circuit = circuit
+	lp.Unlock()
+
+	swapDevice(prevCircuit, circuit)
+
+	charger, err := deviceInstance(config.Chargers(), chargerRef)
+	if err == nil && charger == nil {
+		err = errors.New("missing charger")
+	}
+	if err != nil {
+		lp.log.ERROR.Printf("reload: charger: %v", err)
+		return
+	}
+
+	meter, err := deviceInstance(config.Meters(), meterRef)
+	if err != nil {
+		lp.log.ERROR.Printf("reload: meter: %v", err)
+		return
+	}
+
+	lp.RLock()
+	unchanged := lp.charger == charger && lp.configuredMeter == meter
+	lp.RUnlock()
+
+	if unchanged {
+		lp.log.DEBUG.Printf("reloaded circuit: %s", circuitRef)
+		return
+	}
+
+	// keep the running session's accounting, retry after disconnect
+	if lp.connected() {
+		lp.log.DEBUG.Println("reload: deferring charger and meter change until vehicle disconnects")
+		lp.reload.Store(true)
+		return
+	}
+
+	lp.Lock()
+	defer lp.Unlock()
+
+	prevCharger, prevMeter := lp.charger, lp.configuredMeter
+
+	lp.charger = charger
+	lp.chargeMeter = meter
+	lp.configuredMeter = meter
+
+	// wrapped charge meter, rater and timer subscribe to the event bus. Without a session
+	// in progress they can be recreated together with the loadpoint's own subscriptions.
+	lp.bus = evbus.New()
+	lp.subscribeEvents()
+	lp.configureChargerType(charger)
+
+	swapDevice(prevCharger, charger)
+	swapDevice(prevMeter, meter)
+
+	lp.log.INFO.Printf("reloaded devices (charger: %s, meter: %s, circuit: %s)", chargerRef, meterRef, circuitRef)
+}
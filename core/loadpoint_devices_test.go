@@ -0,0 +1,50 @@
+package core
+
+import (
+	"testing"
+
+	evbus "github.com/asaskevich/EventBus"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestReloadDevicesDeferredWhileConnected(t *testing.T) {
+	config.Reset()
+	t.Cleanup(config.Reset)
+
+	ctrl := gomock.NewController(t)
+
+	old := api.NewMockCharger(ctrl)
+	charger := api.NewMockCharger(ctrl)
+
+	require.NoError(t, config.Chargers().Add(
+		config.NewStaticDevice(config.Named{Name: "charger"}, api.Charger(charger)),
+	))
+
+	bus := evbus.New()
+
+	lp := &Loadpoint{
+		log:        util.NewLogger("foo"),
+		bus:        bus,
+		charger:    old,
+		status:     api.StatusC,
+		ChargerRef: "charger",
+	}
+
+	// session in progress, keep charger and accounting
+	lp.reloadDevices()
+	assert.Equal(t, api.Charger(old), lp.charger)
+	assert.Equal(t, bus, lp.bus)
+	assert.True(t, lp.reload.Load())
+
+	// vehicle disconnected, swap devices
+	lp.reload.Store(false)
+	lp.status = api.StatusA
+	lp.reloadDevices()
+	assert.Equal(t, api.Charger(charger), lp.charger)
+	assert.False(t, lp.reload.Load())
+}
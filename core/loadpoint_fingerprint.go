@@ -0,0 +1,173 @@
+package core
+
+import (
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/fingerprint"
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/core/session"
+	"github.com/evcc-io/evcc/core/vehicle"
+)
+
+const (
+	fingerprintSessions   = 10  // learned sessions per vehicle
+	fingerprintConfidence = 0.8 // minimum confidence for selecting a vehicle
+)
+
+// GetVehicleCandidate returns the vehicle proposed by charging fingerprint
+func (lp *Loadpoint) GetVehicleCandidate() *fingerprint.Candidate {
+	lp.RLock()
+	defer lp.RUnlock()
+	return lp.vehicleCandidate
+}
+
+func (lp *Loadpoint) setVehicleCandidate(c *fingerprint.Candidate) {
+	lp.Lock()
+	lp.vehicleCandidate = c
+	lp.Unlock()
+
+	lp.publish(keys.VehicleCandidate, c)
+}
+
+// resetFingerprint starts recording a new charging fingerprint
+func (lp *Loadpoint) resetFingerprint() {
+	if lp.fingerprint == nil {
+		lp.fingerprint = fingerprint.NewRecorder(lp.clock)
+	}
+
+	lp.fingerprint.Reset()
+	lp.fingerprinted = false
+	lp.fingerprintPartial = nil
+	lp.setVehicleCandidate(nil)
+}
+
+// proposeVehicleByPartialFingerprint proposes a vehicle from standby power and active phases
+// before the ramp-up window has elapsed. The partial fingerprint is matched again once
+// active phases become known. Vehicles are only proposed, never selected.
+func (lp *Loadpoint) proposeVehicleByPartialFingerprint() {
+	fp, ok := lp.fingerprint.Partial()
+	if !ok || lp.fingerprintPartial != nil && lp.fingerprintPartial.Phases == fp.Phases {
+		return
+	}
+
+	lp.fingerprintPartial = &fp
+
+	// vehicle already identified
+	active := lp.GetVehicle()
+	if active != nil && active != lp.defaultVehicle {
+		return
+	}
+
+	candidates, _ := lp.matchFingerprint(fp)
+	if len(candidates) == 0 {
+		return
+	}
+
+	v, best := lp.candidateVehicle(candidates[0])
+	if v == nil || v == active {
+		return
+	}
+
+	lp.setVehicleCandidate(&best)
+	lp.log.DEBUG.Printf("fingerprint: proposing %s from standby (confidence %.0f%%)", v.GetTitle(), 100*best.Confidence)
+}
+
+// matchFingerprint matches the fingerprint against the fingerprints learned from previous sessions.
+// The result is false if there are no vehicles to match.
+func (lp *Loadpoint) matchFingerprint(fp fingerprint.Fingerprint) ([]fingerprint.Candidate, bool) {
+	if lp.db == nil || len(lp.coordinatedVehicles()) == 0 {
+		return nil, false
+	}
+
+	learned, err := lp.db.Fingerprints(fingerprintSessions)
+	if err != nil {
+		lp.log.ERROR.Printf("fingerprint: %v", err)
+		return nil, false
+	}
+
+	return fingerprint.Match(fp, learned), true
+}
+
+// candidateVehicle resolves the candidate's vehicle. Sessions refer to vehicles by title,
+// the returned candidate refers to the vehicle by name.
+func (lp *Loadpoint) candidateVehicle(c fingerprint.Candidate) (api.Vehicle, fingerprint.Candidate) {
+	for _, v := range lp.availableVehicles() {
+		if v.GetTitle() == c.Vehicle {
+			c.Vehicle = vehicle.Settings(lp.log, v).Name()
+			return v, c
+		}
+	}
+
+	return nil, c
+}
+
+// identifyVehicleByFingerprint records the charging fingerprint into the session.
+// Unidentified vehicles are matched against fingerprints learned from previous sessions
+// and selected if the match is confident. A default vehicle is never replaced but the candidate is proposed.
+func (lp *Loadpoint) identifyVehicleByFingerprint() {
+	if lp.fingerprint == nil {
+		lp.resetFingerprint()
+	}
+
+	if lp.fingerprinted {
+		return
+	}
+
+	lp.fingerprint.Update(lp.charging(), lp.chargePower, lp.chargeCurrents, lp.offeredCurrent)
+
+	fp, ok := lp.fingerprint.Fingerprint()
+	if !ok {
+		lp.proposeVehicleByPartialFingerprint()
+		return
+	}
+
+	lp.fingerprinted = true
+	lp.log.DEBUG.Printf("charging fingerprint: %dp, %.1fA, ramp-up %v, standby %.0fW", fp.Phases, fp.MaxCurrent, fp.RampUp, fp.StandbyPower)
+
+	lp.updateSession(func(session *session.Session) {
+		session.SetFingerprint(fp)
+	})
+
+	// vehicle already identified
+	active := lp.GetVehicle()
+	if active != nil && active != lp.defaultVehicle {
+		return
+	}
+
+	candidates, ok := lp.matchFingerprint(fp)
+	if !ok {
+		return
+	}
+
+	if len(candidates) == 0 {
+		lp.log.INFO.Println("fingerprint: unknown vehicle")
+
+		// keep guest vehicles out of the default vehicle's fingerprints
+		lp.updateSession(func(s *session.Session) {
+			s.Detection = session.DetectionUnknown
+		})
+
+		return
+	}
+
+	v, best := lp.candidateVehicle(candidates[0])
+	if v == nil || v == active {
+		return
+	}
+
+	lp.setVehicleCandidate(&best)
+
+	if active != nil || best.Confidence < fingerprintConfidence {
+		lp.log.INFO.Printf("fingerprint: proposing %s (confidence %.0f%%)", v.GetTitle(), 100*best.Confidence)
+		return
+	}
+
+	lp.log.INFO.Printf("fingerprint: identified %s (confidence %.0f%%)", v.GetTitle(), 100*best.Confidence)
+
+	lp.stopVehicleDetection()
+	lp.setActiveVehicle(v)
+
+	lp.updateSession(func(s *session.Session) {
+		s.Detection = session.DetectionFingerprint
+		s.DetectionConfidence = &best.Confidence
+	})
+}
@@ -0,0 +1,112 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/audit"
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/core/loadpoint"
+)
+
+// GetSchedules returns the loadpoint schedules
+func (lp *Loadpoint) GetSchedules() loadpoint.Schedules {
+	lp.RLock()
+	defer lp.RUnlock()
+	return lp.schedules
+}
+
+// setSchedules sets the loadpoint schedules (no mutex)
+func (lp *Loadpoint) setSchedules(schedules loadpoint.Schedules) error {
+	if err := lp.settings.SetJson(keys.Schedules, schedules); err != nil {
+		return err
+	}
+
+	lp.schedules = schedules
+	lp.publish(keys.Schedules, schedules)
+
+	lp.requestUpdate()
+
+	return nil
+}
+
+// SetSchedules sets the loadpoint schedules
+func (lp *Loadpoint) SetSchedules(schedules loadpoint.Schedules) error {
+	if err := schedules.Validate(); err != nil {
+		return err
+	}
+
+	lp.Lock()
+	defer lp.Unlock()
+
+	lp.log.DEBUG.Printf("set schedules: %+v", schedules)
+
+	return lp.setSchedules(schedules)
+}
+
+// reapplySchedules applies the settings of the active schedule again after they have been
+// reset by disconnect or overridden by the identified vehicle's mode
+func (lp *Loadpoint) reapplySchedules() {
+	lp.Lock()
+	lp.scheduleWindow = ""
+	lp.Unlock()
+
+	lp.applySchedules()
+}
+
+// applySchedules applies the settings of the active schedule once when its window starts.
+// Manual changes are kept until the next window starts.
+func (lp *Loadpoint) applySchedules() {
+	schedules := lp.GetSchedules()
+	if len(schedules.Windows) == 0 {
+		return
+	}
+
+	w, ok := schedules.Active(lp.clock.Now())
+	if !ok {
+		return
+	}
+
+	window := fmt.Sprintf("%d@%d", w.Index, w.Start.Unix())
+
+	lp.Lock()
+	applied := lp.scheduleWindow == window
+	if !applied {
+		lp.scheduleWindow = window
+		lp.settings.SetString(keys.ScheduleWindow, window)
+	}
+	lp.Unlock()
+
+	if applied {
+		return
+	}
+
+	s := schedules.Windows[w.Index]
+
+	lp.log.INFO.Printf("schedule %d: %s-%s", w.Index+1, s.Start, s.End)
+
+	// record schedule changes like any other setter invocation
+	alp := audit.Loadpoint(lp, lp.auditTarget, audit.Source{Kind: audit.Internal, Actor: fmt.Sprintf("schedule-%d", w.Index+1)})
+
+	if s.Mode != api.ModeEmpty {
+		alp.SetMode(s.Mode)
+	}
+
+	if s.MinCurrent > 0 {
+		if err := alp.SetMinCurrent(s.MinCurrent); err != nil {
+			lp.log.ERROR.Printf("schedule %d: %v", w.Index+1, err)
+		}
+	}
+
+	if s.LimitSoc > 0 {
+		alp.SetLimitSoc(s.LimitSoc)
+	}
+
+	if s.LimitEnergy > 0 {
+		alp.SetLimitEnergy(s.LimitEnergy)
+	}
+
+	if s.Priority != nil {
+		alp.SetPriority(*s.Priority)
+	}
+}
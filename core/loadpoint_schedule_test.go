@@ -0,0 +1,69 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/settings"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplySchedules(t *testing.T) {
+	clck := clock.NewMock()
+	clck.Set(time.Date(2026, 10, 19, 7, 0, 0, 0, time.Local)) // monday
+
+	lp := &Loadpoint{
+		log:        util.NewLogger("foo"),
+		clock:      clck,
+		settings:   settings.NewDatabaseSettingsAdapter("foo"),
+		minCurrent: minA,
+		maxCurrent: maxA,
+		mode:       api.ModeOff,
+	}
+
+	require.NoError(t, lp.SetSchedules(loadpoint.Schedules{
+		Windows: []loadpoint.Schedule{
+			{Weekdays: []int{1, 2, 3, 4, 5}, Start: "08:00", End: "17:00", Mode: api.ModePV, Priority: new(2), Active: true},
+		},
+	}))
+
+	// outside window
+	lp.applySchedules()
+	assert.Equal(t, api.ModeOff, lp.GetMode())
+
+	// window starts
+	clck.Add(time.Hour)
+	lp.applySchedules()
+	assert.Equal(t, api.ModePV, lp.GetMode())
+	assert.Equal(t, 2, lp.GetPriority())
+
+	// manual change is kept until the next window
+	lp.SetMode(api.ModeNow)
+	clck.Add(time.Hour)
+	lp.applySchedules()
+	assert.Equal(t, api.ModeNow, lp.GetMode())
+
+	// next day
+	clck.Add(24 * time.Hour)
+	lp.applySchedules()
+	assert.Equal(t, api.ModePV, lp.GetMode())
+
+	// default mode reset on disconnect is overridden by the active window
+	lp.DefaultMode = api.ModeOff
+	lp.defaultMode()
+	assert.Equal(t, api.ModeOff, lp.GetMode())
+
+	lp.reapplySchedules()
+	assert.Equal(t, api.ModePV, lp.GetMode())
+
+	// outside window
+	clck.Add(12 * time.Hour)
+	lp.defaultMode()
+	lp.reapplySchedules()
+	assert.Equal(t, api.ModeOff, lp.GetMode())
+}
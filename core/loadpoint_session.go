@@ -84,6 +84,7 @@ func (lp *Loadpoint) applyEnergyMetrics(s *session.Session) {
 	s.Price = lp.energyMetrics.Price()
 	s.PricePerKWh = lp.energyMetrics.PricePerKWh()
 	s.Co2PerKWh = lp.energyMetrics.Co2PerKWh()
+	s.Tariffs = lp.energyMetrics.Tariffs()
 	s.ChargedEnergy = lp.energyMetrics.TotalWh() / 1e3
 
 	lp.db.Persist(s)
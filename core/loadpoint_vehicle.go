@@ -161,6 +161,9 @@ func (lp *Loadpoint) setActiveVehicle(v api.Vehicle) {
 		}
 		if ok && mode != "" {
 			lp.SetMode(mode)
+
+			// active schedule takes precedence over the vehicle mode
+			lp.reapplySchedules()
 		}
 
 		lp.addTask(lp.vehicleOdometer)
@@ -355,6 +358,12 @@ func (lp *Loadpoint) vehicleSocPollAllowed() bool {
 		return true
 	}
 
+	// skip vehicles away from home
+	if !lp.connected() && lp.coordinator != nil && lp.coordinator.Away(lp.GetVehicle()) {
+		lp.log.DEBUG.Println("skip soc poll: vehicle away")
+		return false
+	}
+
 	// update if connected and soc unknown
 	if lp.connected() && lp.socUpdated.IsZero() {
 		return true
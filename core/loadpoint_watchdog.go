@@ -0,0 +1,108 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/core/loadpoint"
+)
+
+// watchdogCycles is the number of loadpoint update cycles the watchdog timeout must exceed
+const watchdogCycles = 2
+
+// checkWatchdog validates the watchdog timeout against the loadpoint's update cycle
+func (lp *Loadpoint) checkWatchdog(cycle time.Duration) error {
+	if timeout := lp.Watchdog.Timeout; timeout > 0 && timeout <= watchdogCycles*cycle {
+		return fmt.Errorf("timeout %v must exceed %d update cycles of %v", timeout, watchdogCycles, cycle)
+	}
+
+	return nil
+}
+
+// setWatchdogCycle records the loadpoint's update cycle and validates the watchdog timeout against it
+func (lp *Loadpoint) setWatchdogCycle(cycle time.Duration) error {
+	lp.Lock()
+	defer lp.Unlock()
+
+	lp.watchdogCycle = cycle
+
+	return lp.checkWatchdog(cycle)
+}
+
+// GetWatchdog returns the charger watchdog settings
+func (lp *Loadpoint) GetWatchdog() loadpoint.WatchdogConfig {
+	lp.RLock()
+	defer lp.RUnlock()
+	return lp.Watchdog
+}
+
+// SetWatchdog sets the charger watchdog settings
+func (lp *Loadpoint) SetWatchdog(watchdog loadpoint.WatchdogConfig) error {
+	lp.Lock()
+	defer lp.Unlock()
+
+	lp.log.DEBUG.Printf("set watchdog config: %+v", watchdog)
+
+	if watchdog.Timeout < 0 || watchdog.FallbackCurrent < 0 {
+		return fmt.Errorf("invalid watchdog config: %+v", watchdog)
+	}
+
+	prev := lp.Watchdog
+	if lp.Watchdog = watchdog; watchdog == prev {
+		return nil
+	}
+
+	if err := lp.checkWatchdog(lp.watchdogCycle); err != nil {
+		lp.Watchdog = prev
+		return err
+	}
+
+	if watchdog.Timeout > 0 && !api.HasCap[api.Watchdog](lp.charger) {
+		lp.log.WARN.Println("watchdog: charger does not support fallback current")
+	}
+
+	// restart the timeout from the new settings
+	lp.watchdogUpdated = lp.clock.Now()
+	lp.settings.SetJson(keys.Watchdog, watchdog)
+
+	return nil
+}
+
+// refreshWatchdog refreshes the charger watchdog. The charger is considered to have fallen back
+// to the fallback current if the watchdog could not be refreshed within the timeout.
+func (lp *Loadpoint) refreshWatchdog() {
+	timeout := lp.Watchdog.Timeout
+	if timeout == 0 {
+		return
+	}
+
+	wd, ok := api.Cap[api.Watchdog](lp.charger)
+	if !ok {
+		return
+	}
+
+	if err := wd.Watchdog(timeout, lp.Watchdog.FallbackCurrent); err != nil {
+		lp.log.ERROR.Printf("watchdog: %v", err)
+
+		if !lp.watchdogFallback && lp.clock.Since(lp.watchdogUpdated) > timeout {
+			lp.log.WARN.Printf("watchdog: charger fell back to %.3gA", lp.Watchdog.FallbackCurrent)
+			lp.watchdogFallback = true
+			lp.publish(keys.ChargerFallback, true)
+		}
+
+		return
+	}
+
+	lp.watchdogUpdated = lp.clock.Now()
+
+	if lp.watchdogFallback {
+		lp.log.INFO.Println("watchdog: charger control resumed")
+		lp.watchdogFallback = false
+		lp.publish(keys.ChargerFallback, false)
+
+		// ensure current is re-set
+		lp.offeredCurrent = 0
+	}
+}
@@ -0,0 +1,107 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/settings"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type watchdogCharger struct {
+	api.Charger
+	watchdog func(time.Duration, float64) error
+}
+
+func (c *watchdogCharger) Watchdog(timeout time.Duration, fallback float64) error {
+	return c.watchdog(timeout, fallback)
+}
+
+func TestRefreshWatchdog(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	clck := clock.NewMock()
+
+	var err error
+	var refreshed int
+
+	charger := &watchdogCharger{
+		Charger: api.NewMockCharger(ctrl),
+		watchdog: func(timeout time.Duration, fallback float64) error {
+			assert.Equal(t, time.Minute, timeout)
+			assert.Equal(t, 6.0, fallback)
+			refreshed++
+			return err
+		},
+	}
+
+	lp := &Loadpoint{
+		log:            util.NewLogger("foo"),
+		clock:          clck,
+		charger:        charger,
+		offeredCurrent: 16,
+		Watchdog: loadpoint.WatchdogConfig{
+			Timeout:         time.Minute,
+			FallbackCurrent: 6,
+		},
+	}
+
+	lp.refreshWatchdog()
+	assert.Equal(t, 1, refreshed)
+	assert.False(t, lp.watchdogFallback)
+
+	// refresh failing within timeout
+	err = errors.New("timeout")
+	clck.Add(30 * time.Second)
+	lp.refreshWatchdog()
+	assert.False(t, lp.watchdogFallback)
+
+	// refresh failing beyond timeout
+	clck.Add(31 * time.Second)
+	lp.refreshWatchdog()
+	assert.True(t, lp.watchdogFallback)
+
+	// control resumed, current must be re-set
+	err = nil
+	lp.refreshWatchdog()
+	assert.False(t, lp.watchdogFallback)
+	assert.Equal(t, 0.0, lp.offeredCurrent)
+	assert.Equal(t, 4, refreshed)
+}
+
+func TestCheckWatchdog(t *testing.T) {
+	lp := &Loadpoint{Watchdog: loadpoint.WatchdogConfig{Timeout: time.Minute}}
+
+	assert.NoError(t, lp.checkWatchdog(20*time.Second))
+	assert.Error(t, lp.checkWatchdog(30*time.Second))
+	assert.Error(t, lp.checkWatchdog(time.Minute))
+
+	// disabled
+	lp.Watchdog.Timeout = 0
+	assert.NoError(t, lp.checkWatchdog(time.Minute))
+}
+
+func TestSetWatchdog(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	lp := NewLoadpoint(util.NewLogger("foo"), settings.NewDatabaseSettingsAdapter("foo"))
+	lp.clock = clock.NewMock()
+	lp.charger = &watchdogCharger{Charger: api.NewMockCharger(ctrl)}
+	lp.watchdogCycle = 30 * time.Second
+
+	assert.NoError(t, lp.SetWatchdog(loadpoint.WatchdogConfig{Timeout: 2 * time.Minute, FallbackCurrent: 6}))
+	assert.Equal(t, loadpoint.WatchdogConfig{Timeout: 2 * time.Minute, FallbackCurrent: 6}, lp.GetWatchdog())
+
+	// timeout must exceed the update cycles, previous settings are kept
+	assert.Error(t, lp.SetWatchdog(loadpoint.WatchdogConfig{Timeout: time.Minute, FallbackCurrent: 6}))
+	assert.Error(t, lp.SetWatchdog(loadpoint.WatchdogConfig{Timeout: 2 * time.Minute, FallbackCurrent: -1}))
+	assert.Equal(t, loadpoint.WatchdogConfig{Timeout: 2 * time.Minute, FallbackCurrent: 6}, lp.GetWatchdog())
+
+	// disabled
+	assert.NoError(t, lp.SetWatchdog(loadpoint.WatchdogConfig{}))
+}
@@ -16,6 +16,7 @@ type Accumulator struct {
 	Energy            float64  `json:"energy"`       // kWh
 	ReturnEnergy      float64  `json:"returnEnergy"` // kWh
 	SocTemp           *float64 `json:"socTemp,omitempty"`
+	quality           qualityAverage
 }
 
 // AccumulatorState is the resumable meter-reading checkpoint of an Accumulator.
@@ -51,6 +52,16 @@ func (m *Accumulator) setSocTemp(value float64) {
 	}
 }
 
+// AddQuality adds the power quality measurements to the slot averages
+func (m *Accumulator) AddQuality(reactivePower, powerFactor, frequency *float64) {
+	m.quality.add(reactivePower, powerFactor, frequency)
+}
+
+// Quality returns the slot averages of the power quality measurements
+func (m *Accumulator) Quality() Quality {
+	return m.quality.value()
+}
+
 func WithClock(clock clock.Clock) func(*Accumulator) {
 	return func(m *Accumulator) {
 		m.clock = clock
@@ -139,6 +139,7 @@ func (c *Collector) process(fun func()) error {
 	c.accu.Energy = 0
 	c.accu.ReturnEnergy = 0
 	c.accu.SocTemp = nil
+	c.accu.quality = qualityAverage{}
 	return nil
 }
 
@@ -147,6 +148,10 @@ func (c *Collector) persist(recovered bool) error {
 		return err
 	}
 
+	if err := persistQuality(c.entity, c.started, c.accu.Quality()); err != nil {
+		return err
+	}
+
 	// checkpoint meter readings for downtime recovery (scoped write, keeps identity intact)
 	s := c.accu.Snapshot()
 	c.entity.EnergyMeter = s.EnergyMeter
@@ -166,6 +171,13 @@ func (c *Collector) SetSocTemp(value float64, isTemp bool) error {
 	return c.entity.updateIsTemp(isTemp)
 }
 
+// AddQuality adds the power quality measurements to the slot averages. Nil values are not available.
+func (c *Collector) AddQuality(reactivePower, powerFactor, frequency *float64) error {
+	return c.process(func() {
+		c.accu.AddQuality(reactivePower, powerFactor, frequency)
+	})
+}
+
 func (c *Collector) EnergyProfile(from time.Time) (*[96]float64, error) {
 	return energyProfile(c.entity, from)
 }
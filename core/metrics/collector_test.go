@@ -569,3 +569,28 @@ func TestCollectorLastSlotEnergy(t *testing.T) {
 	_, ok = col.LastSlotEnergy()
 	require.False(t, ok)
 }
+
+func TestCollectorAddQuality(t *testing.T) {
+	clk := clock.NewMock() // 1970-01-01 00:00:00 UTC, on a slot boundary
+
+	require.NoError(t, db.NewInstance("sqlite", ":memory:"))
+	require.NoError(t, SetupSchema())
+
+	col, err := NewCollector(Grid, "grid", "", WithClock(clk))
+	require.NoError(t, err)
+
+	require.NoError(t, col.AddEnergy(nil, nil, 0))
+	require.NoError(t, col.AddQuality(new(100.0), new(0.9), new(50.0)))
+	require.NoError(t, col.AddQuality(new(300.0), nil, new(49.9)))
+
+	// cross into the next slot: prior slot persisted, averages cleared
+	clk.Add(15 * time.Minute)
+	require.NoError(t, col.AddEnergy(nil, nil, 0))
+	require.Equal(t, Quality{}, col.accu.Quality())
+
+	var m meter
+	require.NoError(t, db.Instance.Where("meter = ?", col.entity.Id).First(&m).Error)
+	require.Equal(t, 200.0, *m.ReactivePower)
+	require.Equal(t, 0.9, *m.PowerFactor)
+	require.InDelta(t, 49.95, *m.Frequency, 1e-9)
+}
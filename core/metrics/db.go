@@ -19,6 +19,7 @@ type meter struct {
 	ReturnEnergy float64  `json:"returnEnergy" gorm:"column:return_energy"`
 	SocTemp      *float64 `json:"socTemp,omitempty" gorm:"column:soc_temp"`    // at start of slot
 	Recovered    bool     `json:"recovered,omitempty" gorm:"column:recovered"` // downtime catchup slot, excluded from profile
+	Quality      `gorm:"embedded"`
 }
 
 type entity struct {
@@ -154,3 +155,15 @@ func persist(entity entity, ts time.Time, energy, returnEnergy float64, socTemp
 	}
 	return nil
 }
+
+// persistQuality stores the power quality averages of a persisted 15min slot
+func persistQuality(entity entity, ts time.Time, quality Quality) error {
+	if quality == (Quality{}) {
+		return nil
+	}
+
+	slot := ts.Truncate(tariff.SlotDuration)
+	return db.Instance.Model(new(meter)).
+		Where("meter = ? AND ts = ?", entity.Id, slot.Unix()).
+		Updates(meter{Quality: quality}).Error
+}
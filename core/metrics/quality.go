@@ -0,0 +1,47 @@
+package metrics
+
+// Quality holds the slot averages of a meter's power quality measurements
+type Quality struct {
+	ReactivePower *float64 `json:"reactivePower,omitempty" gorm:"column:reactive_power"` // var
+	PowerFactor   *float64 `json:"powerFactor,omitempty" gorm:"column:power_factor"`
+	Frequency     *float64 `json:"frequency,omitempty" gorm:"column:frequency"` // Hz
+}
+
+// average is the running average of a measurement
+type average struct {
+	sum   float64
+	count int
+}
+
+func (a *average) add(v *float64) {
+	if v != nil {
+		a.sum += *v
+		a.count++
+	}
+}
+
+func (a average) value() *float64 {
+	if a.count == 0 {
+		return nil
+	}
+	return new(a.sum / float64(a.count))
+}
+
+// qualityAverage accumulates the power quality measurements of a slot
+type qualityAverage struct {
+	reactivePower, powerFactor, frequency average
+}
+
+func (q *qualityAverage) add(reactivePower, powerFactor, frequency *float64) {
+	q.reactivePower.add(reactivePower)
+	q.powerFactor.add(powerFactor)
+	q.frequency.add(frequency)
+}
+
+func (q qualityAverage) value() Quality {
+	return Quality{
+		ReactivePower: q.reactivePower.value(),
+		PowerFactor:   q.powerFactor.value(),
+		Frequency:     q.frequency.value(),
+	}
+}
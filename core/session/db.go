@@ -1,6 +1,9 @@
 package session
 
 import (
+	"time"
+
+	"github.com/evcc-io/evcc/core/fingerprint"
 	"github.com/evcc-io/evcc/server/db"
 	"github.com/evcc-io/evcc/util"
 	"gorm.io/gorm"
@@ -66,6 +69,48 @@ func (s *DB) Sessions() (Sessions, error) {
 	return res, tx.Error
 }
 
+// Fingerprints returns the latest charging fingerprints by vehicle title, limited per vehicle.
+// Sessions with vehicles identified by fingerprint or unknown fingerprint are excluded to avoid reinforcing wrong matches.
+func (s *DB) Fingerprints(limit int) (map[string][]fingerprint.Fingerprint, error) {
+	latest := s.db.Model(new(Session)).
+		Select("*, ROW_NUMBER() OVER (PARTITION BY vehicle ORDER BY created DESC) AS n").
+		Where("vehicle <> '' AND phases IS NOT NULL AND detection NOT IN ?", []string{DetectionFingerprint, DetectionUnknown})
+
+	var sessions Sessions
+	if tx := s.db.Table("(?) AS latest", latest).Where("n <= ?", limit).Order("created DESC").Find(&sessions); tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	res := make(map[string][]fingerprint.Fingerprint)
+	for _, session := range sessions {
+		if fp, ok := session.Fingerprint(); ok {
+			res[session.Vehicle] = append(res[session.Vehicle], fp)
+		}
+	}
+
+	return res, nil
+}
+
+// Departures returns the times a vehicle left after its sessions since the given time.
+// The finish time is used for sessions recorded before disconnect times were available.
+func Departures(db *gorm.DB, vehicle string, since time.Time) ([]time.Time, error) {
+	var sessions Sessions
+	if tx := db.Where("vehicle = ? AND finished >= ?", vehicle, since).Order("created").Find(&sessions); tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	res := make([]time.Time, 0, len(sessions))
+	for _, s := range sessions {
+		if !s.Disconnected.IsZero() {
+			res = append(res, s.Disconnected)
+		} else if !s.Finished.IsZero() {
+			res = append(res, s.Finished)
+		}
+	}
+
+	return res, nil
+}
+
 func (s *DB) ClosePendingSessionsInHistory(chargeMeterTotal float64) error {
 	var res Sessions
 	if tx := s.db.Find(&res, map[string]any{"finished": "0001-01-01 00:00:00+00:00", "Loadpoint": s.name}); tx.Error != nil {
@@ -0,0 +1,45 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFingerprints(t *testing.T) {
+	require.NoError(t, db.NewInstance("sqlite", ":memory:"))
+
+	s, err := NewStore("lp-1", db.Instance)
+	require.NoError(t, err)
+
+	now := time.Now()
+
+	for i := range 5 {
+		for _, vehicle := range []string{"Car", "Other"} {
+			session := Session{Vehicle: vehicle, Created: now.Add(time.Duration(i) * time.Hour), Phases: new(i + 1), MaxCurrent: new(16.0), RampUp: new(time.Minute), StandbyPower: new(0.0)}
+			require.NoError(t, db.Instance.Create(&session).Error)
+		}
+	}
+
+	// excluded sessions
+	for _, session := range []Session{
+		{Vehicle: "Car", Created: now.Add(time.Hour), Detection: DetectionFingerprint, Phases: new(9), MaxCurrent: new(16.0), RampUp: new(time.Minute), StandbyPower: new(0.0)},
+		{Vehicle: "Car", Created: now.Add(time.Hour), Detection: DetectionUnknown, Phases: new(9), MaxCurrent: new(16.0), RampUp: new(time.Minute), StandbyPower: new(0.0)},
+		{Vehicle: "Car", Created: now.Add(time.Hour)},
+	} {
+		require.NoError(t, db.Instance.Create(&session).Error)
+	}
+
+	res, err := s.Fingerprints(2)
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+
+	for _, vehicle := range []string{"Car", "Other"} {
+		require.Len(t, res[vehicle], 2)
+		assert.Equal(t, 5, res[vehicle][0].Phases)
+		assert.Equal(t, 4, res[vehicle][1].Phases)
+	}
+}
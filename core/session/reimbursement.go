@@ -0,0 +1,230 @@
+package session
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/server/db/settings"
+	"github.com/evcc-io/evcc/util/export"
+	"gorm.io/gorm"
+)
+
+// Reimbursement is a session in a company-car reimbursement report
+type Reimbursement struct {
+	Created     time.Time `json:"created"`
+	Finished    time.Time `json:"finished"`
+	Loadpoint   string    `json:"loadpoint"`
+	Identifier  string    `json:"identifier"`
+	Vehicle     string    `json:"vehicle"`
+	MeterStart  *float64  `json:"meterStart" csv:"Meter Start (kWh)"`
+	MeterStop   *float64  `json:"meterStop" csv:"Meter Stop (kWh)"`
+	Energy      float64   `json:"energy" csv:"Energy (kWh)"`
+	PricePerKWh *float64  `json:"pricePerKWh" csv:"Price/kWh"`
+	Amount      *float64  `json:"amount" csv:"Amount"`
+}
+
+// Reimbursements is a reimbursement report
+type Reimbursements []Reimbursement
+
+var _ export.Writer = (*Reimbursements)(nil)
+
+// Write implements the export.Writer interface
+func (t *Reimbursements) Write(ww export.RowWriter) error {
+	return export.WriteStructSlice(ww, t, export.Config{
+		I18nPrefix: "sessions.reimbursement.csv",
+	})
+}
+
+// NewReimbursement converts a session. Energy is taken from the charge meter readings if available.
+// The price is the flat rate if given, else the tariff applied while charging.
+func NewReimbursement(s Session, rate *float64) Reimbursement {
+	res := Reimbursement{
+		Created:     s.Created,
+		Finished:    s.Finished,
+		Loadpoint:   s.Loadpoint,
+		Identifier:  s.Identifier,
+		Vehicle:     s.Vehicle,
+		MeterStart:  s.MeterStart,
+		MeterStop:   s.MeterStop,
+		Energy:      s.ChargedEnergy,
+		PricePerKWh: s.PricePerKWh,
+	}
+
+	if s.MeterStart != nil && s.MeterStop != nil && *s.MeterStop >= *s.MeterStart {
+		res.Energy = *s.MeterStop - *s.MeterStart
+	}
+
+	if p := s.Tariffs.PricePerKWh(); p != nil {
+		res.PricePerKWh = p
+	}
+
+	if rate != nil {
+		res.PricePerKWh = rate
+	}
+
+	if res.PricePerKWh != nil {
+		res.Amount = new(res.Energy * *res.PricePerKWh)
+	}
+
+	return res
+}
+
+// ReimbursementQuery selects the sessions of a reimbursement report
+type ReimbursementQuery struct {
+	Vehicle    string    // vehicle title, optional
+	Identifier string    // charger identifier, optional
+	Month      time.Time // any time within the month
+	Rate       *float64  // flat rate per kWh, optional
+}
+
+// Reimbursements returns the finished sessions of the given month
+func (q ReimbursementQuery) Reimbursements(db *gorm.DB) (Reimbursements, error) {
+	from := time.Date(q.Month.Year(), q.Month.Month(), 1, 0, 0, 0, 0, q.Month.Location())
+	to := from.AddDate(0, 1, 0)
+
+	tx := db.Where("created >= ? AND created < ? AND finished > ? AND charged_kwh >= 0.05", from, to, time.Time{})
+	if q.Vehicle != "" {
+		tx = tx.Where("vehicle = ?", q.Vehicle)
+	}
+	if q.Identifier != "" {
+		tx = tx.Where("identifier = ?", q.Identifier)
+	}
+
+	var sessions Sessions
+	if err := tx.Order("created").Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+
+	res := make(Reimbursements, 0, len(sessions))
+	for _, s := range sessions {
+		res = append(res, NewReimbursement(s, q.Rate))
+	}
+
+	return res, nil
+}
+
+// ReimbursementSummary is the signed monthly report including its sessions
+type ReimbursementSummary struct {
+	Vehicle        string         `json:"vehicle,omitempty"`
+	Identifier     string         `json:"identifier,omitempty"`
+	Month          string         `json:"month"` // YYYY-MM
+	Currency       string         `json:"currency,omitempty"`
+	Rate           *float64       `json:"rate,omitempty"`
+	Sessions       int            `json:"sessions"`
+	Energy         float64        `json:"energy"` // kWh
+	Amount         float64        `json:"amount"`
+	Created        time.Time      `json:"created"`
+	Reimbursements Reimbursements `json:"reimbursements"`
+	Signature      string         `json:"signature,omitempty"` // base64 ed25519 signature of the summary json without signature
+}
+
+// Summary returns the unsigned report summary
+func (q ReimbursementQuery) Summary(res Reimbursements, currency string) ReimbursementSummary {
+	summary := ReimbursementSummary{
+		Vehicle:        q.Vehicle,
+		Identifier:     q.Identifier,
+		Month:          q.Month.Format("2006-01"),
+		Currency:       currency,
+		Rate:           q.Rate,
+		Sessions:       len(res),
+		Created:        time.Now().Truncate(time.Second),
+		Reimbursements: res,
+	}
+
+	for _, r := range res {
+		summary.Energy += r.Energy
+		if r.Amount != nil {
+			summary.Amount += *r.Amount
+		}
+	}
+
+	return summary
+}
+
+func (s ReimbursementSummary) message() ([]byte, error) {
+	s.Signature = ""
+	return json.Marshal(s)
+}
+
+// Sign signs the summary with the given key
+func (s *ReimbursementSummary) Sign(key ed25519.PrivateKey) error {
+	b, err := s.message()
+	if err == nil {
+		s.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(key, b))
+	}
+	return err
+}
+
+// Verify verifies the summary signature with the given public key
+func (s ReimbursementSummary) Verify(key ed25519.PublicKey) bool {
+	sig, err := base64.StdEncoding.DecodeString(s.Signature)
+	if err != nil {
+		return false
+	}
+
+	b, err := s.message()
+	return err == nil && ed25519.Verify(key, b, sig)
+}
+
+var signingKeyMu sync.Mutex
+
+// SigningKey returns the reimbursement signing key. The key is created on first use and
+// its public key is published for third parties to verify reimbursement reports.
+// A lost or corrupt key is an error, it is never replaced silently.
+func SigningKey() (ed25519.PrivateKey, error) {
+	signingKeyMu.Lock()
+	defer signingKeyMu.Unlock()
+
+	pub, err := settings.String(keys.ReimbursementPublicKey)
+	if err != nil && !errors.Is(err, settings.ErrNotFound) {
+		return nil, err
+	}
+
+	s, err := settings.String(keys.ReimbursementKey)
+	switch {
+	case errors.Is(err, settings.ErrNotFound) && pub != "":
+		return nil, errors.New("reimbursement signing key missing")
+
+	case errors.Is(err, settings.ErrNotFound):
+		_, key, err := ed25519.GenerateKey(nil)
+		if err != nil {
+			return nil, err
+		}
+
+		settings.SetString(keys.ReimbursementKey, base64.StdEncoding.EncodeToString(key.Seed()))
+		settings.SetString(keys.ReimbursementPublicKey, publicKey(key))
+
+		return key, settings.Persist()
+
+	case err != nil:
+		return nil, err
+	}
+
+	seed, err := base64.StdEncoding.DecodeString(s)
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return nil, errors.New("reimbursement signing key corrupt")
+	}
+
+	key := ed25519.NewKeyFromSeed(seed)
+
+	switch pub {
+	case "":
+		settings.SetString(keys.ReimbursementPublicKey, publicKey(key))
+		return key, settings.Persist()
+	case publicKey(key):
+	default:
+		return nil, errors.New("reimbursement signing key does not match public key")
+	}
+
+	return key, nil
+}
+
+// publicKey returns the base64 encoded public key
+func publicKey(key ed25519.PrivateKey) string {
+	return base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey))
+}
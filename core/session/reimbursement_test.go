@@ -0,0 +1,131 @@
+package session
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/server/db/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTariffs(t *testing.T) {
+	now := time.Now()
+
+	var tt Tariffs
+	tt = tt.Add(now, 0.30, 1)
+	tt = tt.Add(now.Add(time.Minute), 0.30, 1)
+	tt = tt.Add(now.Add(15*time.Minute), 0.20, 2)
+
+	require.Len(t, tt, 2)
+	assert.Equal(t, 2.0, tt[0].Energy)
+	assert.Equal(t, now.Add(time.Minute), tt[0].End)
+	assert.Equal(t, 4.0, tt.Energy())
+	assert.InDelta(t, 0.25, *tt.PricePerKWh(), 1e-6)
+
+	assert.Nil(t, Tariffs(nil).PricePerKWh())
+}
+
+func TestReimbursement(t *testing.T) {
+	require.NoError(t, db.NewInstance("sqlite", ":memory:"))
+	require.NoError(t, db.Instance.AutoMigrate(new(Session)))
+
+	month := time.Date(2026, 9, 1, 0, 0, 0, 0, time.Local)
+
+	for i, s := range []Session{
+		// metered session with tariff slots
+		{Vehicle: "Car", Identifier: "card", MeterStart: new(100.0), MeterStop: new(110.0), ChargedEnergy: 9.8, PricePerKWh: new(0.1),
+			Tariffs: Tariffs{{Price: 0.30, Energy: 5}, {Price: 0.20, Energy: 5}}},
+		// unmetered session without tariff slots
+		{Vehicle: "Car", Identifier: "card", ChargedEnergy: 5, PricePerKWh: new(0.4)},
+		// other vehicle
+		{Vehicle: "Other", ChargedEnergy: 5},
+	} {
+		s.Created = month.AddDate(0, 0, i+1)
+		s.Finished = s.Created.Add(time.Hour)
+		require.NoError(t, db.Instance.Create(&s).Error)
+	}
+
+	// session of next month
+	require.NoError(t, db.Instance.Create(&Session{Vehicle: "Car", Created: month.AddDate(0, 1, 0), Finished: month.AddDate(0, 1, 1), ChargedEnergy: 5}).Error)
+
+	q := ReimbursementQuery{Vehicle: "Car", Month: month.AddDate(0, 0, 10)}
+
+	res, err := q.Reimbursements(db.Instance)
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+
+	assert.Equal(t, 10.0, res[0].Energy)
+	assert.InDelta(t, 0.25, *res[0].PricePerKWh, 1e-6)
+	assert.InDelta(t, 2.5, *res[0].Amount, 1e-6)
+	assert.Equal(t, 5.0, res[1].Energy)
+	assert.InDelta(t, 2.0, *res[1].Amount, 1e-6)
+
+	summary := q.Summary(res, "EUR")
+	assert.Equal(t, "2026-09", summary.Month)
+	assert.Equal(t, 2, summary.Sessions)
+	assert.Equal(t, 15.0, summary.Energy)
+	assert.InDelta(t, 4.5, summary.Amount, 1e-6)
+
+	key, err := SigningKey()
+	require.NoError(t, err)
+
+	// key is persistent
+	key2, err := SigningKey()
+	require.NoError(t, err)
+	assert.Equal(t, key, key2)
+
+	pub := key.Public().(ed25519.PublicKey)
+
+	require.NoError(t, summary.Sign(key))
+	assert.True(t, summary.Verify(pub))
+
+	summary.Amount = 100
+	assert.False(t, summary.Verify(pub))
+	summary.Amount = 4.5
+
+	// sessions are signed
+	require.True(t, summary.Verify(pub))
+	summary.Reimbursements[0].Energy = 20
+	assert.False(t, summary.Verify(pub))
+
+	// flat rate
+	q.Rate = new(0.3)
+	res, err = q.Reimbursements(db.Instance)
+	require.NoError(t, err)
+	assert.InDelta(t, 3.0, *res[0].Amount, 1e-6)
+	assert.InDelta(t, 1.5, *res[1].Amount, 1e-6)
+}
+
+func TestSigningKeyLost(t *testing.T) {
+	require.NoError(t, db.NewInstance("sqlite", ":memory:"))
+
+	key, err := SigningKey()
+	require.NoError(t, err)
+
+	// corrupt key
+	settings.SetString(keys.ReimbursementKey, "foo")
+	_, err = SigningKey()
+	assert.Error(t, err)
+
+	// replaced key
+	_, other, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	settings.SetString(keys.ReimbursementKey, base64.StdEncoding.EncodeToString(other.Seed()))
+	_, err = SigningKey()
+	assert.Error(t, err)
+
+	// missing key
+	require.NoError(t, settings.Delete(keys.ReimbursementKey))
+	_, err = SigningKey()
+	assert.Error(t, err)
+
+	settings.SetString(keys.ReimbursementKey, base64.StdEncoding.EncodeToString(key.Seed()))
+	key2, err := SigningKey()
+	require.NoError(t, err)
+	assert.Equal(t, key, key2)
+}
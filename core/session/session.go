@@ -3,6 +3,7 @@ package session
 import (
 	"time"
 
+	"github.com/evcc-io/evcc/core/fingerprint"
 	"github.com/evcc-io/evcc/util/export"
 )
 
@@ -11,6 +12,7 @@ type Session struct {
 	ID                   uint           `json:"id" csv:"-" gorm:"primarykey"`
 	Created              time.Time      `json:"created"`
 	Finished             time.Time      `json:"finished"`
+	Disconnected         time.Time      `json:"disconnected,omitzero" csv:"-"`
 	Loadpoint            string         `json:"loadpoint"`
 	Identifier           string         `json:"identifier"`
 	Vehicle              string         `json:"vehicle"`
@@ -28,6 +30,47 @@ type Session struct {
 	Co2PerKWh            *float64       `json:"co2PerKWh" csv:"CO2/kWh (gCO2eq)" gorm:"column:co2_per_kwh"`
 	ReferencePricePerKWh *float64       `json:"referencePricePerKWh" csv:"Reference Price/kWh" gorm:"column:reference_price_per_kwh"`
 	ReferenceCo2PerKWh   *float64       `json:"referenceCo2PerKWh" csv:"Reference CO2/kWh (gCO2eq)" gorm:"column:reference_co2_per_kwh"`
+	Tariffs              Tariffs        `json:"tariffs,omitempty" csv:"-" gorm:"serializer:json"`
+
+	// charging fingerprint
+	Phases       *int           `json:"phases,omitempty" csv:"-"`
+	MaxCurrent   *float64       `json:"maxCurrent,omitempty" csv:"-"`
+	RampUp       *time.Duration `json:"rampUp,omitempty" csv:"-"`
+	StandbyPower *float64       `json:"standbyPower,omitempty" csv:"-"`
+
+	// vehicle identification by fingerprint
+	Detection           string   `json:"detection,omitempty" csv:"-"`
+	DetectionConfidence *float64 `json:"detectionConfidence,omitempty" csv:"-"`
+}
+
+const (
+	// DetectionFingerprint marks vehicles identified by charging fingerprint
+	DetectionFingerprint = "fingerprint"
+
+	// DetectionUnknown marks sessions whose fingerprint matched no learned vehicle
+	DetectionUnknown = "unknown"
+)
+
+// SetFingerprint stores the charging fingerprint
+func (s *Session) SetFingerprint(fp fingerprint.Fingerprint) {
+	s.Phases = &fp.Phases
+	s.MaxCurrent = &fp.MaxCurrent
+	s.RampUp = &fp.RampUp
+	s.StandbyPower = &fp.StandbyPower
+}
+
+// Fingerprint returns the charging fingerprint if available
+func (s *Session) Fingerprint() (fingerprint.Fingerprint, bool) {
+	if s.Phases == nil || s.MaxCurrent == nil || s.RampUp == nil || s.StandbyPower == nil {
+		return fingerprint.Fingerprint{}, false
+	}
+
+	return fingerprint.Fingerprint{
+		Phases:       *s.Phases,
+		MaxCurrent:   *s.MaxCurrent,
+		RampUp:       *s.RampUp,
+		StandbyPower: *s.StandbyPower,
+	}, true
 }
 
 // Sessions is a list of sessions
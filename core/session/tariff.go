@@ -0,0 +1,49 @@
+package session
+
+import "time"
+
+// Tariff is the energy charged at a single price
+type Tariff struct {
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Price  float64   `json:"price"`  // price per kWh
+	Energy float64   `json:"energy"` // kWh
+}
+
+// Tariffs is the session's charged energy by applied price
+type Tariffs []Tariff
+
+// Add adds energy charged at the given price. Consecutive energy at the same price is merged.
+func (t Tariffs) Add(ts time.Time, price, energy float64) Tariffs {
+	if n := len(t); n > 0 && t[n-1].Price == price {
+		t[n-1].End = ts
+		t[n-1].Energy += energy
+		return t
+	}
+
+	return append(t, Tariff{Start: ts, End: ts, Price: price, Energy: energy})
+}
+
+// Energy returns the total energy in kWh
+func (t Tariffs) Energy() float64 {
+	var res float64
+	for _, s := range t {
+		res += s.Energy
+	}
+	return res
+}
+
+// PricePerKWh returns the energy-weighted price or nil if no energy has been charged
+func (t Tariffs) PricePerKWh() *float64 {
+	energy := t.Energy()
+	if energy == 0 {
+		return nil
+	}
+
+	var cost float64
+	for _, s := range t {
+		cost += s.Price * s.Energy
+	}
+
+	return new(cost / energy)
+}
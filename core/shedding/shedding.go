@@ -0,0 +1,157 @@
+// Package shedding switches off sheddable loadpoints in priority order when the site exceeds its limits.
+package shedding
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/benbjohnson/clock"
+)
+
+// DefaultDelay is the minimum time between restoring devices if not configured
+const DefaultDelay = time.Minute
+
+// Config is the load shedding configuration
+type Config struct {
+	Devices    []string      `json:"devices"`              // loadpoint names, shed first to last
+	GridLimit  float64       `json:"gridLimit,omitempty"`  // max grid import in W, 0 = no limit
+	Hysteresis float64       `json:"hysteresis,omitempty"` // headroom in W required in addition to the device power for restoring
+	Delay      time.Duration `json:"delay,omitempty"`      // min time between restoring devices in seconds
+}
+
+type config struct {
+	Devices    []string `json:"devices"`
+	GridLimit  float64  `json:"gridLimit,omitempty"`
+	Hysteresis float64  `json:"hysteresis,omitempty"`
+	Delay      int64    `json:"delay,omitempty"`
+}
+
+func (c Config) MarshalJSON() ([]byte, error) {
+	return json.Marshal(config{
+		Devices:    c.Devices,
+		GridLimit:  c.GridLimit,
+		Hysteresis: c.Hysteresis,
+		Delay:      int64(c.Delay.Seconds()),
+	})
+}
+
+func (c *Config) UnmarshalJSON(data []byte) error {
+	var res config
+	if err := json.Unmarshal(data, &res); err != nil {
+		return err
+	}
+
+	*c = Config{
+		Devices:    res.Devices,
+		GridLimit:  res.GridLimit,
+		Hysteresis: res.Hysteresis,
+		Delay:      time.Duration(res.Delay) * time.Second,
+	}
+
+	return nil
+}
+
+// Configured returns true if any sheddable devices are configured
+func (c Config) Configured() bool {
+	return len(c.Devices) > 0
+}
+
+// Validate validates the configuration
+func (c Config) Validate() error {
+	if c.GridLimit < 0 {
+		return fmt.Errorf("invalid grid limit: %g", c.GridLimit)
+	}
+	if c.Hysteresis < 0 {
+		return fmt.Errorf("invalid hysteresis: %g", c.Hysteresis)
+	}
+	if c.Delay < 0 {
+		return fmt.Errorf("invalid delay: %v", c.Delay)
+	}
+
+	for i, name := range c.Devices {
+		if name == "" {
+			return errors.New("missing device name")
+		}
+		if slices.Contains(c.Devices[:i], name) {
+			return fmt.Errorf("duplicate device: %s", name)
+		}
+	}
+
+	return nil
+}
+
+// shed is a device switched off by the manager
+type shed struct {
+	name  string
+	power float64 // power when shed
+}
+
+// Manager keeps track of shed devices. Devices are restored in reverse order.
+type Manager struct {
+	clock   clock.Clock
+	shed    []shed
+	updated time.Time // last shed or restore
+}
+
+// New creates a load shedding manager
+func New(clock clock.Clock) *Manager {
+	return &Manager{
+		clock: clock,
+	}
+}
+
+// Shed returns the names of the shed devices
+func (m *Manager) Shed() []string {
+	res := make([]string, 0, len(m.shed))
+	for _, s := range m.shed {
+		res = append(res, s.name)
+	}
+	return res
+}
+
+// Update sheds or restores devices for the given headroom in W (negative if a limit is exceeded)
+// and the devices' current power by name. It returns the names of the shed devices.
+func (m *Manager) Update(c Config, headroom float64, power map[string]float64) []string {
+	// restore devices no longer configured
+	m.shed = slices.DeleteFunc(m.shed, func(s shed) bool {
+		return !slices.Contains(c.Devices, s.name)
+	})
+
+	now := m.clock.Now()
+
+	if headroom < 0 {
+		for _, name := range c.Devices {
+			if headroom >= 0 {
+				break
+			}
+
+			// shedding idle devices does not help
+			p := power[name]
+			if p <= 0 || slices.ContainsFunc(m.shed, func(s shed) bool { return s.name == name }) {
+				continue
+			}
+
+			m.shed = append(m.shed, shed{name: name, power: p})
+			m.updated = now
+			headroom += p
+		}
+
+		return m.Shed()
+	}
+
+	delay := c.Delay
+	if delay == 0 {
+		delay = DefaultDelay
+	}
+
+	// restore last shed device if it fits
+	if n := len(m.shed); n > 0 && now.Sub(m.updated) >= delay && headroom >= m.shed[n-1].power+c.Hysteresis {
+		m.shed = m.shed[:n-1]
+		m.updated = now
+	}
+
+	return m.Shed()
+}
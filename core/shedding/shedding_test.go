@@ -0,0 +1,80 @@
+package shedding
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Config{Devices: []string{"heater", "socket"}, GridLimit: 10e3}.Validate())
+	assert.Error(t, Config{Devices: []string{"heater", "heater"}}.Validate())
+	assert.Error(t, Config{Devices: []string{""}}.Validate())
+	assert.Error(t, Config{GridLimit: -1}.Validate())
+}
+
+func TestJson(t *testing.T) {
+	c := Config{Devices: []string{"heater"}, GridLimit: 10e3, Delay: 2 * time.Minute}
+
+	b, err := json.Marshal(c)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"devices":["heater"],"gridLimit":10000,"delay":120}`, string(b))
+
+	var res Config
+	require.NoError(t, json.Unmarshal(b, &res))
+	assert.Equal(t, c, res)
+}
+
+func TestShedRestore(t *testing.T) {
+	clck := clock.NewMock()
+	m := New(clck)
+
+	c := Config{
+		Devices:    []string{"heater", "socket", "wallbox"},
+		Hysteresis: 500,
+	}
+
+	power := map[string]float64{"heater": 2000, "socket": 0, "wallbox": 11000}
+
+	// no limit exceeded
+	assert.Empty(t, m.Update(c, 1000, power))
+
+	// idle devices are skipped, shed until headroom is positive
+	assert.Equal(t, []string{"heater", "wallbox"}, m.Update(c, -3000, power))
+
+	// still exceeded, nothing left to shed
+	assert.Equal(t, []string{"heater", "wallbox"}, m.Update(c, -100, power))
+
+	// restore requires delay
+	assert.Equal(t, []string{"heater", "wallbox"}, m.Update(c, 20e3, power))
+
+	// restore requires device power plus hysteresis
+	clck.Add(time.Minute)
+	assert.Equal(t, []string{"heater", "wallbox"}, m.Update(c, 11000, power))
+
+	// restore in reverse order
+	assert.Equal(t, []string{"heater"}, m.Update(c, 11500, power))
+
+	clck.Add(30 * time.Second)
+	assert.Equal(t, []string{"heater"}, m.Update(c, 5000, power))
+
+	clck.Add(30 * time.Second)
+	assert.Empty(t, m.Update(c, 5000, power))
+}
+
+func TestUnconfigured(t *testing.T) {
+	m := New(clock.NewMock())
+
+	power := map[string]float64{"heater": 2000, "socket": 1000}
+
+	c := Config{Devices: []string{"heater", "socket"}}
+	assert.Equal(t, []string{"heater", "socket"}, m.Update(c, -2500, power))
+
+	// removed devices are restored immediately
+	c.Devices = []string{"socket"}
+	assert.Equal(t, []string{"socket"}, m.Update(c, -100, power))
+}
@@ -8,20 +8,26 @@ import (
 	"math"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/benbjohnson/clock"
 	"github.com/cenkalti/backoff/v4"
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/cmd/shutdown"
-	"github.com/evcc-io/evcc/core/circuit"
+	"github.com/evcc-io/evcc/core/audit"
+	"github.com/evcc-io/evcc/core/calendar"
 	"github.com/evcc-io/evcc/core/coordinator"
+	"github.com/evcc-io/evcc/core/departure"
 	"github.com/evcc-io/evcc/core/keys"
 	"github.com/evcc-io/evcc/core/loadpoint"
 	"github.com/evcc-io/evcc/core/metrics"
 	"github.com/evcc-io/evcc/core/planner"
 	"github.com/evcc-io/evcc/core/prioritizer"
 	"github.com/evcc-io/evcc/core/session"
+	coresettings "github.com/evcc-io/evcc/core/settings"
+	"github.com/evcc-io/evcc/core/shedding"
 	"github.com/evcc-io/evcc/core/site"
 	"github.com/evcc-io/evcc/core/soc"
 	"github.com/evcc-io/evcc/core/types"
@@ -33,6 +39,7 @@ import (
 	"github.com/evcc-io/evcc/tariff"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/config"
+	"github.com/evcc-io/evcc/util/geo"
 	"github.com/evcc-io/evcc/util/modbus"
 	"github.com/evcc-io/evcc/util/telemetry"
 	"github.com/samber/lo"
@@ -59,11 +66,18 @@ type Site struct {
 	sync.RWMutex
 	log *util.Logger
 
+	// identity
+	id       string                // site id, empty for the default site
+	primary  *Site                 // default site sharing vehicles and coordinator, nil for the default site
+	settings coresettings.Settings // site settings namespace
+
 	// configuration
 	Title         string       `mapstructure:"title"`         // UI title
 	Voltage       float64      `mapstructure:"voltage"`       // Operating voltage. 230V for Germany.
 	ResidualPower float64      `mapstructure:"residualPower"` // PV meter only: household usage. Grid meter: household safety margin
 	Meters        MetersConfig `mapstructure:"meters"`        // Meter references
+	Backup        BackupConfig `mapstructure:"backup"`        // Grid outage backup mode
+	CircuitRef    string       `mapstructure:"circuit"`       // Root circuit, defaults to the only root circuit for the default site
 
 	// meters
 	circuit        api.Circuit                // Circuit
@@ -79,6 +93,10 @@ type Site struct {
 	dimmed         *bool
 	curtailPercent *int
 
+	// last successfully applied HEMS state for auditing, kept across failed attempts
+	auditDimmed         *bool
+	auditCurtailPercent *int
+
 	// battery settings
 	prioritySoc             float64  // prefer battery up to this Soc
 	bufferSoc               float64  // continue charging on battery above this Soc
@@ -105,6 +123,18 @@ type Site struct {
 	collectors map[string]*metrics.Collector // keyed by meter ref
 	tariffSlot time.Time                     // last persisted tariff slot
 
+	home            geo.Home        // home location
+	vehicleArriving map[string]bool // vehicles inside arrival radius by name
+
+	loadShedding shedding.Config   // load shedding configuration
+	shedder      *shedding.Manager // shed loadpoints
+
+	outageG    func() (bool, error) // grid outage signal
+	gridOutage bool                 // grid outage detected, backup mode active
+
+	reloadMeters  atomic.Bool // site meters need to be re-resolved from their refs
+	reloadCircuit atomic.Bool // root circuit needs to be re-evaluated
+
 	// cached state
 	gridPower                float64                     // Grid power
 	pvPower                  float64                     // PV power
@@ -150,28 +180,74 @@ func NewSiteFromConfig(other map[string]any) (*Site, error) {
 	return site, nil
 }
 
+// NewNamedSiteFromConfig creates an additional site. It has its own settings namespace
+// and shares vehicles and the coordinator with the primary site.
+func NewNamedSiteFromConfig(id string, primary *Site, other map[string]any) (*Site, error) {
+	site := NewSite()
+	site.id = id
+	site.primary = primary
+	site.log = util.NewLogger("site-" + id)
+	site.settings = coresettings.NewDatabaseSettingsAdapter("site." + id + ".")
+
+	if err := util.DecodeOther(other, site); err != nil {
+		return nil, err
+	}
+
+	// operating voltage is shared by all loadpoints
+	if site.Voltage != Voltage {
+		site.log.WARN.Printf("voltage: using %.0fV of the default site", Voltage)
+		site.Voltage = Voltage
+	}
+
+	// add meters from config
+	site.restoreMetersAndTitle()
+
+	return site, nil
+}
+
+// ID returns the site id, empty for the default site
+func (site *Site) ID() string {
+	return site.id
+}
+
+// entityName returns the metrics entity name of a site-wide measurement
+func (site *Site) entityName(name string) string {
+	if site.id == "" {
+		return name
+	}
+	return site.id + "." + name
+}
+
 func (site *Site) Boot(log *util.Logger, loadpoints []*Loadpoint, tariffs *tariff.Tariffs) error {
 	site.loadpoints = loadpoints
 	site.tariffs = tariffs
 
-	handler := config.Vehicles()
-	site.coordinator = coordinator.New(log, config.Instances(handler.Devices()))
-	handler.Subscribe(site.updateVehicles)
+	// vehicles are shared by all sites
+	if site.primary != nil {
+		site.coordinator = site.primary.coordinator
+	} else {
+		handler := config.Vehicles()
+		site.coordinator = coordinator.New(log, config.Instances(handler.Devices()))
+		handler.Subscribe(site.updateVehicles)
+	}
+	site.subscribeDevices()
 
 	site.prioritizer = prioritizer.New(log)
 	site.stats = NewStats()
 
-	me, err := metrics.NewCollector(metrics.Home, metrics.Home, metrics.Home)
+	me, err := metrics.NewCollector(metrics.Home, site.entityName(metrics.Home), metrics.Home)
 	if err != nil {
 		return err
 	}
 	site.collectors[metrics.Home] = me
 
 	// reload history in the UI on each persisted 15min slot instead of polling
-	metrics.OnPersist = func(slot time.Time) { site.publish(keys.HistoryUpdated, slot) }
+	if site.primary == nil {
+		metrics.OnPersist = func(slot time.Time) { site.publish(keys.HistoryUpdated, slot) }
+	}
 
 	// upload telemetry on shutdown
-	if telemetry.Enabled() {
+	if telemetry.Enabled() && site.primary == nil {
 		shutdown.Register(func() {
 			telemetry.Persist(log)
 		})
@@ -200,10 +276,15 @@ func (site *Site) Boot(log *util.Logger, loadpoints []*Loadpoint, tariffs *tarif
 	}
 
 	// circuit
-	if c := circuit.Root(); c != nil {
+	if c := site.rootCircuit(); c != nil {
 		site.circuit = c
 	}
 
+	// grid outage signal
+	if err := site.configureBackup(); err != nil {
+		return fmt.Errorf("backup: %w", err)
+	}
+
 	// grid meter
 	if site.Meters.GridMeterRef != "" {
 		dev, err := config.Meters().ByName(site.Meters.GridMeterRef)
@@ -237,14 +318,14 @@ func (site *Site) Boot(log *util.Logger, loadpoints []*Loadpoint, tariffs *tarif
 	}
 
 	// solar forecast collector (mirrors PV history shape, used for scale lookup)
-	fc, err := metrics.NewCollector(metrics.Forecast, metrics.Forecast, metrics.Forecast)
+	fc, err := metrics.NewCollector(metrics.Forecast, site.entityName(metrics.Forecast), metrics.Forecast)
 	if err != nil {
 		return err
 	}
 	site.collectors[metrics.Forecast] = fc
 
 	// temperature forecast collector (populated when TariffUsageTemperature is configured)
-	tc, err := metrics.NewCollector(metrics.Temperature, metrics.Temperature, metrics.Temperature)
+	tc, err := metrics.NewCollector(metrics.Temperature, site.entityName(metrics.Temperature), metrics.Temperature)
 	if err != nil {
 		return err
 	}
@@ -327,7 +408,9 @@ func NewSite() *Site {
 	site := &Site{
 		log:        util.NewLogger("site"),
 		Voltage:    230, // V
+		settings:   coresettings.NewDatabaseSettingsAdapter(""),
 		collectors: make(map[string]*metrics.Collector),
+		shedder:    shedding.New(clock.New()),
 	}
 
 	return site
@@ -338,25 +421,25 @@ func (site *Site) restoreMetersAndTitle() {
 	if testing.Testing() {
 		return
 	}
-	if v, err := settings.String(keys.Title); err == nil {
+	if v, err := site.settings.String(keys.Title); err == nil {
 		site.Title = v
 	}
-	if v, err := settings.String(keys.GridMeter); err == nil && v != "" {
+	if v, err := site.settings.String(keys.GridMeter); err == nil && v != "" {
 		site.Meters.GridMeterRef = v
 	}
-	if v, err := settings.String(keys.PvMeters); err == nil && v != "" {
+	if v, err := site.settings.String(keys.PvMeters); err == nil && v != "" {
 		site.Meters.PVMetersRef = append(site.Meters.PVMetersRef, filterConfigurable(strings.Split(v, ","))...)
 	}
-	if v, err := settings.String(keys.BatteryMeters); err == nil && v != "" {
+	if v, err := site.settings.String(keys.BatteryMeters); err == nil && v != "" {
 		site.Meters.BatteryMetersRef = append(site.Meters.BatteryMetersRef, filterConfigurable(strings.Split(v, ","))...)
 	}
-	if v, err := settings.String(keys.ExtMeters); err == nil && v != "" {
+	if v, err := site.settings.String(keys.ExtMeters); err == nil && v != "" {
 		site.Meters.ExtMetersRef = append(site.Meters.ExtMetersRef, filterConfigurable(strings.Split(v, ","))...)
 	}
-	if v, err := settings.String(keys.AuxMeters); err == nil && v != "" {
+	if v, err := site.settings.String(keys.AuxMeters); err == nil && v != "" {
 		site.Meters.AuxMetersRef = append(site.Meters.AuxMetersRef, filterConfigurable(strings.Split(v, ","))...)
 	}
-	if v, err := settings.String(keys.ConsumerMeters); err == nil && v != "" {
+	if v, err := site.settings.String(keys.ConsumerMeters); err == nil && v != "" {
 		site.Meters.ConsumerMetersRef = append(site.Meters.ConsumerMetersRef, filterConfigurable(strings.Split(v, ","))...)
 	}
 }
@@ -366,50 +449,62 @@ func (site *Site) restoreSettings() error {
 	if testing.Testing() {
 		return nil
 	}
-	if v, err := settings.Float(keys.BufferSoc); err == nil {
+	if v, err := site.settings.Float(keys.BufferSoc); err == nil {
 		if err := site.SetBufferSoc(v); err != nil && !errors.Is(err, ErrBatteryNotConfigured) {
 			return err
 		}
 	}
-	if v, err := settings.Float(keys.BufferStartSoc); err == nil {
+	if v, err := site.settings.Float(keys.BufferStartSoc); err == nil {
 		if err := site.SetBufferStartSoc(v); err != nil && !errors.Is(err, ErrBatteryNotConfigured) {
 			return err
 		}
 	}
-	if v, err := settings.Float(keys.PrioritySoc); err == nil {
+	if v, err := site.settings.Float(keys.PrioritySoc); err == nil {
 		if err := site.SetPrioritySoc(v); err != nil && !errors.Is(err, ErrBatteryNotConfigured) {
 			return err
 		}
 	}
-	if v, err := settings.Bool(keys.BatteryDischargeControl); err == nil {
+	if v, err := site.settings.Bool(keys.BatteryDischargeControl); err == nil {
 		if err := site.SetBatteryDischargeControl(v); err != nil && !errors.Is(err, ErrBatteryControlNotAvailable) {
 			return err
 		}
 	}
-	if v, err := settings.Bool(keys.BatteryGridDischarge); err == nil {
+	if v, err := site.settings.Bool(keys.BatteryGridDischarge); err == nil {
 		if err := site.SetBatteryGridDischarge(v); err != nil && !errors.Is(err, ErrBatteryControlNotAvailable) {
 			return err
 		}
 	}
-	if v, err := settings.Float(keys.ResidualPower); err == nil {
+	if v, err := site.settings.Float(keys.ResidualPower); err == nil {
 		if err := site.SetResidualPower(v); err != nil {
 			return err
 		}
 	}
-	if v, err := settings.Float(keys.BatteryGridChargeLimit); err == nil {
+	if v, err := site.settings.Float(keys.BatteryGridChargeLimit); err == nil {
 		if err := site.SetBatteryGridChargeLimit(&v); err != nil && !errors.Is(err, ErrBatteryControlNotAvailable) {
 			return err
 		}
 	}
-	if v, err := settings.Float(keys.GridExportLimit); err == nil {
+	if v, err := site.settings.Float(keys.GridExportLimit); err == nil {
 		if err := site.SetGridExportLimit(v); err != nil {
 			return err
 		}
 	}
-	if v, err := settings.Bool(keys.SolarAdjusted); err == nil {
+	if v, err := site.settings.Bool(keys.SolarAdjusted); err == nil {
 		site.SetSolarAdjusted(v)
 	}
-	if v, err := settings.String(keys.OptimizerChargingStrategy); err == nil && v != "" {
+	var home geo.Home
+	if err := site.settings.Json(keys.Home, &home); err == nil {
+		if err := site.SetHome(home); err != nil {
+			site.log.WARN.Printf("home: %v", err)
+		}
+	}
+	var loadShedding shedding.Config
+	if err := site.settings.Json(keys.LoadShedding, &loadShedding); err == nil {
+		if err := site.SetLoadShedding(loadShedding); err != nil {
+			site.log.WARN.Printf("load shedding: %v", err)
+		}
+	}
+	if v, err := site.settings.String(keys.OptimizerChargingStrategy); err == nil && v != "" {
 		if err := site.SetOptimizerChargingStrategy(v); err != nil {
 			site.log.WARN.Printf("optimizer charging strategy: %v", err)
 		}
@@ -418,9 +513,11 @@ func (site *Site) restoreSettings() error {
 	site.publish(keys.OptimizerChargingStrategies, optimizerChargingStrategies)
 
 	// drop legacy accumulator-based forecast settings (now stored via metrics collector)
-	settings.Delete("solarAccForecast")
-	settings.Delete("solarAccYield")
-	settings.Delete("solarAccDay")
+	if site.primary == nil {
+		settings.Delete("solarAccForecast")
+		settings.Delete("solarAccYield")
+		settings.Delete("solarAccDay")
+	}
 
 	return nil
 }
@@ -613,6 +710,9 @@ func (site *Site) collectMeters(key string, meters []config.Device[api.Meter]) [
 				site.log.ERROR.Printf("%s %d return energy: %v", key, i+1, err)
 			}
 		}
+
+		// power quality
+		site.updateQuality(fmt.Sprintf("%s %d", key, i+1), meter, &mm[i])
 	}
 
 	var wg sync.WaitGroup
@@ -683,6 +783,7 @@ func (site *Site) updatePvMeters() {
 		if err := c.AddEnergy(mm[i].Energy, mm[i].ReturnEnergy, mm[i].Power); err != nil {
 			site.log.ERROR.Printf("persist pv %d energy: %v", i+1, err)
 		}
+		site.addQuality(fmt.Sprintf("pv %d", i+1), c, mm[i])
 	}
 }
 
@@ -779,6 +880,7 @@ func (site *Site) updateBatteryMeters() {
 		if err := c.AddEnergy(mm[i].ReturnEnergy, mm[i].Energy, -mm[i].Power); err != nil {
 			site.log.ERROR.Printf("persist battery %d energy: %v", i+1, err)
 		}
+		site.addQuality(fmt.Sprintf("battery %d", i+1), c, mm[i])
 		if mm[i].Soc != nil {
 			c.SetSocTemp(*mm[i].Soc, false)
 		}
@@ -827,6 +929,7 @@ func (site *Site) addMeterEnergy(meters []config.Device[api.Meter], mm []types.M
 		if err := c.AddEnergy(mm[i].Energy, mm[i].ReturnEnergy, mm[i].Power); err != nil {
 			site.log.ERROR.Printf("persist meter %s energy: %v", ref, err)
 		}
+		site.addQuality("meter "+ref, c, mm[i])
 	}
 }
 
@@ -937,8 +1040,12 @@ func (site *Site) updateGridMeter() error {
 		}
 	}
 
+	// grid power quality
+	site.updateQuality("grid", meter, &mm)
+
 	if c, ok := site.collectors[site.gridMeter.Config().Name]; ok {
 		c.AddEnergy(mm.Energy, mm.ReturnEnergy, mm.Power)
+		site.addQuality("grid", c, mm)
 	}
 
 	site.publish(keys.Grid, mm)
@@ -1099,6 +1206,9 @@ func (site *Site) reservedPVPower(lp updater) float64 {
 func (site *Site) update(lp updater) {
 	site.log.DEBUG.Println("----")
 
+	// swap reconfigured devices before using them
+	site.reloadDevices()
+
 	// smart cost and battery mode handling
 	consumption, err := site.tariffRates(api.TariffUsagePlanner)
 	if err != nil {
@@ -1144,6 +1254,10 @@ func (site *Site) update(lp updater) {
 		wg.Wait()
 	}
 
+	// limit loadpoints during grid outage before updating the loadpoint,
+	// independent of site power which may be unavailable while the grid is down
+	site.updateBackup()
+
 	// prioritize if possible
 	var flexiblePower float64
 	if lp != nil && lp.GetMode() == api.ModePV {
@@ -1168,6 +1282,9 @@ func (site *Site) update(lp updater) {
 		greenShareHome := site.greenShare(0, homePower)
 		greenShareLoadpoints := site.greenShare(nonChargePower, nonChargePower+totalChargePower)
 
+		// switch sheddable loadpoints before updating the loadpoint
+		site.updateLoadShedding()
+
 		// TODO
 		if lp != nil {
 			// reserve surplus claimed by higher-priority loadpoints that are starting up (#31194)
@@ -1193,6 +1310,11 @@ func (site *Site) update(lp updater) {
 		}
 	} else {
 		site.log.ERROR.Println(err)
+
+		// loadpoint is not updated, still enforce the backup power budget
+		if lp, ok := lp.(*Loadpoint); ok && site.gridOutage {
+			lp.enforceBackupPower()
+		}
 	}
 
 	// smart grid charging
@@ -1217,6 +1339,11 @@ func (site *Site) update(lp updater) {
 	// re-evaluate against the updated loadpoint state
 	site.publishSuggestions()
 
+	// notify about vehicles approaching home
+	if site.primary == nil {
+		site.updateVehiclePositions()
+	}
+
 	site.stats.Update(site)
 }
 
@@ -1243,6 +1370,7 @@ func (site *Site) prepare() {
 	site.publish(keys.SolarAdjusted, site.solarAdjusted)
 	site.publish(keys.ResidualPower, site.GetResidualPower())
 	site.publish(keys.GridExportLimit, site.GetGridExportLimit())
+	site.publish(keys.GridOutage, site.gridOutage)
 	site.publish(keys.SmartCostAvailable, site.isDynamicTariff(api.TariffUsagePlanner))
 	site.publish(keys.SmartFeedInPriorityAvailable, site.isDynamicTariff(api.TariffUsageFeedIn))
 
@@ -1253,10 +1381,14 @@ func (site *Site) prepare() {
 		site.publish(keys.SmartCostType, nil)
 	}
 
-	site.publishVehicles()
 	site.publishTariffs(0, 0)
-	vehicle.Publish = site.publishVehicles
-	vehicle.ClearPlanLocks = site.clearPlanLocks
+
+	// vehicles are published by the default site
+	if site.primary == nil {
+		site.publishVehicles()
+		vehicle.Publish = site.publishVehicles
+		vehicle.ClearPlanLocks = site.clearPlanLocks
+	}
 }
 
 // pushEvent queues the event in the value stream. The cache attaches its state
@@ -1268,8 +1400,12 @@ func (site *Site) pushEvent(ev messenger.Event) {
 		return
 	}
 
+	ev.Site = site.id
+
 	site.valueChan <- util.Param{Val: util.Snapshot(func(state []util.Param) {
-		ev.State = state
+		ev.State = lo.Filter(state, func(p util.Param, _ int) bool {
+			return p.Site == site.id
+		})
 		pushChan <- ev
 	})}
 }
@@ -1289,6 +1425,7 @@ func (site *Site) Prepare(valueChan chan<- util.Param, pushChan chan<- messenger
 	// use ch.Out for reading
 	go func() {
 		for p := range ch.Out {
+			p.Site = site.id
 			valueChan <- p
 		}
 	}()
@@ -1297,7 +1434,11 @@ func (site *Site) Prepare(valueChan chan<- util.Param, pushChan chan<- messenger
 
 	site.prepare()
 
-	lpDevices := config.Loadpoints().Devices()
+	// loadpoints of additional sites are not in the device registry
+	var lpDevices []config.Device[loadpoint.API]
+	if site.primary == nil {
+		lpDevices = config.Loadpoints().Devices()
+	}
 
 	for id, lp := range site.loadpoints {
 		lpUIChan := make(chan util.Param)
@@ -1322,6 +1463,7 @@ func (site *Site) Prepare(valueChan chan<- util.Param, pushChan chan<- messenger
 			site.valueChan <- util.Param{Loadpoint: &id, Key: keys.Name, Val: lpDevices[id].Config().Name}
 		}
 
+		lp.auditTarget = audit.LoadpointTarget(id)
 		lp.Prepare(site, lpUIChan, lpPushChan, site.lpUpdateChan)
 	}
 }
@@ -1351,19 +1493,70 @@ func (site *Site) Run(stopC chan struct{}, interval time.Duration) {
 		site.log.INFO.Printf("interval <%.0fs can lead to unexpected behavior, see https://docs.evcc.io/docs/reference/configuration/interval", max.Seconds())
 	}
 
+	// loadpoints are updated in turn, chargers must not fall back in between
+	cycle := interval * time.Duration(max(1, len(site.loadpoints)))
+	for _, lp := range site.loadpoints {
+		if err := lp.setWatchdogCycle(cycle); err != nil {
+			lp.log.WARN.Printf("watchdog: %v", err)
+		}
+	}
+
 	loadpointChan := make(chan updater)
 	if site.IsConfigured() {
 		go site.loopLoadpoints(loadpointChan)
 	}
 
-	site.update(<-loadpointChan) // start immediately
+	// pushed device values trigger an update, at most every pushInterval
+	pushC := util.Notifications()
+
+	var (
+		updated   time.Time
+		pushTimer <-chan time.Time
+		pushLp    updater
+	)
+
+	update := func(lp updater) {
+		site.update(lp)
+		updated = time.Now()
+	}
+
+	update(<-loadpointChan) // start immediately
+
+	// protect circuits from overload between site updates
+	go site.protectCircuits(stopC)
+
+	// vehicles are shared, the default site keeps them up to date
+	if site.primary == nil {
+		// refresh vehicle positions outside the site loop
+		go site.coordinator.RunPositions(stopC, vehiclePositionInterval)
+
+		// keep plans in sync with trip calendars
+		go calendar.New(site.Vehicles(), site.GetHome).Run(stopC, calendarInterval)
+
+		// create soft plans for predicted departures
+		go departure.New(site.Vehicles()).Run(stopC, predictionInterval)
+	}
 
 	for tick := time.Tick(interval); ; {
 		select {
 		case <-tick:
-			site.update(<-loadpointChan)
+			update(<-loadpointChan)
 		case lp := <-site.lpUpdateChan:
-			site.update(lp)
+			update(lp)
+		case name := <-pushC:
+			lp, ok := site.pushed(name)
+			if !ok || pushTimer != nil {
+				continue
+			}
+			pushLp = lp
+			pushTimer = time.After(pushInterval - time.Since(updated))
+		case <-pushTimer:
+			pushTimer = nil
+			if pushLp == nil {
+				update(<-loadpointChan)
+			} else {
+				update(pushLp)
+			}
 		case <-stopC:
 			return
 		}
@@ -3,6 +3,8 @@ package site
 import (
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/shedding"
+	"github.com/evcc-io/evcc/util/geo"
 )
 
 // publisher gives access to the site's publish function
@@ -22,6 +24,16 @@ type API interface {
 	GetTitle() string
 	SetTitle(string)
 
+	// GetHome returns the home location
+	GetHome() geo.Home
+	// SetHome sets the home location
+	SetHome(geo.Home) error
+
+	// GetLoadShedding returns the load shedding configuration
+	GetLoadShedding() shedding.Config
+	// SetLoadShedding sets the load shedding configuration
+	SetLoadShedding(shedding.Config) error
+
 	// Config
 	GetGridMeterRef() string
 	SetGridMeterRef(string)
@@ -11,7 +11,6 @@ import (
 	"github.com/evcc-io/evcc/core/keys"
 	"github.com/evcc-io/evcc/core/loadpoint"
 	"github.com/evcc-io/evcc/core/site"
-	"github.com/evcc-io/evcc/server/db/settings"
 	"github.com/evcc-io/evcc/util/config"
 	"github.com/evcc-io/evcc/util/sponsor"
 	"github.com/samber/lo"
@@ -62,7 +61,7 @@ func (site *Site) SetTitle(title string) {
 
 	site.Title = title
 	site.publish(keys.SiteTitle, title)
-	settings.SetString(keys.Title, title)
+	site.settings.SetString(keys.Title, title)
 }
 
 // GetGridMeterRef returns the GridMeterRef
@@ -78,7 +77,8 @@ func (site *Site) SetGridMeterRef(ref string) {
 	defer site.Unlock()
 
 	site.Meters.GridMeterRef = ref
-	settings.SetString(keys.GridMeter, ref)
+	site.settings.SetString(keys.GridMeter, ref)
+	site.reloadMeters.Store(true)
 }
 
 // GetPVMeterRefs returns the PvMeterRef
@@ -94,7 +94,8 @@ func (site *Site) SetPVMeterRefs(ref []string) {
 	defer site.Unlock()
 
 	site.Meters.PVMetersRef = ref
-	settings.SetString(keys.PvMeters, strings.Join(filterConfigurable(ref), ","))
+	site.settings.SetString(keys.PvMeters, strings.Join(filterConfigurable(ref), ","))
+	site.reloadMeters.Store(true)
 }
 
 // GetBatteryMeterRefs returns the BatteryMeterRef
@@ -110,7 +111,8 @@ func (site *Site) SetBatteryMeterRefs(ref []string) {
 	defer site.Unlock()
 
 	site.Meters.BatteryMetersRef = ref
-	settings.SetString(keys.BatteryMeters, strings.Join(filterConfigurable(ref), ","))
+	site.settings.SetString(keys.BatteryMeters, strings.Join(filterConfigurable(ref), ","))
+	site.reloadMeters.Store(true)
 }
 
 // GetAuxMeterRefs returns the AuxMeterRef
@@ -126,7 +128,8 @@ func (site *Site) SetAuxMeterRefs(ref []string) {
 	defer site.Unlock()
 
 	site.Meters.AuxMetersRef = ref
-	settings.SetString(keys.AuxMeters, strings.Join(filterConfigurable(ref), ","))
+	site.settings.SetString(keys.AuxMeters, strings.Join(filterConfigurable(ref), ","))
+	site.reloadMeters.Store(true)
 }
 
 // GetConsumerMeterRefs returns the ConsumerMeterRef
@@ -142,7 +145,8 @@ func (site *Site) SetConsumerMeterRefs(ref []string) {
 	defer site.Unlock()
 
 	site.Meters.ConsumerMetersRef = ref
-	settings.SetString(keys.ConsumerMeters, strings.Join(filterConfigurable(ref), ","))
+	site.settings.SetString(keys.ConsumerMeters, strings.Join(filterConfigurable(ref), ","))
+	site.reloadMeters.Store(true)
 }
 
 // GetExtMeterRefs returns the ExtMeterRef
@@ -158,7 +162,8 @@ func (site *Site) SetExtMeterRefs(ref []string) {
 	defer site.Unlock()
 
 	site.Meters.ExtMetersRef = ref
-	settings.SetString(keys.ExtMeters, strings.Join(filterConfigurable(ref), ","))
+	site.settings.SetString(keys.ExtMeters, strings.Join(filterConfigurable(ref), ","))
+	site.reloadMeters.Store(true)
 }
 
 // GetBatterySoc returns the current battery soc
@@ -240,7 +245,7 @@ func (site *Site) SetPrioritySoc(soc float64) error {
 
 	if site.prioritySoc != soc {
 		site.prioritySoc = soc
-		settings.SetFloat(keys.PrioritySoc, site.prioritySoc)
+		site.settings.SetFloat(keys.PrioritySoc, site.prioritySoc)
 		site.publish(keys.PrioritySoc, site.prioritySoc)
 	}
 
@@ -275,7 +280,7 @@ func (site *Site) SetBufferSoc(soc float64) error {
 
 	if site.bufferSoc != soc {
 		site.bufferSoc = soc
-		settings.SetFloat(keys.BufferSoc, site.bufferSoc)
+		site.settings.SetFloat(keys.BufferSoc, site.bufferSoc)
 		site.publish(keys.BufferSoc, site.bufferSoc)
 	}
 
@@ -306,7 +311,7 @@ func (site *Site) SetBufferStartSoc(soc float64) error {
 
 	if site.bufferStartSoc != soc {
 		site.bufferStartSoc = soc
-		settings.SetFloat(keys.BufferStartSoc, site.bufferStartSoc)
+		site.settings.SetFloat(keys.BufferStartSoc, site.bufferStartSoc)
 		site.publish(keys.BufferStartSoc, site.bufferStartSoc)
 	}
 
@@ -336,7 +341,7 @@ func (site *Site) SetResidualPower(power float64) error {
 
 	if site.ResidualPower != power {
 		site.ResidualPower = power
-		settings.SetFloat(keys.ResidualPower, site.ResidualPower)
+		site.settings.SetFloat(keys.ResidualPower, site.ResidualPower)
 		site.publish(keys.ResidualPower, site.ResidualPower)
 	}
 
@@ -365,7 +370,7 @@ func (site *Site) SetGridExportLimit(power float64) error {
 
 	if changed {
 		site.log.DEBUG.Println("set grid export limit:", power)
-		settings.SetFloat(keys.GridExportLimit, power)
+		site.settings.SetFloat(keys.GridExportLimit, power)
 		site.publish(keys.GridExportLimit, power)
 
 		// re-run the optimizer so the new limit takes effect immediately
@@ -402,7 +407,7 @@ func (site *Site) SetBatteryDischargeControl(val bool) error {
 
 	if site.batteryDischargeControl != val {
 		site.batteryDischargeControl = val
-		settings.SetBool(keys.BatteryDischargeControl, val)
+		site.settings.SetBool(keys.BatteryDischargeControl, val)
 		site.publish(keys.BatteryDischargeControl, val)
 	}
 
@@ -429,7 +434,7 @@ func (site *Site) SetBatteryGridDischarge(val bool) error {
 
 	if site.batteryGridDischarge != val {
 		site.batteryGridDischarge = val
-		settings.SetBool(keys.BatteryGridDischarge, val)
+		site.settings.SetBool(keys.BatteryGridDischarge, val)
 		site.publish(keys.BatteryGridDischarge, val)
 	}
 
@@ -452,7 +457,7 @@ func (site *Site) SetSolarAdjusted(val bool) {
 
 	if site.solarAdjusted != val {
 		site.solarAdjusted = val
-		settings.SetBool(keys.SolarAdjusted, val)
+		site.settings.SetBool(keys.SolarAdjusted, val)
 		site.publish(keys.SolarAdjusted, val)
 	}
 }
@@ -477,10 +482,10 @@ func (site *Site) SetBatteryGridChargeLimit(val *float64) error {
 		site.batteryGridChargeLimit = val
 
 		if val == nil {
-			settings.SetString(keys.BatteryGridChargeLimit, "")
+			site.settings.SetString(keys.BatteryGridChargeLimit, "")
 			site.publish(keys.BatteryGridChargeLimit, nil)
 		} else {
-			settings.SetFloat(keys.BatteryGridChargeLimit, *val)
+			site.settings.SetFloat(keys.BatteryGridChargeLimit, *val)
 			site.publish(keys.BatteryGridChargeLimit, *val)
 		}
 	}
@@ -515,7 +520,7 @@ func (site *Site) SetOptimizerChargingStrategy(strategy string) error {
 
 	if changed {
 		site.log.DEBUG.Println("set optimizer charging strategy:", strategy)
-		settings.SetString(keys.OptimizerChargingStrategy, strategy)
+		site.settings.SetString(keys.OptimizerChargingStrategy, strategy)
 		site.publish(keys.OptimizerChargingStrategy, strategy)
 
 		// re-run the optimizer so the new strategy takes effect immediately
@@ -0,0 +1,119 @@
+package core
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/messenger"
+	"github.com/evcc-io/evcc/plugin"
+	"github.com/evcc-io/evcc/util"
+)
+
+const (
+	evGridOutage  = "gridoutage"  // grid outage detected
+	evGridRestore = "gridrestore" // grid restored after outage
+)
+
+// BackupConfig is the grid outage detection and backup mode configuration
+type BackupConfig struct {
+	MinVoltage float64        `mapstructure:"minVoltage"` // grid is down if all grid meter phase voltages are below this value
+	Outage     *plugin.Config `mapstructure:"outage"`     // explicit grid outage signal, e.g. inverter status
+	MaxPower   float64        `mapstructure:"maxPower"`   // loadpoint power budget in W during grid outage, 0 = pause
+}
+
+// Configured returns true if grid outage detection is configured
+func (c BackupConfig) Configured() bool {
+	return c.MinVoltage > 0 || c.Outage != nil
+}
+
+// configureBackup creates the grid outage signal
+func (site *Site) configureBackup() error {
+	if site.Backup.Outage == nil {
+		return nil
+	}
+
+	ctx := util.WithLogger(context.TODO(), site.log)
+
+	outageG, err := site.Backup.Outage.BoolGetter(ctx)
+	if err != nil {
+		return err
+	}
+
+	site.outageG = outageG
+
+	return nil
+}
+
+// detectGridOutage returns true if the grid is down
+func (site *Site) detectGridOutage() (bool, error) {
+	if site.outageG != nil {
+		return site.outageG()
+	}
+
+	if site.Backup.MinVoltage == 0 || site.gridMeter == nil {
+		return false, nil
+	}
+
+	phaseMeter, ok := api.Cap[api.PhaseVoltages](site.gridMeter.Instance())
+	if !ok {
+		return false, nil
+	}
+
+	// the grid meter may be unavailable while the grid is down, assume an outage
+	u1, u2, u3, err := phaseMeter.Voltages()
+	if err != nil {
+		site.log.WARN.Printf("grid outage: voltages: %v", err)
+		return true, nil
+	}
+
+	return max(u1, u2, u3) < site.Backup.MinVoltage, nil
+}
+
+// updateBackup detects grid outages and limits the loadpoints to the backup power budget while the grid is down
+func (site *Site) updateBackup() {
+	if !site.Backup.Configured() {
+		return
+	}
+
+	if outage, err := site.detectGridOutage(); err != nil {
+		site.log.ERROR.Printf("grid outage: %v", err)
+	} else if outage != site.gridOutage {
+		site.gridOutage = outage
+		site.publish(keys.GridOutage, outage)
+
+		if outage {
+			site.log.WARN.Println("grid outage: backup mode active")
+			site.pushEvent(messenger.Event{Event: evGridOutage})
+		} else {
+			site.log.INFO.Println("grid outage: grid restored")
+			site.pushEvent(messenger.Event{Event: evGridRestore})
+		}
+	}
+
+	if !site.gridOutage {
+		for _, lp := range site.loadpoints {
+			lp.setBackupPower(nil)
+		}
+		return
+	}
+
+	// loadpoints with higher priority claim the budget first
+	lps := slices.Clone(site.loadpoints)
+	slices.SortStableFunc(lps, func(a, b *Loadpoint) int {
+		return cmp.Compare(b.EffectivePriority(), a.EffectivePriority())
+	})
+
+	// the granted limit is reserved regardless of the actual charge power, which may rise up to it
+	budget := site.Backup.MaxPower
+	for _, lp := range lps {
+		var limit float64
+		if lp.GetStatus() != api.StatusA {
+			limit = max(0, budget)
+		}
+		lp.setBackupPower(&limit)
+		budget -= limit
+	}
+}
@@ -0,0 +1,96 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/api/implement"
+	"github.com/evcc-io/evcc/plugin"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestBackup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	voltage := 230.0
+	var voltageErr error
+
+	grid := struct {
+		api.Meter
+		api.PhaseVoltages
+	}{
+		api.NewMockMeter(ctrl),
+		implement.PhaseVoltages(func() (float64, float64, float64, error) {
+			return voltage, voltage, voltage, voltageErr
+		}),
+	}
+
+	lp1 := &Loadpoint{log: util.NewLogger("lp1"), priority: 1, status: api.StatusC, chargePower: 3000}
+	lp2 := &Loadpoint{log: util.NewLogger("lp2"), status: api.StatusB, chargePower: 2000}
+	lp3 := &Loadpoint{log: util.NewLogger("lp3"), priority: 2, status: api.StatusA}
+
+	site := &Site{
+		log:        util.NewLogger("foo"),
+		gridMeter:  config.NewStaticDevice[api.Meter](config.Named{Name: "grid"}, grid),
+		loadpoints: []*Loadpoint{lp2, lp1, lp3},
+		Backup: BackupConfig{
+			MinVoltage: 100,
+			MaxPower:   4000,
+		},
+	}
+
+	// grid available
+	site.updateBackup()
+	assert.False(t, site.gridOutage)
+	assert.Nil(t, lp1.backupPower)
+	assert.Nil(t, lp2.backupPower)
+
+	// grid down, higher priority loadpoint claims the granted budget first,
+	// disconnected loadpoints don't claim any budget
+	voltage = 0
+	site.updateBackup()
+	assert.True(t, site.gridOutage)
+	require.NotNil(t, lp1.backupPower)
+	require.NotNil(t, lp2.backupPower)
+	require.NotNil(t, lp3.backupPower)
+	assert.Equal(t, 4000.0, *lp1.backupPower)
+	assert.Equal(t, 0.0, *lp2.backupPower)
+	assert.Equal(t, 0.0, *lp3.backupPower)
+
+	// grid restored
+	voltage = 230
+	site.updateBackup()
+	assert.False(t, site.gridOutage)
+	assert.Nil(t, lp1.backupPower)
+	assert.Nil(t, lp2.backupPower)
+
+	// grid meter unavailable, possibly down
+	voltageErr = errors.New("timeout")
+	site.updateBackup()
+	assert.True(t, site.gridOutage)
+	require.NotNil(t, lp1.backupPower)
+}
+
+func TestBackupOutageSignal(t *testing.T) {
+	lp := &Loadpoint{log: util.NewLogger("lp"), chargePower: 2000}
+
+	var outage bool
+
+	site := &Site{
+		log:        util.NewLogger("foo"),
+		loadpoints: []*Loadpoint{lp},
+		outageG:    func() (bool, error) { return outage, nil },
+		Backup:     BackupConfig{Outage: new(plugin.Config)},
+	}
+
+	outage = true
+	site.updateBackup()
+	assert.True(t, site.gridOutage)
+	require.NotNil(t, lp.backupPower)
+	assert.Equal(t, 0.0, *lp.backupPower)
+}
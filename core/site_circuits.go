@@ -7,6 +7,7 @@ import (
 
 	"github.com/cenkalti/backoff/v4"
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/audit"
 	"github.com/evcc-io/evcc/core/keys"
 	"github.com/evcc-io/evcc/util/config"
 	"github.com/evcc-io/evcc/util/modbus"
@@ -92,6 +93,10 @@ func (site *Site) dimMeters(dim bool) error {
 
 	if errs == nil {
 		site.dimmed = &dim
+		if site.auditDimmed == nil || *site.auditDimmed != dim {
+			audit.Record(audit.Source{Kind: audit.HEMS}, audit.SiteTarget, keys.Dimmed, site.auditDimmed, dim)
+			site.auditDimmed = &dim
+		}
 	}
 
 	return errs
@@ -138,6 +143,10 @@ func (site *Site) curtailPV(percent *int) error {
 
 	if errs == nil {
 		site.curtailPercent = new(*percent)
+		if site.auditCurtailPercent == nil || *site.auditCurtailPercent != *percent {
+			audit.Record(audit.Source{Kind: audit.HEMS}, audit.SiteTarget, keys.CurtailPercent, site.auditCurtailPercent, *percent)
+			site.auditCurtailPercent = new(*percent)
+		}
 	}
 
 	return errs
@@ -0,0 +1,178 @@
+package core
+
+import (
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/circuit"
+	"github.com/evcc-io/evcc/core/metrics"
+	"github.com/evcc-io/evcc/util/config"
+)
+
+// subscribeDevices hot-reloads meters, chargers, circuits and tariffs on configuration changes
+func (site *Site) subscribeDevices() {
+	config.Meters().Subscribe(func(op config.Operation, dev config.Device[api.Meter]) {
+		site.reloadMeters.Store(true)
+		site.reloadLoadpoints("meter", dev.Config().Name)
+	})
+
+	config.Chargers().Subscribe(func(op config.Operation, dev config.Device[api.Charger]) {
+		site.reloadLoadpoints("charger", dev.Config().Name)
+	})
+
+	config.Circuits().Subscribe(func(op config.Operation, dev config.Device[api.Circuit]) {
+		switch op {
+		case config.OpUpdate:
+			if previous, ok := config.Previous(dev); ok {
+				circuit.Reparent(previous, dev.Instance())
+			}
+		case config.OpDelete:
+			circuit.Remove(dev.Instance())
+		}
+
+		site.reloadCircuit.Store(true)
+		site.reloadLoadpoints("circuit", dev.Config().Name)
+	})
+
+	config.Tariffs().Subscribe(site.updateTariffs)
+}
+
+// reloadLoadpoints requests loadpoints using the named device to re-resolve their devices
+func (site *Site) reloadLoadpoints(class, name string) {
+	for _, lp := range site.loadpoints {
+		if lp.usesDevice(class, name) {
+			lp.requestReload()
+		}
+	}
+}
+
+// updateTariffs swaps reconfigured or removed tariffs in all roles
+func (site *Site) updateTariffs(op config.Operation, dev config.Device[api.Tariff]) {
+	var previous, tariff api.Tariff
+
+	switch op {
+	case config.OpUpdate:
+		previous, _ = config.Previous(dev)
+		tariff = dev.Instance()
+
+	case config.OpDelete:
+		previous = dev.Instance()
+
+	default:
+		// new tariffs become active once referenced
+		return
+	}
+
+	site.Lock()
+	defer site.Unlock()
+
+	if site.tariffs != nil {
+		site.tariffs.Replace(previous, tariff)
+	}
+}
+
+// reloadDevices applies pending meter and circuit changes. Runs from the site loop.
+func (site *Site) reloadDevices() {
+	if site.reloadMeters.CompareAndSwap(true, false) {
+		site.reloadMeterDevices()
+	}
+
+	if site.reloadCircuit.CompareAndSwap(true, false) {
+		site.reloadRootCircuit()
+	}
+}
+
+// reloadMeterDevices re-resolves the site meters from their refs
+func (site *Site) reloadMeterDevices() {
+	site.RLock()
+	refs := site.Meters
+	site.RUnlock()
+
+	var grid config.Device[api.Meter]
+	if gm := site.meterDevices(metrics.Grid, []string{refs.GridMeterRef}); len(gm) > 0 {
+		grid = gm[0]
+	}
+
+	pv := site.meterDevices(metrics.PV, refs.PVMetersRef)
+	battery := site.meterDevices(metrics.Battery, refs.BatteryMetersRef)
+	ext := site.meterDevices(metrics.Meter, refs.ExtMetersRef)
+	aux := site.meterDevices(metrics.Consumer, refs.AuxMetersRef)
+	consumer := site.meterDevices(metrics.Consumer, refs.ConsumerMetersRef)
+
+	site.Lock()
+	defer site.Unlock()
+
+	site.gridMeter = grid
+	site.pvMeters = pv
+	site.batteryMeters = battery
+	site.extMeters = ext
+	site.auxMeters = aux
+	site.consumerMeters = consumer
+
+	site.log.DEBUG.Println("reloaded meters")
+}
+
+// meterDevices resolves meter refs and ensures their energy collectors exist
+func (site *Site) meterDevices(group string, refs []string) []config.Device[api.Meter] {
+	var res []config.Device[api.Meter]
+
+	for _, ref := range refs {
+		if ref == "" {
+			continue
+		}
+
+		dev, err := config.Meters().ByName(ref)
+		if err != nil {
+			site.log.ERROR.Printf("reload meter: %v", err)
+			continue
+		}
+
+		res = append(res, dev)
+
+		if _, ok := site.collectors[ref]; ok {
+			continue
+		}
+
+		title := deviceTitleOrName(dev)
+		if group == metrics.Grid {
+			title = metrics.Grid
+		}
+
+		me, err := metrics.NewCollector(group, ref, title)
+		if err != nil {
+			site.log.ERROR.Printf("reload meter: %v", err)
+			continue
+		}
+		site.collectors[ref] = me
+	}
+
+	return res
+}
+
+// rootCircuit returns the site's root circuit. Without reference, only the default site uses the root circuit.
+func (site *Site) rootCircuit() api.Circuit {
+	if site.CircuitRef == "" {
+		if site.primary != nil {
+			return nil
+		}
+		return circuit.Root()
+	}
+
+	dev, err := config.Circuits().ByName(site.CircuitRef)
+	if err != nil {
+		site.log.ERROR.Printf("circuit: %v", err)
+		return nil
+	}
+
+	return dev.Instance()
+}
+
+// reloadRootCircuit re-evaluates the root circuit and attaches the HEMS
+func (site *Site) reloadRootCircuit() {
+	site.Lock()
+	defer site.Unlock()
+
+	site.circuit = site.rootCircuit()
+
+	if site.circuit != nil && site.hems != nil {
+		site.circuit.SetHEMS(site.hems)
+	}
+}
@@ -0,0 +1,104 @@
+package core
+
+import (
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/messenger"
+	"github.com/evcc-io/evcc/util/config"
+	"github.com/evcc-io/evcc/util/geo"
+)
+
+const (
+	evVehicleArriving = "arriving" // vehicle entered arrival radius
+
+	// vehiclePositionInterval is the vehicle position refresh interval
+	vehiclePositionInterval = 5 * time.Minute
+)
+
+// GetHome returns the home location
+func (site *Site) GetHome() geo.Home {
+	site.RLock()
+	defer site.RUnlock()
+	return site.home
+}
+
+// SetHome sets the home location
+func (site *Site) SetHome(home geo.Home) error {
+	if err := home.Validate(); err != nil {
+		return err
+	}
+
+	site.Lock()
+	site.home = home
+	site.Unlock()
+
+	if site.coordinator != nil {
+		site.coordinator.SetHome(home.Fence)
+	}
+
+	if home.Configured() {
+		if err := site.settings.SetJson(keys.Home, home); err != nil {
+			return err
+		}
+	} else {
+		site.settings.SetString(keys.Home, "")
+	}
+
+	site.publish(keys.Home, home)
+
+	return nil
+}
+
+// updateVehiclePositions raises the arriving event for vehicles entering the arrival radius.
+// Positions are cached by the coordinator and refreshed outside the site loop.
+func (site *Site) updateVehiclePositions() {
+	home := site.GetHome()
+	if !home.Configured() {
+		return
+	}
+
+	arriving := make(map[string]bool)
+
+	for _, dev := range config.Vehicles().Devices() {
+		v := dev.Instance()
+
+		if !api.HasCap[api.VehiclePosition](v) {
+			continue
+		}
+
+		name := dev.Config().Name
+
+		// connected vehicles have already arrived
+		if site.coordinator.Owner(v) != nil {
+			arriving[name] = true
+			continue
+		}
+
+		lat, lon, ok := site.coordinator.Position(v)
+		if !ok {
+			if prev, ok := site.vehicleArriving[name]; ok {
+				arriving[name] = prev
+			}
+
+			continue
+		}
+
+		arriving[name] = home.Arriving(lat, lon)
+
+		// initial position does not count as arrival
+		if prev, ok := site.vehicleArriving[name]; ok && !prev && arriving[name] {
+			distance := home.Distance(lat, lon) / 1e3
+			site.log.INFO.Printf("vehicle arriving: %s (%.1fkm)", v.GetTitle(), distance)
+
+			site.pushEvent(messenger.Event{Event: evVehicleArriving, Attributes: map[string]any{
+				"vehicleName":     name,
+				"vehicleTitle":    v.GetTitle(),
+				"vehicleDistance": distance,
+			}})
+		}
+	}
+
+	site.vehicleArriving = arriving
+}
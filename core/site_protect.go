@@ -0,0 +1,33 @@
+package core
+
+import (
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/core/circuit"
+	"github.com/evcc-io/evcc/util/config"
+)
+
+// protectTick is the resolution of the circuits' overload protection intervals
+const protectTick = 250 * time.Millisecond
+
+// protectCircuits runs the circuits' fast overload protection independently of the site loop
+func (site *Site) protectCircuits(stopC chan struct{}) {
+	loads := site.loadpointsAsCircuitDevices()
+
+	for tick := time.Tick(protectTick); ; {
+		select {
+		case <-tick:
+		case <-stopC:
+			return
+		}
+
+		var wg sync.WaitGroup
+		for _, dev := range config.Circuits().Devices() {
+			if c, ok := dev.Instance().(*circuit.Circuit); ok {
+				wg.Go(func() { c.Protect(loads) })
+			}
+		}
+		wg.Wait()
+	}
+}
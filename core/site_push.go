@@ -0,0 +1,25 @@
+package core
+
+import (
+	"slices"
+	"time"
+)
+
+// pushInterval is the minimum time between updates triggered by pushed device values
+const pushInterval = 5 * time.Second
+
+// pushed returns the loadpoint to update for values pushed by the named device.
+// Site meters update the next loadpoint (nil). It returns false for devices not used by the site.
+func (site *Site) pushed(name string) (updater, bool) {
+	if name == site.GetGridMeterRef() || slices.Contains(site.GetPVMeterRefs(), name) || slices.Contains(site.GetBatteryMeterRefs(), name) {
+		return nil, true
+	}
+
+	for _, lp := range site.loadpoints {
+		if name == lp.GetChargerRef() || name == lp.GetMeterRef() {
+			return lp, true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,30 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPushed(t *testing.T) {
+	lp := &Loadpoint{ChargerRef: "wallbox", MeterRef: "wallbox-meter"}
+
+	site := &Site{loadpoints: []*Loadpoint{lp}}
+	site.Meters.GridMeterRef = "grid"
+	site.Meters.PVMetersRef = []string{"pv"}
+
+	for _, name := range []string{"grid", "pv"} {
+		res, ok := site.pushed(name)
+		assert.True(t, ok, name)
+		assert.Nil(t, res, name)
+	}
+
+	for _, name := range []string{"wallbox", "wallbox-meter"} {
+		res, ok := site.pushed(name)
+		assert.True(t, ok, name)
+		assert.Equal(t, lp, res, name)
+	}
+
+	_, ok := site.pushed("heater")
+	assert.False(t, ok)
+}
@@ -0,0 +1,46 @@
+package core
+
+import (
+	"errors"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/metrics"
+	"github.com/evcc-io/evcc/core/types"
+)
+
+// updateQuality reads the meter's optional power quality measurements
+func (site *Site) updateQuality(name string, meter api.Meter, mm *types.Measurement) {
+	read := func(quantity string, fun func() (float64, error)) *float64 {
+		f, err := fun()
+		if err == nil {
+			return &f
+		}
+		if !errors.Is(err, api.ErrNotAvailable) {
+			site.log.ERROR.Printf("%s %s: %v", name, quantity, err)
+		}
+		return nil
+	}
+
+	if m, ok := api.Cap[api.MeterReactivePower](meter); ok {
+		mm.ReactivePower = read("reactive power", m.ReactivePower)
+	}
+
+	if m, ok := api.Cap[api.MeterPowerFactor](meter); ok {
+		mm.PowerFactor = read("power factor", m.PowerFactor)
+	}
+
+	if m, ok := api.Cap[api.MeterFrequency](meter); ok {
+		mm.Frequency = read("frequency", m.Frequency)
+	}
+}
+
+// addQuality persists the meter's power quality measurements
+func (site *Site) addQuality(name string, c *metrics.Collector, mm types.Measurement) {
+	if mm.ReactivePower == nil && mm.PowerFactor == nil && mm.Frequency == nil {
+		return
+	}
+
+	if err := c.AddQuality(mm.ReactivePower, mm.PowerFactor, mm.Frequency); err != nil {
+		site.log.ERROR.Printf("persist %s quality: %v", name, err)
+	}
+}
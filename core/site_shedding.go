@@ -0,0 +1,111 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/core/shedding"
+	"github.com/evcc-io/evcc/util/config"
+)
+
+// GetLoadShedding returns the load shedding configuration
+func (site *Site) GetLoadShedding() shedding.Config {
+	site.RLock()
+	defer site.RUnlock()
+	return site.loadShedding
+}
+
+// SetLoadShedding sets the load shedding configuration
+func (site *Site) SetLoadShedding(c shedding.Config) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	names := site.loadpointNames()
+	for _, name := range c.Devices {
+		if !slices.Contains(names, name) {
+			return fmt.Errorf("unknown loadpoint: %s", name)
+		}
+	}
+
+	site.Lock()
+	site.loadShedding = c
+	site.Unlock()
+
+	if c.Configured() {
+		if err := site.settings.SetJson(keys.LoadShedding, c); err != nil {
+			return err
+		}
+	} else {
+		site.settings.SetString(keys.LoadShedding, "")
+	}
+
+	site.publish(keys.LoadShedding, c)
+
+	return nil
+}
+
+// loadpointNames returns the loadpoint names by loadpoint index
+func (site *Site) loadpointNames() []string {
+	devs := config.Loadpoints().Devices()
+
+	res := make([]string, len(site.loadpoints))
+	for i := range site.loadpoints {
+		if i < len(devs) {
+			res[i] = devs[i].Config().Name
+		}
+	}
+
+	return res
+}
+
+// sheddingHeadroom returns the power in W until the first of grid import, §14a consumption or circuit limit is exceeded
+func (site *Site) sheddingHeadroom(c shedding.Config) float64 {
+	res := math.Inf(1)
+
+	if site.gridMeter != nil {
+		if c.GridLimit > 0 {
+			res = min(res, c.GridLimit-site.gridPower)
+		}
+
+		if site.hems != nil {
+			if limit := site.hems.MaxConsumptionPower(); limit != nil && *limit > 0 {
+				res = min(res, *limit-site.gridPower)
+			}
+		}
+	}
+
+	if site.circuit != nil && site.circuit.GetMaxPower() > 0 {
+		res = min(res, site.circuit.GetMaxPower()-site.circuit.GetChargePower())
+	}
+
+	return res
+}
+
+// updateLoadShedding switches sheddable loadpoints off or on depending on the site's limits
+func (site *Site) updateLoadShedding() {
+	c := site.GetLoadShedding()
+	if !c.Configured() && len(site.shedder.Shed()) == 0 {
+		return
+	}
+
+	names := site.loadpointNames()
+
+	power := make(map[string]float64, len(names))
+	for i, lp := range site.loadpoints {
+		power[names[i]] = lp.GetChargePower()
+	}
+
+	headroom := site.sheddingHeadroom(c)
+	shed := site.shedder.Update(c, headroom, power)
+
+	if len(shed) > 0 {
+		site.log.DEBUG.Printf("load shedding: %v (headroom %.0fW)", shed, headroom)
+	}
+
+	for i, lp := range site.loadpoints {
+		lp.setShed(slices.Contains(shed, names[i]))
+	}
+}
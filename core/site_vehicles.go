@@ -13,27 +13,38 @@ import (
 )
 
 type planStruct struct {
-	Soc  int       `json:"soc"`
-	Time time.Time `json:"time"`
+	Soc       int       `json:"soc"`
+	Time      time.Time `json:"time"`
+	Predicted bool      `json:"predicted,omitempty"`
 }
 
 type vehicleStruct struct {
-	Title          string              `json:"title"`
-	Icon           string              `json:"icon,omitempty"`
-	Capacity       float64             `json:"capacity,omitempty"`
-	Phases         int                 `json:"phases,omitempty"`
-	Mode           api.ChargeMode      `json:"mode,omitempty"`
-	MinSoc         int                 `json:"minSoc,omitempty"`
-	LimitSoc       int                 `json:"limitSoc,omitempty"`
-	MinCurrent     float64             `json:"minCurrent,omitempty"`
-	MaxCurrent     float64             `json:"maxCurrent,omitempty"`
-	Priority       int                 `json:"priority,omitempty"`
-	Features       []string            `json:"features,omitempty"`
-	Plan           *planStruct         `json:"plan,omitempty"`
-	RepeatingPlans []api.RepeatingPlan `json:"repeatingPlans"`
-	PlanStrategy   api.PlanStrategy    `json:"planStrategy"`
+	Title               string                   `json:"title"`
+	Icon                string                   `json:"icon,omitempty"`
+	Capacity            float64                  `json:"capacity,omitempty"`
+	Phases              int                      `json:"phases,omitempty"`
+	Mode                api.ChargeMode           `json:"mode,omitempty"`
+	MinSoc              int                      `json:"minSoc,omitempty"`
+	LimitSoc            int                      `json:"limitSoc,omitempty"`
+	MinCurrent          float64                  `json:"minCurrent,omitempty"`
+	MaxCurrent          float64                  `json:"maxCurrent,omitempty"`
+	Priority            int                      `json:"priority,omitempty"`
+	Features            []string                 `json:"features,omitempty"`
+	Plan                *planStruct              `json:"plan,omitempty"`
+	RepeatingPlans      []api.RepeatingPlan      `json:"repeatingPlans"`
+	PlanStrategy        api.PlanStrategy         `json:"planStrategy"`
+	TripCalendar        *api.TripCalendar        `json:"tripCalendar,omitempty"`
+	DeparturePrediction *api.DeparturePrediction `json:"departurePrediction,omitempty"`
 }
 
+const (
+	// calendarInterval is the trip calendar refresh interval
+	calendarInterval = 15 * time.Minute
+
+	// predictionInterval is the departure prediction refresh interval
+	predictionInterval = time.Hour
+)
+
 // publishVehicles returns a list of vehicle titles
 func (site *Site) publishVehicles() {
 	vv := site.Vehicles().Settings()
@@ -50,26 +61,29 @@ func (site *Site) publishVehicles() {
 		var plan *planStruct
 		if time, soc := v.GetPlanSoc(); !time.IsZero() {
 			plan = &planStruct{
-				Soc:  soc,
-				Time: time,
+				Soc:       soc,
+				Time:      time,
+				Predicted: v.GetPlanPredicted(),
 			}
 		}
 
 		res[v.Name()] = vehicleStruct{
-			Title:          instance.GetTitle(),
-			Icon:           instance.Icon(),
-			Capacity:       instance.Capacity(),
-			Phases:         instance.Phases(),
-			Mode:           v.GetMode(),
-			MinSoc:         v.GetMinSoc(),
-			LimitSoc:       v.GetLimitSoc(),
-			MinCurrent:     ac.MinCurrent,
-			MaxCurrent:     ac.MaxCurrent,
-			Priority:       ac.Priority,
-			Features:       lo.Map(instance.Features(), func(f api.Feature, _ int) string { return f.String() }),
-			Plan:           plan,
-			RepeatingPlans: v.GetRepeatingPlans(),
-			PlanStrategy:   v.GetPlanStrategy(),
+			Title:               instance.GetTitle(),
+			Icon:                instance.Icon(),
+			Capacity:            instance.Capacity(),
+			Phases:              instance.Phases(),
+			Mode:                v.GetMode(),
+			MinSoc:              v.GetMinSoc(),
+			LimitSoc:            v.GetLimitSoc(),
+			MinCurrent:          ac.MinCurrent,
+			MaxCurrent:          ac.MaxCurrent,
+			Priority:            ac.Priority,
+			Features:            lo.Map(instance.Features(), func(f api.Feature, _ int) string { return f.String() }),
+			Plan:                plan,
+			RepeatingPlans:      v.GetRepeatingPlans(),
+			PlanStrategy:        v.GetPlanStrategy(),
+			TripCalendar:        v.GetTripCalendar(),
+			DeparturePrediction: v.GetDeparturePrediction(),
 		}
 
 		// publish effective plan strategy immediately for soc-based planning
@@ -89,6 +103,12 @@ func (site *Site) updateVehicles(op config.Operation, dev config.Device[api.Vehi
 	case config.OpAdd:
 		site.coordinator.Add(vehicle)
 
+	case config.OpUpdate:
+		if previous, ok := config.Previous(dev); ok {
+			site.coordinator.Delete(previous)
+		}
+		site.coordinator.Add(vehicle)
+
 	case config.OpDelete:
 		site.coordinator.Delete(vehicle)
 	}
@@ -16,6 +16,9 @@ type Measurement struct {
 	ReturnEnergy  *float64    `json:"returnEnergy,omitempty"`
 	Powers        []float64   `json:"powers,omitempty"`
 	Currents      []float64   `json:"currents,omitempty"`
+	ReactivePower *float64    `json:"reactivePower,omitempty"`
+	PowerFactor   *float64    `json:"powerFactor,omitempty"`
+	Frequency     *float64    `json:"frequency,omitempty"`
 	ExcessDCPower float64     `json:"excessdcpower,omitempty"`
 	Capacity      *float64    `json:"capacity,omitempty"`
 	Soc           *float64    `json:"soc,omitempty"`
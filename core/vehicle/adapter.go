@@ -3,6 +3,7 @@ package vehicle
 import (
 	"errors"
 	"fmt"
+	"net/url"
 	"time"
 
 	"github.com/evcc-io/evcc/api"
@@ -109,6 +110,21 @@ func (v *adapter) GetPlanSoc() (time.Time, int) {
 
 // SetPlanSoc sets the charge plan soc
 func (v *adapter) SetPlanSoc(ts time.Time, soc int) error {
+	return v.setPlanSoc(ts, soc, false)
+}
+
+// GetPlanPredicted returns true if the charge plan has been predicted
+func (v *adapter) GetPlanPredicted() bool {
+	res, _ := settings.Bool(v.key() + keys.PlanPredicted)
+	return res
+}
+
+// SetPredictedPlanSoc sets the charge plan soc of a predicted departure
+func (v *adapter) SetPredictedPlanSoc(ts time.Time, soc int) error {
+	return v.setPlanSoc(ts, soc, true)
+}
+
+func (v *adapter) setPlanSoc(ts time.Time, soc int, predicted bool) error {
 	if !ts.IsZero() && ts.Before(time.Now()) {
 		return errors.New("timestamp is in the past")
 	}
@@ -123,6 +139,7 @@ func (v *adapter) SetPlanSoc(ts time.Time, soc int) error {
 
 	settings.SetTime(v.key()+keys.PlanTime, ts)
 	settings.SetInt(v.key()+keys.PlanSoc, int64(soc))
+	settings.SetBool(v.key()+keys.PlanPredicted, predicted && soc > 0)
 
 	// note: could be optimized by only clearing plan lock of the relevant loadpoint
 	v.clearPlanLocks()
@@ -171,6 +188,82 @@ func (v *adapter) GetRepeatingPlans() []api.RepeatingPlan {
 	return plans
 }
 
+func (v *adapter) GetTripCalendar() *api.TripCalendar {
+	var res api.TripCalendar
+	if err := settings.Json(v.key()+keys.TripCalendar, &res); err != nil || res.Uri == "" {
+		return nil
+	}
+
+	return &res
+}
+
+func (v *adapter) SetTripCalendar(calendar *api.TripCalendar) error {
+	if calendar == nil {
+		v.log.DEBUG.Printf("delete %s trip calendar", v.name)
+		settings.SetString(v.key()+keys.TripCalendar, "")
+		v.publish()
+		return nil
+	}
+
+	if u, err := url.Parse(calendar.Uri); err != nil || calendar.Uri == "" || (u.Scheme == "http" || u.Scheme == "https") && u.Host == "" {
+		return fmt.Errorf("invalid uri: %s", calendar.Uri)
+	}
+	if u, err := url.Parse(calendar.Geocoder); calendar.Geocoder != "" && (err != nil || u.Scheme != "http" && u.Scheme != "https" || u.Host == "") {
+		return fmt.Errorf("invalid geocoder: %s", calendar.Geocoder)
+	}
+	if calendar.Consumption < 0 {
+		return fmt.Errorf("invalid consumption: %v", calendar.Consumption)
+	}
+	if calendar.Reserve < 0 || calendar.Reserve > 100 {
+		return fmt.Errorf("invalid reserve: %v", calendar.Reserve)
+	}
+
+	if err := settings.SetJson(v.key()+keys.TripCalendar, calendar); err != nil {
+		return err
+	}
+
+	v.log.DEBUG.Printf("set %s trip calendar: %s", v.name, calendar.Uri)
+
+	v.publish()
+
+	return nil
+}
+
+func (v *adapter) GetDeparturePrediction() *api.DeparturePrediction {
+	var res api.DeparturePrediction
+	if err := settings.Json(v.key()+keys.Prediction, &res); err != nil || res.Soc == 0 {
+		return nil
+	}
+
+	return &res
+}
+
+func (v *adapter) SetDeparturePrediction(prediction *api.DeparturePrediction) error {
+	if prediction == nil {
+		v.log.DEBUG.Printf("disable %s departure prediction", v.name)
+		settings.SetString(v.key()+keys.Prediction, "")
+		v.publish()
+		return nil
+	}
+
+	if prediction.Soc <= 0 || prediction.Soc > 100 {
+		return fmt.Errorf("invalid soc: %v", prediction.Soc)
+	}
+	if prediction.Confidence < 0 || prediction.Confidence > 1 {
+		return fmt.Errorf("invalid confidence: %v", prediction.Confidence)
+	}
+
+	if err := settings.SetJson(v.key()+keys.Prediction, prediction); err != nil {
+		return err
+	}
+
+	v.log.DEBUG.Printf("set %s departure prediction: %d%%", v.name, prediction.Soc)
+
+	v.publish()
+
+	return nil
+}
+
 func (v *adapter) GetPlanStrategy() api.PlanStrategy {
 	var strategy api.PlanStrategy
 	if err := settings.Json(v.key()+keys.PlanStrategy, &strategy); err != nil {
@@ -31,14 +31,28 @@ type API interface {
 
 	// GetPlanSoc returns the charge plan soc
 	GetPlanSoc() (time.Time, int)
-	// SetPlanSoc sets the charge plan time and soc
+	// SetPlanSoc sets the charge plan time and soc, replacing a predicted plan
 	SetPlanSoc(time.Time, int) error
+	// GetPlanPredicted returns true if the charge plan has been predicted
+	GetPlanPredicted() bool
+	// SetPredictedPlanSoc sets the charge plan time and soc of a predicted departure
+	SetPredictedPlanSoc(time.Time, int) error
 
 	// GetRepeatingPlans returns every repeating plan
 	GetRepeatingPlans() []api.RepeatingPlan
 	// SetRepeatingPlans stores every repeating plan
 	SetRepeatingPlans([]api.RepeatingPlan) error
 
+	// GetTripCalendar returns the trip calendar or nil if not configured
+	GetTripCalendar() *api.TripCalendar
+	// SetTripCalendar sets the trip calendar, nil removes it
+	SetTripCalendar(*api.TripCalendar) error
+
+	// GetDeparturePrediction returns the departure prediction or nil if disabled
+	GetDeparturePrediction() *api.DeparturePrediction
+	// SetDeparturePrediction sets the departure prediction, nil disables it
+	SetDeparturePrediction(*api.DeparturePrediction) error
+
 	// GetPlanStrategy returns the plan strategy
 	GetPlanStrategy() api.PlanStrategy
 	// SetPlanStrategy sets the plan strategy
@@ -66,6 +66,30 @@ func (v *dummy) GetRepeatingPlans() []api.RepeatingPlan {
 	return nil
 }
 
+func (v *dummy) GetPlanPredicted() bool {
+	return false
+}
+
+func (v *dummy) SetPredictedPlanSoc(ts time.Time, soc int) error {
+	return nil
+}
+
+func (v *dummy) GetTripCalendar() *api.TripCalendar {
+	return nil
+}
+
+func (v *dummy) SetTripCalendar(calendar *api.TripCalendar) error {
+	return nil
+}
+
+func (v *dummy) GetDeparturePrediction() *api.DeparturePrediction {
+	return nil
+}
+
+func (v *dummy) SetDeparturePrediction(prediction *api.DeparturePrediction) error {
+	return nil
+}
+
 func (v *dummy) GetPlanStrategy() api.PlanStrategy {
 	return api.PlanStrategy{}
 }
@@ -41,6 +41,20 @@ func (m *MockAPI) EXPECT() *MockAPIMockRecorder {
 	return m.recorder
 }
 
+// GetDeparturePrediction mocks base method.
+func (m *MockAPI) GetDeparturePrediction() *api.DeparturePrediction {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeparturePrediction")
+	ret0, _ := ret[0].(*api.DeparturePrediction)
+	return ret0
+}
+
+// GetDeparturePrediction indicates an expected call of GetDeparturePrediction.
+func (mr *MockAPIMockRecorder) GetDeparturePrediction() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeparturePrediction", reflect.TypeOf((*MockAPI)(nil).GetDeparturePrediction))
+}
+
 // GetLimitSoc mocks base method.
 func (m *MockAPI) GetLimitSoc() int {
 	m.ctrl.T.Helper()
@@ -83,6 +97,20 @@ func (mr *MockAPIMockRecorder) GetMode() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMode", reflect.TypeOf((*MockAPI)(nil).GetMode))
 }
 
+// GetPlanPredicted mocks base method.
+func (m *MockAPI) GetPlanPredicted() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPlanPredicted")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// GetPlanPredicted indicates an expected call of GetPlanPredicted.
+func (mr *MockAPIMockRecorder) GetPlanPredicted() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPlanPredicted", reflect.TypeOf((*MockAPI)(nil).GetPlanPredicted))
+}
+
 // GetPlanSoc mocks base method.
 func (m *MockAPI) GetPlanSoc() (time.Time, int) {
 	m.ctrl.T.Helper()
@@ -126,6 +154,20 @@ func (mr *MockAPIMockRecorder) GetRepeatingPlans() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRepeatingPlans", reflect.TypeOf((*MockAPI)(nil).GetRepeatingPlans))
 }
 
+// GetTripCalendar mocks base method.
+func (m *MockAPI) GetTripCalendar() *api.TripCalendar {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTripCalendar")
+	ret0, _ := ret[0].(*api.TripCalendar)
+	return ret0
+}
+
+// GetTripCalendar indicates an expected call of GetTripCalendar.
+func (mr *MockAPIMockRecorder) GetTripCalendar() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTripCalendar", reflect.TypeOf((*MockAPI)(nil).GetTripCalendar))
+}
+
 // Instance mocks base method.
 func (m *MockAPI) Instance() api.Vehicle {
 	m.ctrl.T.Helper()
@@ -154,6 +196,20 @@ func (mr *MockAPIMockRecorder) Name() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockAPI)(nil).Name))
 }
 
+// SetDeparturePrediction mocks base method.
+func (m *MockAPI) SetDeparturePrediction(arg0 *api.DeparturePrediction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDeparturePrediction", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDeparturePrediction indicates an expected call of SetDeparturePrediction.
+func (mr *MockAPIMockRecorder) SetDeparturePrediction(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDeparturePrediction", reflect.TypeOf((*MockAPI)(nil).SetDeparturePrediction), arg0)
+}
+
 // SetLimitSoc mocks base method.
 func (m *MockAPI) SetLimitSoc(soc int) {
 	m.ctrl.T.Helper()
@@ -218,6 +274,20 @@ func (mr *MockAPIMockRecorder) SetPlanStrategy(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPlanStrategy", reflect.TypeOf((*MockAPI)(nil).SetPlanStrategy), arg0)
 }
 
+// SetPredictedPlanSoc mocks base method.
+func (m *MockAPI) SetPredictedPlanSoc(arg0 time.Time, arg1 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPredictedPlanSoc", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPredictedPlanSoc indicates an expected call of SetPredictedPlanSoc.
+func (mr *MockAPIMockRecorder) SetPredictedPlanSoc(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPredictedPlanSoc", reflect.TypeOf((*MockAPI)(nil).SetPredictedPlanSoc), arg0, arg1)
+}
+
 // SetRepeatingPlans mocks base method.
 func (m *MockAPI) SetRepeatingPlans(arg0 []api.RepeatingPlan) error {
 	m.ctrl.T.Helper()
@@ -231,3 +301,17 @@ func (mr *MockAPIMockRecorder) SetRepeatingPlans(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRepeatingPlans", reflect.TypeOf((*MockAPI)(nil).SetRepeatingPlans), arg0)
 }
+
+// SetTripCalendar mocks base method.
+func (m *MockAPI) SetTripCalendar(arg0 *api.TripCalendar) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTripCalendar", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTripCalendar indicates an expected call of SetTripCalendar.
+func (mr *MockAPIMockRecorder) SetTripCalendar(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTripCalendar", reflect.TypeOf((*MockAPI)(nil).SetTripCalendar), arg0)
+}
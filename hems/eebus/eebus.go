@@ -54,6 +54,10 @@ type EEBus struct {
 	interval          time.Duration
 }
 
+// HeartbeatTimeout is the time without Energy Guard heartbeat after which the
+// failsafe state is entered ([LPC-031]). Shortened by the simulator harness.
+var HeartbeatTimeout = 2 * time.Minute
+
 // failsafeReleaseTimeout is how long the CS keeps the failsafe limit after the
 // heartbeat resumed but the Energy Guard has not stated a limit yet ([LPC-921]).
 const failsafeReleaseTimeout = 2 * time.Minute
@@ -108,7 +112,7 @@ func NewEEBus(ctx context.Context, ski string, limits Limits, passthrough func(b
 		passthrough: passthrough,
 		cs:          inst.ControllableSystem(),
 		Connector:   eebus.NewConnector(),
-		heartbeat:   util.NewValue[struct{}](2 * time.Minute), // LPC-031
+		heartbeat:   util.NewValue[struct{}](HeartbeatTimeout),
 		interval:    interval,
 
 		failsafeDuration:         limits.FailsafeDurationMinimum,
@@ -3,7 +3,9 @@ package messenger
 import (
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
+	"sync"
 	"text/template"
 
 	"github.com/Masterminds/sprig/v3"
@@ -15,12 +17,18 @@ import (
 
 // Event is a notification event
 type Event struct {
-	Loadpoint  *int // optional loadpoint id
+	Site       string // site id, empty for the default site
+	Loadpoint  *int   // optional loadpoint id
 	Event      string
 	Attributes map[string]any // optional event-specific template attributes
 	State      []util.Param   // cache state at the time the event was raised
 }
 
+// EventSender is implemented by messengers that consume the structured event in addition to the rendered message
+type EventSender interface {
+	SendEvent(ev Event, title, msg string)
+}
+
 type Vehicles interface {
 	// ByName returns a single vehicle adapter by name
 	ByName(string) (vehicle.API, error)
@@ -28,6 +36,7 @@ type Vehicles interface {
 
 // Hub subscribes to event notifications and sends them to client devices
 type Hub struct {
+	mu          sync.RWMutex
 	definitions globalconfig.MessagingEvents
 	sender      []api.Messenger
 	vehicles    Vehicles
@@ -64,13 +73,47 @@ func NewHub(cc globalconfig.MessagingEvents, vv Vehicles) (*Hub, error) {
 
 // Add adds a sender to the list of senders
 func (h *Hub) Add(sender api.Messenger) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
 	h.sender = append(h.sender, sender)
 }
 
+// Delete removes a sender from the list of senders
+func (h *Hub) Delete(sender api.Messenger) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.sender = slices.DeleteFunc(h.sender, func(s api.Messenger) bool { return s == sender })
+}
+
+// Replace swaps a reconfigured sender. The previous sender is shut down by its
+// config handler once replaced.
+func (h *Hub) Replace(previous, sender api.Messenger) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if i := slices.Index(h.sender, previous); i >= 0 {
+		h.sender[i] = sender
+	} else {
+		h.sender = append(h.sender, sender)
+	}
+}
+
+// senders returns the current list of senders
+func (h *Hub) senders() []api.Messenger {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return slices.Clone(h.sender)
+}
+
 // apply applies the event template to the content to produce the actual message
 func (h *Hub) apply(ev Event, tmpl string) (string, error) {
 	attr := make(map[string]any)
 
+	// site id
+	if ev.Site != "" {
+		attr["site"] = ev.Site
+	}
+
 	// loadpoint id
 	if ev.Loadpoint != nil {
 		attr["loadpoint"] = *ev.Loadpoint + 1
@@ -117,7 +160,8 @@ func (h *Hub) Run(events <-chan Event) {
 	log := util.NewLogger("push")
 
 	for ev := range events {
-		if len(h.sender) == 0 {
+		senders := h.senders()
+		if len(senders) == 0 {
 			continue
 		}
 
@@ -142,7 +186,12 @@ func (h *Hub) Run(events <-chan Event) {
 			continue
 		}
 
-		for _, sender := range h.sender {
+		for _, sender := range senders {
+			if es, ok := sender.(EventSender); ok {
+				go es.SendEvent(ev, title, msg)
+				continue
+			}
+
 			go sender.Send(title, msg)
 		}
 	}
@@ -0,0 +1,354 @@
+package messenger
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+)
+
+func init() {
+	registry.AddCtx("webhook", NewWebhookFromConfig)
+}
+
+const (
+	webhookRetryInterval = 5 * time.Second
+	webhookMaxBackoff    = time.Hour
+)
+
+// WebhookPayload is the JSON document posted to webhook receivers
+type WebhookPayload struct {
+	Event      string         `json:"event"`
+	Site       string         `json:"site,omitempty"`
+	Loadpoint  *int           `json:"loadpoint,omitempty"`
+	Title      string         `json:"title,omitempty"`
+	Msg        string         `json:"msg,omitempty"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+	State      map[string]any `json:"state,omitempty"`
+	Timestamp  time.Time      `json:"timestamp"`
+}
+
+// Webhook posts structured event payloads to one or more urls
+type Webhook struct {
+	log      *util.Logger
+	clock    clock.Clock
+	mu       sync.Mutex
+	client   *request.Helper
+	uris     []string
+	secret   string
+	headers  map[string]string
+	attempts int
+	queue    []*WebhookDelivery
+	wakeup   chan struct{}
+}
+
+// NewWebhookFromConfig creates new webhook messenger
+func NewWebhookFromConfig(ctx context.Context, other map[string]any) (api.Messenger, error) {
+	cc := struct {
+		URI     string
+		URIs    []string
+		Secret  string
+		Headers map[string]string
+		Retries int
+		Timeout time.Duration
+	}{
+		Retries: 10,
+		Timeout: request.Timeout,
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	uris := cc.URIs
+	if cc.URI != "" {
+		uris = append([]string{cc.URI}, uris...)
+	}
+
+	if len(uris) == 0 {
+		return nil, errors.New("missing uri")
+	}
+
+	log := util.NewLogger("webhook").Redact(cc.Secret)
+
+	m := &Webhook{
+		log:      log,
+		clock:    clock.New(),
+		client:   request.NewHelper(log),
+		uris:     uris,
+		secret:   cc.Secret,
+		headers:  cc.Headers,
+		attempts: max(cc.Retries, 0) + 1,
+		wakeup:   make(chan struct{}, 1),
+	}
+
+	m.client.Timeout = cc.Timeout
+
+	// resume deliveries that were pending at shutdown
+	pending, err := claimPendingDeliveries(uris)
+	if err != nil {
+		log.ERROR.Printf("loading pending deliveries: %v", err)
+	}
+	m.queue = pending
+
+	go m.run(ctx)
+
+	return m, nil
+}
+
+// Send implements the api.Messenger interface
+func (m *Webhook) Send(title, msg string) {
+	m.enqueue(WebhookPayload{
+		Title:     title,
+		Msg:       msg,
+		Timestamp: m.clock.Now(),
+	})
+}
+
+// SendEvent implements the EventSender interface
+func (m *Webhook) SendEvent(ev Event, title, msg string) {
+	payload := WebhookPayload{
+		Event:      ev.Event,
+		Site:       ev.Site,
+		Title:      title,
+		Msg:        msg,
+		Attributes: ev.Attributes,
+		State:      make(map[string]any),
+		Timestamp:  m.clock.Now(),
+	}
+
+	if ev.Loadpoint != nil {
+		payload.Loadpoint = new(*ev.Loadpoint + 1)
+	}
+
+	for _, p := range ev.State {
+		if p.Loadpoint != nil && (ev.Loadpoint == nil || *p.Loadpoint != *ev.Loadpoint) {
+			continue
+		}
+
+		val := p.Val
+		if rv := reflect.ValueOf(p.Val); rv.Kind() == reflect.Pointer && !rv.IsNil() {
+			val = rv.Elem().Interface()
+		}
+
+		// skip values that cannot be represented as json
+		if _, err := json.Marshal(val); err != nil {
+			continue
+		}
+
+		payload.State[p.Key] = val
+	}
+
+	m.enqueue(payload)
+}
+
+// enqueue creates a delivery per receiver and triggers immediate processing
+func (m *Webhook) enqueue(payload WebhookPayload) {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		m.log.ERROR.Printf("marshal: %v", err)
+		return
+	}
+
+	m.mu.Lock()
+	for _, uri := range m.uris {
+		d := &WebhookDelivery{
+			URI:         uri,
+			Event:       payload.Event,
+			Payload:     string(b),
+			Status:      DeliveryPending,
+			Created:     payload.Timestamp,
+			NextAttempt: payload.Timestamp,
+		}
+
+		if err := d.persist(); err != nil {
+			m.log.ERROR.Printf("persist: %v", err)
+		}
+
+		claimDelivery(d)
+		m.queue = append(m.queue, d)
+	}
+	m.mu.Unlock()
+
+	select {
+	case m.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+// run processes the delivery queue until the context is cancelled
+func (m *Webhook) run(ctx context.Context) {
+	tick := m.clock.Ticker(webhookRetryInterval)
+	defer tick.Stop()
+
+	var pruned time.Time
+
+	for {
+		m.process()
+
+		if m.clock.Since(pruned) > time.Hour {
+			if err := pruneDeliveries(); err != nil {
+				m.log.ERROR.Printf("prune: %v", err)
+			}
+			pruned = m.clock.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			// hand pending deliveries over to a future messenger instance
+			m.mu.Lock()
+			releaseDeliveries(m.queue)
+			m.mu.Unlock()
+			return
+		case <-tick.C:
+			m.resume()
+		case <-m.wakeup:
+		}
+	}
+}
+
+// resume picks up pending deliveries released by a replaced messenger instance
+func (m *Webhook) resume() {
+	pending, err := claimPendingDeliveries(m.uris)
+	if err != nil {
+		m.log.ERROR.Printf("loading pending deliveries: %v", err)
+		return
+	}
+
+	if len(pending) > 0 {
+		m.mu.Lock()
+		m.queue = append(m.queue, pending...)
+		m.mu.Unlock()
+	}
+}
+
+// process attempts all due deliveries and drops completed ones from the queue
+func (m *Webhook) process() {
+	m.mu.Lock()
+	queue := m.queue
+	m.queue = nil
+	m.mu.Unlock()
+
+	var remaining []*WebhookDelivery
+
+	for _, d := range queue {
+		if m.clock.Now().Before(d.NextAttempt) {
+			remaining = append(remaining, d)
+			continue
+		}
+
+		m.deliver(d)
+
+		if err := d.persist(); err != nil {
+			m.log.ERROR.Printf("persist: %v", err)
+		}
+
+		if d.Status == DeliveryPending {
+			remaining = append(remaining, d)
+		} else {
+			releaseDeliveries([]*WebhookDelivery{d})
+		}
+	}
+
+	m.mu.Lock()
+	m.queue = append(remaining, m.queue...)
+	m.mu.Unlock()
+}
+
+// deliver makes a single delivery attempt and updates the delivery status
+func (m *Webhook) deliver(d *WebhookDelivery) {
+	d.Attempts++
+	d.Updated = m.clock.Now()
+
+	err := m.post(d)
+	if err == nil {
+		d.Status = DeliverySucceeded
+		d.Error = ""
+		return
+	}
+
+	d.Error = err.Error()
+	m.log.DEBUG.Printf("delivery %d to %s failed (attempt %d): %v", d.ID, d.URI, d.Attempts, err)
+
+	if d.Attempts >= m.attempts || permanentError(err) {
+		d.Status = DeliveryFailed
+		m.log.ERROR.Printf("delivery to %s failed: %v", d.URI, err)
+		return
+	}
+
+	d.NextAttempt = d.Updated.Add(retryDelay(d.Attempts))
+}
+
+func (m *Webhook) post(d *WebhookDelivery) error {
+	ts := strconv.FormatInt(m.clock.Now().Unix(), 10)
+
+	headers := map[string]string{
+		"Content-Type":     request.JSONContent,
+		"X-Evcc-Event":     d.Event,
+		"X-Evcc-Delivery":  strconv.FormatUint(uint64(d.ID), 10),
+		"X-Evcc-Timestamp": ts,
+	}
+
+	if m.secret != "" {
+		headers["X-Evcc-Signature"] = "sha256=" + Signature(m.secret, ts, []byte(d.Payload))
+	}
+
+	for k, v := range m.headers {
+		headers[k] = v
+	}
+
+	req, err := request.New(http.MethodPost, d.URI, bytes.NewBufferString(d.Payload), headers)
+	if err != nil {
+		return err
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return request.ResponseError(resp)
+}
+
+// Signature returns the hex-encoded HMAC-SHA256 of timestamp and body.
+// Receivers verify deliveries by recomputing it from the X-Evcc-Timestamp header and the raw request body.
+func Signature(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// retryDelay returns the exponential backoff delay after the given number of attempts
+func retryDelay(attempts int) time.Duration {
+	delay := webhookRetryInterval
+	for range attempts - 1 {
+		if delay *= 2; delay >= webhookMaxBackoff {
+			return webhookMaxBackoff
+		}
+	}
+	return delay
+}
+
+// permanentError returns true for client errors that will not succeed on retry
+func permanentError(err error) bool {
+	if se, ok := errors.AsType[*request.StatusError](err); ok {
+		code := se.StatusCode()
+		return code >= 400 && code < 500 && code != http.StatusRequestTimeout && code != http.StatusTooManyRequests
+	}
+	return false
+}
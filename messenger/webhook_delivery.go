@@ -0,0 +1,132 @@
+package messenger
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/evcc-io/evcc/server/db"
+	"gorm.io/gorm"
+)
+
+// DeliveryStatus is the state of a webhook delivery
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// WebhookDelivery is a single webhook message and its delivery state
+type WebhookDelivery struct {
+	ID          uint           `json:"id" gorm:"primarykey"`
+	URI         string         `json:"uri" gorm:"index"`
+	Event       string         `json:"event"`
+	Payload     string         `json:"payload"`
+	Status      DeliveryStatus `json:"status" gorm:"index"`
+	Attempts    int            `json:"attempts"`
+	Error       string         `json:"error,omitempty"`
+	Created     time.Time      `json:"created"`
+	Updated     time.Time      `json:"updated,omitzero"`
+	NextAttempt time.Time      `json:"nextAttempt,omitzero"`
+}
+
+// webhookRetention is the time completed deliveries are kept in the delivery log
+const webhookRetention = 7 * 24 * time.Hour
+
+var (
+	// deliveryID numbers deliveries if the database is not available
+	deliveryID atomic.Uint64
+
+	// claimed are the pending deliveries resumed by a running messenger
+	claimedMu sync.Mutex
+	claimed   = make(map[uint]bool)
+)
+
+func init() {
+	db.Register(func(db *gorm.DB) error {
+		return db.AutoMigrate(new(WebhookDelivery))
+	})
+}
+
+// persist saves the delivery if the database is available
+func (d *WebhookDelivery) persist() error {
+	if db.Instance == nil {
+		if d.ID == 0 {
+			d.ID = uint(deliveryID.Add(1))
+		}
+		return nil
+	}
+	return db.Instance.Save(d).Error
+}
+
+// claimPendingDeliveries returns the persisted pending deliveries for the given receivers.
+// Deliveries are claimed so that messengers sharing a receiver do not deliver them twice.
+func claimPendingDeliveries(uris []string) ([]*WebhookDelivery, error) {
+	if db.Instance == nil {
+		return nil, nil
+	}
+
+	var pending []*WebhookDelivery
+	if err := db.Instance.Where("uri IN ? AND status = ?", uris, DeliveryPending).Order("id").Find(&pending).Error; err != nil {
+		return nil, err
+	}
+
+	claimedMu.Lock()
+	defer claimedMu.Unlock()
+
+	var res []*WebhookDelivery
+	for _, d := range pending {
+		if !claimed[d.ID] {
+			claimed[d.ID] = true
+			res = append(res, d)
+		}
+	}
+
+	return res, nil
+}
+
+// claimDelivery marks a new delivery as owned by the running messenger
+func claimDelivery(d *WebhookDelivery) {
+	claimedMu.Lock()
+	defer claimedMu.Unlock()
+
+	claimed[d.ID] = true
+}
+
+// releaseDeliveries returns claimed deliveries, e.g. when the messenger is stopped
+func releaseDeliveries(deliveries []*WebhookDelivery) {
+	claimedMu.Lock()
+	defer claimedMu.Unlock()
+
+	for _, d := range deliveries {
+		delete(claimed, d.ID)
+	}
+}
+
+// pruneDeliveries removes completed deliveries older than the retention period
+func pruneDeliveries() error {
+	if db.Instance == nil {
+		return nil
+	}
+
+	return db.Instance.Where("status <> ? AND updated < ?", DeliveryPending, time.Now().Add(-webhookRetention)).Delete(new(WebhookDelivery)).Error
+}
+
+// WebhookDeliveries returns the most recent webhook deliveries, optionally filtered by status
+func WebhookDeliveries(status DeliveryStatus, limit int) ([]WebhookDelivery, error) {
+	if db.Instance == nil {
+		return nil, nil
+	}
+
+	tx := db.Instance.Order("id DESC").Limit(limit)
+	if status != "" {
+		tx = tx.Where("status = ?", status)
+	}
+
+	res := make([]WebhookDelivery, 0)
+	err := tx.Find(&res).Error
+
+	return res, err
+}
@@ -0,0 +1,116 @@
+package messenger
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookDelivery(t *testing.T) {
+	var calls atomic.Int32
+	received := make(chan []byte, 1)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		ts := r.Header.Get("X-Evcc-Timestamp")
+		assert.Equal(t, "sha256="+Signature("secret", ts, body), r.Header.Get("X-Evcc-Signature"))
+		assert.Equal(t, "connect", r.Header.Get("X-Evcc-Event"))
+
+		// fail first attempt
+		if n == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		received <- body
+	}))
+	defer srv.Close()
+
+	clck := clock.NewMock()
+	log := util.NewLogger("webhook")
+
+	wh := &Webhook{
+		log:      log,
+		clock:    clck,
+		client:   request.NewHelper(log),
+		uris:     []string{srv.URL},
+		secret:   "secret",
+		attempts: 2,
+		wakeup:   make(chan struct{}, 1),
+	}
+
+	go wh.run(t.Context())
+
+	lp := 0
+	ev := Event{
+		Loadpoint:  &lp,
+		Event:      "connect",
+		Attributes: map[string]any{"foo": "bar"},
+		State: []util.Param{
+			{Key: "gridPower", Val: 1000.0},
+			{Loadpoint: &lp, Key: "vehicleSoc", Val: 50.0},
+			{Loadpoint: new(1), Key: "vehicleSoc", Val: 80.0},
+		},
+	}
+
+	wh.SendEvent(ev, "title", "msg")
+
+	queued := func() int {
+		wh.mu.Lock()
+		defer wh.mu.Unlock()
+		return len(wh.queue)
+	}
+
+	// failed delivery is queued again after the first attempt
+	require.Eventually(t, func() bool {
+		return calls.Load() == 1 && queued() == 1
+	}, time.Second, 10*time.Millisecond)
+
+	// retry is due after backoff
+	clck.Add(retryDelay(1))
+
+	require.Eventually(t, func() bool {
+		return calls.Load() == 2 && queued() == 0
+	}, time.Second, 10*time.Millisecond)
+
+	var payload WebhookPayload
+	require.NoError(t, json.Unmarshal(<-received, &payload))
+
+	assert.Equal(t, "connect", payload.Event)
+	assert.Equal(t, 1, *payload.Loadpoint)
+	assert.Equal(t, "msg", payload.Msg)
+	assert.Equal(t, map[string]any{"foo": "bar"}, payload.Attributes)
+	assert.Equal(t, map[string]any{"gridPower": 1000.0, "vehicleSoc": 50.0}, payload.State)
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+	assert.Equal(t, webhookRetryInterval, retryDelay(1))
+	assert.Equal(t, 4*webhookRetryInterval, retryDelay(3))
+	assert.Equal(t, webhookMaxBackoff, retryDelay(20))
+}
+
+func TestWebhookClaimDeliveries(t *testing.T) {
+	d := new(WebhookDelivery)
+	require.NoError(t, d.persist())
+	assert.NotZero(t, d.ID, "delivery id without database")
+
+	claimDelivery(d)
+	assert.True(t, claimed[d.ID])
+
+	releaseDeliveries([]*WebhookDelivery{d})
+	assert.False(t, claimed[d.ID])
+}
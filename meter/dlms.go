@@ -0,0 +1,109 @@
+package meter
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/meter/dlms"
+	"github.com/evcc-io/evcc/util"
+)
+
+func init() {
+	registry.AddCtx("dlms", NewDlmsFromConfig)
+}
+
+// NewDlmsFromConfig creates a DLMS/COSEM meter reading (encrypted) push telegrams
+// from the customer interface, either attached to a serial device or via a TCP serial bridge
+//
+//	type:     dlms
+//	device:   /dev/ttyUSB0   # serial device, or
+//	uri:      192.168.1.20:8088 # tcp serial bridge
+//	baudrate: 2400           # M-Bus 2400 8E1, HDLC typically 115200 8N1
+//	comset:   8E1
+//	key:      00112233445566778899AABBCCDDEEFF # encryption key (hex) provided by the grid operator
+//	authkey:  # optional authentication key (hex)
+func NewDlmsFromConfig(ctx context.Context, other map[string]any) (api.Meter, error) {
+	cc := struct {
+		URI      string
+		Device   string
+		Baudrate int
+		Comset   string
+		Key      string
+		AuthKey  string
+		Timeout  time.Duration
+	}{
+		Baudrate: 2400,
+		Comset:   "8E1",
+		Timeout:  15 * time.Second,
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	if cc.URI == "" && cc.Device == "" {
+		return nil, errors.New("missing uri or device")
+	}
+
+	key, err := hex.DecodeString(cc.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	authKey, err := hex.DecodeString(cc.AuthKey)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder, err := dlms.NewDecoder(key, authKey)
+	if err != nil {
+		return nil, err
+	}
+
+	dial, err := obisDialer(ctx, cc.URI, cc.Device, cc.Baudrate, cc.Comset)
+	if err != nil {
+		return nil, err
+	}
+
+	log := util.NewLogger("dlms").Redact(cc.Key, cc.AuthKey)
+
+	m, err := newDsmr(ctx, log, dial, dlmsReader(log, decoder), cc.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	m.decorate()
+
+	return m, nil
+}
+
+// dlmsReader returns a frame reader decoding DLMS data notifications. Corrupt
+// frames are logged and skipped, only transport errors are returned.
+func dlmsReader(log *util.Logger, decoder *dlms.Decoder) func(*bufio.Reader) (map[string]string, error) {
+	return func(reader *bufio.Reader) (map[string]string, error) {
+		for {
+			b, err := dlms.ReadFrame(reader)
+			if errors.Is(err, dlms.ErrInvalid) {
+				log.ERROR.Println(err)
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			log.TRACE.Printf("read: % x", b)
+
+			values, err := decoder.Decode(b)
+			if err != nil {
+				log.ERROR.Printf("decode: %v", err)
+				continue
+			}
+
+			return obisFrame(values), nil
+		}
+	}
+}
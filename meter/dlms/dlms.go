@@ -0,0 +1,395 @@
+// Package dlms decodes DLMS/COSEM push telegrams (data-notification) as sent
+// by Austrian, Luxembourgish and other smart meters via their customer interface.
+//
+// APDUs may be protected using general-glo-ciphering (AES-128-GCM). The
+// notification body is walked for OBIS code/value pairs:
+//
+//	structure { octet-string[6] obis, value, structure { int8 scaler, enum unit } } …
+package dlms
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/evcc-io/evcc/meter/obis"
+)
+
+// APDU tags
+const (
+	tagDataNotification    = 0x0F
+	tagGeneralGloCiphering = 0xDB
+)
+
+// security control bits
+const (
+	scAuthentication = 0x10
+	scEncryption     = 0x20
+)
+
+const tagSize = 12
+
+// Decoder decodes data-notification APDUs
+type Decoder struct {
+	block   cipher.Block
+	authKey []byte
+}
+
+// NewDecoder creates a decoder. The encryption key is required for ciphered
+// APDUs, the optional authentication key enables tag verification.
+func NewDecoder(key, authKey []byte) (*Decoder, error) {
+	d := &Decoder{authKey: authKey}
+
+	if len(key) > 0 {
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		d.block = block
+	}
+
+	return d, nil
+}
+
+// Decode decrypts the APDU if required and returns the scaled notification
+// values keyed by OBIS code, e.g. 1-0:1.7.0 in W or 1-0:1.8.0 in Wh
+func (d *Decoder) Decode(apdu []byte) (map[string]float64, error) {
+	if len(apdu) == 0 {
+		return nil, errors.New("empty apdu")
+	}
+
+	switch apdu[0] {
+	case tagGeneralGloCiphering:
+		plain, err := d.decrypt(apdu[1:])
+		if err != nil {
+			return nil, err
+		}
+		return d.Decode(plain)
+
+	case tagDataNotification:
+		return parseNotification(apdu[1:])
+
+	default:
+		return nil, fmt.Errorf("unsupported apdu: %#x", apdu[0])
+	}
+}
+
+// decrypt decrypts a general-glo-ciphering APDU body
+func (d *Decoder) decrypt(b []byte) ([]byte, error) {
+	if d.block == nil {
+		return nil, errors.New("missing key for encrypted apdu")
+	}
+
+	// system title
+	if len(b) < 1 || len(b) < 1+int(b[0]) || b[0] != 8 {
+		return nil, errors.New("invalid system title")
+	}
+	title := b[1:9]
+
+	length, b, err := berLength(b[9:])
+	if err != nil {
+		return nil, err
+	}
+	if length < 5 || len(b) < length {
+		return nil, errors.New("short ciphered apdu")
+	}
+	b = b[:length]
+
+	sc := b[0]
+	iv := append(title[:8:8], b[1:5]...)
+	data := b[5:]
+
+	if sc&scEncryption == 0 {
+		return nil, fmt.Errorf("unsupported security control: %#x", sc)
+	}
+
+	// authenticated encryption
+	if sc&scAuthentication != 0 && len(d.authKey) > 0 {
+		gcm, err := cipher.NewGCMWithTagSize(d.block, tagSize)
+		if err != nil {
+			return nil, err
+		}
+
+		return gcm.Open(nil, iv, data, append([]byte{sc}, d.authKey...))
+	}
+
+	if sc&scAuthentication != 0 {
+		if len(data) < tagSize {
+			return nil, errors.New("short ciphered apdu")
+		}
+		data = data[:len(data)-tagSize]
+	}
+
+	// GCM without tag verification is plain CTR starting at counter 2
+	counter := binary.BigEndian.AppendUint32(iv, 2)
+
+	res := make([]byte, len(data))
+	cipher.NewCTR(d.block, counter).XORKeyStream(res, data)
+
+	return res, nil
+}
+
+// berLength decodes a BER length
+func berLength(b []byte) (int, []byte, error) {
+	if len(b) == 0 {
+		return 0, nil, errors.New("short data")
+	}
+
+	if b[0] < 0x80 {
+		return int(b[0]), b[1:], nil
+	}
+
+	n := int(b[0] & 0x7F)
+	if n > 4 || len(b) < 1+n {
+		return 0, nil, errors.New("invalid length")
+	}
+
+	var res int
+	for _, c := range b[1 : 1+n] {
+		res = res<<8 | int(c)
+	}
+
+	return res, b[1+n:], nil
+}
+
+// parseNotification decodes a data-notification body
+func parseNotification(b []byte) (map[string]float64, error) {
+	// long-invoke-id-and-priority
+	if len(b) < 5 {
+		return nil, errors.New("short notification")
+	}
+	b = b[4:]
+
+	// optional date-time
+	if b[0] == 0x09 {
+		b = b[1:]
+	}
+	n, b, err := berLength(b)
+	if err != nil || len(b) < n {
+		return nil, errors.New("invalid notification date-time")
+	}
+
+	v, _, err := decode(b[n:])
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]float64)
+	walk(v, res)
+
+	return res, nil
+}
+
+// list is a decoded array or structure
+type list []any
+
+// scaler is the decimal exponent of a decoded scaler-unit structure
+type scaler int8
+
+// decode decodes a single A-XDR encoded data element
+func decode(b []byte) (any, []byte, error) {
+	if len(b) == 0 {
+		return nil, nil, errors.New("short data")
+	}
+
+	tag, b := b[0], b[1:]
+
+	switch tag {
+	case 0x00: // null
+		return nil, b, nil
+
+	case 0x01, 0x02: // array, structure
+		n, b, err := berLength(b)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		res := make(list, 0, n)
+		for range n {
+			var v any
+			if v, b, err = decode(b); err != nil {
+				return nil, nil, err
+			}
+			res = append(res, v)
+		}
+
+		// structure { int8 scaler, enum unit }
+		if tag == 0x02 && len(res) == 2 {
+			exp, ok1 := res[0].(int64)
+			_, ok2 := res[1].(enum)
+			if ok1 && ok2 && exp >= math.MinInt8 && exp <= math.MaxInt8 {
+				return scaler(exp), b, nil
+			}
+		}
+
+		return res, b, nil
+
+	case 0x04: // bit-string
+		n, b, err := berLength(b)
+		if err != nil {
+			return nil, nil, err
+		}
+		_, b, err = take(b, (n+7)/8)
+		return nil, b, err
+
+	case 0x09, 0x0A, 0x0C: // octet-string, visible-string, utf8-string
+		n, b, err := berLength(b)
+		if err != nil {
+			return nil, nil, err
+		}
+		return take(b, n)
+
+	case 0x03, 0x11: // boolean, unsigned
+		v, b, err := take(b, 1)
+		if err != nil {
+			return nil, nil, err
+		}
+		return uint64(v[0]), b, nil
+
+	case 0x16: // enum
+		v, b, err := take(b, 1)
+		if err != nil {
+			return nil, nil, err
+		}
+		return enum(v[0]), b, nil
+
+	case 0x0F: // integer
+		v, b, err := take(b, 1)
+		if err != nil {
+			return nil, nil, err
+		}
+		return int64(int8(v[0])), b, nil
+
+	case 0x10: // long
+		v, b, err := take(b, 2)
+		if err != nil {
+			return nil, nil, err
+		}
+		return int64(int16(binary.BigEndian.Uint16(v))), b, nil
+
+	case 0x12: // long-unsigned
+		v, b, err := take(b, 2)
+		if err != nil {
+			return nil, nil, err
+		}
+		return uint64(binary.BigEndian.Uint16(v)), b, nil
+
+	case 0x05: // double-long
+		v, b, err := take(b, 4)
+		if err != nil {
+			return nil, nil, err
+		}
+		return int64(int32(binary.BigEndian.Uint32(v))), b, nil
+
+	case 0x06: // double-long-unsigned
+		v, b, err := take(b, 4)
+		if err != nil {
+			return nil, nil, err
+		}
+		return uint64(binary.BigEndian.Uint32(v)), b, nil
+
+	case 0x14: // long64
+		v, b, err := take(b, 8)
+		if err != nil {
+			return nil, nil, err
+		}
+		return int64(binary.BigEndian.Uint64(v)), b, nil
+
+	case 0x15: // long64-unsigned
+		v, b, err := take(b, 8)
+		if err != nil {
+			return nil, nil, err
+		}
+		return binary.BigEndian.Uint64(v), b, nil
+
+	case 0x17: // float32
+		v, b, err := take(b, 4)
+		if err != nil {
+			return nil, nil, err
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(v))), b, nil
+
+	case 0x18: // float64
+		v, b, err := take(b, 8)
+		if err != nil {
+			return nil, nil, err
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(v)), b, nil
+
+	case 0x19: // date-time
+		_, b, err := take(b, 12)
+		return nil, b, err
+
+	case 0x1A: // date
+		_, b, err := take(b, 5)
+		return nil, b, err
+
+	case 0x1B: // time
+		_, b, err := take(b, 4)
+		return nil, b, err
+
+	default:
+		return nil, nil, fmt.Errorf("unsupported type: %#x", tag)
+	}
+}
+
+// take splits n bytes from b
+func take(b []byte, n int) ([]byte, []byte, error) {
+	if len(b) < n {
+		return nil, nil, errors.New("short data")
+	}
+	return b[:n], b[n:], nil
+}
+
+// enum is a decoded enum value
+type enum uint8
+
+// walk collects OBIS code/value pairs with optional scaler-unit from arrays and structures
+func walk(v any, res map[string]float64) {
+	l, ok := v.(list)
+	if !ok {
+		return
+	}
+
+	for i, e := range l {
+		name, ok := e.([]byte)
+		if !ok || i+1 >= len(l) {
+			walk(e, res)
+			continue
+		}
+
+		code, ok := obis.Code(name)
+		if !ok {
+			continue
+		}
+
+		f, ok := number(l[i+1])
+		if !ok {
+			continue
+		}
+
+		if i+2 < len(l) {
+			if exp, ok := l[i+2].(scaler); ok {
+				f *= math.Pow10(int(exp))
+			}
+		}
+
+		res[code] = f
+	}
+}
+
+func number(v any) (float64, bool) {
+	switch v := v.(type) {
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
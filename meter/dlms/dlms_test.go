@@ -0,0 +1,157 @@
+package dlms
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	key     = []byte{0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88, 0x99, 0xAA, 0xBB, 0xCC, 0xDD, 0xEE, 0xFF}
+	authKey = []byte{0xD0, 0xD1, 0xD2, 0xD3, 0xD4, 0xD5, 0xD6, 0xD7, 0xD8, 0xD9, 0xDA, 0xDB, 0xDC, 0xDD, 0xDE, 0xDF}
+	title   = []byte{0x4B, 0x46, 0x4D, 0x10, 0x20, 0x00, 0x00, 0x01}
+)
+
+// notification is a data-notification with a date-time header and a flat
+// structure of obis, value and scaler-unit triples
+var notification = []byte{
+	0x0F,                   // data-notification
+	0x00, 0x00, 0x00, 0x01, // long-invoke-id-and-priority
+	0x0C, 0x07, 0xEA, 0x0A, 0x12, 0x07, 0x0C, 0x00, 0x00, 0xFF, 0x80, 0x00, 0x00, // date-time
+	0x02, 0x07, // structure
+	0x09, 0x0C, 0x07, 0xEA, 0x0A, 0x12, 0x07, 0x0C, 0x00, 0x00, 0xFF, 0x80, 0x00, 0x00, // clock
+	0x09, 0x06, 0x01, 0x00, 0x01, 0x07, 0x00, 0xFF, // 1.7.0
+	0x06, 0x00, 0x00, 0x01, 0xF4, // 500
+	0x02, 0x02, 0x0F, 0x00, 0x16, 0x1B, // W
+	0x09, 0x06, 0x01, 0x00, 0x01, 0x08, 0x00, 0xFF, // 1.8.0
+	0x06, 0x00, 0x01, 0xE2, 0x40, // 123456
+	0x02, 0x02, 0x0F, 0xFF, 0x16, 0x1E, // 0.1 Wh
+}
+
+// encrypt wraps the APDU into a general-glo-ciphering APDU
+func encrypt(t *testing.T, apdu []byte) []byte {
+	block, err := aes.NewCipher(key)
+	require.NoError(t, err)
+
+	gcm, err := cipher.NewGCMWithTagSize(block, tagSize)
+	require.NoError(t, err)
+
+	sc, fc := byte(0x30), []byte{0x00, 0x00, 0x00, 0x2A}
+	iv := append(bytes.Clone(title), fc...)
+	ct := gcm.Seal(nil, iv, apdu, append([]byte{sc}, authKey...))
+
+	res := append([]byte{tagGeneralGloCiphering, 0x08}, title...)
+	res = append(res, 0x81, byte(1+len(fc)+len(ct)), sc)
+	res = append(res, fc...)
+	return append(res, ct...)
+}
+
+// mbus splits the APDU into M-Bus long frames with DLMS transport segments
+func mbus(apdu []byte, size int) []byte {
+	var res []byte
+
+	for i := 0; len(apdu) > 0; i++ {
+		n := min(size, len(apdu))
+		ci := byte(i)
+		if n == len(apdu) {
+			ci |= 0x10
+		}
+
+		data := append([]byte{0x53, 0xFF, ci, 0x67, 0x67}, apdu[:n]...)
+		apdu = apdu[n:]
+
+		var sum byte
+		for _, b := range data {
+			sum += b
+		}
+
+		res = append(res, 0x68, byte(len(data)), byte(len(data)), 0x68)
+		res = append(res, data...)
+		res = append(res, sum, 0x16)
+	}
+
+	return res
+}
+
+// hdlc wraps the APDU into a single HDLC frame
+func hdlc(apdu []byte) []byte {
+	info := append([]byte{0xE6, 0xE7, 0x00}, apdu...)
+	length := 2 + 3 + 2 + len(info) + 2 // format, addresses and control, hcs, info, fcs
+
+	frame := []byte{0xA0 | byte(length>>8), byte(length), 0x03, 0x21, 0x13}
+	hcs := checksum(frame)
+	frame = append(frame, byte(hcs), byte(hcs>>8))
+	frame = append(frame, info...)
+	fcs := checksum(frame)
+	frame = append(frame, byte(fcs), byte(fcs>>8))
+
+	return append(append([]byte{0x7E}, frame...), 0x7E)
+}
+
+func TestChecksum(t *testing.T) {
+	assert.Equal(t, uint16(0x906E), checksum([]byte("123456789")))
+}
+
+func TestReadFrame(t *testing.T) {
+	apdu := encrypt(t, notification)
+
+	corrupt := hdlc(apdu)
+	corrupt[len(corrupt)-2] ^= 0xFF
+
+	var stream []byte
+	stream = append(stream, 0x00, 0x01) // garbage
+	stream = append(stream, corrupt...)
+	stream = append(stream, hdlc(apdu)...)
+	stream = append(stream, mbus(apdu, 50)...)
+
+	r := bufio.NewReader(bytes.NewReader(stream))
+
+	_, err := ReadFrame(r)
+	assert.True(t, errors.Is(err, ErrInvalid))
+
+	for range 2 {
+		b, err := ReadFrame(r)
+		require.NoError(t, err)
+		assert.Equal(t, apdu, b)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	expected := map[string]float64{
+		"1-0:1.7.0": 500,
+		"1-0:1.8.0": 12345.6,
+	}
+
+	for _, tc := range []struct {
+		name    string
+		key     []byte
+		authKey []byte
+		apdu    []byte
+	}{
+		{"plain", nil, nil, notification},
+		{"encrypted", key, nil, encrypt(t, notification)},
+		{"authenticated", key, authKey, encrypt(t, notification)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			d, err := NewDecoder(tc.key, tc.authKey)
+			require.NoError(t, err)
+
+			res, err := d.Decode(tc.apdu)
+			require.NoError(t, err)
+			assert.Equal(t, expected, res)
+		})
+	}
+
+	// wrong authentication key
+	d, err := NewDecoder(key, key)
+	require.NoError(t, err)
+
+	_, err = d.Decode(encrypt(t, notification))
+	assert.Error(t, err)
+}
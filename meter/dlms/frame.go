@@ -0,0 +1,171 @@
+package dlms
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrInvalid is returned for a corrupt frame
+var ErrInvalid = errors.New("invalid frame")
+
+// maxApduSize bounds reassembly of segmented frames
+const maxApduSize = 8192
+
+// ReadFrame reads the next complete APDU from the stream, reassembling
+// segmented HDLC (7E…7E) or M-Bus long frames (68 L L 68…16).
+// Transport errors are returned as is, ErrInvalid indicates a corrupt frame
+// which should be skipped.
+func ReadFrame(r *bufio.Reader) ([]byte, error) {
+	var apdu []byte
+
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+
+		var segment []byte
+		var more bool
+
+		switch b {
+		case 0x7E:
+			// skip closing or repeated flags
+			if next, err := r.Peek(1); err != nil {
+				return nil, err
+			} else if next[0]&0xF0 != 0xA0 {
+				continue
+			}
+
+			segment, more, err = readHDLC(r)
+		case 0x68:
+			segment, more, err = readMBus(r)
+		default:
+			// skip garbage and inter-frame flags
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		apdu = append(apdu, segment...)
+		if len(apdu) > maxApduSize {
+			return nil, fmt.Errorf("%w: apdu too large", ErrInvalid)
+		}
+
+		if !more {
+			return apdu, nil
+		}
+	}
+}
+
+// readHDLC reads an HDLC frame after the opening flag and returns its information field.
+// The closing flag is left for the next read as it may be shared with the next frame.
+func readHDLC(r *bufio.Reader) ([]byte, bool, error) {
+	format := make([]byte, 2)
+	if _, err := io.ReadFull(r, format); err != nil {
+		return nil, false, err
+	}
+
+	// frame type 3
+	if format[0]&0xF0 != 0xA0 {
+		return nil, false, fmt.Errorf("%w: hdlc format % x", ErrInvalid, format)
+	}
+
+	length := int(format[0]&0x07)<<8 | int(format[1])
+	if length < 9 {
+		return nil, false, fmt.Errorf("%w: hdlc length %d", ErrInvalid, length)
+	}
+
+	frame := make([]byte, length)
+	copy(frame, format)
+	if _, err := io.ReadFull(r, frame[2:]); err != nil {
+		return nil, false, err
+	}
+
+	if fcs := checksum(frame[:length-2]); fcs != uint16(frame[length-2])|uint16(frame[length-1])<<8 {
+		return nil, false, fmt.Errorf("%w: hdlc checksum", ErrInvalid)
+	}
+
+	// skip destination and source address, terminated by lsb set
+	i := 2
+	for range 2 {
+		for i < length && frame[i]&0x01 == 0 {
+			i++
+		}
+		i++
+	}
+
+	// control and header check sequence
+	i += 3
+	if i > length-2 {
+		return nil, false, fmt.Errorf("%w: short hdlc frame", ErrInvalid)
+	}
+
+	info := frame[i : length-2]
+
+	// llc header
+	if len(info) >= 3 && info[0] == 0xE6 && (info[1] == 0xE7 || info[1] == 0xE6) {
+		info = info[3:]
+	}
+
+	return info, format[0]&0x08 != 0, nil
+}
+
+// readMBus reads an M-Bus long frame after the start byte and returns its DLMS segment
+func readMBus(r *bufio.Reader) ([]byte, bool, error) {
+	head := make([]byte, 3)
+	if _, err := io.ReadFull(r, head); err != nil {
+		return nil, false, err
+	}
+
+	if head[0] != head[1] || head[2] != 0x68 {
+		return nil, false, fmt.Errorf("%w: mbus header % x", ErrInvalid, head)
+	}
+
+	length := int(head[0])
+	frame := make([]byte, length+2)
+	if _, err := io.ReadFull(r, frame); err != nil {
+		return nil, false, err
+	}
+
+	if frame[length+1] != 0x16 {
+		return nil, false, fmt.Errorf("%w: missing mbus stop byte", ErrInvalid)
+	}
+
+	var sum byte
+	for _, b := range frame[:length] {
+		sum += b
+	}
+	if sum != frame[length] {
+		return nil, false, fmt.Errorf("%w: mbus checksum", ErrInvalid)
+	}
+
+	// control, address, control information, source and destination tsap
+	if length < 5 {
+		return nil, false, fmt.Errorf("%w: short mbus frame", ErrInvalid)
+	}
+
+	ci := frame[2]
+
+	return frame[5:length], ci&0x10 == 0, nil
+}
+
+// checksum computes the CRC-16/X-25 frame check sequence
+func checksum(data []byte) uint16 {
+	crc := uint16(0xFFFF)
+	for _, b := range data {
+		crc ^= uint16(b)
+		for range 8 {
+			if crc&1 != 0 {
+				crc = crc>>1 ^ 0x8408
+			} else {
+				crc >>= 1
+			}
+		}
+	}
+
+	return ^crc
+}
@@ -9,6 +9,7 @@ import (
 	"net"
 	"os"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -24,11 +25,13 @@ import (
 
 // Dsmr is a DSMR P1 meter. The raw P1 byte stream is provided by a pluggable
 // transport: a raw TCP socket for classic P1-to-LAN gateways, or a WebSocket.
+// SML and DLMS meters reuse it with their own frame reader.
 type Dsmr struct {
 	implement.Caps
 	mu      sync.Mutex
 	log     *util.Logger
 	dial    func() (io.ReadCloser, error)
+	read    func(*bufio.Reader) (map[string]string, error)
 	timeout time.Duration
 	frame   map[string]string
 	updated time.Time
@@ -72,15 +75,12 @@ func NewDsmrFromConfig(ctx context.Context, other map[string]any) (api.Meter, er
 func NewDsmr(ctx context.Context, uri string, timeout time.Duration) (api.Meter, error) {
 	dial := dsmrDialer(ctx, uri)
 
-	m, err := newDsmr(ctx, util.NewLogger("dsmr"), dial, timeout)
+	m, err := newDsmr(ctx, util.NewLogger("dsmr"), dial, nil, timeout)
 	if err != nil {
 		return nil, err
 	}
 
-	m.decorateEnergy()
-	m.decorateCurrents()
-	m.decorateVoltages()
-	m.decoratePowers()
+	m.decorate()
 
 	return m, nil
 }
@@ -103,8 +103,9 @@ func dsmrDialer(ctx context.Context, uri string) func() (io.ReadCloser, error) {
 }
 
 // newDsmr starts the read loop over the given transport and blocks until the
-// first valid frame so callers can probe the available registers.
-func newDsmr(ctx context.Context, log *util.Logger, dial func() (io.ReadCloser, error), timeout time.Duration) (*Dsmr, error) {
+// first valid frame so callers can probe the available registers. Frames are
+// decoded by read, defaulting to DSMR P1 telegrams.
+func newDsmr(ctx context.Context, log *util.Logger, dial func() (io.ReadCloser, error), read func(*bufio.Reader) (map[string]string, error), timeout time.Duration) (*Dsmr, error) {
 	conn, err := dial()
 	if err != nil {
 		return nil, err
@@ -114,10 +115,15 @@ func newDsmr(ctx context.Context, log *util.Logger, dial func() (io.ReadCloser,
 		Caps:    implement.New(),
 		log:     log,
 		dial:    dial,
+		read:    read,
 		timeout: timeout,
 	}
 	m.setConn(conn)
 
+	if m.read == nil {
+		m.read = m.readFrame
+	}
+
 	// close the active connection when ctx is canceled, unblocking a pending
 	// read so run observes ctx and returns
 	go func() {
@@ -153,6 +159,40 @@ func parseFrame(frame string) map[string]string {
 	return objects
 }
 
+// activePowerChannels are the OBIS C fields of active power and energy
+var activePowerChannels = []int{1, 2, 16, 21, 22, 36, 41, 42, 56, 61, 62, 76}
+
+// obisFrame converts SML or DLMS values in W and Wh into a frame using the
+// DSMR units kW and kWh. Signed power sums are mapped to import power.
+func obisFrame(values map[string]float64) map[string]string {
+	res := make(map[string]string, len(values))
+
+	for code, v := range values {
+		var c, d int
+		if _, err := fmt.Sscanf(code[strings.Index(code, ":")+1:], "%d.%d.", &c, &d); err == nil &&
+			slices.Contains(activePowerChannels, c) && (d == 4 || d == 7 || d == 8) {
+			v /= 1e3
+		}
+
+		res[code] = strconv.FormatFloat(v, 'f', -1, 64)
+	}
+
+	for sum, regs := range map[string][2]string{
+		obis.PowerSum:   {obis.PowerImport, obis.PowerExport},
+		obis.PowerSumL1: {obis.PowerImportL1, obis.PowerExportL1},
+		obis.PowerSumL2: {obis.PowerImportL2, obis.PowerExportL2},
+		obis.PowerSumL3: {obis.PowerImportL3, obis.PowerExportL3},
+	} {
+		if v, ok := res[sum]; ok {
+			if _, ok := res[regs[0]]; !ok {
+				res[regs[0]], res[regs[1]] = v, "0"
+			}
+		}
+	}
+
+	return res
+}
+
 // crc16ARC computes the CRC-16/ARC checksum (reflected, polynomial 0xA001,
 // init 0x0000) used to verify DSMR P1 telegrams.
 func crc16ARC(data []byte) uint16 {
@@ -211,7 +251,7 @@ func (m *Dsmr) run(ctx context.Context, conn io.ReadCloser, done chan struct{})
 			bo.Reset()
 		}
 
-		objects, err := m.readFrame(reader)
+		objects, err := m.read(reader)
 		if err != nil {
 			if ctx.Err() != nil {
 				return
@@ -286,6 +326,14 @@ func (m *Dsmr) hasObis(obis ...string) bool {
 	return true
 }
 
+// decorate registers the optional capabilities available in the first frame
+func (m *Dsmr) decorate() {
+	m.decorateEnergy()
+	m.decorateCurrents()
+	m.decorateVoltages()
+	m.decoratePowers()
+}
+
 // decorateEnergy registers MeterEnergy/MeterReturnEnergy when import/export
 // energy is available, either as the combined register or summed tariffs.
 func (m *Dsmr) decorateEnergy() {
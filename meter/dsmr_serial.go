@@ -0,0 +1,96 @@
+package meter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"go.bug.st/serial"
+)
+
+// serialReadTimeout bounds a single read. Meters push telegrams every few
+// seconds, so a read blocking this long means the reading head is disconnected.
+const serialReadTimeout = 30 * time.Second
+
+// obisDialer returns a transport dialer for a serial device if given,
+// otherwise for the TCP (serial bridge) or WebSocket uri.
+func obisDialer(ctx context.Context, uri, device string, baudrate int, comset string) (func() (io.ReadCloser, error), error) {
+	if device == "" {
+		return dsmrDialer(ctx, uri), nil
+	}
+
+	mode, err := serialMode(baudrate, comset)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() (io.ReadCloser, error) {
+		port, err := serial.Open(device, mode)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := port.SetReadTimeout(serialReadTimeout); err != nil {
+			port.Close()
+			return nil, err
+		}
+
+		return &serialReader{port}, nil
+	}, nil
+}
+
+// serialMode parses the communication settings, e.g. 8N1
+func serialMode(baudrate int, comset string) (*serial.Mode, error) {
+	comset = strings.ToUpper(comset)
+	if len(comset) != 3 {
+		return nil, fmt.Errorf("invalid comset: %s", comset)
+	}
+
+	mode := &serial.Mode{
+		BaudRate: baudrate,
+		DataBits: int(comset[0] - '0'),
+	}
+
+	switch comset[1] {
+	case 'N':
+		mode.Parity = serial.NoParity
+	case 'E':
+		mode.Parity = serial.EvenParity
+	case 'O':
+		mode.Parity = serial.OddParity
+	default:
+		return nil, fmt.Errorf("invalid comset: %s", comset)
+	}
+
+	switch comset[2] {
+	case '1':
+		mode.StopBits = serial.OneStopBit
+	case '2':
+		mode.StopBits = serial.TwoStopBits
+	default:
+		return nil, fmt.Errorf("invalid comset: %s", comset)
+	}
+
+	if mode.DataBits < 5 || mode.DataBits > 8 {
+		return nil, fmt.Errorf("invalid comset: %s", comset)
+	}
+
+	return mode, nil
+}
+
+// serialReader turns a read timeout, which the serial port signals as an
+// empty read, into an error to trigger a reconnect.
+type serialReader struct {
+	serial.Port
+}
+
+func (r *serialReader) Read(p []byte) (int, error) {
+	n, err := r.Port.Read(p)
+	if n == 0 && err == nil {
+		err = os.ErrDeadlineExceeded
+	}
+	return n, err
+}
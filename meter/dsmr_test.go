@@ -521,3 +521,33 @@ func TestDsmrPhaseGating(t *testing.T) {
 	_, ok = api.Cap[api.PhasePowers](m)
 	require.False(t, ok, "PhasePowers must not be offered without per-phase power")
 }
+
+// TestObisFrame verifies SML/DLMS values in W and Wh are converted to DSMR
+// units and a signed power sum is mapped to import power.
+func TestObisFrame(t *testing.T) {
+	m := newTestDsmr(obisFrame(map[string]float64{
+		obis.PowerSum:     -1500,
+		obis.EnergyImport: 12345600,
+		obis.CurrentL1:    6.5,
+		obis.VoltageL1:    231,
+	}))
+
+	require.Equal(t, map[string]string{
+		obis.PowerSum:     "-1.5",
+		obis.PowerImport:  "-1.5",
+		obis.PowerExport:  "0",
+		obis.EnergyImport: "12345.6",
+		obis.CurrentL1:    "6.5",
+		obis.VoltageL1:    "231",
+	}, m.frame)
+
+	p, err := m.CurrentPower()
+	require.NoError(t, err)
+	require.Equal(t, -1500.0, p)
+
+	me, ok := api.Cap[api.MeterEnergy](m)
+	require.True(t, ok, "MeterEnergy expected")
+	e, err := me.TotalEnergy()
+	require.NoError(t, err)
+	require.Equal(t, 12345.6, e)
+}
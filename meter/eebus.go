@@ -45,24 +45,27 @@ type EEBus struct {
 // MGCP and MPC use different scenario numbers for the same physical quantity, so
 // IsScenarioAvailableAtEntity must be called with the per-UC value.
 type maScenarios struct {
-	power    uint
-	energy   uint
-	currents uint
-	voltages uint
+	power     uint
+	energy    uint
+	currents  uint
+	voltages  uint
+	frequency uint
 }
 
 var (
 	mpcScenarios = maScenarios{
-		power:    eebus.MPCPower,
-		energy:   eebus.MPCEnergyConsumed,
-		currents: eebus.MPCCurrentPerPhase,
-		voltages: eebus.MPCVoltagePerPhase,
+		power:     eebus.MPCPower,
+		energy:    eebus.MPCEnergyConsumed,
+		currents:  eebus.MPCCurrentPerPhase,
+		voltages:  eebus.MPCVoltagePerPhase,
+		frequency: eebus.MPCFrequency,
 	}
 	mgcpScenarios = maScenarios{
-		power:    eebus.MGCPPower,
-		energy:   eebus.MGCPEnergyConsumed,
-		currents: eebus.MGCPCurrentPerPhase,
-		voltages: eebus.MGCPVoltagePerPhase,
+		power:     eebus.MGCPPower,
+		energy:    eebus.MGCPEnergyConsumed,
+		currents:  eebus.MGCPCurrentPerPhase,
+		voltages:  eebus.MGCPVoltagePerPhase,
+		frequency: eebus.MGCPFrequency,
 	}
 )
 
@@ -72,6 +75,7 @@ type measurements interface {
 	EnergyConsumed(entity spineapi.EntityRemoteInterface) (float64, error)
 	CurrentPerPhase(entity spineapi.EntityRemoteInterface) ([]float64, error)
 	VoltagePerPhase(entity spineapi.EntityRemoteInterface) ([]float64, error)
+	Frequency(entity spineapi.EntityRemoteInterface) (float64, error)
 }
 
 func init() {
@@ -204,6 +208,12 @@ func (c *EEBus) TotalEnergy() (float64, error) {
 	return c.readValue(c.scenarios.energy, c.mm.EnergyConsumed)
 }
 
+var _ api.MeterFrequency = (*EEBus)(nil)
+
+func (c *EEBus) Frequency() (float64, error) {
+	return c.readValue(c.scenarios.frequency, c.mm.Frequency)
+}
+
 func (c *EEBus) readPhases(scenario uint, update func(entity spineapi.EntityRemoteInterface) ([]float64, error)) (float64, float64, float64, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -197,6 +197,33 @@ func TestMGCP_SCE6_ACVoltage(t *testing.T) {
 	})
 }
 
+// SCE7: grid frequency (ATC_SCE7_*_MAFrequency_*)
+func TestMGCP_SCE7_Frequency(t *testing.T) {
+	t.Run("ATC_SCE7_PT_MAFrequency", func(t *testing.T) {
+		c, mm, entity := newMGCPMeter(t)
+		mm.EXPECT().IsScenarioAvailableAtEntity(entity, eebus.MGCPFrequency).Return(true)
+		mm.EXPECT().Frequency(entity).Return(49.98, nil)
+
+		f, err := c.Frequency()
+		require.NoError(t, err)
+		assert.Equal(t, 49.98, f)
+	})
+
+	// NT_*: error/out-of-range → discarded.
+	t.Run("ATC_SCE7_NT_MAFrequency", func(t *testing.T) {
+		for _, badErr := range nonNormalErrors {
+			t.Run(badErr.Error(), func(t *testing.T) {
+				c, mm, entity := newMGCPMeter(t)
+				mm.EXPECT().IsScenarioAvailableAtEntity(entity, eebus.MGCPFrequency).Return(true)
+				mm.EXPECT().Frequency(entity).Return(0.0, badErr)
+
+				_, err := c.Frequency()
+				assert.ErrorIs(t, err, api.ErrNotAvailable)
+			})
+		}
+	})
+}
+
 // Availability gating: an unannounced scenario or unconnected entity yields
 // ErrNotAvailable — the MA must not invent a value for an unsupported data point.
 func TestMGCP_ScenarioGating(t *testing.T) {
@@ -218,12 +245,13 @@ func TestMGCP_ScenarioGating(t *testing.T) {
 }
 
 // MGCP-TS-009: the MA supports at least one of SCE2/3/4. evcc wires SCE2, SCE4
-// plus SCE5/SCE6; these compile-time assertions guard the capabilities.
+// plus SCE5/SCE6/SCE7; these compile-time assertions guard the capabilities.
 var (
-	_ api.Meter         = (*EEBus)(nil)
-	_ api.MeterEnergy   = (*EEBus)(nil)
-	_ api.PhaseCurrents = (*EEBus)(nil)
-	_ api.PhaseVoltages = (*EEBus)(nil)
+	_ api.Meter          = (*EEBus)(nil)
+	_ api.MeterEnergy    = (*EEBus)(nil)
+	_ api.PhaseCurrents  = (*EEBus)(nil)
+	_ api.PhaseVoltages  = (*EEBus)(nil)
+	_ api.MeterFrequency = (*EEBus)(nil)
 )
 
 // TestMGCPNonCoverage records the MA abstract test cases intentionally out of
@@ -233,8 +261,6 @@ func TestMGCPNonCoverage(t *testing.T) {
 		"ATC_SCE1_PT_MAPowerLimitFactor_001",  // power-limit factor not exposed by api.Meter
 		"ATC_SCE3_PT_MATotalFeedInEnergy_001", // feed-in energy: evcc reads consumed energy (SCE4) only
 		"ATC_SCE3_NT_MATotalFeedInEnergy_002",
-		"ATC_SCE7_PT_MAFrequency_001", // grid frequency not exposed by api.Meter
-		"ATC_SCE7_NT_MAFrequency_002",
 		"ATC_COM_PT_MAPolling_001",      // polling cadence owned by eebus-go
 		"ATC_COM_PT_MANotification_001", // notification timing owned by eebus-go
 	} {
@@ -150,12 +150,24 @@ func TestMPC_SCE4_ACVoltage(t *testing.T) {
 	})
 }
 
+// SCE5: grid frequency (ATC_SCE5_*_MAFrequency_*)
+func TestMPC_SCE5_Frequency(t *testing.T) {
+	t.Run("ATC_SCE5_PT_MAFrequency", func(t *testing.T) {
+		c, mm, entity := newMPCMeter(t)
+		mm.EXPECT().IsScenarioAvailableAtEntity(entity, eebus.MPCFrequency).Return(true)
+		mm.EXPECT().Frequency(entity).Return(50.02, nil)
+
+		f, err := c.Frequency()
+		require.NoError(t, err)
+		assert.Equal(t, 50.02, f)
+	})
+}
+
 // TestMPCNonCoverage records MPC MA abstract test cases out of scope for evcc.
 func TestMPCNonCoverage(t *testing.T) {
 	for _, atc := range []string{
 		"ATC_SCE1_PT_MAPhaseActivePower_001",    // per-phase active power not exposed by api.Meter
 		"ATC_SCE2_PT_MATotalProducedEnergy_001", // produced energy not exposed (consumed only)
-		"ATC_SCE5_PT_MAFrequency_001",           // grid frequency not exposed by api.Meter
 		"ATC_COM_PT_MAPolling_001",              // polling cadence owned by eebus-go
 		"ATC_COM_PT_MANotification_001",         // notification timing owned by eebus-go
 	} {
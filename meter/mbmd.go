@@ -38,6 +38,9 @@ func NewMbmdFromConfig(ctx context.Context, other map[string]any) (api.Meter, er
 		Currents           []string
 		Voltages           []string
 		Powers             []string
+		ReactivePower      string
+		PowerFactor        string
+		Frequency          string
 	}{
 		Power: "Power",
 		Settings: modbus.Settings{
@@ -140,6 +143,33 @@ func NewMbmdFromConfig(ctx context.Context, other map[string]any) (api.Meter, er
 	}
 	implement.May(m, implement.PhasePowers(powersG))
 
+	// decorate reactive power
+	if cc.ReactivePower != "" {
+		reactivePower, err := mbmd.deviceOp(ops, cc.ReactivePower)
+		if err != nil {
+			return nil, fmt.Errorf("invalid measurement for reactivepower: %s", cc.ReactivePower)
+		}
+		implement.Has(m, implement.MeterReactivePower(reactivePower))
+	}
+
+	// decorate power factor
+	if cc.PowerFactor != "" {
+		powerFactor, err := mbmd.deviceOp(ops, cc.PowerFactor)
+		if err != nil {
+			return nil, fmt.Errorf("invalid measurement for powerfactor: %s", cc.PowerFactor)
+		}
+		implement.Has(m, implement.MeterPowerFactor(powerFactor))
+	}
+
+	// decorate frequency
+	if cc.Frequency != "" {
+		frequency, err := mbmd.deviceOp(ops, cc.Frequency)
+		if err != nil {
+			return nil, fmt.Errorf("invalid measurement for frequency: %s", cc.Frequency)
+		}
+		implement.Has(m, implement.MeterFrequency(frequency))
+	}
+
 	return m, nil
 }
 
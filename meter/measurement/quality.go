@@ -0,0 +1,38 @@
+package measurement
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/evcc-io/evcc/plugin"
+)
+
+type Quality struct {
+	ReactivePower *plugin.Config // optional
+	PowerFactor   *plugin.Config // optional
+	Frequency     *plugin.Config // optional
+}
+
+func (cc *Quality) Configure(ctx context.Context) (
+	func() (float64, error),
+	func() (float64, error),
+	func() (float64, error),
+	error,
+) {
+	reactivePowerG, err := cc.ReactivePower.FloatGetter(ctx)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("reactivePower: %w", err)
+	}
+
+	powerFactorG, err := cc.PowerFactor.FloatGetter(ctx)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("powerFactor: %w", err)
+	}
+
+	frequencyG, err := cc.Frequency.FloatGetter(ctx)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("frequency: %w", err)
+	}
+
+	return reactivePowerG, powerFactorG, frequencyG, nil
+}
@@ -20,6 +20,7 @@ func NewConfigurableFromConfig(ctx context.Context, other map[string]any) (api.M
 	cc := struct {
 		measurement.Energy    `mapstructure:",squash"` // energy optional
 		measurement.Phases    `mapstructure:",squash"` // optional
+		measurement.Quality   `mapstructure:",squash"` // optional
 		measurement.Dimmer    `mapstructure:",squash"` // optional
 		measurement.Curtailer `mapstructure:",squash"` // optional
 
@@ -129,6 +130,15 @@ func NewConfigurableFromConfig(ctx context.Context, other map[string]any) (api.M
 	implement.May(m, implement.PhasePowers(powersG))
 	implement.May(m, implement.MaxACPowerGetter(cc.pvMaxACPower.Decorator()))
 
+	reactivePowerG, powerFactorG, frequencyG, err := cc.Quality.Configure(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	implement.May(m, implement.MeterReactivePower(reactivePowerG))
+	implement.May(m, implement.MeterPowerFactor(powerFactorG))
+	implement.May(m, implement.MeterFrequency(frequencyG))
+
 	return m, nil
 }
 
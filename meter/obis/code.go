@@ -0,0 +1,12 @@
+package obis
+
+import "fmt"
+
+// Code formats a 6 byte OBIS value as reduced ID-code A-B:C.D.E, e.g. 1-0:1.8.0
+func Code(b []byte) (string, bool) {
+	if len(b) != 6 {
+		return "", false
+	}
+
+	return fmt.Sprintf("%d-%d:%d.%d.%d", b[0], b[1], b[2], b[3], b[4]), true
+}
@@ -67,3 +67,12 @@ const (
 	VoltageDemandL2 = "1-0:52.4.0"
 	VoltageDemandL3 = "1-0:72.4.0"
 )
+
+// Active power sum (kW), signed: import positive, export negative. Used by SML meters.
+const (
+	PowerSum = "1-0:16.7.0"
+
+	PowerSumL1 = "1-0:36.7.0"
+	PowerSumL2 = "1-0:56.7.0"
+	PowerSumL3 = "1-0:76.7.0"
+)
@@ -0,0 +1,89 @@
+package meter
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/meter/sml"
+	"github.com/evcc-io/evcc/util"
+)
+
+func init() {
+	registry.AddCtx("sml", NewSmlFromConfig)
+}
+
+// NewSmlFromConfig creates an SML meter reading push telegrams from an optical
+// reading head, either attached to a serial device or via a TCP serial bridge
+//
+//	type:     sml
+//	device:   /dev/ttyUSB0   # serial device, or
+//	uri:      192.168.1.20:8088 # tcp serial bridge
+//	baudrate: 9600
+//	comset:   8N1
+func NewSmlFromConfig(ctx context.Context, other map[string]any) (api.Meter, error) {
+	cc := struct {
+		URI      string
+		Device   string
+		Baudrate int
+		Comset   string
+		Timeout  time.Duration
+	}{
+		Baudrate: 9600,
+		Comset:   "8N1",
+		Timeout:  15 * time.Second,
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	if cc.URI == "" && cc.Device == "" {
+		return nil, errors.New("missing uri or device")
+	}
+
+	dial, err := obisDialer(ctx, cc.URI, cc.Device, cc.Baudrate, cc.Comset)
+	if err != nil {
+		return nil, err
+	}
+
+	log := util.NewLogger("sml")
+
+	m, err := newDsmr(ctx, log, dial, smlReader(log), cc.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	m.decorate()
+
+	return m, nil
+}
+
+// smlReader returns a frame reader decoding SML files. Corrupt files are
+// logged and skipped, only transport errors are returned.
+func smlReader(log *util.Logger) func(*bufio.Reader) (map[string]string, error) {
+	return func(reader *bufio.Reader) (map[string]string, error) {
+		for {
+			b, err := sml.ReadFile(reader)
+			if errors.Is(err, sml.ErrInvalid) {
+				log.ERROR.Println(err)
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			log.TRACE.Printf("read: % x", b)
+
+			values, err := sml.Parse(b)
+			if err != nil {
+				log.ERROR.Printf("parse: %v", err)
+				continue
+			}
+
+			return obisFrame(values), nil
+		}
+	}
+}
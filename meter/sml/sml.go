@@ -0,0 +1,260 @@
+// Package sml decodes Smart Message Language (SML) push telegrams as sent by
+// German smart meters (eHZ, "moderne Messeinrichtung") over the optical interface.
+//
+// SML files are framed by the transport escape sequence and 4-byte aligned:
+//
+//	1B 1B 1B 1B 01 01 01 01 [messages…] 1B 1B 1B 1B 1A [pad] [CRC-16/X-25]
+package sml
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/evcc-io/evcc/meter/obis"
+)
+
+// maxFileSize bounds a single SML file to resync on garbage
+const maxFileSize = 8192
+
+var (
+	escape = []byte{0x1B, 0x1B, 0x1B, 0x1B}
+	begin  = []byte{0x01, 0x01, 0x01, 0x01}
+)
+
+// ErrInvalid is returned for a corrupt file
+var ErrInvalid = errors.New("invalid file")
+
+// ReadFile reads the next SML file from the stream and returns the messages
+// with transport escaping and padding removed. Transport errors are returned
+// as is, ErrInvalid indicates a corrupt file which should be skipped.
+func ReadFile(r *bufio.Reader) ([]byte, error) {
+	start := append(bytes.Clone(escape), begin...)
+
+	// sync to start sequence
+	window := make([]byte, 0, len(start))
+	for !bytes.Equal(window, start) {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(window) == len(start) {
+			window = window[1:]
+		}
+		window = append(window, b)
+	}
+
+	file := bytes.Clone(start)
+	var body []byte
+
+	block := make([]byte, 4)
+	for len(file) < maxFileSize {
+		if _, err := io.ReadFull(r, block); err != nil {
+			return nil, err
+		}
+		file = append(file, block...)
+
+		if !bytes.Equal(block, escape) {
+			body = append(body, block...)
+			continue
+		}
+
+		if _, err := io.ReadFull(r, block); err != nil {
+			return nil, err
+		}
+		file = append(file, block...)
+
+		switch {
+		case bytes.Equal(block, escape):
+			// escaped escape sequence
+			body = append(body, block...)
+
+		case bytes.Equal(block, begin):
+			// restart
+			file, body = bytes.Clone(start), nil
+
+		case block[0] == 0x1A:
+			pad := int(block[1])
+			if pad > 3 || pad > len(body) {
+				return nil, fmt.Errorf("%w: padding %d", ErrInvalid, pad)
+			}
+
+			if crc := checksum(file[:len(file)-2]); crc != uint16(block[2])|uint16(block[3])<<8 {
+				return nil, fmt.Errorf("%w: crc mismatch", ErrInvalid)
+			}
+
+			return body[:len(body)-pad], nil
+
+		default:
+			return nil, fmt.Errorf("%w: escape sequence % x", ErrInvalid, block)
+		}
+	}
+
+	return nil, fmt.Errorf("%w: too large", ErrInvalid)
+}
+
+// checksum computes the CRC-16/X-25 (reflected, polynomial 0x8408, init and xorout 0xFFFF)
+func checksum(data []byte) uint16 {
+	crc := uint16(0xFFFF)
+	for _, b := range data {
+		crc ^= uint16(b)
+		for range 8 {
+			if crc&1 != 0 {
+				crc = crc>>1 ^ 0x8408
+			} else {
+				crc >>= 1
+			}
+		}
+	}
+
+	return ^crc
+}
+
+// Parse decodes the messages of an SML file and returns the scaled list
+// entry values keyed by OBIS code, e.g. 1-0:16.7.0 in W or 1-0:1.8.0 in Wh
+func Parse(b []byte) (map[string]float64, error) {
+	res := make(map[string]float64)
+
+	for len(b) > 0 {
+		// end of message or padding
+		if b[0] == 0x00 {
+			b = b[1:]
+			continue
+		}
+
+		v, rest, err := decode(b)
+		if err != nil {
+			return nil, err
+		}
+
+		walk(v, res)
+		b = rest
+	}
+
+	return res, nil
+}
+
+// list is a decoded SML sequence
+type list []any
+
+// decode decodes a single TL-encoded SML element
+func decode(b []byte) (any, []byte, error) {
+	if len(b) == 0 {
+		return nil, nil, errors.New("short data")
+	}
+
+	// end of message
+	if b[0] == 0x00 {
+		return nil, b[1:], nil
+	}
+
+	typ := b[0] & 0x70
+	length := int(b[0] & 0x0F)
+
+	n := 1
+	for b[n-1]&0x80 != 0 {
+		if n >= len(b) {
+			return nil, nil, errors.New("short data")
+		}
+		length = length<<4 | int(b[n]&0x0F)
+		n++
+	}
+
+	if typ == 0x70 {
+		res := make(list, 0, length)
+		b = b[n:]
+
+		for range length {
+			v, rest, err := decode(b)
+			if err != nil {
+				return nil, nil, err
+			}
+			res = append(res, v)
+			b = rest
+		}
+
+		return res, b, nil
+	}
+
+	// length includes type-length field
+	if length < n || length > len(b) {
+		return nil, nil, fmt.Errorf("invalid length: %d", length)
+	}
+
+	data := b[n:length]
+	rest := b[length:]
+
+	switch typ {
+	case 0x00:
+		// empty optional
+		if len(data) == 0 {
+			return nil, rest, nil
+		}
+		return data, rest, nil
+
+	case 0x40:
+		return len(data) > 0 && data[0] != 0, rest, nil
+
+	case 0x50:
+		var v int64
+		for i, c := range data {
+			if i == 0 {
+				v = int64(int8(c))
+			} else {
+				v = v<<8 | int64(c)
+			}
+		}
+		return v, rest, nil
+
+	case 0x60:
+		var v uint64
+		for _, c := range data {
+			v = v<<8 | uint64(c)
+		}
+		return v, rest, nil
+
+	default:
+		return nil, nil, fmt.Errorf("invalid type: %#x", typ)
+	}
+}
+
+// walk collects list entries (objName, status, valTime, unit, scaler, value, valueSignature)
+func walk(v any, res map[string]float64) {
+	l, ok := v.(list)
+	if !ok {
+		return
+	}
+
+	if len(l) == 7 {
+		name, _ := l[0].([]byte)
+		if code, ok := obis.Code(name); ok {
+			if f, ok := number(l[5]); ok {
+				if scaler, ok := l[4].(int64); ok {
+					f *= math.Pow10(int(scaler))
+				}
+
+				res[code] = f
+				return
+			}
+		}
+	}
+
+	for _, e := range l {
+		walk(e, res)
+	}
+}
+
+func number(v any) (float64, bool) {
+	switch v := v.(type) {
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,80 @@
+package sml
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// getListResponse is an SML_GetList.Res message with 1.8.0 (scaler -1) and 16.7.0 entries
+var getListResponse = []byte{
+	0x76,                         // message
+	0x05, 0x01, 0x02, 0x03, 0x04, // transactionId
+	0x62, 0x00, // groupNo
+	0x62, 0x00, // abortOnError
+	0x72,             // messageBody
+	0x63, 0x07, 0x01, // SML_GetList.Res
+	0x77,
+	0x01,                                                             // clientId
+	0x0B, 0x0A, 0x01, 0x44, 0x5A, 0x47, 0x00, 0x02, 0x82, 0x2C, 0x0F, // serverId
+	0x07, 0x01, 0x00, 0x62, 0x0A, 0xFF, 0xFF, // listName
+	0x72, 0x62, 0x01, 0x65, 0x00, 0x00, 0x00, 0x01, // actSensorTime
+	0x72, // valList
+	0x77, 0x07, 0x01, 0x00, 0x01, 0x08, 0x00, 0xFF, 0x65, 0x00, 0x1C, 0x01, 0x04, 0x01, 0x62, 0x1E, 0x52, 0xFF, 0x59, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0xE2, 0x40, 0x01,
+	0x77, 0x07, 0x01, 0x00, 0x10, 0x07, 0x00, 0xFF, 0x01, 0x01, 0x62, 0x1B, 0x52, 0x00, 0x55, 0xFF, 0xFF, 0xFE, 0x0C, 0x01,
+	0x01,             // listSignature
+	0x01,             // actGatewayTime
+	0x63, 0x12, 0x34, // crc16
+	0x00, // endOfSmlMsg
+}
+
+// file wraps messages into an SML transport v1 file
+func file(messages []byte) []byte {
+	res := append(bytes.Clone(escape), begin...)
+	res = append(res, bytes.ReplaceAll(messages, escape, append(bytes.Clone(escape), escape...))...)
+
+	pad := (4 - len(messages)%4) % 4
+	res = append(res, make([]byte, pad)...)
+	res = append(res, escape...)
+	res = append(res, 0x1A, byte(pad))
+
+	crc := checksum(res)
+	return append(res, byte(crc), byte(crc>>8))
+}
+
+func TestChecksum(t *testing.T) {
+	assert.Equal(t, uint16(0x906E), checksum([]byte("123456789")))
+}
+
+func TestReadFile(t *testing.T) {
+	corrupt := file(getListResponse)
+	corrupt[len(corrupt)-1] ^= 0xFF
+
+	var stream []byte
+	stream = append(stream, 0xAA, 0x1B, 0x1B) // garbage
+	stream = append(stream, corrupt...)
+	stream = append(stream, file(getListResponse)...)
+
+	r := bufio.NewReader(bytes.NewReader(stream))
+
+	_, err := ReadFile(r)
+	assert.True(t, errors.Is(err, ErrInvalid))
+
+	b, err := ReadFile(r)
+	require.NoError(t, err)
+	assert.Equal(t, getListResponse, b)
+}
+
+func TestParse(t *testing.T) {
+	res, err := Parse(getListResponse)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]float64{
+		"1-0:1.8.0":  12345.6,
+		"1-0:16.7.0": -500,
+	}, res)
+}
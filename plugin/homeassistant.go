@@ -38,6 +38,9 @@ func NewHomeAssistantPluginFromConfig(ctx context.Context, other map[string]any)
 		return nil, err
 	}
 
+	// push state changes to the site
+	conn.Listen(cc.Entity, func() { util.Notify(ctx) })
+
 	return &HomeAssistant{
 		conn:   conn,
 		entity: cc.Entity,
@@ -62,8 +62,11 @@ func setConsole(vm *otto.Otto, suffix string) error {
 		name = name + "-" + suffix
 	}
 
-	log := util.NewLogger(name)
+	return SetConsole(vm, util.NewLogger(name))
+}
 
+// SetConsole sets the VM's console to print to the given logger
+func SetConsole(vm *otto.Otto, log *util.Logger) error {
 	console := map[string]any{
 		"trace": printer(log.TRACE),
 		"log":   printer(log.DEBUG),
@@ -0,0 +1,199 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/plugin/knx"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+)
+
+// Knx provider
+type Knx struct {
+	ctx     context.Context
+	log     *util.Logger
+	conn    *knx.Connection
+	ga      knx.GroupAddress
+	dpt     knx.DPT
+	scale   float64
+	timeout time.Duration
+
+	mu      sync.Mutex
+	val     []byte
+	updated time.Time
+}
+
+func init() {
+	registry.AddCtx("knx", NewKnxFromConfig)
+}
+
+// NewKnxFromConfig creates a KNX provider. Received group telegrams are cached
+// and returned while younger than timeout, otherwise the group address is read.
+//
+//	source:  knx
+//	uri:     192.168.1.10    # KNXnet/IP interface (tunnel) or multicast group (routing, default 224.0.23.12), port 3671
+//	mode:    tunnel          # tunnel (default) | routing
+//	address: 1/2/3           # group address
+//	dpt:     14.056          # datapoint type: 1.x bool, 5.001 percent, 9.x float16, 13.x energy, 14.x float32, ...
+//	scale:   1.0             # optional multiplier for getters (default 1.0)
+//	timeout: 1m              # max age of received values (0 always reads)
+func NewKnxFromConfig(ctx context.Context, other map[string]any) (Plugin, error) {
+	cc := struct {
+		URI     string
+		Mode    knx.Mode
+		Address string
+		DPT     string
+		Scale   float64
+		Timeout time.Duration
+	}{
+		Mode:    knx.Tunnel,
+		Scale:   1,
+		Timeout: time.Minute,
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	ga, err := knx.ParseGroupAddress(cc.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	dpt, err := knx.ParseDPT(cc.DPT)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := knx.NewConnection(cc.URI, cc.Mode)
+	if err != nil {
+		return nil, fmt.Errorf("knx: %w", err)
+	}
+
+	log := util.ContextLoggerWithDefault(ctx, util.NewLogger("knx"))
+
+	return NewKnx(ctx, log, conn, ga, dpt, cc.Scale, cc.Timeout), nil
+}
+
+// NewKnx creates KNX provider for given group address
+func NewKnx(ctx context.Context, log *util.Logger, conn *knx.Connection, ga knx.GroupAddress, dpt knx.DPT, scale float64, timeout time.Duration) *Knx {
+	p := &Knx{
+		ctx:     ctx,
+		log:     log,
+		conn:    conn,
+		ga:      ga,
+		dpt:     dpt,
+		scale:   scale,
+		timeout: timeout,
+	}
+
+	conn.Subscribe(ga, p.receive)
+
+	return p
+}
+
+func (p *Knx) receive(t knx.Telegram) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.val = t.Data
+	p.updated = time.Now()
+}
+
+// value returns the cached value or reads the group address if outdated
+func (p *Knx) value() (float64, error) {
+	p.mu.Lock()
+	data, updated := p.val, p.updated
+	p.mu.Unlock()
+
+	if data == nil || time.Since(updated) > p.timeout {
+		p.log.TRACE.Printf("read %s", p.ga)
+
+		ctx, cancel := context.WithTimeout(p.ctx, request.Timeout)
+		defer cancel()
+
+		var err error
+		if data, err = p.conn.Read(ctx, p.ga); err != nil {
+			return 0, fmt.Errorf("%s: %w", p.ga, err)
+		}
+	}
+
+	f, err := p.dpt.Decode(data)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", p.ga, err)
+	}
+
+	return f * p.scale, nil
+}
+
+var _ FloatGetter = (*Knx)(nil)
+
+// FloatGetter returns the decoded group address value
+func (p *Knx) FloatGetter() (func() (float64, error), error) {
+	return p.value, nil
+}
+
+var _ IntGetter = (*Knx)(nil)
+
+// IntGetter returns the decoded group address value
+func (p *Knx) IntGetter() (func() (int64, error), error) {
+	return func() (int64, error) {
+		f, err := p.value()
+		return int64(f), err
+	}, nil
+}
+
+var _ BoolGetter = (*Knx)(nil)
+
+// BoolGetter returns true if the decoded group address value is not zero
+func (p *Knx) BoolGetter() (func() (bool, error), error) {
+	return func() (bool, error) {
+		f, err := p.value()
+		return f != 0, err
+	}, nil
+}
+
+// write encodes and writes a value to the group address
+func (p *Knx) write(v float64) error {
+	data, err := p.dpt.Encode(v)
+	if err == nil {
+		err = p.conn.Write(p.ga, data)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", p.ga, err)
+	}
+
+	return nil
+}
+
+var _ FloatSetter = (*Knx)(nil)
+
+// FloatSetter writes the encoded value to the group address
+func (p *Knx) FloatSetter(_ string) (func(float64) error, error) {
+	return p.write, nil
+}
+
+var _ IntSetter = (*Knx)(nil)
+
+// IntSetter writes the encoded value to the group address
+func (p *Knx) IntSetter(_ string) (func(int64) error, error) {
+	return func(v int64) error {
+		return p.write(float64(v))
+	}, nil
+}
+
+var _ BoolSetter = (*Knx)(nil)
+
+// BoolSetter writes the encoded value to the group address
+func (p *Knx) BoolSetter(_ string) (func(bool) error, error) {
+	return func(v bool) error {
+		var f float64
+		if v {
+			f = 1
+		}
+		return p.write(f)
+	}, nil
+}
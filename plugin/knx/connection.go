@@ -0,0 +1,365 @@
+package knx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+)
+
+// Mode selects the KNXnet/IP communication mode
+type Mode string
+
+const (
+	Tunnel  Mode = "tunnel"
+	Routing Mode = "routing"
+)
+
+const (
+	connectTimeout    = 10 * time.Second
+	ackTimeout        = time.Second
+	heartbeatInterval = time.Minute
+)
+
+// map of created connections
+var (
+	connections      = make(map[string]*Connection)
+	connectionsMutex sync.Mutex
+)
+
+// Connection is a shared KNXnet/IP tunnel or routing connection
+type Connection struct {
+	log  *util.Logger
+	mode Mode
+	conn *net.UDPConn
+	addr *net.UDPAddr
+	resp chan []byte
+
+	mu        sync.Mutex // serializes requests and guards tunnel state
+	connected atomic.Bool
+	channel   byte
+	seq       byte
+
+	listenersMu sync.RWMutex
+	listeners   map[int]listener
+	id          int
+}
+
+type listener struct {
+	ga GroupAddress
+	fn func(Telegram)
+}
+
+// NewConnection returns a shared connection to a KNXnet/IP gateway (tunnel) or multicast group (routing)
+func NewConnection(uri string, mode Mode) (*Connection, error) {
+	if mode == "" {
+		mode = Tunnel
+	}
+
+	if uri == "" && mode == Routing {
+		uri = MulticastAddr
+	}
+	uri = util.DefaultPort(uri, Port)
+
+	connectionsMutex.Lock()
+	defer connectionsMutex.Unlock()
+
+	key := string(mode) + "://" + uri
+	if c, ok := connections[key]; ok {
+		return c, nil
+	}
+
+	addr, err := net.ResolveUDPAddr("udp4", uri)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Connection{
+		log:       util.NewLogger("knx"),
+		mode:      mode,
+		addr:      addr,
+		resp:      make(chan []byte, 8),
+		listeners: make(map[int]listener),
+	}
+
+	switch mode {
+	case Tunnel:
+		c.conn, err = net.DialUDP("udp4", nil, addr)
+	case Routing:
+		c.conn, err = net.ListenMulticastUDP("udp4", nil, addr)
+	default:
+		err = fmt.Errorf("invalid mode: %s", mode)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	go c.receive()
+
+	if mode == Tunnel {
+		c.mu.Lock()
+		err := c.connect()
+		c.mu.Unlock()
+
+		if err != nil {
+			_ = c.conn.Close()
+			return nil, err
+		}
+
+		go c.heartbeat()
+	}
+
+	connections[key] = c
+
+	return c, nil
+}
+
+func (c *Connection) write(b []byte) error {
+	var err error
+	if c.mode == Routing {
+		_, err = c.conn.WriteToUDP(b, c.addr)
+	} else {
+		_, err = c.conn.Write(b)
+	}
+	return err
+}
+
+// receive handles incoming frames
+func (c *Connection) receive() {
+	buf := make([]byte, 1024)
+
+	for {
+		n, err := c.conn.Read(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			c.log.ERROR.Println(err)
+			continue
+		}
+
+		service, body, err := parseFrame(buf[:n])
+		if err != nil {
+			c.log.TRACE.Printf("recv: %v", err)
+			continue
+		}
+
+		switch service {
+		case tunnelingRequest:
+			if len(body) < 4 || len(body) < int(body[0]) {
+				continue
+			}
+
+			if err := c.write(frame(tunnelingAck, []byte{0x04, body[1], body[2], 0x00})); err != nil {
+				c.log.ERROR.Printf("ack: %v", err)
+			}
+
+			c.dispatch(body[body[0]:])
+
+		case routingIndication:
+			c.dispatch(body)
+
+		case disconnectRequest:
+			if len(body) > 0 {
+				_ = c.write(frame(disconnectResponse, []byte{body[0], 0x00}))
+			}
+
+			c.connected.Store(false)
+
+		case connectResponse, connectionStateResponse, disconnectResponse, tunnelingAck:
+			select {
+			case c.resp <- append([]byte{byte(service >> 8), byte(service)}, body...):
+			default:
+			}
+		}
+	}
+}
+
+// dispatch notifies listeners of group value writes and responses
+func (c *Connection) dispatch(b []byte) {
+	code, t, err := decodeCEMI(b)
+	if err != nil || code == lDataCon || t.APCI == GroupValueRead {
+		return
+	}
+
+	c.log.TRACE.Printf("recv %s %s: % x", t.Destination, t.APCI, t.Data)
+
+	c.listenersMu.RLock()
+	defer c.listenersMu.RUnlock()
+
+	for _, l := range c.listeners {
+		if l.ga == t.Destination {
+			l.fn(t)
+		}
+	}
+}
+
+// request sends a frame and waits for the matching response body. Must be called with mu held.
+func (c *Connection) request(b []byte, service uint16, match func([]byte) bool, timeout time.Duration) ([]byte, error) {
+	// drain stale responses
+	for len(c.resp) > 0 {
+		<-c.resp
+	}
+
+	if err := c.write(b); err != nil {
+		return nil, err
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case res := <-c.resp:
+			if uint16(res[0])<<8|uint16(res[1]) == service && (match == nil || match(res[2:])) {
+				return res[2:], nil
+			}
+		case <-timer.C:
+			return nil, api.ErrTimeout
+		}
+	}
+}
+
+// connect establishes the tunnel connection. Must be called with mu held.
+func (c *Connection) connect() error {
+	res, err := c.request(frame(connectRequest, hpai, hpai, tunnelCRI), connectResponse, nil, connectTimeout)
+	if err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+
+	if len(res) < 2 {
+		return errors.New("connect: invalid response")
+	}
+
+	if res[1] != 0 {
+		return fmt.Errorf("connect: status %#x", res[1])
+	}
+
+	c.channel = res[0]
+	c.seq = 0
+	c.connected.Store(true)
+
+	return nil
+}
+
+// heartbeat monitors the tunnel connection and reconnects if lost
+func (c *Connection) heartbeat() {
+	for range time.Tick(heartbeatInterval) {
+		c.mu.Lock()
+
+		if c.connected.Load() {
+			channel := c.channel
+			res, err := c.request(frame(connectionStateRequest, []byte{channel, 0x00}, hpai), connectionStateResponse, func(b []byte) bool {
+				return len(b) >= 2 && b[0] == channel
+			}, connectTimeout)
+
+			if err != nil || res[1] != 0 {
+				c.log.WARN.Println("connection lost")
+				c.connected.Store(false)
+			}
+		}
+
+		if !c.connected.Load() {
+			if err := c.connect(); err != nil {
+				c.log.ERROR.Println(err)
+			}
+		}
+
+		c.mu.Unlock()
+	}
+}
+
+// Send sends a group telegram
+func (c *Connection) Send(t Telegram) error {
+	c.log.TRACE.Printf("send %s %s: % x", t.Destination, t.APCI, t.Data)
+
+	if c.mode == Routing {
+		return c.write(frame(routingIndication, encodeCEMI(lDataInd, t)))
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.connected.Load() {
+		if err := c.connect(); err != nil {
+			return err
+		}
+	}
+
+	channel, seq := c.channel, c.seq
+	b := frame(tunnelingRequest, []byte{0x04, channel, seq, 0x00}, encodeCEMI(lDataReq, t))
+
+	match := func(b []byte) bool {
+		return len(b) >= 4 && b[1] == channel && b[2] == seq
+	}
+
+	// repeat once if not acknowledged
+	res, err := c.request(b, tunnelingAck, match, ackTimeout)
+	if err != nil {
+		res, err = c.request(b, tunnelingAck, match, ackTimeout)
+	}
+
+	if err != nil {
+		c.connected.Store(false)
+		return fmt.Errorf("send: %w", err)
+	}
+
+	c.seq++
+
+	if res[3] != 0 {
+		return fmt.Errorf("send: status %#x", res[3])
+	}
+
+	return nil
+}
+
+// Write sends a group value write telegram
+func (c *Connection) Write(ga GroupAddress, data []byte) error {
+	return c.Send(Telegram{Destination: ga, APCI: GroupValueWrite, Data: data})
+}
+
+// Read sends a group value read request and waits for the response
+func (c *Connection) Read(ctx context.Context, ga GroupAddress) ([]byte, error) {
+	res := make(chan []byte, 1)
+
+	cancel := c.Subscribe(ga, func(t Telegram) {
+		select {
+		case res <- t.Data:
+		default:
+		}
+	})
+	defer cancel()
+
+	if err := c.Send(Telegram{Destination: ga, APCI: GroupValueRead}); err != nil {
+		return nil, err
+	}
+
+	select {
+	case data := <-res:
+		return data, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Subscribe registers a callback for group value writes and responses. It returns a function to unsubscribe.
+func (c *Connection) Subscribe(ga GroupAddress, fn func(Telegram)) func() {
+	c.listenersMu.Lock()
+	defer c.listenersMu.Unlock()
+
+	id := c.id
+	c.id++
+	c.listeners[id] = listener{ga: ga, fn: fn}
+
+	return func() {
+		c.listenersMu.Lock()
+		defer c.listenersMu.Unlock()
+		delete(c.listeners, id)
+	}
+}
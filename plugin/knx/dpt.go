@@ -0,0 +1,201 @@
+package knx
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DPT is a KNX datapoint type main.sub, e.g. 9.024 for power in kW
+type DPT struct {
+	Main, Sub int
+}
+
+// ParseDPT parses a datapoint type like 1.001, 9 or 14.056
+func ParseDPT(s string) (DPT, error) {
+	main, sub, _ := strings.Cut(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(s)), "dpt"), ".")
+
+	var res DPT
+	var err error
+
+	if res.Main, err = strconv.Atoi(main); err == nil && sub != "" {
+		res.Sub, err = strconv.Atoi(sub)
+	}
+
+	if err != nil {
+		return DPT{}, fmt.Errorf("invalid dpt: %s", s)
+	}
+
+	switch res.Main {
+	case 1, 5, 6, 7, 8, 9, 12, 13, 14, 29:
+		return res, nil
+	default:
+		return DPT{}, fmt.Errorf("unsupported dpt: %s", s)
+	}
+}
+
+func (d DPT) String() string {
+	return fmt.Sprintf("%d.%03d", d.Main, d.Sub)
+}
+
+// size returns the payload length following the APCI
+func (d DPT) size() int {
+	switch d.Main {
+	case 1:
+		return 0
+	case 5, 6:
+		return 1
+	case 7, 8, 9:
+		return 2
+	case 12, 13, 14:
+		return 4
+	case 29:
+		return 8
+	default:
+		return 0
+	}
+}
+
+// percent returns the full scale value of scaled 8 bit types
+func (d DPT) percent() float64 {
+	switch {
+	case d.Main == 5 && d.Sub == 1:
+		return 100
+	case d.Main == 5 && d.Sub == 3:
+		return 360
+	default:
+		return 0
+	}
+}
+
+// Decode converts telegram data into a float value
+func (d DPT) Decode(data []byte) (float64, error) {
+	if len(data) < 1+d.size() {
+		return 0, fmt.Errorf("dpt %s: invalid length %d", d, len(data))
+	}
+
+	b := data[1:]
+
+	switch d.Main {
+	case 1:
+		return float64(data[0] & 0x01), nil
+	case 5:
+		if fs := d.percent(); fs > 0 {
+			return float64(b[0]) * fs / 255, nil
+		}
+		return float64(b[0]), nil
+	case 6:
+		return float64(int8(b[0])), nil
+	case 7:
+		return float64(binary.BigEndian.Uint16(b)), nil
+	case 8:
+		return float64(int16(binary.BigEndian.Uint16(b))), nil
+	case 9:
+		return decodeFloat16(binary.BigEndian.Uint16(b)), nil
+	case 12:
+		return float64(binary.BigEndian.Uint32(b)), nil
+	case 13:
+		return float64(int32(binary.BigEndian.Uint32(b))), nil
+	case 14:
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), nil
+	case 29:
+		return float64(int64(binary.BigEndian.Uint64(b))), nil
+	default:
+		return 0, fmt.Errorf("unsupported dpt: %s", d)
+	}
+}
+
+// Encode converts a float value into telegram data
+func (d DPT) Encode(v float64) ([]byte, error) {
+	res := make([]byte, 1+d.size())
+	b := res[1:]
+
+	switch d.Main {
+	case 1:
+		if v != 0 {
+			res[0] = 1
+		}
+	case 5:
+		if fs := d.percent(); fs > 0 {
+			v = v * 255 / fs
+		}
+		if v < 0 || v > math.MaxUint8 {
+			return nil, fmt.Errorf("dpt %s: value out of range: %v", d, v)
+		}
+		b[0] = uint8(math.Round(v))
+	case 6:
+		if v < math.MinInt8 || v > math.MaxInt8 {
+			return nil, fmt.Errorf("dpt %s: value out of range: %v", d, v)
+		}
+		b[0] = byte(int8(math.Round(v)))
+	case 7:
+		if v < 0 || v > math.MaxUint16 {
+			return nil, fmt.Errorf("dpt %s: value out of range: %v", d, v)
+		}
+		binary.BigEndian.PutUint16(b, uint16(math.Round(v)))
+	case 8:
+		if v < math.MinInt16 || v > math.MaxInt16 {
+			return nil, fmt.Errorf("dpt %s: value out of range: %v", d, v)
+		}
+		binary.BigEndian.PutUint16(b, uint16(int16(math.Round(v))))
+	case 9:
+		raw, err := encodeFloat16(v)
+		if err != nil {
+			return nil, fmt.Errorf("dpt %s: %w", d, err)
+		}
+		binary.BigEndian.PutUint16(b, raw)
+	case 12:
+		if v < 0 || v > math.MaxUint32 {
+			return nil, fmt.Errorf("dpt %s: value out of range: %v", d, v)
+		}
+		binary.BigEndian.PutUint32(b, uint32(math.Round(v)))
+	case 13:
+		if v < math.MinInt32 || v > math.MaxInt32 {
+			return nil, fmt.Errorf("dpt %s: value out of range: %v", d, v)
+		}
+		binary.BigEndian.PutUint32(b, uint32(int32(math.Round(v))))
+	case 14:
+		binary.BigEndian.PutUint32(b, math.Float32bits(float32(v)))
+	case 29:
+		binary.BigEndian.PutUint64(b, uint64(int64(math.Round(v))))
+	default:
+		return nil, fmt.Errorf("unsupported dpt: %s", d)
+	}
+
+	return res, nil
+}
+
+// decodeFloat16 decodes the KNX 2-byte float (0.01 * M * 2^E)
+func decodeFloat16(raw uint16) float64 {
+	m := int(raw & 0x07FF)
+	if raw&0x8000 != 0 {
+		m -= 0x0800
+	}
+	e := (raw >> 11) & 0x0F
+
+	return 0.01 * float64(m) * float64(int(1)<<e)
+}
+
+// encodeFloat16 encodes the KNX 2-byte float (0.01 * M * 2^E)
+func encodeFloat16(v float64) (uint16, error) {
+	m := math.Round(v * 100)
+
+	var e uint16
+	for m < -2048 || m > 2047 {
+		if e == 15 {
+			return 0, fmt.Errorf("value out of range: %v", v)
+		}
+
+		e++
+		m = math.Round(v * 100 / float64(int(1)<<e))
+	}
+
+	raw := e<<11 | uint16(int16(m))&0x07FF
+	if m < 0 {
+		raw |= 0x8000
+	}
+
+	return raw, nil
+}
@@ -0,0 +1,216 @@
+// Package knx implements a minimal KNXnet/IP client for group communication.
+//
+// Both tunneling (unicast connection to an IP interface, port 3671) and routing
+// (multicast to 224.0.23.12:3671) are supported. Group telegrams are exchanged
+// as cEMI L_Data frames:
+//
+//	Header: 06 10 [service type] [total length]
+//	cEMI:   [msg code] 00 [ctrl1] [ctrl2] [src] [dst] [len] [tpci/apci] [apci/data] [data…]
+package knx
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Port is the default KNXnet/IP port
+const Port = 3671
+
+// MulticastAddr is the default KNXnet/IP routing multicast address
+const MulticastAddr = "224.0.23.12"
+
+// KNXnet/IP service types
+const (
+	connectRequest          uint16 = 0x0205
+	connectResponse         uint16 = 0x0206
+	connectionStateRequest  uint16 = 0x0207
+	connectionStateResponse uint16 = 0x0208
+	disconnectRequest       uint16 = 0x0209
+	disconnectResponse      uint16 = 0x020A
+	tunnelingRequest        uint16 = 0x0420
+	tunnelingAck            uint16 = 0x0421
+	routingIndication       uint16 = 0x0530
+)
+
+// cEMI message codes
+const (
+	lDataReq byte = 0x11
+	lDataInd byte = 0x29
+	lDataCon byte = 0x2E
+)
+
+const headerLen = 6
+
+// APCI is the application layer service of a group telegram
+type APCI uint16
+
+const (
+	GroupValueRead     APCI = 0x000
+	GroupValueResponse APCI = 0x040
+	GroupValueWrite    APCI = 0x080
+)
+
+func (a APCI) String() string {
+	switch a {
+	case GroupValueRead:
+		return "read"
+	case GroupValueResponse:
+		return "response"
+	case GroupValueWrite:
+		return "write"
+	default:
+		return fmt.Sprintf("apci(%#x)", uint16(a))
+	}
+}
+
+// GroupAddress is a 16 bit KNX group address
+type GroupAddress uint16
+
+// ParseGroupAddress parses 3-level (main/middle/sub), 2-level (main/sub) or raw group addresses
+func ParseGroupAddress(s string) (GroupAddress, error) {
+	segs := strings.Split(strings.TrimSpace(s), "/")
+
+	vals := make([]uint16, len(segs))
+	limits := map[int][]uint64{
+		1: {0xFFFF},
+		2: {0x1F, 0x7FF},
+		3: {0x1F, 0x07, 0xFF},
+	}[len(segs)]
+
+	if limits == nil {
+		return 0, fmt.Errorf("invalid group address: %s", s)
+	}
+
+	for i, seg := range segs {
+		v, err := strconv.ParseUint(seg, 10, 16)
+		if err != nil || v > limits[i] {
+			return 0, fmt.Errorf("invalid group address: %s", s)
+		}
+		vals[i] = uint16(v)
+	}
+
+	var res uint16
+	switch len(vals) {
+	case 1:
+		res = vals[0]
+	case 2:
+		res = vals[0]<<11 | vals[1]
+	case 3:
+		res = vals[0]<<11 | vals[1]<<8 | vals[2]
+	}
+
+	return GroupAddress(res), nil
+}
+
+func (ga GroupAddress) String() string {
+	return fmt.Sprintf("%d/%d/%d", ga>>11, (ga>>8)&0x07, ga&0xFF)
+}
+
+// Telegram is a group telegram. Data holds the 6 bit value embedded into the
+// APCI as first byte, followed by any additional payload.
+type Telegram struct {
+	Source      uint16
+	Destination GroupAddress
+	APCI        APCI
+	Data        []byte
+}
+
+// header creates the KNXnet/IP header for a frame with given body length
+func header(service uint16, bodyLen int) []byte {
+	b := make([]byte, headerLen, headerLen+bodyLen)
+	b[0], b[1] = 0x06, 0x10
+	binary.BigEndian.PutUint16(b[2:], service)
+	binary.BigEndian.PutUint16(b[4:], uint16(headerLen+bodyLen))
+	return b
+}
+
+// frame creates a KNXnet/IP frame
+func frame(service uint16, body ...[]byte) []byte {
+	var n int
+	for _, b := range body {
+		n += len(b)
+	}
+
+	res := header(service, n)
+	for _, b := range body {
+		res = append(res, b...)
+	}
+
+	return res
+}
+
+// parseFrame validates the KNXnet/IP header and returns service type and body
+func parseFrame(b []byte) (uint16, []byte, error) {
+	if len(b) < headerLen || b[0] != 0x06 || b[1] != 0x10 {
+		return 0, nil, errors.New("invalid header")
+	}
+
+	n := int(binary.BigEndian.Uint16(b[4:]))
+	if n < headerLen || len(b) < n {
+		return 0, nil, errors.New("short frame")
+	}
+
+	return binary.BigEndian.Uint16(b[2:]), b[headerLen:n], nil
+}
+
+// hpai is the NAT route-back host protocol address information (0.0.0.0:0, UDP)
+var hpai = []byte{0x08, 0x01, 0, 0, 0, 0, 0, 0}
+
+// tunnelCRI is the connection request information for a link layer tunnel
+var tunnelCRI = []byte{0x04, 0x04, 0x02, 0x00}
+
+// encodeCEMI creates a cEMI L_Data frame for the telegram
+func encodeCEMI(code byte, t Telegram) []byte {
+	data := t.Data
+	if len(data) == 0 {
+		data = []byte{0}
+	}
+
+	b := []byte{
+		code, 0x00, // no additional info
+		0xBC, // standard frame, no repeat, broadcast, low priority
+		0xE0, // group address, hop count 6
+		byte(t.Source >> 8), byte(t.Source),
+		byte(t.Destination >> 8), byte(t.Destination),
+		byte(len(data)),
+		byte(t.APCI>>8) & 0x03,
+		byte(t.APCI) | data[0]&0x3F,
+	}
+
+	return append(b, data[1:]...)
+}
+
+// decodeCEMI parses a cEMI L_Data group telegram
+func decodeCEMI(b []byte) (byte, Telegram, error) {
+	if len(b) < 2 || len(b) < 2+int(b[1]) {
+		return 0, Telegram{}, errors.New("short cemi frame")
+	}
+
+	code := b[0]
+	b = b[2+int(b[1]):] // skip additional info
+
+	if len(b) < 9 {
+		return 0, Telegram{}, errors.New("short cemi frame")
+	}
+
+	if b[1]&0x80 == 0 {
+		return code, Telegram{}, errors.New("not a group telegram")
+	}
+
+	n := int(b[6])
+	if n < 1 || len(b) < 8+n {
+		return 0, Telegram{}, errors.New("invalid cemi length")
+	}
+
+	t := Telegram{
+		Source:      binary.BigEndian.Uint16(b[2:]),
+		Destination: GroupAddress(binary.BigEndian.Uint16(b[4:])),
+		APCI:        APCI(uint16(b[7]&0x03)<<8 | uint16(b[8]&0xC0)),
+		Data:        append([]byte{b[8] & 0x3F}, b[9:8+n]...),
+	}
+
+	return code, t, nil
+}
@@ -0,0 +1,202 @@
+package knx
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGroupAddress(t *testing.T) {
+	for _, tc := range []struct {
+		in  string
+		out GroupAddress
+	}{
+		{"1/2/3", 0x0A03},
+		{"31/7/255", 0xFFFF},
+		{"1/515", 0x0A03},
+		{"2563", 0x0A03},
+	} {
+		ga, err := ParseGroupAddress(tc.in)
+		require.NoError(t, err, tc.in)
+		assert.Equal(t, tc.out, ga, tc.in)
+	}
+
+	assert.Equal(t, "1/2/3", GroupAddress(0x0A03).String())
+
+	for _, in := range []string{"", "32/0/0", "1/8/0", "1/2/256", "1/2/3/4", "a/b/c"} {
+		_, err := ParseGroupAddress(in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestDPT(t *testing.T) {
+	for _, tc := range []struct {
+		dpt  string
+		val  float64
+		data []byte
+	}{
+		{"1.001", 1, []byte{0x01}},
+		{"1.001", 0, []byte{0x00}},
+		{"5.001", 100, []byte{0x00, 0xFF}},
+		{"5.010", 42, []byte{0x00, 0x2A}},
+		{"9.001", 21.5, []byte{0x00, 0x0C, 0x33}},
+		{"9.001", -30, []byte{0x00, 0x8A, 0x24}},
+		{"9.024", 0, []byte{0x00, 0x00, 0x00}},
+		{"13.010", -1234, []byte{0x00, 0xFF, 0xFF, 0xFB, 0x2E}},
+		{"14.056", 1500, []byte{0x00, 0x44, 0xBB, 0x80, 0x00}},
+	} {
+		dpt, err := ParseDPT(tc.dpt)
+		require.NoError(t, err)
+
+		data, err := dpt.Encode(tc.val)
+		require.NoError(t, err)
+		assert.Equal(t, tc.data, data, tc.dpt)
+
+		val, err := dpt.Decode(tc.data)
+		require.NoError(t, err)
+		assert.InDelta(t, tc.val, val, 0.01, tc.dpt)
+	}
+
+	_, err := ParseDPT("16.000")
+	assert.Error(t, err)
+}
+
+func TestFloat16(t *testing.T) {
+	for _, v := range []float64{0, 0.5, -0.5, 20.48, 100, -671088.64, 670760.96} {
+		raw, err := encodeFloat16(v)
+		require.NoError(t, err)
+		assert.InDelta(t, v, decodeFloat16(raw), 0.01*float64(int(1)<<(raw>>11&0x0F)), v)
+	}
+
+	_, err := encodeFloat16(1e7)
+	assert.Error(t, err)
+}
+
+// simulator is a minimal KNXnet/IP tunneling gateway holding group values
+type simulator struct {
+	t      *testing.T
+	conn   *net.UDPConn
+	mu     sync.Mutex
+	client *net.UDPAddr
+	seq    byte
+	values map[GroupAddress][]byte
+}
+
+func newSimulator(t *testing.T) *simulator {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+
+	s := &simulator{
+		t:      t,
+		conn:   conn,
+		values: make(map[GroupAddress][]byte),
+	}
+
+	t.Cleanup(func() { _ = conn.Close() })
+	go s.run()
+
+	return s
+}
+
+func (s *simulator) value(ga GroupAddress) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.values[ga]
+}
+
+// indicate sends a group telegram to the connected client
+func (s *simulator) indicate(apci APCI, ga GroupAddress, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b := frame(tunnelingRequest, []byte{0x04, 0x01, s.seq, 0x00}, encodeCEMI(lDataInd, Telegram{Source: 0x1101, Destination: ga, APCI: apci, Data: data}))
+	s.seq++
+
+	_, err := s.conn.WriteToUDP(b, s.client)
+	assert.NoError(s.t, err)
+}
+
+func (s *simulator) run() {
+	buf := make([]byte, 1024)
+
+	for {
+		n, addr, err := s.conn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+
+		service, body, err := parseFrame(buf[:n])
+		require.NoError(s.t, err)
+
+		switch service {
+		case connectRequest:
+			s.mu.Lock()
+			s.client = addr
+			s.mu.Unlock()
+
+			_, _ = s.conn.WriteToUDP(frame(connectResponse, []byte{0x01, 0x00}, hpai, []byte{0x04, 0x04, 0x11, 0xFF}), addr)
+
+		case tunnelingRequest:
+			_, _ = s.conn.WriteToUDP(frame(tunnelingAck, []byte{0x04, body[1], body[2], 0x00}), addr)
+
+			_, tel, err := decodeCEMI(body[4:])
+			require.NoError(s.t, err)
+
+			switch tel.APCI {
+			case GroupValueWrite:
+				s.mu.Lock()
+				s.values[tel.Destination] = tel.Data
+				s.mu.Unlock()
+			case GroupValueRead:
+				if data := s.value(tel.Destination); data != nil {
+					s.indicate(GroupValueResponse, tel.Destination, data)
+				}
+			}
+		}
+	}
+}
+
+func TestTunnel(t *testing.T) {
+	sim := newSimulator(t)
+
+	conn, err := NewConnection(sim.conn.LocalAddr().String(), Tunnel)
+	require.NoError(t, err)
+
+	ga, dpt := GroupAddress(0x0A03), DPT{14, 56}
+
+	// write
+	data, err := dpt.Encode(3700)
+	require.NoError(t, err)
+	require.NoError(t, conn.Write(ga, data))
+
+	require.Eventually(t, func() bool {
+		return sim.value(ga) != nil
+	}, time.Second, 10*time.Millisecond)
+
+	// read
+	res, err := conn.Read(t.Context(), ga)
+	require.NoError(t, err)
+
+	val, err := dpt.Decode(res)
+	require.NoError(t, err)
+	assert.Equal(t, 3700.0, val)
+
+	// listen
+	received := make(chan Telegram, 1)
+	cancel := conn.Subscribe(0x0101, func(t Telegram) { received <- t })
+	defer cancel()
+
+	sim.indicate(GroupValueWrite, 0x0101, []byte{0x01})
+
+	select {
+	case tel := <-received:
+		assert.Equal(t, GroupValueWrite, tel.APCI)
+		assert.Equal(t, []byte{0x01}, tel.Data)
+	case <-time.After(time.Second):
+		t.Fatal("telegram not received")
+	}
+}
@@ -117,7 +117,7 @@ func (m *Mqtt) newReceiver() (*msgHandler, error) {
 		val:      util.NewMonitor[string](m.timeout),
 	}
 
-	err := m.client.Listen(m.topic, h.receive)
+	err := m.client.ListenContext(m.ctx, m.topic, h.receive)
 
 	// without timeout, wait briefly for a retained message- it arrives right after suback
 	if err == nil && m.timeout == 0 {
@@ -6,8 +6,10 @@ import (
 	"crypto/x509"
 	"fmt"
 	"math/rand/v2"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	paho "github.com/eclipse/paho.mqtt.golang"
@@ -20,6 +22,21 @@ import (
 // Instance is the paho Mqtt client singleton
 var Instance *Client
 
+// TapFunc wraps a paho client
+type TapFunc func(client paho.Client) paho.Client
+
+var tap atomic.Pointer[TapFunc]
+
+// SetTap wraps all new paho clients, e.g. for recording or replaying device traffic.
+// A nil tap restores unwrapped clients.
+func SetTap(fun TapFunc) {
+	if fun == nil {
+		tap.Store(nil)
+		return
+	}
+	tap.Store(&fun)
+}
+
 const parallelInflightLimit int64 = 32
 
 // ClientID created unique mqtt client id
@@ -46,7 +63,7 @@ type Client struct {
 	client   paho.Client
 	broker   string
 	Qos      byte
-	listener map[string][]func(string)
+	listener map[string][]*listener
 	inflight *semaphore.Weighted
 
 	connMu     sync.Mutex
@@ -71,7 +88,7 @@ func NewClient(log *util.Logger, broker, user, password, clientID string, qos by
 	mc := &Client{
 		log:      log,
 		Qos:      qos,
-		listener: make(map[string][]func(string)),
+		listener: make(map[string][]*listener),
 		inflight: semaphore.NewWeighted(parallelInflightLimit),
 	}
 
@@ -112,6 +129,9 @@ func NewClient(log *util.Logger, broker, user, password, clientID string, qos by
 	}
 
 	client := paho.NewClient(options)
+	if fun := tap.Load(); fun != nil {
+		client = (*fun)(client)
+	}
 
 	or := client.OptionsReader()
 	mc.broker = fmt.Sprintf("%v", or.Servers())
@@ -258,12 +278,31 @@ func (m *Client) Publish(topic string, retained bool, payload string) {
 	}()
 }
 
+// listener is a callback attached to a topic
+type listener struct {
+	callback func(string)
+}
+
 // Listen attaches listener to slice of listeners for given topic
 func (m *Client) Listen(topic string, callback func(string)) error {
+	return m.ListenContext(context.Background(), topic, callback)
+}
+
+// ListenContext attaches listener to slice of listeners for given topic until the context is cancelled
+func (m *Client) ListenContext(ctx context.Context, topic string, callback func(string)) error {
+	l := &listener{callback: callback}
+
 	m.mux.Lock()
-	m.listener[topic] = append(m.listener[topic], callback)
+	m.listener[topic] = append(m.listener[topic], l)
 	m.mux.Unlock()
 
+	if ctx.Done() != nil {
+		go func() {
+			<-ctx.Done()
+			m.unlisten(topic, l)
+		}()
+	}
+
 	token := m.listen(topic)
 
 	select {
@@ -286,6 +325,21 @@ func (m *Client) ListenSetter(topic string, callback func(string) error) error {
 	return err
 }
 
+// unlisten detaches listener from topic and unsubscribes the topic once it has no listeners left
+func (m *Client) unlisten(topic string, l *listener) {
+	m.mux.Lock()
+	m.listener[topic] = slices.DeleteFunc(m.listener[topic], func(e *listener) bool { return e == l })
+	unused := len(m.listener[topic]) == 0
+	if unused {
+		delete(m.listener, topic)
+	}
+	m.mux.Unlock()
+
+	if unused {
+		m.client.Unsubscribe(topic)
+	}
+}
+
 // listen attaches listener to topic
 func (m *Client) listen(topic string) paho.Token {
 	token := m.client.Subscribe(topic, m.Qos, func(c paho.Client, msg paho.Message) {
@@ -296,8 +350,8 @@ func (m *Client) listen(topic string) paho.Token {
 			callbacks := m.listener[topic]
 			m.mux.Unlock()
 
-			for _, cb := range callbacks {
-				cb(payload)
+			for _, l := range callbacks {
+				l.callback(payload)
 			}
 		}
 	})
@@ -15,7 +15,16 @@ type msgHandler struct {
 }
 
 func (h *msgHandler) receive(payload string) {
-	h.val.Set(payload)
+	var changed bool
+	h.val.SetFunc(func(old string) string {
+		changed = old != payload
+		return payload
+	})
+
+	// push changed values to the site
+	if changed {
+		util.Notify(h.ctx)
+	}
 }
 
 // value returns the received and processed payload as string
@@ -1,6 +1,7 @@
 package plugin
 
 import (
+	"bytes"
 	"context"
 	"net/http"
 	"sync"
@@ -20,6 +21,7 @@ const retryDelay = 5 * time.Second
 type Socket struct {
 	*getter
 	*request.Helper
+	ctx      context.Context
 	log      *util.Logger
 	url      string
 	headers  map[string]string
@@ -28,12 +30,12 @@ type Socket struct {
 }
 
 func init() {
-	registry.Add("ws", NewSocketPluginFromConfig)
-	registry.Add("websocket", NewSocketPluginFromConfig)
+	registry.AddCtx("ws", NewSocketPluginFromConfig)
+	registry.AddCtx("websocket", NewSocketPluginFromConfig)
 }
 
 // NewSocketPluginFromConfig creates a HTTP provider
-func NewSocketPluginFromConfig(other map[string]any) (Plugin, error) {
+func NewSocketPluginFromConfig(ctx context.Context, other map[string]any) (Plugin, error) {
 	cc := struct {
 		URI               string
 		Headers           map[string]string
@@ -59,6 +61,7 @@ func NewSocketPluginFromConfig(other map[string]any) (Plugin, error) {
 	}
 
 	p := &Socket{
+		ctx:     ctx,
 		log:     log,
 		Helper:  request.NewHelper(log),
 		url:     url,
@@ -114,8 +117,9 @@ func (p *Socket) run(errC chan error) {
 		HTTPHeader: headers,
 	}
 
-	for {
-		ctx, cancel := context.WithTimeout(context.Background(), request.Timeout)
+	// stop when the device is shut down
+	for p.ctx.Err() == nil {
+		ctx, cancel := context.WithTimeout(p.ctx, request.Timeout)
 		conn, _, err := websocket.Dial(ctx, p.url, opts)
 		cancel()
 
@@ -124,12 +128,16 @@ func (p *Socket) run(errC chan error) {
 			once.Do(func() { errC <- err })
 
 			p.log.ERROR.Println(err)
-			time.Sleep(retryDelay)
+
+			select {
+			case <-p.ctx.Done():
+			case <-time.After(retryDelay):
+			}
 			continue
 		}
 
 		for {
-			_, b, err := conn.Read(context.Background())
+			_, b, err := conn.Read(p.ctx)
 			if err != nil {
 				p.log.TRACE.Println("read:", err)
 				_ = conn.Close(websocket.StatusAbnormalClosure, "done")
@@ -139,12 +147,25 @@ func (p *Socket) run(errC chan error) {
 			p.log.TRACE.Printf("recv: %s", b)
 
 			if v, err := p.pipeline.Process(b); err == nil {
-				p.val.Set(v)
+				p.set(v)
 			}
 		}
 	}
 }
 
+// set updates the value and pushes changes to the site
+func (p *Socket) set(v []byte) {
+	var changed bool
+	p.val.SetFunc(func(old []byte) []byte {
+		changed = !bytes.Equal(old, v)
+		return v
+	})
+
+	if changed {
+		util.Notify(p.ctx)
+	}
+}
+
 var _ Getters = (*Socket)(nil)
 
 // StringGetter sends string request
@@ -40,7 +40,7 @@ func TestSockePlugin(t *testing.T) {
 	defer srv.Close()
 
 	addr := "ws://" + srv.Listener.Addr().String()
-	p, err := NewSocketPluginFromConfig(map[string]any{
+	p, err := NewSocketPluginFromConfig(t.Context(), map[string]any{
 		"uri": addr,
 		"jq":  `.data | select(.uuid=="bar") .tuples[0][1]`,
 	})
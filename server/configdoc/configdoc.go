@@ -0,0 +1,491 @@
+// Package configdoc serializes the database-managed configuration into a single
+// yaml document and reconciles the database to such a document.
+package configdoc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/server/db/history"
+	"github.com/evcc-io/evcc/server/db/settings"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/config"
+	"github.com/evcc-io/evcc/util/templates"
+	"go.yaml.in/yaml/v4"
+	"gorm.io/gorm"
+)
+
+var log = util.NewLogger("config")
+
+// Device is a database-managed device
+type Device struct {
+	ID      int            `yaml:"id" json:"id"`
+	Type    string         `yaml:"type,omitempty" json:"type,omitempty"`
+	Title   string         `yaml:"deviceTitle,omitempty" json:"deviceTitle,omitempty"`
+	Icon    string         `yaml:"deviceIcon,omitempty" json:"deviceIcon,omitempty"`
+	Product string         `yaml:"deviceProduct,omitempty" json:"deviceProduct,omitempty"`
+	Config  map[string]any `yaml:"config,omitempty" json:"config,omitempty"`
+}
+
+// YamlDevice is a device defined in evcc.yaml
+type YamlDevice struct {
+	Name   string         `yaml:"name" json:"name"`
+	Type   string         `yaml:"type,omitempty" json:"type,omitempty"`
+	Config map[string]any `yaml:"config,omitempty" json:"config,omitempty"`
+}
+
+// Document is the complete database-managed configuration with secrets redacted.
+// Devices defined in evcc.yaml are not managed by the document and only exported for reference as Yaml.
+type Document struct {
+	Chargers   []Device          `yaml:"chargers,omitempty" json:"chargers,omitempty"`
+	Meters     []Device          `yaml:"meters,omitempty" json:"meters,omitempty"`
+	Vehicles   []Device          `yaml:"vehicles,omitempty" json:"vehicles,omitempty"`
+	Loadpoints []Device          `yaml:"loadpoints,omitempty" json:"loadpoints,omitempty"`
+	Circuits   []Device          `yaml:"circuits,omitempty" json:"circuits,omitempty"`
+	Tariffs    []Device          `yaml:"tariffs,omitempty" json:"tariffs,omitempty"`
+	Messengers []Device          `yaml:"messengers,omitempty" json:"messengers,omitempty"`
+	Hems       []Device          `yaml:"hems,omitempty" json:"hems,omitempty"`
+	Settings   map[string]string `yaml:"settings,omitempty" json:"settings,omitempty"`
+
+	// Yaml lists the devices defined in evcc.yaml by class with secrets redacted. It is ignored by Apply.
+	Yaml map[string][]YamlDevice `yaml:"yaml,omitempty" json:"yaml,omitempty"`
+}
+
+// devices returns the document's devices by class
+func (d *Document) devices() map[templates.Class]*[]Device {
+	return map[templates.Class]*[]Device{
+		templates.Charger:   &d.Chargers,
+		templates.Meter:     &d.Meters,
+		templates.Vehicle:   &d.Vehicles,
+		templates.Loadpoint: &d.Loadpoints,
+		templates.Circuit:   &d.Circuits,
+		templates.Tariff:    &d.Tariffs,
+		templates.Messenger: &d.Messengers,
+		templates.Hems:      &d.Hems,
+	}
+}
+
+var (
+	loadpointSettingRegex = regexp.MustCompile(`^lp\d+\.([^.]+)$`)
+	vehicleSettingRegex   = regexp.MustCompile(`^vehicle\..+\.([^.]+)$`)
+)
+
+// configSettings are the global and site settings keys holding configuration
+var configSettings = []string{
+	// global
+	keys.Interval, keys.Experimental, keys.Network, keys.Mqtt, keys.Influx, keys.EEBus, keys.Hems,
+	keys.Shm, keys.Messaging, keys.MessagingEvents, keys.ModbusProxy, keys.Ocpp, keys.OcppForwarder,
+	keys.Tariffs, keys.TariffRefs, keys.Circuits, keys.Telemetry, keys.Optimizer, keys.Mcp, keys.DeviceColors,
+	// site
+	keys.Title, keys.Home, keys.Currency, keys.ResidualPower, keys.GridMeter, keys.PvMeters, keys.BatteryMeters,
+	keys.ExtMeters, keys.AuxMeters, keys.ConsumerMeters, keys.PrioritySoc, keys.BufferSoc, keys.BufferStartSoc,
+	keys.BatteryDischargeControl, keys.BatteryGridChargeLimit, keys.BatteryGridDischarge,
+	keys.GridExportLimit, keys.LoadShedding, keys.SolarAdjusted, keys.OptimizerChargingStrategy,
+}
+
+// loadpointConfigSettings are the per-loadpoint settings keys holding configuration.
+// Charge mode, limits and plans are runtime state.
+var loadpointConfigSettings = []string{
+	keys.Title, keys.Charger, keys.Meter, keys.Circuit, keys.DefaultVehicle, keys.DefaultMode,
+	keys.Priority, keys.MinCurrent, keys.MaxCurrent, keys.PhasesConfigured, keys.Thresholds,
+	keys.Soc, keys.UI, keys.Watchdog, keys.Schedules,
+}
+
+// vehicleConfigSettings are the per-vehicle settings keys holding configuration.
+// Plans and predictions are runtime state.
+var vehicleConfigSettings = []string{
+	keys.Mode, keys.MinSoc, keys.LimitSoc,
+}
+
+// IsConfigSetting checks if a settings key is configuration as opposed to runtime state or tokens
+func IsConfigSetting(key string) bool {
+	if m := loadpointSettingRegex.FindStringSubmatch(key); m != nil {
+		return slices.Contains(loadpointConfigSettings, m[1])
+	}
+
+	if m := vehicleSettingRegex.FindStringSubmatch(key); m != nil {
+		return slices.Contains(vehicleConfigSettings, m[1])
+	}
+
+	return slices.Contains(configSettings, key)
+}
+
+// yamlDevices returns the handler's devices not stored in the database
+func yamlDevices[T any](h config.Handler[T]) ([]YamlDevice, error) {
+	var res []YamlDevice
+	for _, dev := range h.Devices() {
+		cc := dev.Config()
+		if strings.HasPrefix(cc.Name, "db:") {
+			continue
+		}
+
+		data, err := redactConfig(cc.Other)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, YamlDevice{Name: cc.Name, Type: cc.Type, Config: data})
+	}
+	return res, nil
+}
+
+// unmanaged returns the devices defined in evcc.yaml by class
+func unmanaged() (map[string][]YamlDevice, error) {
+	res := make(map[string][]YamlDevice)
+
+	for class, fun := range map[templates.Class]func() ([]YamlDevice, error){
+		templates.Charger:   func() ([]YamlDevice, error) { return yamlDevices(config.Chargers()) },
+		templates.Meter:     func() ([]YamlDevice, error) { return yamlDevices(config.Meters()) },
+		templates.Vehicle:   func() ([]YamlDevice, error) { return yamlDevices(config.Vehicles()) },
+		templates.Loadpoint: func() ([]YamlDevice, error) { return yamlDevices(config.Loadpoints()) },
+		templates.Circuit:   func() ([]YamlDevice, error) { return yamlDevices(config.Circuits()) },
+		templates.Tariff:    func() ([]YamlDevice, error) { return yamlDevices(config.Tariffs()) },
+		templates.Messenger: func() ([]YamlDevice, error) { return yamlDevices(config.Messengers()) },
+		templates.Hems:      func() ([]YamlDevice, error) { return yamlDevices(config.Hems()) },
+	} {
+		devs, err := fun()
+		if err != nil {
+			return nil, err
+		}
+		if len(devs) > 0 {
+			res[class.String()] = devs
+		}
+	}
+
+	if len(res) == 0 {
+		return nil, nil
+	}
+
+	return res, nil
+}
+
+// redactConfig masks the secrets of a device configuration
+func redactConfig(data map[string]any) (map[string]any, error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var res map[string]any
+	err = json.Unmarshal([]byte(history.Redact(string(b))), &res)
+
+	return res, err
+}
+
+// unredactConfig restores the redacted secrets of a device configuration from the current configuration
+func unredactConfig(data, current map[string]any) (map[string]any, error) {
+	b, err := json.Marshal(data)
+	if err != nil || !strings.Contains(string(b), history.Redacted) {
+		return data, err
+	}
+
+	cur, err := json.Marshal(current)
+	if err != nil {
+		return nil, err
+	}
+
+	var res map[string]any
+	err = json.Unmarshal([]byte(history.Unredact(string(b), string(cur))), &res)
+
+	return res, err
+}
+
+// Export returns the current database-managed configuration
+func Export() (Document, error) {
+	var res Document
+
+	if db.Instance == nil {
+		return res, errors.New("database offline")
+	}
+
+	for class, devs := range res.devices() {
+		configs, err := config.ConfigurationsByClass(class)
+		if err != nil {
+			return res, err
+		}
+
+		for _, c := range configs {
+			data, err := redactConfig(c.Data)
+			if err != nil {
+				return res, err
+			}
+
+			*devs = append(*devs, Device{
+				ID:      c.ID,
+				Type:    c.Type,
+				Title:   c.Title,
+				Icon:    c.Icon,
+				Product: c.Product,
+				Config:  data,
+			})
+		}
+
+		slices.SortFunc(*devs, func(a, b Device) int { return a.ID - b.ID })
+	}
+
+	for _, s := range settings.All() {
+		if IsConfigSetting(s.Key) && s.Value != "" {
+			if res.Settings == nil {
+				res.Settings = make(map[string]string)
+			}
+			res.Settings[s.Key] = history.Redact(s.Value)
+		}
+	}
+
+	devs, err := unmanaged()
+	res.Yaml = devs
+
+	return res, err
+}
+
+// Marshal encodes the document as yaml
+func Marshal(doc Document) ([]byte, error) {
+	return yaml.Marshal(doc)
+}
+
+// Unmarshal decodes a yaml document
+func Unmarshal(b []byte) (Document, error) {
+	var res Document
+	err := yaml.Unmarshal(b, &res)
+	return res, err
+}
+
+// Op is a reconciliation operation
+type Op string
+
+const (
+	Add    Op = "add"
+	Update Op = "update"
+	Delete Op = "delete"
+)
+
+// Change is a single reconciliation step
+type Change struct {
+	Op    Op     `json:"op"`
+	Class string `json:"class"` // device class or "setting"
+	Key   string `json:"key"`   // device name or setting key
+}
+
+// normalize converts yaml-decoded values into their json representation for comparison
+func normalize(m map[string]any) (map[string]any, error) {
+	if len(m) == 0 {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	var res map[string]any
+	err = json.Unmarshal(b, &res)
+
+	return res, err
+}
+
+// Apply reconciles the database to the document on behalf of author in a single transaction.
+// Devices are matched by id, devices and configuration settings missing from the document are deleted.
+// Redacted secrets keep their current value. If dryRun is set, the changes are returned without applying them.
+func Apply(doc Document, author string, dryRun bool) ([]Change, error) {
+	if db.Instance == nil {
+		return nil, errors.New("database offline")
+	}
+
+	// save unrelated changes first, a failed apply discards all unsaved settings
+	if !dryRun {
+		if err := settings.Persist(); err != nil {
+			return nil, err
+		}
+	}
+
+	var res []Change
+
+	err := db.Instance.Transaction(func(tx *gorm.DB) error {
+		for _, class := range templates.ClassValues() {
+			devs, ok := doc.devices()[class]
+			if !ok {
+				continue
+			}
+
+			changes, err := applyDevices(tx, class, *devs, author, dryRun)
+			res = append(res, changes...)
+			if err != nil {
+				return fmt.Errorf("%s: %w", class, err)
+			}
+		}
+
+		changes, err := applySettings(doc.Settings, author, dryRun)
+		res = append(res, changes...)
+		if err != nil || dryRun {
+			return err
+		}
+
+		return settings.PersistTx(tx)
+	})
+
+	if err != nil {
+		if !dryRun {
+			if err := settings.Reload(); err != nil {
+				log.ERROR.Printf("reload settings: %v", err)
+			}
+		}
+
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func applyDevices(tx *gorm.DB, class templates.Class, devs []Device, author string, dryRun bool) ([]Change, error) {
+	existing, err := config.ConfigurationsByClassTx(tx, class)
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[int]config.Config, len(existing))
+	for _, c := range existing {
+		current[c.ID] = c
+	}
+
+	var res []Change
+	seen := make(map[int]bool)
+
+	for _, dev := range devs {
+		if dev.ID != 0 && seen[dev.ID] {
+			return res, fmt.Errorf("duplicate id: %d", dev.ID)
+		}
+		seen[dev.ID] = true
+
+		data, err := normalize(dev.Config)
+		if err != nil {
+			return res, err
+		}
+
+		props := config.Properties{
+			Type:    dev.Type,
+			Title:   dev.Title,
+			Icon:    dev.Icon,
+			Product: dev.Product,
+		}
+
+		c, ok := current[dev.ID]
+		if !ok && dev.ID != 0 {
+			// id may be used by a device without details or of another class
+			if other, err := config.ConfigByIDTx(tx, dev.ID); err == nil {
+				if other.Class != class {
+					return res, fmt.Errorf("id %d is used by %s", dev.ID, other.Class)
+				}
+				c, ok = other, true
+			}
+		}
+
+		if !ok {
+			if b, _ := json.Marshal(data); strings.Contains(string(b), history.Redacted) {
+				return res, fmt.Errorf("%s: missing redacted secret of new device", config.NameForID(dev.ID))
+			}
+
+			change := Change{Op: Add, Class: class.String(), Key: config.NameForID(dev.ID)}
+
+			if !dryRun {
+				c, err := config.AddConfig(class, data, config.WithProperties(props), config.WithAuthor(author), config.WithTx(tx), func(c *config.Config) {
+					c.ID = dev.ID
+				})
+				if err != nil {
+					return res, err
+				}
+				change.Key = config.NameForID(c.ID)
+			}
+
+			res = append(res, change)
+			continue
+		}
+
+		prev, _ := normalize(c.Data)
+
+		if data, err = unredactConfig(data, prev); err != nil {
+			return res, err
+		}
+
+		if c.Properties == props && reflect.DeepEqual(prev, data) {
+			continue
+		}
+
+		res = append(res, Change{Op: Update, Class: class.String(), Key: config.NameForID(c.ID)})
+
+		if !dryRun {
+			if err := c.Update(data, config.WithProperties(props), config.WithAuthor(author), config.WithTx(tx)); err != nil {
+				return res, err
+			}
+		}
+	}
+
+	for _, id := range slices.Sorted(maps.Keys(current)) {
+		if seen[id] {
+			continue
+		}
+
+		res = append(res, Change{Op: Delete, Class: class.String(), Key: config.NameForID(id)})
+
+		if !dryRun {
+			c := current[id]
+			if err := c.Delete(config.WithAuthor(author), config.WithTx(tx)); err != nil {
+				return res, err
+			}
+		}
+	}
+
+	return res, nil
+}
+
+func applySettings(values map[string]string, author string, dryRun bool) ([]Change, error) {
+	var res []Change
+
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		if !IsConfigSetting(key) {
+			return res, fmt.Errorf("not a configuration setting: %s", key)
+		}
+
+		val := values[key]
+
+		op := Add
+		if cur, err := settings.String(key); err == nil {
+			if val = history.Unredact(val, cur); cur == val {
+				continue
+			}
+			op = Update
+		}
+
+		res = append(res, Change{Op: op, Class: "setting", Key: key})
+
+		if !dryRun {
+			settings.Attribute(author, func() {
+				settings.SetString(key, val)
+			})
+		}
+	}
+
+	for _, s := range settings.All() {
+		if _, ok := values[s.Key]; ok || !IsConfigSetting(s.Key) || s.Value == "" {
+			continue
+		}
+
+		res = append(res, Change{Op: Delete, Class: "setting", Key: s.Key})
+
+		// cleared like the settings api does
+		if !dryRun {
+			settings.Attribute(author, func() {
+				settings.SetString(s.Key, "")
+			})
+		}
+	}
+
+	return res, nil
+}
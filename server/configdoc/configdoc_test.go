@@ -0,0 +1,152 @@
+package configdoc
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/server/db/history"
+	"github.com/evcc-io/evcc/server/db/settings"
+	"github.com/evcc-io/evcc/util/config"
+	"github.com/evcc-io/evcc/util/templates"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportApply(t *testing.T) {
+	require.NoError(t, db.NewInstance("sqlite", ":memory:"))
+
+	_, err := config.AddConfig(templates.Meter, map[string]any{"power": 1.5, "nested": map[string]any{"foo": "bar"}})
+	require.NoError(t, err)
+	settings.SetString("title", "home")
+	settings.SetString("sponsorToken", "secret")
+	require.NoError(t, settings.Persist())
+
+	doc, err := Export()
+	require.NoError(t, err)
+	require.Len(t, doc.Meters, 1)
+	assert.Equal(t, map[string]string{"title": "home"}, doc.Settings)
+
+	b, err := Marshal(doc)
+	require.NoError(t, err)
+
+	doc, err = Unmarshal(b)
+	require.NoError(t, err)
+
+	// applying the exported document is a no-op
+	changes, err := Apply(doc, "alice", false)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	// add, update and delete
+	id := doc.Meters[0].ID
+	doc.Meters[0].Config["power"] = 2
+	doc.Chargers = []Device{{ID: id + 10, Type: "template", Config: map[string]any{"template": "demo-charger"}}}
+	doc.Settings = map[string]string{"currency": "EUR"}
+
+	expected := []Change{
+		{Op: Add, Class: "charger", Key: config.NameForID(id + 10)},
+		{Op: Update, Class: "meter", Key: config.NameForID(id)},
+		{Op: Add, Class: "setting", Key: "currency"},
+		{Op: Delete, Class: "setting", Key: "title"},
+	}
+
+	changes, err = Apply(doc, "alice", true)
+	require.NoError(t, err)
+	assert.Equal(t, expected, changes)
+
+	changes, err = Apply(doc, "alice", false)
+	require.NoError(t, err)
+	assert.Equal(t, expected, changes)
+
+	_, err = config.ConfigByID(id + 10)
+	require.NoError(t, err)
+
+	_, err = settings.String("sponsorToken")
+	require.NoError(t, err)
+
+	changes, err = Apply(doc, "alice", false)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestExportRedacted(t *testing.T) {
+	require.NoError(t, db.NewInstance("sqlite", ":memory:"))
+
+	history.Secrets(func(key string) bool { return key == "password" })
+	t.Cleanup(func() { history.Secrets(func(string) bool { return false }) })
+
+	c, err := config.AddConfig(templates.Meter, map[string]any{"user": "alice", "password": "secret"})
+	require.NoError(t, err)
+
+	doc, err := Export()
+	require.NoError(t, err)
+	require.Len(t, doc.Meters, 1)
+	assert.Equal(t, history.Redacted, doc.Meters[0].Config["password"])
+
+	// redacted secrets keep their current value
+	doc.Meters[0].Config["user"] = "bob"
+
+	changes, err := Apply(doc, "alice", false)
+	require.NoError(t, err)
+	assert.Equal(t, []Change{{Op: Update, Class: "meter", Key: config.NameForID(c.ID)}}, changes)
+
+	c, err = config.ConfigByID(c.ID)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"user": "bob", "password": "secret"}, c.Data)
+
+	// new devices require their secrets, nothing is applied
+	doc.Meters[0].Config["user"] = "carol"
+	doc.Meters = append(doc.Meters, Device{ID: c.ID + 1, Config: map[string]any{"password": history.Redacted}})
+
+	_, err = Apply(doc, "alice", false)
+	require.Error(t, err)
+
+	c, err = config.ConfigByID(c.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "bob", c.Data["user"])
+}
+
+func TestIsConfigSetting(t *testing.T) {
+	for key, expected := range map[string]bool{
+		"title":                 true,
+		"sponsorToken":          false,
+		"lp1.title":             true,
+		"lp12.charger":          true,
+		"lp1.mode":              false,
+		"lp1.planTime":          false,
+		"lp1.sessionEnergy":     false,
+		"vehicle.db:3.minSoc":   true,
+		"vehicle.db:3.planTime": false,
+		"vehicle.myCar.mode":    true,
+		"vehicle.calendarPlan":  false,
+		"lp1.thresholds.enable": false,
+	} {
+		assert.Equal(t, expected, IsConfigSetting(key), key)
+	}
+}
+
+func TestExportYaml(t *testing.T) {
+	require.NoError(t, db.NewInstance("sqlite", ":memory:"))
+
+	history.Secrets(func(key string) bool { return key == "password" })
+	t.Cleanup(func() { history.Secrets(func(string) bool { return false }) })
+
+	require.NoError(t, config.Meters().Add(config.NewStaticDevice(config.Named{
+		Name:  "grid",
+		Type:  "custom",
+		Other: map[string]any{"password": "secret"},
+	}, api.Meter(nil))))
+	t.Cleanup(func() { _ = config.Meters().Delete("grid") })
+
+	doc, err := Export()
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]YamlDevice{
+		"meter": {{Name: "grid", Type: "custom", Config: map[string]any{"password": history.Redacted}}},
+	}, doc.Yaml)
+
+	// yaml devices are not applied
+	changes, err := Apply(doc, "alice", true)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
@@ -0,0 +1,293 @@
+// Package history keeps revisions of device configurations and settings.
+package history
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/util"
+	"gorm.io/gorm"
+)
+
+// Kind is the storage a revision belongs to
+type Kind string
+
+const (
+	Device  Kind = "device"
+	Setting Kind = "setting"
+)
+
+// System is the author of changes not initiated by a user
+const System = "evcc"
+
+// Revision is a single change of a device configuration or setting.
+// Old and New are nil if the key did not exist before or after the change.
+type Revision struct {
+	ID      uint      `json:"id" gorm:"primarykey"`
+	Created time.Time `json:"created" gorm:"index"`
+	Author  string    `json:"author"`
+	Kind    Kind      `json:"kind"`
+	Key     string    `json:"key" gorm:"index"`
+	Old     *string   `json:"old"`
+	New     *string   `json:"new"`
+}
+
+// Change is the difference of a key between two revisions
+type Change struct {
+	Kind Kind    `json:"kind"`
+	Key  string  `json:"key"`
+	Old  *string `json:"old"`
+	New  *string `json:"new"`
+}
+
+// RestoreFunc restores the value of a key on behalf of author. A nil value deletes the key.
+// Values are redacted, see Unredact.
+type RestoreFunc func(author, key string, value *string) error
+
+var (
+	log = util.NewLogger("history")
+
+	mu       sync.RWMutex
+	restorer = make(map[Kind]RestoreFunc)
+	tracked  = make(map[Kind]func(key string) bool)
+)
+
+func init() {
+	db.Register(func(db *gorm.DB) error {
+		return db.AutoMigrate(new(Revision))
+	})
+}
+
+// Register registers the restore function for a kind
+func Register(kind Kind, fun RestoreFunc) {
+	mu.Lock()
+	defer mu.Unlock()
+	restorer[kind] = fun
+}
+
+// Track limits the revisions of a kind to the keys matching fun
+func Track(kind Kind, fun func(key string) bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	tracked[kind] = fun
+}
+
+func isTracked(kind Kind, key string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	fun, ok := tracked[kind]
+	return !ok || fun(key)
+}
+
+// Record stores a revision with secrets redacted. Unchanged values and untracked keys are ignored.
+func Record(author string, kind Kind, key string, old, new *string) {
+	RecordTx(db.Instance, author, kind, key, old, new)
+}
+
+// RecordTx stores a revision as part of a database transaction
+func RecordTx(tx *gorm.DB, author string, kind Kind, key string, old, new *string) {
+	if tx == nil || !isTracked(kind, key) {
+		return
+	}
+
+	if old, new = redact(old), redact(new); equal(old, new) {
+		return
+	}
+
+	if author == "" {
+		author = System
+	}
+
+	r := Revision{
+		Created: time.Now(),
+		Author:  author,
+		Kind:    kind,
+		Key:     key,
+		Old:     old,
+		New:     new,
+	}
+
+	if err := tx.Create(&r).Error; err != nil {
+		log.ERROR.Printf("persist: %v", err)
+	}
+}
+
+func equal(a, b *string) bool {
+	return a == b || a != nil && b != nil && *a == *b
+}
+
+// Query selects revisions
+type Query struct {
+	Kind   Kind
+	Key    string
+	Offset int
+	Limit  int
+}
+
+// Find returns the revisions matching the query, newest first, and the total number of matches
+func Find(q Query) ([]Revision, int64, error) {
+	res := make([]Revision, 0)
+	if db.Instance == nil {
+		return res, 0, nil
+	}
+
+	tx := db.Instance.Model(new(Revision))
+
+	if q.Kind != "" {
+		tx = tx.Where("kind = ?", q.Kind)
+	}
+	if q.Key != "" {
+		tx = tx.Where("key = ?", q.Key)
+	}
+
+	var total int64
+	if err := tx.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if q.Limit > 0 {
+		tx = tx.Limit(q.Limit)
+	}
+
+	err := tx.Order("id DESC").Offset(q.Offset).Find(&res).Error
+
+	for i := range res {
+		res[i].Old, res[i].New = redact(res[i].Old), redact(res[i].New)
+	}
+
+	return res, total, err
+}
+
+// At returns the latest revision created at or before ts
+func At(ts time.Time) (uint, error) {
+	if db.Instance == nil {
+		return 0, errors.New("database offline")
+	}
+
+	var r Revision
+	err := db.Instance.Where("created <= ?", ts).Order("id DESC").Limit(1).Find(&r).Error
+
+	return r.ID, err
+}
+
+// latest returns the value of a key as of revision id (inclusive), i.e. the new value of its
+// latest revision up to id. If the key was not changed up to id, ok is false.
+func latest(kind Kind, key string, id uint) (*string, bool, error) {
+	var r Revision
+	tx := db.Instance.Where("kind = ? AND key = ? AND id <= ?", kind, key, id).Order("id DESC").Limit(1).Find(&r)
+	if tx.Error != nil || tx.RowsAffected == 0 {
+		return nil, false, tx.Error
+	}
+
+	return redact(r.New), true, nil
+}
+
+// span is the range of revisions of a key
+type span struct {
+	Kind  Kind
+	Key   string
+	First uint
+	Last  uint
+}
+
+// Diff returns the changes between the states as of two revisions
+func Diff(from, to uint) ([]Change, error) {
+	if db.Instance == nil {
+		return nil, errors.New("database offline")
+	}
+
+	lo, hi := min(from, to), max(from, to)
+
+	// keys changed between the revisions
+	var spans []span
+	if err := db.Instance.Model(new(Revision)).
+		Select("kind, key, MIN(id) AS first, MAX(id) AS last").
+		Where("id > ? AND id <= ?", lo, hi).
+		Group("kind, key").
+		Scan(&spans).Error; err != nil {
+		return nil, err
+	}
+
+	var res []Change
+	for _, s := range spans {
+		before, ok, err := latest(s.Kind, s.Key, lo)
+		if err != nil {
+			return nil, err
+		}
+
+		// key first recorded later, resolve to the value before its first change
+		if !ok {
+			var first Revision
+			if err := db.Instance.First(&first, s.First).Error; err != nil {
+				return nil, err
+			}
+			before = redact(first.Old)
+		}
+
+		var last Revision
+		if err := db.Instance.First(&last, s.Last).Error; err != nil {
+			return nil, err
+		}
+		after := redact(last.New)
+
+		if from > to {
+			before, after = after, before
+		}
+
+		if !equal(before, after) {
+			res = append(res, Change{Kind: s.Kind, Key: s.Key, Old: before, New: after})
+		}
+	}
+
+	slices.SortFunc(res, func(a, b Change) int {
+		return cmp.Or(cmp.Compare(a.Kind, b.Kind), cmp.Compare(a.Key, b.Key))
+	})
+
+	return res, nil
+}
+
+// Rollback restores the state as of revision id. If key is not empty, only this key is restored.
+// The restore is recorded as a new revision by the restore functions.
+func Rollback(author string, id uint, kind Kind, key string) ([]Change, error) {
+	if db.Instance == nil {
+		return nil, errors.New("database offline")
+	}
+
+	var latest Revision
+	if err := db.Instance.Order("id DESC").First(&latest).Error; err != nil {
+		return nil, err
+	}
+
+	changes, err := Diff(latest.ID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if key != "" {
+		changes = slices.DeleteFunc(changes, func(c Change) bool {
+			return c.Kind != kind || c.Key != key
+		})
+	}
+
+	for _, c := range changes {
+		mu.RLock()
+		restore, ok := restorer[c.Kind]
+		mu.RUnlock()
+
+		if !ok {
+			return nil, fmt.Errorf("cannot restore %s", c.Kind)
+		}
+
+		if err := restore(author, c.Key, c.New); err != nil {
+			return nil, fmt.Errorf("%s %s: %w", c.Kind, c.Key, err)
+		}
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,157 @@
+package history_test
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/server/db/history"
+	"github.com/evcc-io/evcc/server/db/settings"
+	"github.com/evcc-io/evcc/util/config"
+	"github.com/evcc-io/evcc/util/templates"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func latest(t *testing.T) history.Revision {
+	t.Helper()
+
+	res, _, err := history.Find(history.Query{Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+
+	return res[0]
+}
+
+func TestHistory(t *testing.T) {
+	require.NoError(t, db.NewInstance("sqlite", ":memory:"))
+
+	conf, err := config.AddConfig(templates.Meter, map[string]any{"power": 1}, config.WithAuthor("alice"))
+	require.NoError(t, err)
+	name := config.NameForID(conf.ID)
+
+	added := latest(t)
+	assert.Equal(t, "alice", added.Author)
+	assert.Equal(t, history.Device, added.Kind)
+	assert.Equal(t, name, added.Key)
+	assert.Nil(t, added.Old)
+
+	settings.Attribute("bob", func() {
+		settings.SetString("title", "foo")
+	})
+	require.NoError(t, settings.Persist())
+
+	require.NoError(t, conf.Update(map[string]any{"power": 2}))
+	settings.SetString("title", "bar")
+	require.NoError(t, settings.Persist())
+
+	// unchanged settings are not recorded again
+	require.NoError(t, settings.Persist())
+
+	res, total, err := history.Find(history.Query{Kind: history.Setting})
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, total)
+	assert.Equal(t, history.System, res[0].Author)
+	assert.Equal(t, "bob", res[1].Author)
+
+	changes, err := history.Diff(added.ID, latest(t).ID)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	assert.Equal(t, name, changes[0].Key)
+	assert.Equal(t, "title", changes[1].Key)
+	assert.Equal(t, "bar", *changes[1].New)
+
+	// roll back a single setting
+	_, err = history.Rollback("carol", added.ID+1, history.Setting, "title")
+	require.NoError(t, err)
+
+	title, err := settings.String("title")
+	require.NoError(t, err)
+	assert.Equal(t, "foo", title)
+
+	dev, err := config.ConfigByID(conf.ID)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, dev.Data["power"])
+
+	// roll back everything before the device was added
+	_, err = history.Rollback("carol", added.ID-1, "", "")
+	require.NoError(t, err)
+
+	_, err = config.ConfigByID(conf.ID)
+	assert.Error(t, err)
+
+	_, err = settings.String("title")
+	assert.ErrorIs(t, err, settings.ErrNotFound)
+	assert.Equal(t, "carol", latest(t).Author)
+}
+
+func TestHistoryRedacted(t *testing.T) {
+	require.NoError(t, db.NewInstance("sqlite", ":memory:"))
+
+	history.Secrets(func(key string) bool { return key == "password" })
+	t.Cleanup(func() { history.Secrets(func(string) bool { return false }) })
+
+	settings.SetString("mqtt", `{"broker":"foo","password":"secret"}`)
+	require.NoError(t, settings.Persist())
+
+	rev := latest(t)
+	assert.Equal(t, `{"broker":"foo","password":"*****"}`, *rev.New)
+
+	// secret-only changes are not recorded
+	settings.SetString("mqtt", `{"broker":"foo","password":"other"}`)
+	require.NoError(t, settings.Persist())
+	assert.Equal(t, rev.ID, latest(t).ID)
+
+	settings.SetString("mqtt", `{"broker":"bar","password":"other"}`)
+	require.NoError(t, settings.Persist())
+
+	// rollback keeps the current secret
+	_, err := history.Rollback("alice", rev.ID, history.Setting, "mqtt")
+	require.NoError(t, err)
+
+	mqtt, err := settings.String("mqtt")
+	require.NoError(t, err)
+	assert.Equal(t, `{"broker":"foo","password":"other"}`, mqtt)
+
+	// yaml settings
+	assert.Equal(t, "password: secret\nuser: foo", history.Unredact("user: foo\npassword: '*****'", "user: bar\npassword: secret"))
+}
+
+func TestHistoryDiff(t *testing.T) {
+	require.NoError(t, db.NewInstance("sqlite", ":memory:"))
+
+	settings.SetString("title", "foo")
+	require.NoError(t, settings.Persist())
+	first := latest(t)
+
+	settings.SetString("title", "bar")
+	settings.SetString("currency", "EUR")
+	require.NoError(t, settings.Persist())
+
+	settings.SetString("title", "baz")
+	require.NoError(t, settings.Persist())
+	last := latest(t)
+
+	changes, err := history.Diff(first.ID, last.ID)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	assert.Equal(t, "currency", changes[0].Key)
+	assert.Nil(t, changes[0].Old)
+	assert.Equal(t, "EUR", *changes[0].New)
+	assert.Equal(t, "title", changes[1].Key)
+	assert.Equal(t, "foo", *changes[1].Old)
+	assert.Equal(t, "baz", *changes[1].New)
+
+	// reverse diff
+	changes, err = history.Diff(last.ID, first.ID)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	assert.Equal(t, "EUR", *changes[0].Old)
+	assert.Nil(t, changes[0].New)
+	assert.Equal(t, "baz", *changes[1].Old)
+	assert.Equal(t, "foo", *changes[1].New)
+
+	// unchanged
+	changes, err = history.Diff(last.ID, last.ID)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
@@ -0,0 +1,184 @@
+package history
+
+import (
+	"encoding/json"
+	"strings"
+
+	"go.yaml.in/yaml/v4"
+)
+
+// Redacted replaces secret values in recorded revisions
+const Redacted = "*****"
+
+var secret = func(string) bool { return false }
+
+// Secrets registers the predicate identifying keys holding secrets. Secret values are
+// neither recorded nor served and keep their current value on rollback.
+func Secrets(fun func(key string) bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	secret = fun
+}
+
+func isSecret(key string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return secret(key)
+}
+
+// parse decodes json or yaml documents. Scalar values are not documents.
+func parse(s string) (any, bool, bool) {
+	var v any
+
+	isJSON := json.Valid([]byte(s))
+	if isJSON {
+		if err := json.Unmarshal([]byte(s), &v); err != nil {
+			return nil, false, false
+		}
+	} else if err := yaml.Unmarshal([]byte(s), &v); err != nil {
+		return nil, false, false
+	}
+
+	switch v.(type) {
+	case map[string]any, []any:
+		return v, isJSON, true
+	}
+
+	return nil, false, false
+}
+
+func format(v any, isJSON bool) (string, error) {
+	if isJSON {
+		b, err := json.Marshal(v)
+		return string(b), err
+	}
+
+	b, err := yaml.Marshal(v)
+	return strings.TrimSpace(string(b)), err
+}
+
+// Redact masks the secrets of a json or yaml document
+func Redact(value string) string {
+	return *redact(&value)
+}
+
+// redact masks secrets in a json or yaml document
+func redact(s *string) *string {
+	if s == nil {
+		return nil
+	}
+
+	v, isJSON, ok := parse(*s)
+	if !ok {
+		return s
+	}
+
+	v, changed := redactValue(v)
+	if !changed {
+		return s
+	}
+
+	res, err := format(v, isJSON)
+	if err != nil {
+		return new(Redacted)
+	}
+
+	return &res
+}
+
+func redactValue(v any) (any, bool) {
+	var changed bool
+
+	switch t := v.(type) {
+	case map[string]any:
+		res := make(map[string]any, len(t))
+		for k, v := range t {
+			if isSecret(k) && v != nil && v != "" {
+				res[k] = Redacted
+				changed = true
+				continue
+			}
+
+			var c bool
+			res[k], c = redactValue(v)
+			changed = changed || c
+		}
+		return res, changed
+
+	case []any:
+		res := make([]any, len(t))
+		for i, v := range t {
+			var c bool
+			res[i], c = redactValue(v)
+			changed = changed || c
+		}
+		return res, changed
+
+	case string:
+		// embedded yaml configuration
+		if strings.Contains(t, "\n") {
+			if res := redact(&t); *res != t {
+				return *res, true
+			}
+		}
+	}
+
+	return v, false
+}
+
+// Unredact replaces the redacted secrets of value with the secrets at the same position in current
+func Unredact(value, current string) string {
+	if !strings.Contains(value, Redacted) {
+		return value
+	}
+
+	v, isJSON, ok := parse(value)
+	if !ok {
+		return value
+	}
+
+	cur, _, _ := parse(current)
+
+	res, err := format(unredactValue(v, cur), isJSON)
+	if err != nil {
+		return value
+	}
+
+	return res
+}
+
+func unredactValue(v, current any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		cur, _ := current.(map[string]any)
+		for k, v := range t {
+			if v == Redacted {
+				// secrets missing from the current value are dropped
+				if c, ok := cur[k]; ok {
+					t[k] = c
+				} else {
+					delete(t, k)
+				}
+				continue
+			}
+			t[k] = unredactValue(v, cur[k])
+		}
+
+	case []any:
+		cur, _ := current.([]any)
+		for i, v := range t {
+			var c any
+			if i < len(cur) {
+				c = cur[i]
+			}
+			t[i] = unredactValue(v, c)
+		}
+
+	case string:
+		if c, ok := current.(string); ok && strings.Contains(t, Redacted) {
+			return Unredact(t, c)
+		}
+	}
+
+	return v
+}
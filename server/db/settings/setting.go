@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/server/db/history"
 	"github.com/evcc-io/evcc/util"
 	"github.com/samber/lo"
 	"go.yaml.in/yaml/v4"
@@ -24,9 +25,11 @@ var ErrNotFound = errors.New("not found")
 
 // setting is a settings entry
 type setting struct {
-	dirty bool
-	Key   string `json:"key" gorm:"primarykey"`
-	Value string `json:"value"`
+	dirty     bool
+	persisted *string // last persisted value for revision history
+	author    string  // author of the pending change for revision history
+	Key       string  `json:"key" gorm:"primarykey"`
+	Value     string  `json:"value"`
 }
 
 var (
@@ -40,29 +43,107 @@ func init() {
 			return err
 		}
 
-		return db.Find(&settings).Error
+		return load(db)
 	})
+
+	history.Register(history.Setting, restore)
 }
 
+func load(db *gorm.DB) error {
+	var res []setting
+	if err := db.Find(&res).Error; err != nil {
+		return err
+	}
+
+	for i := range res {
+		res[i].persisted = new(res[i].Value)
+	}
+
+	mu.Lock()
+	settings = res
+	mu.Unlock()
+
+	return nil
+}
+
+// Reload discards unsaved changes and reloads the settings from the database
+func Reload() error {
+	return load(db.Instance)
+}
+
+// Persist saves changed settings to the database and records them as revisions by their author
 func Persist() error {
+	return PersistTx(db.Instance)
+}
+
+// PersistTx saves changed settings as part of a database transaction
+func PersistTx(tx *gorm.DB) error {
 	mu.Lock()
 	defer mu.Unlock()
 
-	if dirty := lo.FilterMap(settings, func(s setting, _ int) (*setting, bool) {
-		return &s, s.dirty
+	if dirty := lo.Filter(settings, func(s setting, _ int) bool {
+		return s.dirty
 	}); len(dirty) > 0 {
-		if err := db.Instance.Save(dirty).Error; err != nil {
+		if err := tx.Save(dirty).Error; err != nil {
 			return err
 		}
 
-		for _, s := range dirty {
-			s.dirty = false
+		for i := range settings {
+			if s := &settings[i]; s.dirty {
+				history.RecordTx(tx, s.author, history.Setting, s.Key, s.persisted, &s.Value)
+				s.dirty = false
+				s.author = ""
+				s.persisted = new(s.Value)
+			}
 		}
 	}
 
 	return nil
 }
 
+// restore restores a setting from the revision history
+func restore(author, key string, value *string) error {
+	if value == nil {
+		return deleteAs(author, key)
+	}
+
+	val := *value
+	if cur, err := String(key); err == nil {
+		val = history.Unredact(val, cur)
+	}
+
+	Attribute(author, func() {
+		SetString(key, val)
+	})
+
+	return Persist()
+}
+
+// Attribute runs fun and attributes the settings it changes to author in the revision history.
+// Changes not attributed are recorded as made by the system.
+func Attribute(author string, fun func()) {
+	mu.RLock()
+	before := make(map[string]string)
+	for _, s := range settings {
+		if s.dirty {
+			before[s.Key] = s.Value
+		}
+	}
+	mu.RUnlock()
+
+	fun()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	for i := range settings {
+		s := &settings[i]
+		if val, ok := before[s.Key]; s.dirty && (!ok || val != s.Value) {
+			s.author = author
+		}
+	}
+}
+
 func All() []setting {
 	mu.RLock()
 	defer mu.RUnlock()
@@ -81,6 +162,10 @@ func equal(key string) func(setting) bool {
 }
 
 func Delete(key string) error {
+	return deleteAs(history.System, key)
+}
+
+func deleteAs(author, key string) error {
 	mu.Lock()
 	defer mu.Unlock()
 
@@ -91,6 +176,7 @@ func Delete(key string) error {
 			return err
 		}
 
+		history.Record(author, history.Setting, key, settings[idx].persisted, nil)
 		settings = slices.Delete(settings, idx, idx+1)
 	}
 
@@ -102,7 +188,7 @@ func SetString(key string, val string) {
 	defer mu.Unlock()
 
 	if idx := slices.IndexFunc(settings, equal(key)); idx < 0 {
-		settings = append(settings, setting{true, key, val})
+		settings = append(settings, setting{dirty: true, Key: key, Value: val})
 	} else if settings[idx].Value != val {
 		settings[idx].dirty = true
 		settings[idx].Value = val
@@ -3,7 +3,7 @@ package eebus
 // EEBUS use case scenario numbers per the respective Use Case Technical Specifications.
 //
 // Spec scenario numbers diverge between use cases (e.g. MPC scenario 1 = active power,
-// MGCP scenario 1 = power factor; MPC scenario 2 = energy, MGCP scenario 2 = active power).
+// MGCP scenario 1 = power limitation factor; MPC scenario 2 = energy, MGCP scenario 2 = active power).
 // Passing the wrong number to IsScenarioAvailableAtEntity gates reads on the wrong feature.
 //
 // Each block mirrors the scenarios registered in the corresponding eebus-go usecase, which
@@ -11,7 +11,7 @@ package eebus
 
 // MGCP — Monitoring of Grid Connection Point (UC TS v1.0.0)
 const (
-	MGCPPowerFactor     uint = 1 // S1 power factor (cos phi)
+	MGCPPowerFactor     uint = 1 // S1 power limitation factor (feed-in limit, not cos phi)
 	MGCPPower           uint = 2 // S2 active power per phase + total
 	MGCPEnergyFeedIn    uint = 3 // S3 total feed-in energy
 	MGCPEnergyConsumed  uint = 4 // S4 total consumed energy
@@ -0,0 +1,325 @@
+// Package simulator provides a local controlbox (Energy Guard) peer stating
+// LPC/LPP limits, failsafe values and heartbeats to evcc's controllable system.
+package simulator
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/service"
+	ucapi "github.com/enbility/eebus-go/usecases/api"
+	"github.com/enbility/eebus-go/usecases/eg/lpc"
+	"github.com/enbility/eebus-go/usecases/eg/lpp"
+	shipapi "github.com/enbility/ship-go/api"
+	"github.com/enbility/ship-go/cert"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+	"github.com/evcc-io/evcc/server/eebus"
+	"github.com/evcc-io/evcc/util"
+)
+
+// ErrNotConnected is returned when writing before the controllable system is available
+var ErrNotConnected = errors.New("controllable system not connected")
+
+type Config struct {
+	Port        int
+	Certificate tls.Certificate // generated if empty
+	Heartbeat   time.Duration   // heartbeat timeout
+}
+
+// Controlbox is a simulated grid operator controlbox exposing LPC and LPP on a GridGuard entity
+type Controlbox struct {
+	mu  sync.Mutex
+	log *util.Logger
+
+	ski     string
+	service *service.Service
+
+	lpc ucapi.EgLPCInterface
+	lpp ucapi.EgLPPInterface
+
+	lpcEntity spineapi.EntityRemoteInterface
+	lppEntity spineapi.EntityRemoteInterface
+	connected bool
+}
+
+// New creates a controlbox. It must be started using Start.
+func New(conf Config) (*Controlbox, error) {
+	cc := Config{
+		Port:      4713,
+		Heartbeat: time.Minute,
+	}
+
+	if conf.Port != 0 {
+		cc.Port = conf.Port
+	}
+	if conf.Heartbeat != 0 {
+		cc.Heartbeat = conf.Heartbeat
+	}
+
+	cc.Certificate = conf.Certificate
+	if len(cc.Certificate.Certificate) == 0 {
+		certificate, err := cert.CreateCertificate("Demo", "Demo", "DE", "Demo-ControlBox-01")
+		if err != nil {
+			return nil, err
+		}
+		cc.Certificate = certificate
+	}
+
+	ski, err := eebus.SkiFromCert(cc.Certificate)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Controlbox{
+		log: util.NewLogger("simulator"),
+		ski: ski,
+	}
+
+	// unique per instance: a shared serial collides on ShipID when multiple controlboxes run concurrently
+	serial := ski[:8]
+
+	configuration, err := api.NewConfiguration(
+		"Demo", "Demo", "ControlBox", serial,
+		[]shipapi.DeviceCategoryType{shipapi.DeviceCategoryTypeGridConnectionHub},
+		model.DeviceTypeTypeElectricitySupplySystem,
+		[]model.EntityTypeType{model.EntityTypeTypeGridGuard},
+		cc.Port, cc.Certificate, cc.Heartbeat, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	configuration.SetAlternateIdentifier("Demo-ControlBox-" + serial)
+
+	c.service = service.NewService(configuration, c)
+
+	if err := c.service.Setup(); err != nil {
+		return nil, err
+	}
+
+	localEntity := c.service.LocalDevice().EntityForType(model.EntityTypeTypeGridGuard)
+
+	c.lpc = lpc.NewLPC(localEntity, c.onLPCEvent)
+	c.service.AddUseCase(c.lpc)
+
+	c.lpp = lpp.NewLPP(localEntity, c.onLPPEvent)
+	c.service.AddUseCase(c.lpp)
+
+	return c, nil
+}
+
+// Ski returns the controlbox SKI to be configured as evcc's hems ski
+func (c *Controlbox) Ski() string {
+	return c.ski
+}
+
+// Start trusts the controllable system identified by ski and starts the service until ctx is cancelled
+func (c *Controlbox) Start(ctx context.Context, ski string) {
+	c.service.RegisterRemoteService(shipapi.NewServiceIdentity(ski, "", ""))
+	c.service.Start()
+
+	go func() {
+		<-ctx.Done()
+		c.service.Shutdown()
+	}()
+}
+
+// Shutdown stops the service
+func (c *Controlbox) Shutdown() {
+	c.service.Shutdown()
+}
+
+// Connected reports if the controllable system has stated its LPC limit data
+func (c *Controlbox) Connected() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.connected && c.lpcEntity != nil
+}
+
+// Wait blocks until the controllable system is connected or ctx is cancelled
+func (c *Controlbox) Wait(ctx context.Context) error {
+	for tick := time.Tick(100 * time.Millisecond); !c.Connected(); {
+		select {
+		case <-tick:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+func (c *Controlbox) entities() (spineapi.EntityRemoteInterface, spineapi.EntityRemoteInterface, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.connected || c.lpcEntity == nil {
+		return nil, nil, ErrNotConnected
+	}
+
+	return c.lpcEntity, c.lppEntity, nil
+}
+
+// WriteConsumptionLimit states the LPC consumption limit and waits for the result
+func (c *Controlbox) WriteConsumptionLimit(limit ucapi.LoadLimit) error {
+	entity, _, err := c.entities()
+	if err != nil {
+		return err
+	}
+
+	return eebus.Await(func(cb func(model.ResultDataType, model.MsgCounterType)) (*model.MsgCounterType, error) {
+		return c.lpc.WriteConsumptionLimit(entity, limit, cb)
+	})
+}
+
+// WriteProductionLimit states the LPP production limit (negative watts) and waits for the result
+func (c *Controlbox) WriteProductionLimit(limit ucapi.LoadLimit) error {
+	_, entity, err := c.entities()
+	if err != nil {
+		return err
+	}
+	if entity == nil {
+		return ErrNotConnected
+	}
+
+	return eebus.Await(func(cb func(model.ResultDataType, model.MsgCounterType)) (*model.MsgCounterType, error) {
+		return c.lpp.WriteProductionLimit(entity, limit, cb)
+	})
+}
+
+// WriteFailsafeConsumptionLimit writes the LPC failsafe consumption active power limit
+func (c *Controlbox) WriteFailsafeConsumptionLimit(value float64) error {
+	entity, _, err := c.entities()
+	if err != nil {
+		return err
+	}
+
+	_, err = c.lpc.WriteFailsafeConsumptionActivePowerLimit(entity, value)
+	return err
+}
+
+// WriteFailsafeProductionLimit writes the LPP failsafe production active power limit
+func (c *Controlbox) WriteFailsafeProductionLimit(value float64) error {
+	_, entity, err := c.entities()
+	if err != nil {
+		return err
+	}
+	if entity == nil {
+		return ErrNotConnected
+	}
+
+	_, err = c.lpp.WriteFailsafeProductionActivePowerLimit(entity, value)
+	return err
+}
+
+// WriteFailsafeDuration writes the minimum failsafe duration for both LPC and LPP
+func (c *Controlbox) WriteFailsafeDuration(duration time.Duration) error {
+	lpcEntity, lppEntity, err := c.entities()
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.lpc.WriteFailsafeDurationMinimum(lpcEntity, duration); err != nil {
+		return err
+	}
+
+	if lppEntity != nil {
+		_, err = c.lpp.WriteFailsafeDurationMinimum(lppEntity, duration)
+	}
+
+	return err
+}
+
+// Heartbeat starts or stops sending heartbeats. Stopping the heartbeat makes
+// the controllable system enter its failsafe state after the heartbeat timeout.
+func (c *Controlbox) Heartbeat(enable bool) {
+	if enable {
+		c.lpc.StartHeartbeat()
+	} else {
+		c.lpc.StopHeartbeat()
+	}
+}
+
+// HeartbeatReceived reports if the controllable system's heartbeat is within its timeout
+func (c *Controlbox) HeartbeatReceived() bool {
+	entity, _, err := c.entities()
+	return err == nil && c.lpc.IsHeartbeatWithinDuration(entity)
+}
+
+func (c *Controlbox) onLPCEvent(ski string, device spineapi.DeviceRemoteInterface, entity spineapi.EntityRemoteInterface, event api.EventType) {
+	switch event {
+	// limit descriptions and data are available
+	case lpc.DataUpdateLimit:
+		c.mu.Lock()
+		c.lpcEntity = entity
+		c.mu.Unlock()
+
+		if limit, err := c.lpc.ConsumptionLimit(entity); err == nil {
+			c.log.DEBUG.Printf("lpc: consumption limit %.0fW (active: %t)", limit.Value, limit.IsActive)
+		}
+
+	default:
+		c.log.TRACE.Println("lpc:", event)
+	}
+}
+
+func (c *Controlbox) onLPPEvent(ski string, device spineapi.DeviceRemoteInterface, entity spineapi.EntityRemoteInterface, event api.EventType) {
+	switch event {
+	case lpp.DataUpdateLimit:
+		c.mu.Lock()
+		c.lppEntity = entity
+		c.mu.Unlock()
+
+		if limit, err := c.lpp.ProductionLimit(entity); err == nil {
+			c.log.DEBUG.Printf("lpp: production limit %.0fW (active: %t)", limit.Value, limit.IsActive)
+		}
+
+	default:
+		c.log.TRACE.Println("lpp:", event)
+	}
+}
+
+// EEBUSServiceHandler
+
+func (c *Controlbox) RemoteServiceConnected(service api.ServiceInterface, identity shipapi.ServiceIdentity) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.log.DEBUG.Println("connected:", identity.SKI)
+	c.connected = true
+}
+
+func (c *Controlbox) RemoteServiceDisconnected(service api.ServiceInterface, identity shipapi.ServiceIdentity) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.log.DEBUG.Println("disconnected:", identity.SKI)
+	c.connected = false
+	c.lpcEntity = nil
+	c.lppEntity = nil
+}
+
+func (c *Controlbox) VisibleRemoteMdnsServicesUpdated(service api.ServiceInterface, entries []shipapi.RemoteMdnsService) {
+}
+
+func (c *Controlbox) ServiceUpdated(identity shipapi.ServiceIdentity) {
+}
+
+func (c *Controlbox) ServicePairingDetailUpdate(identity shipapi.ServiceIdentity, detail *shipapi.ConnectionStateDetail) {
+}
+
+func (c *Controlbox) ServiceAutoTrusted(service api.ServiceInterface, identity shipapi.ServiceIdentity) {
+}
+
+func (c *Controlbox) ServiceAutoTrustFailed(service api.ServiceInterface, identity shipapi.ServiceIdentity, reason error) {
+}
+
+func (c *Controlbox) ServiceAutoTrustRemoved(service api.ServiceInterface, identity shipapi.ServiceIdentity, reason string) {
+}
+
+func (c *Controlbox) AllowWaitingForTrust(identity shipapi.ServiceIdentity) bool {
+	return true
+}
@@ -0,0 +1,160 @@
+// Package simulatortest provides a test harness connecting a simulated controlbox to an EEBus HEMS.
+package simulatortest
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/enbility/ship-go/cert"
+	"github.com/evcc-io/evcc/core/circuit"
+	"github.com/evcc-io/evcc/core/site"
+	hems "github.com/evcc-io/evcc/hems/eebus"
+	hemsutil "github.com/evcc-io/evcc/hems/hems"
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/server/eebus"
+	"github.com/evcc-io/evcc/server/eebus/simulator"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// harnessHeartbeat is the heartbeat timeout of the harness controlbox
+	harnessHeartbeat = time.Second
+
+	// harnessTimeout bounds how long assertions wait for the limits to apply
+	harnessTimeout = 30 * time.Second
+)
+
+// Harness connects a simulated controlbox to the eebus server and an EEBus HEMS
+// controlling a root circuit for end-to-end tests of grid operator limits.
+type Harness struct {
+	t testing.TB
+
+	Box     *simulator.Controlbox
+	HEMS    *hems.EEBus
+	Circuit *circuit.Circuit
+}
+
+// harnessSite implements site.API for the HEMS smartgrid sessions
+type harnessSite struct {
+	site.API
+}
+
+func (s *harnessSite) GetGridPower() float64 { return 0 }
+
+// NewHarness starts an eebus server, a controlbox paired via SKI and the HEMS.
+// The root circuit is limited to maxPower (0 = unlimited) and clamped by the HEMS.
+// The HEMS heartbeat timeout is shortened for the duration of the test.
+func NewHarness(t testing.TB, limits hems.Limits, maxPower float64) *Harness {
+	t.Helper()
+
+	require.NoError(t, db.NewInstance("sqlite", ":memory:"))
+
+	// tear down a server instance left over from other tests in this package
+	if inst, err := eebus.Instance(); err == nil {
+		inst.Shutdown()
+	}
+
+	certificate, err := cert.CreateCertificate("Demo", "Demo", "DE", "Demo-Harness-01")
+	require.NoError(t, err, "certificate")
+
+	public, private, err := eebus.GetX509KeyPair(certificate)
+	require.NoError(t, err, "decode certificate")
+
+	_, err = eebus.NewServer(eebus.Config{
+		Port: freePort(t),
+		Certificate: eebus.Certificate{
+			Public:  public,
+			Private: private,
+		},
+	})
+	require.NoError(t, err, "server")
+
+	inst, err := eebus.Instance()
+	require.NoError(t, err, "instance")
+	t.Cleanup(inst.Shutdown)
+
+	box, err := simulator.New(simulator.Config{
+		Port:      freePort(t),
+		Heartbeat: harnessHeartbeat,
+	})
+	require.NoError(t, err, "controlbox")
+
+	box.Start(t.Context(), inst.Ski())
+
+	timeout := hems.HeartbeatTimeout
+	hems.HeartbeatTimeout = 5 * harnessHeartbeat
+	t.Cleanup(func() { hems.HeartbeatTimeout = timeout })
+
+	hm, err := hems.NewEEBus(t.Context(), box.Ski(), limits, nil, &harnessSite{}, 100*time.Millisecond)
+	require.NoError(t, err, "hems")
+
+	go hm.Run()
+
+	root, err := circuit.New(util.NewLogger("harness"), "root", 0, maxPower, nil, 0)
+	require.NoError(t, err, "circuit")
+	root.SetHEMS(hm)
+
+	ctx, cancel := context.WithTimeout(t.Context(), harnessTimeout)
+	defer cancel()
+	require.NoError(t, box.Wait(ctx), "controllable system not connected")
+
+	return &Harness{
+		t:       t,
+		Box:     box,
+		HEMS:    hm,
+		Circuit: root,
+	}
+}
+
+// AssertDimmed waits until the site's dimmed state matches
+func (h *Harness) AssertDimmed(dimmed bool) {
+	h.t.Helper()
+
+	require.Eventually(h.t, func() bool {
+		res := hemsutil.Dimmed(h.HEMS)
+		return res != nil && *res == dimmed
+	}, harnessTimeout, 100*time.Millisecond, "dimmed: expected %t", dimmed)
+}
+
+// AssertMaxConsumptionPower waits until the HEMS consumption limit matches (0 = none)
+func (h *Harness) AssertMaxConsumptionPower(limit float64) {
+	h.t.Helper()
+
+	require.Eventually(h.t, func() bool {
+		res := h.HEMS.MaxConsumptionPower()
+		return res != nil && *res == limit
+	}, harnessTimeout, 100*time.Millisecond, "max consumption power: expected %.0fW", limit)
+}
+
+// AssertLoadpointPower waits until a loadpoint requesting power is capped at expected
+func (h *Harness) AssertLoadpointPower(power, expected float64) {
+	h.t.Helper()
+
+	require.Eventually(h.t, func() bool {
+		return h.Circuit.ValidatePower(0, power) == expected
+	}, harnessTimeout, 100*time.Millisecond, "loadpoint power: expected %.0fW capped at %.0fW", power, expected)
+}
+
+// AssertCurtailedPercent waits until the allowed feed-in percent matches
+func (h *Harness) AssertCurtailedPercent(percent int) {
+	h.t.Helper()
+
+	require.Eventually(h.t, func() bool {
+		res := h.HEMS.CurtailedPercent()
+		return res != nil && *res == percent
+	}, harnessTimeout, 100*time.Millisecond, "curtailed percent: expected %d%%", percent)
+}
+
+// freePort returns a currently unused tcp port
+func freePort(t testing.TB) int {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+
+	return l.Addr().(*net.TCPAddr).Port
+}
@@ -0,0 +1,46 @@
+package simulatortest
+
+import (
+	"testing"
+
+	ucapi "github.com/enbility/eebus-go/usecases/api"
+	hems "github.com/evcc-io/evcc/hems/eebus"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHarness(t *testing.T) {
+	util.LogLevel("error", map[string]string{"eebus": "trace"})
+
+	h := NewHarness(t, hems.Limits{
+		FailsafeConsumptionActivePowerLimit: 4200,
+	}, 22000)
+
+	// unlimited until the controlbox states a limit
+	h.AssertDimmed(false)
+	h.AssertLoadpointPower(11000, 11000)
+
+	// LPC limit dims the site and caps the loadpoint
+	require.NoError(t, h.Box.WriteConsumptionLimit(ucapi.LoadLimit{IsActive: true, Value: 3000}))
+	h.AssertDimmed(true)
+	h.AssertMaxConsumptionPower(3000)
+	h.AssertLoadpointPower(11000, 3000)
+
+	// released limit
+	require.NoError(t, h.Box.WriteConsumptionLimit(ucapi.LoadLimit{IsActive: false, Value: 3000}))
+	h.AssertDimmed(false)
+	h.AssertLoadpointPower(11000, 11000)
+
+	// failsafe value stated by the controlbox applies on heartbeat loss
+	require.NoError(t, h.Box.WriteFailsafeConsumptionLimit(5000))
+	h.Box.Heartbeat(false)
+	h.AssertDimmed(true)
+	h.AssertMaxConsumptionPower(5000)
+	h.AssertLoadpointPower(11000, 5000)
+
+	// failsafe is left on heartbeat and a following limit
+	h.Box.Heartbeat(true)
+	require.NoError(t, h.Box.WriteConsumptionLimit(ucapi.LoadLimit{IsActive: false}))
+	h.AssertDimmed(false)
+	h.AssertLoadpointPower(11000, 11000)
+}
@@ -2,18 +2,24 @@ package server
 
 import (
 	"fmt"
+	"maps"
 	"net/http"
 	"os"
+	"slices"
 	"time"
 
 	eapi "github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/api/globalconfig"
 	"github.com/evcc-io/evcc/core"
+	"github.com/evcc-io/evcc/core/audit"
+	"github.com/evcc-io/evcc/core/automation"
 	"github.com/evcc-io/evcc/core/keys"
 	"github.com/evcc-io/evcc/core/loadpoint"
 	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/core/vehicle"
 	"github.com/evcc-io/evcc/hems/shm"
 	"github.com/evcc-io/evcc/server/assets"
+	"github.com/evcc-io/evcc/server/db/history"
 	"github.com/evcc-io/evcc/server/eebus"
 	"github.com/evcc-io/evcc/server/remote"
 	"github.com/evcc-io/evcc/server/service"
@@ -155,7 +161,7 @@ func (s *HTTPd) Router() *mux.Router {
 }
 
 // RegisterSiteHandlers connects the http handlers to the site
-func (s *HTTPd) RegisterSiteHandlers(site site.API) {
+func (s *HTTPd) RegisterSiteHandlers(site site.API, auth auth.Auth) {
 	router := s.Server.Handler.(*mux.Router)
 
 	// api
@@ -165,8 +171,108 @@ func (s *HTTPd) RegisterSiteHandlers(site site.API) {
 	api.Use(handlers.CORS(
 		handlers.AllowedHeaders([]string{"Content-Type"}),
 	))
+	api.Use(auditSourceHandler(auth))
 
 	// site api
+	siteAudited := newAuditedRoutes(func(src audit.Source) map[string]route {
+		return siteRoutes(audit.Site(site, src))
+	})
+	for name, r := range siteRoutes(site) {
+		api.Methods(r.Methods()...).Path(r.Pattern).Handler(siteAudited.handler(name, r))
+	}
+
+	// vehicle api
+	vehicleAudited := newAuditedRoutes(func(src audit.Source) map[string]route {
+		return vehicleRoutes(audit.Site(site, src))
+	})
+	for name, r := range vehicleRoutes(site) {
+		api.Methods(r.Methods()...).Path(r.Pattern).Handler(vehicleAudited.handler(name, r))
+	}
+
+	// vehicle api fetching remote resources
+	ensureAuth := ensureAuthHandler(auth)
+	vehicleAuthAudited := newAuditedRoutes(func(src audit.Source) map[string]route {
+		return vehicleAuthRoutes(audit.Site(site, src))
+	})
+	for name, r := range vehicleAuthRoutes(site) {
+		api.Methods(r.Methods()...).Path(r.Pattern).Handler(ensureAuth(vehicleAuthAudited.handler(name, r)))
+	}
+
+	// loadpoint api
+	// TODO any loadpoint
+	for id, lp := range site.Loadpoints() {
+		api := api.PathPrefix(fmt.Sprintf("/loadpoints/%d", id+1)).Subrouter()
+
+		lpAudited := newAuditedRoutes(func(src audit.Source) map[string]route {
+			return loadpointRoutes(audit.Site(site, src), audit.Loadpoint(lp, audit.LoadpointTarget(id), src))
+		})
+		for name, r := range loadpointRoutes(site, lp) {
+			api.Methods(r.Methods()...).Path(r.Pattern).Handler(lpAudited.handler(name, r))
+		}
+	}
+}
+
+// RegisterNamedSiteHandlers connects the http handlers of additional sites below /api/sites/{id}.
+// Sessions, history and settings are global and remain served by the default site only.
+func (s *HTTPd) RegisterNamedSiteHandlers(sites map[string]site.API, cache *util.ParamCache, auth auth.Auth) {
+	router := s.Server.Handler.(*mux.Router)
+
+	// api
+	api := router.PathPrefix("/api").Subrouter()
+	api.Use(jsonHandler)
+	api.Use(handlers.CompressHandler)
+	api.Use(handlers.CORS(
+		handlers.AllowedHeaders([]string{"Content-Type"}),
+	))
+	api.Use(auditSourceHandler(auth))
+
+	ids := slices.DeleteFunc(slices.Sorted(maps.Keys(sites)), func(id string) bool {
+		return id == ""
+	})
+	api.Methods("GET").Path("/sites").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jsonWrite(w, ids)
+	})
+
+	for _, id := range ids {
+		site := sites[id]
+		api := api.PathPrefix("/sites/" + id).Subrouter()
+
+		api.Methods("GET").Path("/state").Handler(stateHandler(cache, id))
+
+		// site api
+		siteAudited := newAuditedRoutes(func(src audit.Source) map[string]route {
+			return siteRoutes(audit.NamedSite(site, id, src))
+		})
+		for name, r := range siteRoutes(site) {
+			if slices.Contains(globalSiteRoutes, name) {
+				continue
+			}
+			api.Methods(r.Methods()...).Path(r.Pattern).Handler(siteAudited.handler(name, r))
+		}
+
+		// loadpoint api
+		for lpid, lp := range site.Loadpoints() {
+			api := api.PathPrefix(fmt.Sprintf("/loadpoints/%d", lpid+1)).Subrouter()
+
+			lpAudited := newAuditedRoutes(func(src audit.Source) map[string]route {
+				site := audit.NamedSite(site, id, src)
+				return loadpointRoutes(site, site.Loadpoints()[lpid])
+			})
+			for name, r := range loadpointRoutes(site, lp) {
+				api.Methods(r.Methods()...).Path(r.Pattern).Handler(lpAudited.handler(name, r))
+			}
+		}
+	}
+}
+
+// globalSiteRoutes are site routes not bound to a particular site
+var globalSiteRoutes = []string{
+	"sessions", "reimbursement", "reimbursementkey", "updatesession", "deletesession",
+	"gridsessions", "energyhistory", "telemetry2", "devicecolors",
+}
+
+// siteRoutes returns the site api routes
+func siteRoutes(site site.API) map[string]route {
 	smartCostLimit := func(lp loadpoint.API, limit *float64) {
 		lp.SetSmartCostLimit(limit)
 	}
@@ -174,7 +280,7 @@ func (s *HTTPd) RegisterSiteHandlers(site site.API) {
 		lp.SetSmartFeedInPriorityLimit(limit)
 	}
 
-	routes := map[string]route{
+	return map[string]route{
 		"buffersoc":               {"POST", "/buffersoc/{value:[0-9.]+}", floatHandler(site.SetBufferSoc, site.GetBufferSoc)},
 		"bufferstartsoc":          {"POST", "/bufferstartsoc/{value:[0-9.]+}", floatHandler(site.SetBufferStartSoc, site.GetBufferStartSoc)},
 		"batterydischargecontrol": {"POST", "/batterydischargecontrol/{value:[01truefalse]+}", boolHandler(site.SetBatteryDischargeControl, site.GetBatteryDischargeControl)},
@@ -193,6 +299,8 @@ func (s *HTTPd) RegisterSiteHandlers(site site.API) {
 		"smartfeedindelete":       {"DELETE", "/smartfeedinprioritylimit", updateSmartCostLimit(site, smartFeedInPriorityLimit)},
 		"tariff":                  {"GET", "/tariff/{tariff:[a-z]+}", tariffHandler(site)},
 		"sessions":                {"GET", "/sessions", sessionHandler},
+		"reimbursement":           {"GET", "/sessions/reimbursement", reimbursementHandler},
+		"reimbursementkey":        {"GET", "/sessions/reimbursement/publickey", reimbursementKeyHandler},
 		"updatesession":           {"PUT", "/session/{id:[0-9]+}", updateSessionHandler},
 		"deletesession":           {"DELETE", "/session/{id:[0-9]+}", deleteSessionHandler},
 		"gridsessions":            {"GET", "/gridsessions", gridSessionsHandler},
@@ -203,13 +311,11 @@ func (s *HTTPd) RegisterSiteHandlers(site site.API) {
 
 		"optimizerchargingstrategy": {"POST", "/optimizerchargingstrategy/{value:[a-z_]+}", stringHandler(site.SetOptimizerChargingStrategy, site.GetOptimizerChargingStrategy)},
 	}
+}
 
-	for _, r := range routes {
-		api.Methods(r.Methods()...).Path(r.Pattern).Handler(r.HandlerFunc)
-	}
-
-	// vehicle api
-	vehicles := map[string]route{
+// vehicleRoutes returns the vehicle api routes
+func vehicleRoutes(site site.API) map[string]route {
+	return map[string]route{
 		"mode":           {"POST", "/vehicles/{name:[a-zA-Z0-9_.:-]+}/mode/{value:[a-z]+}", vehicleModeHandler(site)},
 		"modeDelete":     {"DELETE", "/vehicles/{name:[a-zA-Z0-9_.:-]+}/mode", vehicleModeHandler(site)},
 		"minsoc":         {"POST", "/vehicles/{name:[a-zA-Z0-9_.:-]+}/minsoc/{value:[0-9]+}", minSocHandler(site)},
@@ -218,49 +324,69 @@ func (s *HTTPd) RegisterSiteHandlers(site site.API) {
 		"plan2":          {"DELETE", "/vehicles/{name:[a-zA-Z0-9_.:-]+}/plan/soc", planSocRemoveHandler(site)},
 		"repeatingPlans": {"POST", "/vehicles/{name:[a-zA-Z0-9_.:-]+}/plan/repeating", addRepeatingPlansHandler(site)},
 		"planStrategy":   {"POST", "/vehicles/{name:[a-zA-Z0-9_.:-]+}/plan/strategy", updatePlanStrategyHandler(site)},
+		"prediction":     {"POST", "/vehicles/{name:[a-zA-Z0-9_.:-]+}/plan/prediction", vehicleSettingHandler(site, vehicle.API.SetDeparturePrediction)},
+		"prediction2":    {"DELETE", "/vehicles/{name:[a-zA-Z0-9_.:-]+}/plan/prediction", vehicleSettingHandler(site, vehicle.API.SetDeparturePrediction)},
 	}
+}
 
-	for _, r := range vehicles {
-		api.Methods(r.Methods()...).Path(r.Pattern).Handler(r.HandlerFunc)
+// vehicleAuthRoutes returns the vehicle api routes requiring authentication
+func vehicleAuthRoutes(site site.API) map[string]route {
+	return map[string]route{
+		"tripCalendar":  {"POST", "/vehicles/{name:[a-zA-Z0-9_.:-]+}/plan/calendar", vehicleSettingHandler(site, vehicle.API.SetTripCalendar)},
+		"tripCalendar2": {"DELETE", "/vehicles/{name:[a-zA-Z0-9_.:-]+}/plan/calendar", vehicleSettingHandler(site, vehicle.API.SetTripCalendar)},
 	}
+}
 
-	// loadpoint api
-	// TODO any loadpoint
-	for id, lp := range site.Loadpoints() {
-		api := api.PathPrefix(fmt.Sprintf("/loadpoints/%d", id+1)).Subrouter()
+// loadpointRoutes returns the loadpoint api routes
+func loadpointRoutes(site site.API, lp loadpoint.API) map[string]route {
+	return map[string]route{
+		"mode":                      {"POST", "/mode/{value:[a-z]+}", handler(eapi.ChargeModeString, pass(lp.SetMode), lp.GetMode)},
+		"limitsoc":                  {"POST", "/limitsoc/{value:[0-9]+}", intHandler(pass(lp.SetLimitSoc), lp.GetLimitSoc)},
+		"mintemp":                   {"POST", "/mintemp/{value:[0-9]+}", intHandler(pass(lp.SetMinSoc), lp.GetMinSoc)},
+		"limitenergy":               {"POST", "/limitenergy/{value:[0-9.]+}", floatHandler(pass(lp.SetLimitEnergy), lp.GetLimitEnergy)},
+		"mincurrent":                {"POST", "/mincurrent/{value:[0-9.]+}", floatHandler(lp.SetMinCurrent, lp.GetMinCurrent)},
+		"maxcurrent":                {"POST", "/maxcurrent/{value:[0-9.]+}", floatHandler(lp.SetMaxCurrent, lp.GetMaxCurrent)},
+		"phases":                    {"POST", "/phases/{value:[0-9]+}", intHandler(lp.SetPhasesConfigured, lp.GetPhasesConfigured)},
+		"plan":                      {"GET", "/plan", planHandler(lp)},
+		"staticPlanPreview":         {"GET", "/plan/static/preview/{type:(?:soc|energy)}/{value:[0-9.]+}/{time:[0-9TZ:.+-]+}", staticPlanPreviewHandler(lp)},
+		"planenergy":                {"POST", "/plan/energy/{value:[0-9.]+}/{time:[0-9TZ:.+-]+}", planEnergyHandler(lp)},
+		"planenergy2":               {"DELETE", "/plan/energy", planRemoveHandler(lp)},
+		"planStrategy":              {"POST", "/plan/strategy", planStrategyHandler(lp)},
+		"schedules":                 {"POST", "/schedules", schedulesHandler(lp)},
+		"vehicle":                   {"POST", "/vehicle/{name:[a-zA-Z0-9_.:-]+}", vehicleSelectHandler(site, lp)},
+		"vehicle2":                  {"DELETE", "/vehicle", vehicleRemoveHandler(lp)},
+		"vehicleDetect":             {"PATCH", "/vehicle", vehicleDetectHandler(lp)},
+		"enableThreshold":           {"POST", "/enable/threshold/{value:-?[0-9.]+}", floatHandler(pass(lp.SetEnableThreshold), lp.GetEnableThreshold)},
+		"enableDelay":               {"POST", "/enable/delay/{value:[0-9]+}", durationHandler(pass(lp.SetEnableDelay), lp.GetEnableDelay)},
+		"disableThreshold":          {"POST", "/disable/threshold/{value:-?[0-9.]+}", floatHandler(pass(lp.SetDisableThreshold), lp.GetDisableThreshold)},
+		"disableDelay":              {"POST", "/disable/delay/{value:[0-9]+}", durationHandler(pass(lp.SetDisableDelay), lp.GetDisableDelay)},
+		"smartCost":                 {"POST", "/smartcostlimit/{value:-?[0-9.]+}", floatPtrHandler(pass(lp.SetSmartCostLimit), lp.GetSmartCostLimit)},
+		"smartCostDelete":           {"DELETE", "/smartcostlimit", floatPtrHandler(pass(lp.SetSmartCostLimit), lp.GetSmartCostLimit)},
+		"smartFeedInPriority":       {"POST", "/smartfeedinprioritylimit/{value:-?[0-9.]+}", floatPtrHandler(pass(lp.SetSmartFeedInPriorityLimit), lp.GetSmartFeedInPriorityLimit)},
+		"smartFeedInPriorityDelete": {"DELETE", "/smartfeedinprioritylimit", floatPtrHandler(pass(lp.SetSmartFeedInPriorityLimit), lp.GetSmartFeedInPriorityLimit)},
+		"priority":                  {"POST", "/priority/{value:[0-9]+}", intHandler(pass(lp.SetPriority), lp.GetPriority)},
+		"batteryBoost":              {"POST", "/batteryboost/{value:[01truefalse]+}", boolHandler(lp.SetBatteryBoost, func() bool { return lp.GetBatteryBoost() > 0 })},
+		"batteryBoostLimit":         {"POST", "/batteryboostlimit/{value:[0-9]+}", intHandler(pass(lp.SetBatteryBoostLimit), lp.GetBatteryBoostLimit)},
+	}
+}
 
-		routes := map[string]route{
-			"mode":                      {"POST", "/mode/{value:[a-z]+}", handler(eapi.ChargeModeString, pass(lp.SetMode), lp.GetMode)},
-			"limitsoc":                  {"POST", "/limitsoc/{value:[0-9]+}", intHandler(pass(lp.SetLimitSoc), lp.GetLimitSoc)},
-			"mintemp":                   {"POST", "/mintemp/{value:[0-9]+}", intHandler(pass(lp.SetMinSoc), lp.GetMinSoc)},
-			"limitenergy":               {"POST", "/limitenergy/{value:[0-9.]+}", floatHandler(pass(lp.SetLimitEnergy), lp.GetLimitEnergy)},
-			"mincurrent":                {"POST", "/mincurrent/{value:[0-9.]+}", floatHandler(lp.SetMinCurrent, lp.GetMinCurrent)},
-			"maxcurrent":                {"POST", "/maxcurrent/{value:[0-9.]+}", floatHandler(lp.SetMaxCurrent, lp.GetMaxCurrent)},
-			"phases":                    {"POST", "/phases/{value:[0-9]+}", intHandler(lp.SetPhasesConfigured, lp.GetPhasesConfigured)},
-			"plan":                      {"GET", "/plan", planHandler(lp)},
-			"staticPlanPreview":         {"GET", "/plan/static/preview/{type:(?:soc|energy)}/{value:[0-9.]+}/{time:[0-9TZ:.+-]+}", staticPlanPreviewHandler(lp)},
-			"planenergy":                {"POST", "/plan/energy/{value:[0-9.]+}/{time:[0-9TZ:.+-]+}", planEnergyHandler(lp)},
-			"planenergy2":               {"DELETE", "/plan/energy", planRemoveHandler(lp)},
-			"planStrategy":              {"POST", "/plan/strategy", planStrategyHandler(lp)},
-			"vehicle":                   {"POST", "/vehicle/{name:[a-zA-Z0-9_.:-]+}", vehicleSelectHandler(site, lp)},
-			"vehicle2":                  {"DELETE", "/vehicle", vehicleRemoveHandler(lp)},
-			"vehicleDetect":             {"PATCH", "/vehicle", vehicleDetectHandler(lp)},
-			"enableThreshold":           {"POST", "/enable/threshold/{value:-?[0-9.]+}", floatHandler(pass(lp.SetEnableThreshold), lp.GetEnableThreshold)},
-			"enableDelay":               {"POST", "/enable/delay/{value:[0-9]+}", durationHandler(pass(lp.SetEnableDelay), lp.GetEnableDelay)},
-			"disableThreshold":          {"POST", "/disable/threshold/{value:-?[0-9.]+}", floatHandler(pass(lp.SetDisableThreshold), lp.GetDisableThreshold)},
-			"disableDelay":              {"POST", "/disable/delay/{value:[0-9]+}", durationHandler(pass(lp.SetDisableDelay), lp.GetDisableDelay)},
-			"smartCost":                 {"POST", "/smartcostlimit/{value:-?[0-9.]+}", floatPtrHandler(pass(lp.SetSmartCostLimit), lp.GetSmartCostLimit)},
-			"smartCostDelete":           {"DELETE", "/smartcostlimit", floatPtrHandler(pass(lp.SetSmartCostLimit), lp.GetSmartCostLimit)},
-			"smartFeedInPriority":       {"POST", "/smartfeedinprioritylimit/{value:-?[0-9.]+}", floatPtrHandler(pass(lp.SetSmartFeedInPriorityLimit), lp.GetSmartFeedInPriorityLimit)},
-			"smartFeedInPriorityDelete": {"DELETE", "/smartfeedinprioritylimit", floatPtrHandler(pass(lp.SetSmartFeedInPriorityLimit), lp.GetSmartFeedInPriorityLimit)},
-			"priority":                  {"POST", "/priority/{value:[0-9]+}", intHandler(pass(lp.SetPriority), lp.GetPriority)},
-			"batteryBoost":              {"POST", "/batteryboost/{value:[01truefalse]+}", boolHandler(lp.SetBatteryBoost, func() bool { return lp.GetBatteryBoost() > 0 })},
-			"batteryBoostLimit":         {"POST", "/batteryboostlimit/{value:[0-9]+}", intHandler(pass(lp.SetBatteryBoostLimit), lp.GetBatteryBoostLimit)},
-		}
+// RegisterAutomationHandlers connects the http handlers to the automation runner
+func (s *HTTPd) RegisterAutomationHandlers(runner *automation.Runner, auth auth.Auth) {
+	router := s.Server.Handler.(*mux.Router)
 
-		for _, r := range routes {
-			api.Methods(r.Methods()...).Path(r.Pattern).Handler(r.HandlerFunc)
-		}
+	// api
+	api := router.PathPrefix("/api/config/automation").Subrouter()
+	api.Use(jsonHandler)
+	api.Use(ensureAuthHandler(auth))
+
+	routes := map[string]route{
+		"automation":       {"GET", "", automationHandler(runner)},
+		"updateautomation": {"POST", "", updateAutomationHandler(runner, auth)},
+		"enableautomation": {"POST", "/{name:[a-zA-Z0-9_-]+}/{value:[01truefalse]+}", enableAutomationHandler(runner)},
+	}
+
+	for _, r := range routes {
+		api.Methods(r.Methods()...).Path(r.Pattern).Handler(r.HandlerFunc)
 	}
 }
 
@@ -275,6 +401,7 @@ func (s *HTTPd) RegisterSystemHandler(site *core.Site, pub publisher, cache *uti
 	api.Use(handlers.CORS(
 		handlers.AllowedHeaders([]string{"Content-Type"}),
 	))
+	api.Use(auditSourceHandler(auth))
 
 	if site == nil {
 		// If site is nil, create a new empty site. Settings will be loaded during this process and
@@ -289,7 +416,7 @@ func (s *HTTPd) RegisterSystemHandler(site *core.Site, pub publisher, cache *uti
 
 	{ // /api
 		routes := map[string]route{
-			"state": {"GET", "/state", stateHandler(cache)},
+			"state": {"GET", "/state", stateHandler(cache, "")},
 		}
 
 		for _, r := range routes {
@@ -386,11 +513,16 @@ func (s *HTTPd) RegisterSystemHandler(site *core.Site, pub publisher, cache *uti
 		api.PathPrefix("/service").Handler(http.StripPrefix("/api/config/service", service.Handler()))
 
 		// site
-		for _, r := range map[string]route{
+		siteAudited := newAuditedRoutes(func(src audit.Source) map[string]route {
+			return map[string]route{
+				"updatesite": {"PUT", "/site", updateSiteHandler(audit.Site(site, src))},
+			}
+		})
+		for name, r := range map[string]route{
 			"site":       {"GET", "/site", siteHandler(site)},
 			"updatesite": {"PUT", "/site", updateSiteHandler(site)},
 		} {
-			api.Methods(r.Methods()...).Path(r.Pattern).Handler(r.HandlerFunc)
+			api.Methods(r.Methods()...).Path(r.Pattern).Handler(siteAudited.handler(name, r))
 		}
 
 		// tariffs
@@ -402,6 +534,25 @@ func (s *HTTPd) RegisterSystemHandler(site *core.Site, pub publisher, cache *uti
 			api.Methods(r.Methods()...).Path(r.Pattern).Handler(r.HandlerFunc)
 		}
 
+		// revision history
+		history.Register(history.Device, restoreDevice(site))
+
+		for _, r := range map[string]route{
+			"history":         {"GET", "/history", historyHandler},
+			"historydiff":     {"GET", "/history/diff", historyDiffHandler},
+			"historyrollback": {"POST", "/history/rollback", historyRollbackHandler},
+		} {
+			api.Methods(r.Methods()...).Path(r.Pattern).Handler(r.HandlerFunc)
+		}
+
+		// configuration document
+		for _, r := range map[string]route{
+			"export": {"GET", "/export", configExportHandler},
+			"apply":  {"POST", "/apply", configApplyHandler},
+		} {
+			api.Methods(r.Methods()...).Path(r.Pattern).Handler(r.HandlerFunc)
+		}
+
 		// loadpoints
 		for _, r := range map[string]route{
 			"loadpoints":      {"GET", "/loadpoints", loadpointsConfigHandler()},
@@ -414,6 +565,13 @@ func (s *HTTPd) RegisterSystemHandler(site *core.Site, pub publisher, cache *uti
 		}
 	}
 
+	{ // api/audit
+		api := api.PathPrefix("/audit").Subrouter()
+		api.Use(ensureAuthHandler(auth))
+
+		api.Methods("GET").Path("").Handler(http.HandlerFunc(auditHandler))
+	}
+
 	{ // api/system
 		api := api.PathPrefix("/system").Subrouter()
 		api.Use(ensureAuthHandler(auth))
@@ -422,6 +580,7 @@ func (s *HTTPd) RegisterSystemHandler(site *core.Site, pub publisher, cache *uti
 			"log":        {"GET", "/log", logHandler},
 			"logareas":   {"GET", "/log/areas", logAreasHandler},
 			"clearcache": {"DELETE", "/cache", clearCacheHandler},
+			"webhooks":   {"GET", "/webhooks", webhookDeliveriesHandler},
 			"shutdown": {"POST", "/shutdown", func(w http.ResponseWriter, r *http.Request) {
 				shutdown()
 				w.WriteHeader(http.StatusNoContent)
@@ -0,0 +1,171 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/core/audit"
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/util/auth"
+	"github.com/evcc-io/evcc/util/locale"
+	"github.com/gorilla/mux"
+	"golang.org/x/text/language"
+)
+
+// requestIdentity returns the authenticated identity of a request.
+// Api keys are identified by a digest since only their hash is stored.
+func requestIdentity(authObject auth.Auth, r *http.Request) string {
+	if key := apiKeyFromRequest(r); key != "" && authObject.ValidateApiKey(key) {
+		sum := sha256.Sum256([]byte(key))
+		return "apikey " + hex.EncodeToString(sum[:4])
+	}
+	if jwt := jwtFromCookie(r); jwt != "" && authObject.ValidateJwtToken(jwt) {
+		return "admin"
+	}
+	return "anonymous"
+}
+
+// auditSourceHandler attaches the audit source to modifying requests.
+// Credentials are only validated for modifying requests as validation is expensive.
+func auditSourceHandler(authObject auth.Auth) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := audit.SourceFromContext(r.Context()); !ok && r.Method != http.MethodGet {
+				src := audit.Source{Kind: audit.HTTP, Actor: requestIdentity(authObject, r)}
+				r = r.WithContext(audit.WithSource(r.Context(), src))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// requestSource returns the audit source of an api request
+func requestSource(r *http.Request) audit.Source {
+	if src, ok := audit.SourceFromContext(r.Context()); ok {
+		return src
+	}
+	return audit.Source{Kind: audit.HTTP, Actor: "anonymous"}
+}
+
+// auditedRoutes serves routes using handlers bound to the audit source of the request.
+// Handlers are created once per source.
+type auditedRoutes struct {
+	mu     sync.Mutex
+	build  func(audit.Source) map[string]route
+	routes map[audit.Source]map[string]route
+}
+
+func newAuditedRoutes(build func(audit.Source) map[string]route) *auditedRoutes {
+	return &auditedRoutes{
+		build:  build,
+		routes: make(map[audit.Source]map[string]route),
+	}
+}
+
+// handler returns the handler of the named route, serving modifying requests with the audited handler
+func (a *auditedRoutes) handler(name string, r route) http.HandlerFunc {
+	if r.Method == http.MethodGet {
+		return r.HandlerFunc
+	}
+
+	return func(w http.ResponseWriter, req *http.Request) {
+		src := requestSource(req)
+
+		a.mu.Lock()
+		routes, ok := a.routes[src]
+		if !ok {
+			routes = a.build(src)
+			a.routes[src] = routes
+		}
+		a.mu.Unlock()
+
+		routes[name].HandlerFunc(w, req)
+	}
+}
+
+// auditHandler returns a page of audit log entries
+func auditHandler(w http.ResponseWriter, r *http.Request) {
+	if db.Instance == nil {
+		jsonError(w, http.StatusBadRequest, errors.New("database offline"))
+		return
+	}
+
+	q := r.URL.Query()
+
+	query := audit.Query{
+		Source: audit.Kind(q.Get("source")),
+		Target: q.Get("target"),
+		Key:    q.Get("key"),
+	}
+
+	for key, ts := range map[string]*time.Time{"from": &query.From, "to": &query.To} {
+		if s := q.Get(key); s != "" {
+			t, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				jsonError(w, http.StatusBadRequest, err)
+				return
+			}
+			*ts = t
+		}
+	}
+
+	format := q.Get("format")
+	export := format == "csv" || format == "xlsx"
+
+	// exports contain all matching entries
+	page, limit := 1, 100
+	if !export {
+		for key, val := range map[string]*int{"page": &page, "limit": &limit} {
+			if s := q.Get(key); s != "" {
+				i, err := strconv.Atoi(s)
+				if err != nil || i < 1 {
+					jsonError(w, http.StatusBadRequest, errors.New("invalid "+key))
+					return
+				}
+				*val = i
+			}
+		}
+
+		query.Offset = (page - 1) * limit
+		query.Limit = limit
+	}
+
+	res, total, err := audit.Find(query)
+	if err != nil {
+		jsonError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if export {
+		lang := q.Get("lang")
+		if lang == "" {
+			// get request language
+			lang = r.Header.Get("Accept-Language")
+			if tags, _, err := language.ParseAcceptLanguage(lang); err == nil && len(tags) > 0 {
+				lang = tags[0].String()
+			}
+		}
+
+		ctx := context.WithValue(context.Background(), locale.Locale, lang)
+		exportResult(ctx, w, format, &res, "audit")
+		return
+	}
+
+	jsonWrite(w, struct {
+		Entries audit.Entries `json:"entries"`
+		Total   int64         `json:"total"`
+		Page    int           `json:"page"`
+		Limit   int           `json:"limit"`
+	}{
+		Entries: res,
+		Total:   total,
+		Page:    page,
+		Limit:   limit,
+	})
+}
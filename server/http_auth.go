@@ -244,7 +244,15 @@ func regenerateApiKeyHandler(authObject auth.Auth) http.HandlerFunc {
 
 // requireCriticalConfigAuth guards script-plugin configs: API key passes; session users must supply the admin password.
 func requireCriticalConfigAuth(w http.ResponseWriter, r *http.Request, authObject auth.Auth, req configReq) bool {
-	if authObject.GetAuthMode() == auth.Disabled || !configHasCriticalPlugin(req) {
+	if !configHasCriticalPlugin(req) {
+		return true
+	}
+	return requireAdminAuth(w, r, authObject)
+}
+
+// requireAdminAuth guards critical changes: API key passes; session users must supply the admin password.
+func requireAdminAuth(w http.ResponseWriter, r *http.Request, authObject auth.Auth) bool {
+	if authObject.GetAuthMode() == auth.Disabled {
 		return true
 	}
 	if key := apiKeyFromRequest(r); key != "" && authObject.ValidateApiKey(key) {
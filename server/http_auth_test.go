@@ -75,3 +75,23 @@ func TestRequireCriticalConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestRequestIdentity(t *testing.T) {
+	a := fakeAuth{mode: auth.Enabled, apiKey: "evcc_token"}
+
+	req := func(header string) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/", nil)
+		if header != "" {
+			r.Header.Set("Authorization", "Bearer "+header)
+		}
+		return r
+	}
+
+	assert.Equal(t, "anonymous", requestIdentity(a, req("")))
+	assert.Equal(t, "anonymous", requestIdentity(a, req("invalid")))
+	assert.Regexp(t, "^apikey [0-9a-f]{8}$", requestIdentity(a, req("evcc_token")))
+
+	r := req("")
+	r.AddCookie(&http.Cookie{Name: authCookieName, Value: "jwt"})
+	assert.Equal(t, "admin", requestIdentity(a, r))
+}
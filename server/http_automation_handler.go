@@ -0,0 +1,74 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/evcc-io/evcc/core/automation"
+	"github.com/evcc-io/evcc/core/keys"
+	"github.com/evcc-io/evcc/server/db/settings"
+	"github.com/evcc-io/evcc/util/auth"
+	"github.com/gorilla/mux"
+)
+
+// automationHandler returns the automation scripts and their status
+func automationHandler(runner *automation.Runner) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		jsonWrite(w, runner.Status())
+	}
+}
+
+// updateAutomationHandler replaces the automation scripts and applies them at runtime.
+// Scripts execute arbitrary code and require the admin password.
+func updateAutomationHandler(runner *automation.Runner, authObject auth.Auth) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !requireAdminAuth(w, r, authObject) {
+			return
+		}
+
+		var scripts []automation.Script
+		if err := json.NewDecoder(r.Body).Decode(&scripts); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := runner.Apply(scripts); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := settings.SetJson(keys.Automation, scripts); err != nil {
+			jsonError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		jsonWrite(w, runner.Status())
+	}
+}
+
+// enableAutomationHandler enables or disables an automation script
+func enableAutomationHandler(runner *automation.Runner) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+
+		enable, err := strconv.ParseBool(vars["value"])
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		scripts, err := runner.Enable(vars["name"], enable)
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := settings.SetJson(keys.Automation, scripts); err != nil {
+			jsonError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		jsonWrite(w, runner.Status())
+	}
+}
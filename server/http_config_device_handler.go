@@ -24,6 +24,7 @@ import (
 	"github.com/evcc-io/evcc/meter"
 	"github.com/evcc-io/evcc/server/db/settings"
 	"github.com/evcc-io/evcc/tariff"
+	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/auth"
 	"github.com/evcc-io/evcc/util/config"
 	"github.com/evcc-io/evcc/util/templates"
@@ -301,25 +302,43 @@ func deviceStatusHandler(w http.ResponseWriter, r *http.Request) {
 	jsonWrite(w, testInstance(ctx, instance))
 }
 
-func newDevice[T any](ctx context.Context, class templates.Class, req configReq, newFromConf newFromConfFunc[T], h config.Handler[T], force bool) (*config.Config, error) {
+func newDevice[T any](ctx context.Context, class templates.Class, req configReq, newFromConf newFromConfFunc[T], h config.Handler[T], force bool, opt ...func(*config.Config)) (*config.Config, error) {
 	typ, other, err := config.CustomDevice(req.Type, req.Other)
 	if err != nil && !force {
 		return nil, err
 	}
 
-	instance, err := newFromConf(ctx, typ, other)
-	if err != nil && !force {
-		return nil, err
+	ictx, icancel := context.WithCancel(ctx)
+
+	instance, err := newFromConf(ictx, typ, other)
+	if err != nil {
+		icancel()
+		if !force {
+			return nil, err
+		}
+	} else {
+		ownInstance(class, instance, icancel)
 	}
 
-	conf, err := config.AddConfig(class, req.Serialise(), config.WithProperties(req.Properties))
+	conf, err := config.AddConfig(class, req.Serialise(), append(opt, config.WithProperties(req.Properties))...)
 	if err != nil {
+		icancel()
 		return nil, err
 	}
 
 	return &conf, h.Add(config.NewConfigurableDevice(&conf, instance))
 }
 
+// ownInstance ties the instance's context to its lifetime in the device handler.
+// Vehicles and hems may still be in use by loadpoints or the site after being
+// replaced and are not shut down.
+func ownInstance(class templates.Class, instance any, cancel context.CancelFunc) {
+	if class == templates.Vehicle || class == templates.Hems {
+		return
+	}
+	config.Own(instance, cancel)
+}
+
 // newHemsFactory adapts hems.NewFromConfig to the generic newFromConfFunc signature.
 func newHemsFactory(site site.API) newFromConfFunc[hemsapi.API] {
 	return func(ctx context.Context, typ string, other map[string]any) (hemsapi.API, error) {
@@ -359,34 +378,35 @@ func newDeviceHandler(site site.API, authObject auth.Auth) http.HandlerFunc {
 
 		var conf *config.Config
 		ctx, cancel, done := startDeviceTimeout()
+		author := config.WithAuthor(requestSource(r).Actor)
 
 		force := r.URL.Query().Get("force") == "true"
 
 		switch class {
 		case templates.Charger:
-			conf, err = newDevice(ctx, class, req, charger.NewFromConfig, config.Chargers(), force)
+			conf, err = newDevice(ctx, class, req, charger.NewFromConfig, config.Chargers(), force, author)
 
 		case templates.Meter:
-			conf, err = newDevice(ctx, class, req, meter.NewFromConfig, config.Meters(), force)
+			conf, err = newDevice(ctx, class, req, meter.NewFromConfig, config.Meters(), force, author)
 
 		case templates.Vehicle:
-			conf, err = newDevice(ctx, class, req, vehicle.NewFromConfig, config.Vehicles(), force)
+			conf, err = newDevice(ctx, class, req, vehicle.NewFromConfig, config.Vehicles(), force, author)
 
 		case templates.Circuit:
-			conf, err = newDevice(ctx, class, req, circuit.NewFromConfig, config.Circuits(), force)
+			conf, err = newDevice(ctx, class, req, circuit.NewFromConfig, config.Circuits(), force, author)
 
 		case templates.Hems:
 			if existing, _ := config.ConfigurationByClass(templates.Hems); existing != nil {
 				err = errors.New("hems already configured")
 			} else {
-				conf, err = newDevice(ctx, class, req, newHemsFactory(site), config.Hems(), force)
+				conf, err = newDevice(ctx, class, req, newHemsFactory(site), config.Hems(), force, author)
 			}
 
 		case templates.Tariff:
-			conf, err = newDevice(ctx, class, req, tariff.NewFromConfig, config.Tariffs(), force)
+			conf, err = newDevice(ctx, class, req, tariff.NewFromConfig, config.Tariffs(), force, author)
 
 		case templates.Messenger:
-			conf, err = newDevice(ctx, class, req, messenger.NewFromConfig, config.Messengers(), force)
+			conf, err = newDevice(ctx, class, req, messenger.NewFromConfig, config.Messengers(), force, author)
 		}
 
 		if err != nil {
@@ -398,7 +418,9 @@ func newDeviceHandler(site site.API, authObject auth.Auth) http.HandlerFunc {
 		// prevent context from being cancelled
 		close(done)
 
-		setConfigDirty()
+		if requiresRestart(class) || force {
+			setConfigDirty()
+		}
 
 		if class == templates.Hems {
 			site.Publish(keys.Hems, HemsStatus(true))
@@ -416,9 +438,16 @@ func newDeviceHandler(site site.API, authObject auth.Auth) http.HandlerFunc {
 	}
 }
 
-func updateDevice[T any](ctx context.Context, id int, class templates.Class, req configReq, newFromConf newFromConfFunc[T], h config.Handler[T], force bool) error {
-	dev, instance, merged, err := deviceInstanceFromMergedConfig(ctx, id, class, req, newFromConf, h)
+func updateDevice[T any](ctx context.Context, id int, class templates.Class, req configReq, newFromConf newFromConfFunc[T], h config.Handler[T], force bool, opt ...func(*config.Config)) error {
+	// notify about pushed values of the updated instance
+	ctx = util.WithNotifier(ctx, config.NameForID(id))
+
+	ictx, icancel := context.WithCancel(ctx)
+
+	dev, instance, merged, err := deviceInstanceFromMergedConfig(ictx, id, class, req, newFromConf, h)
 	if err != nil {
+		icancel()
+
 		// allow force-updating if merged config exists
 		if !force || merged == nil {
 			return err
@@ -427,10 +456,26 @@ func updateDevice[T any](ctx context.Context, id int, class templates.Class, req
 
 	configurable, ok := dev.(config.ConfigurableDevice[T])
 	if !ok {
+		icancel()
 		return errors.New("not configurable")
 	}
 
-	return configurable.Update(merged, instance, config.WithProperties(req.Properties))
+	opt = append(opt, config.WithProperties(req.Properties))
+
+	// force-update without instance: store the config only and keep the previous instance until restart
+	if err != nil {
+		return configurable.Update(merged, configurable.Instance(), opt...)
+	}
+
+	ownInstance(class, instance, icancel)
+
+	// subscribers swap the instance in place
+	return h.Update(dev.Config().Name, merged, instance, opt...)
+}
+
+// requiresRestart checks if device changes of the class are not applied at runtime
+func requiresRestart(class templates.Class) bool {
+	return class == templates.Hems
 }
 
 // updateDeviceHandler updates database device's configuration by class
@@ -461,33 +506,36 @@ func updateDeviceHandler(site site.API, authObject auth.Auth) http.HandlerFunc {
 		}
 
 		ctx, cancel, done := startDeviceTimeout()
+		author := config.WithAuthor(requestSource(r).Actor)
 
 		force := r.URL.Query().Get("force") == "true"
 
 		switch class {
 		case templates.Charger:
-			err = updateDevice(ctx, id, class, req, charger.NewFromConfig, config.Chargers(), force)
+			err = updateDevice(ctx, id, class, req, charger.NewFromConfig, config.Chargers(), force, author)
 
 		case templates.Meter:
-			err = updateDevice(ctx, id, class, req, meter.NewFromConfig, config.Meters(), force)
+			err = updateDevice(ctx, id, class, req, meter.NewFromConfig, config.Meters(), force, author)
 
 		case templates.Vehicle:
-			err = updateDevice(ctx, id, class, req, vehicle.NewFromConfig, config.Vehicles(), force)
+			err = updateDevice(ctx, id, class, req, vehicle.NewFromConfig, config.Vehicles(), force, author)
 
 		case templates.Circuit:
-			err = updateDevice(ctx, id, class, req, circuit.NewFromConfig, config.Circuits(), force)
+			err = updateDevice(ctx, id, class, req, circuit.NewFromConfig, config.Circuits(), force, author)
 
 		case templates.Hems:
-			err = updateDevice(ctx, id, class, req, newHemsFactory(site), config.Hems(), force)
+			err = updateDevice(ctx, id, class, req, newHemsFactory(site), config.Hems(), force, author)
 
 		case templates.Tariff:
-			err = updateDevice(ctx, id, class, req, tariff.NewFromConfig, config.Tariffs(), force)
+			err = updateDevice(ctx, id, class, req, tariff.NewFromConfig, config.Tariffs(), force, author)
 
 		case templates.Messenger:
-			err = updateDevice(ctx, id, class, req, messenger.NewFromConfig, config.Messengers(), force)
+			err = updateDevice(ctx, id, class, req, messenger.NewFromConfig, config.Messengers(), force, author)
 		}
 
-		setConfigDirty()
+		if requiresRestart(class) || force {
+			setConfigDirty()
+		}
 
 		if err != nil {
 			cancel()
@@ -522,7 +570,7 @@ func configurableDevice[T any](name string, h config.Handler[T]) (config.Configu
 	return configurable, nil
 }
 
-func deleteDevice[T any](id int, h config.Handler[T]) error {
+func deleteDevice[T any](id int, h config.Handler[T], opt ...func(*config.Config)) error {
 	name := config.NameForID(id)
 
 	configurable, err := configurableDevice(name, h)
@@ -530,7 +578,7 @@ func deleteDevice[T any](id int, h config.Handler[T]) error {
 		return err
 	}
 
-	if err := configurable.Delete(); err != nil {
+	if err := configurable.Delete(opt...); err != nil {
 		return err
 	}
 
@@ -591,87 +639,94 @@ func deleteDeviceHandler(site site.API) func(w http.ResponseWriter, r *http.Requ
 			return
 		}
 
-		switch class {
-		case templates.Charger:
-			err = deleteDevice(id, config.Chargers())
+		author := config.WithAuthor(requestSource(r).Actor)
+
+		// attribute cleaned-up refs to the requester
+		settings.Attribute(requestSource(r).Actor, func() {
+			switch class {
+			case templates.Charger:
+				err = deleteDevice(id, config.Chargers(), author)
 
-			// cleanup references
-			for _, dev := range h.Devices() {
-				lp := dev.Instance()
-				if lp.GetChargerRef() == config.NameForID(id) {
-					lp.SetChargerRef("")
+				// cleanup references
+				for _, dev := range h.Devices() {
+					lp := dev.Instance()
+					if lp.GetChargerRef() == config.NameForID(id) {
+						lp.SetChargerRef("")
+					}
 				}
-			}
 
-		case templates.Meter:
-			err = deleteDevice(id, config.Meters())
+			case templates.Meter:
+				err = deleteDevice(id, config.Meters(), author)
 
-			// cleanup references
-			name := config.NameForID(id)
+				// cleanup references
+				name := config.NameForID(id)
 
-			if site.GetGridMeterRef() == name {
-				site.SetGridMeterRef("")
-			}
+				if site.GetGridMeterRef() == name {
+					site.SetGridMeterRef("")
+				}
 
-			for _, fun := range []struct {
-				get func() []string
-				set func([]string)
-			}{
-				{site.GetPVMeterRefs, site.SetPVMeterRefs},
-				{site.GetBatteryMeterRefs, site.SetBatteryMeterRefs},
-				{site.GetAuxMeterRefs, site.SetAuxMeterRefs},
-				{site.GetExtMeterRefs, site.SetExtMeterRefs},
-				{site.GetConsumerMeterRefs, site.SetConsumerMeterRefs},
-			} {
-				cleanupSiteMeterRef(name, fun.get, fun.set)
-			}
+				for _, fun := range []struct {
+					get func() []string
+					set func([]string)
+				}{
+					{site.GetPVMeterRefs, site.SetPVMeterRefs},
+					{site.GetBatteryMeterRefs, site.SetBatteryMeterRefs},
+					{site.GetAuxMeterRefs, site.SetAuxMeterRefs},
+					{site.GetExtMeterRefs, site.SetExtMeterRefs},
+					{site.GetConsumerMeterRefs, site.SetConsumerMeterRefs},
+				} {
+					cleanupSiteMeterRef(name, fun.get, fun.set)
+				}
 
-			for _, dev := range h.Devices() {
-				lp := dev.Instance()
-				if lp.GetMeterRef() == name {
-					lp.SetMeterRef("")
+				for _, dev := range h.Devices() {
+					lp := dev.Instance()
+					if lp.GetMeterRef() == name {
+						lp.SetMeterRef("")
+					}
 				}
-			}
 
-		case templates.Vehicle:
-			err = deleteDevice(id, config.Vehicles())
+			case templates.Vehicle:
+				err = deleteDevice(id, config.Vehicles(), author)
 
-			// cleanup references
-			for _, dev := range h.Devices() {
-				lp := dev.Instance()
-				if lp.GetDefaultVehicleRef() == config.NameForID(id) {
-					lp.SetDefaultVehicleRef("")
+				// cleanup references
+				for _, dev := range h.Devices() {
+					lp := dev.Instance()
+					if lp.GetDefaultVehicleRef() == config.NameForID(id) {
+						lp.SetDefaultVehicleRef("")
+					}
 				}
-			}
 
-		case templates.Circuit:
-			err = deleteDevice(id, config.Circuits())
+			case templates.Circuit:
+				err = deleteDevice(id, config.Circuits(), author)
 
-			// cleanup references
-			for _, dev := range h.Devices() {
-				lp := dev.Instance()
-				if lp.GetCircuitRef() == config.NameForID(id) {
-					lp.SetCircuitRef("")
+				// cleanup references
+				for _, dev := range h.Devices() {
+					lp := dev.Instance()
+					if lp.GetCircuitRef() == config.NameForID(id) {
+						lp.SetCircuitRef("")
+					}
 				}
-			}
 
-		case templates.Hems:
-			err = deleteDevice(id, config.Hems())
+			case templates.Hems:
+				err = deleteDevice(id, config.Hems(), author)
 
-		case templates.Tariff:
-			err = deleteDevice(id, config.Tariffs())
+			case templates.Tariff:
+				err = deleteDevice(id, config.Tariffs(), author)
+
+				// cleanup references
+				if err == nil {
+					cleanupTariffRef(config.NameForID(id))
+				}
 
-			// cleanup references
-			if err == nil {
-				cleanupTariffRef(config.NameForID(id))
+			case templates.Messenger:
+				err = deleteDevice(id, config.Messengers(), author)
 			}
+		})
 
-		case templates.Messenger:
-			err = deleteDevice(id, config.Messengers())
+		if requiresRestart(class) {
+			setConfigDirty()
 		}
 
-		setConfigDirty()
-
 		if err != nil {
 			jsonError(w, http.StatusBadRequest, err)
 			return
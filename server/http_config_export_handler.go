@@ -0,0 +1,57 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/evcc-io/evcc/server/configdoc"
+)
+
+// configExportHandler returns the database-managed configuration as yaml document
+func configExportHandler(w http.ResponseWriter, r *http.Request) {
+	doc, err := configdoc.Export()
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	b, err := configdoc.Marshal(doc)
+	if err != nil {
+		jsonError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/yaml")
+	w.Header().Set("Content-Disposition", `attachment; filename="evcc-config.yaml"`)
+	_, _ = w.Write(b)
+}
+
+// configApplyHandler reconciles the database-managed configuration to the posted yaml document
+func configApplyHandler(w http.ResponseWriter, r *http.Request) {
+	dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dryrun"))
+
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	doc, err := configdoc.Unmarshal(b)
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	changes, err := configdoc.Apply(doc, requestSource(r).Actor, dryRun)
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if !dryRun && len(changes) > 0 {
+		setConfigDirty()
+	}
+
+	jsonWrite(w, changes)
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"io"
 	"maps"
+	"net/http"
 	"reflect"
 	"slices"
 	"strings"
@@ -14,6 +15,7 @@ import (
 
 	"dario.cat/mergo"
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/server/db/settings"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/config"
 	"github.com/evcc-io/evcc/util/templates"
@@ -99,6 +101,15 @@ func setConfigDirty() {
 	dirty = true
 }
 
+// persistSettings applies fun and saves the changed settings, recording them as revisions by the request's author
+func persistSettings(r *http.Request, fun func()) {
+	settings.Attribute(requestSource(r).Actor, fun)
+
+	if err := settings.Persist(); err != nil {
+		log.ERROR.Println("cannot save settings:", err)
+	}
+}
+
 func templateForConfig(class templates.Class, conf map[string]any) (templates.Template, error) {
 	typ, ok := conf[typeTemplate].(string)
 	if !ok {
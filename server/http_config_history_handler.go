@@ -0,0 +1,216 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/evcc-io/evcc/charger"
+	"github.com/evcc-io/evcc/core/circuit"
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/messenger"
+	"github.com/evcc-io/evcc/meter"
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/server/db/history"
+	"github.com/evcc-io/evcc/tariff"
+	"github.com/evcc-io/evcc/util/config"
+	"github.com/evcc-io/evcc/util/templates"
+	"github.com/evcc-io/evcc/vehicle"
+)
+
+// historyHandler returns a page of configuration revisions
+func historyHandler(w http.ResponseWriter, r *http.Request) {
+	if db.Instance == nil {
+		jsonError(w, http.StatusBadRequest, errors.New("database offline"))
+		return
+	}
+
+	q := r.URL.Query()
+
+	query := history.Query{
+		Kind: history.Kind(q.Get("kind")),
+		Key:  q.Get("key"),
+	}
+
+	page, limit := 1, 100
+	for key, val := range map[string]*int{"page": &page, "limit": &limit} {
+		if s := q.Get(key); s != "" {
+			i, err := strconv.Atoi(s)
+			if err != nil || i < 1 {
+				jsonError(w, http.StatusBadRequest, errors.New("invalid "+key))
+				return
+			}
+			*val = i
+		}
+	}
+
+	query.Offset = (page - 1) * limit
+	query.Limit = limit
+
+	res, total, err := history.Find(query)
+	if err != nil {
+		jsonError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	jsonWrite(w, struct {
+		Revisions []history.Revision `json:"revisions"`
+		Total     int64              `json:"total"`
+		Page      int                `json:"page"`
+		Limit     int                `json:"limit"`
+	}{
+		Revisions: res,
+		Total:     total,
+		Page:      page,
+		Limit:     limit,
+	})
+}
+
+// revisionParam resolves a revision id or RFC3339 point in time
+func revisionParam(s string) (uint, error) {
+	if ts, err := time.Parse(time.RFC3339, s); err == nil {
+		return history.At(ts)
+	}
+
+	id, err := strconv.ParseUint(s, 10, 0)
+	return uint(id), err
+}
+
+// historyDiffHandler returns the changes between two revisions
+func historyDiffHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	from, err := revisionParam(q.Get("from"))
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	to, err := revisionParam(q.Get("to"))
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	res, err := history.Diff(from, to)
+	if err != nil {
+		jsonError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	jsonWrite(w, res)
+}
+
+// historyRollbackHandler restores a single key or the whole configuration to a revision or point in time
+func historyRollbackHandler(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Revision string       `json:"revision"` // revision id or RFC3339 timestamp
+		Kind     history.Kind `json:"kind,omitempty"`
+		Key      string       `json:"key,omitempty"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if req.Key != "" && req.Kind == "" {
+		jsonError(w, http.StatusBadRequest, errors.New("missing kind"))
+		return
+	}
+
+	id, err := revisionParam(req.Revision)
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	res, err := history.Rollback(requestSource(r).Actor, id, req.Kind, req.Key)
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// restored settings are applied on restart, devices are reloaded by restoreDevice
+	if slices.ContainsFunc(res, func(c history.Change) bool {
+		return c.Kind == history.Setting
+	}) {
+		setConfigDirty()
+	}
+
+	jsonWrite(w, res)
+}
+
+// restoreDevice applies device revisions through the config handlers, reloading running devices like a device update.
+// Devices that have been added or removed since are restored in the database and applied on restart.
+func restoreDevice(site site.API) history.RestoreFunc {
+	return func(author, name string, value *string) error {
+		if value == nil {
+			setConfigDirty()
+			return config.Restore(author, name, value)
+		}
+
+		conf, err := config.Revision(name, *value)
+		if err != nil {
+			return err
+		}
+
+		req := configReq{Properties: conf.Properties, Other: conf.Data}
+		if yaml, ok := conf.Data["yaml"].(string); ok {
+			req.Yaml = yaml
+		}
+
+		ctx, cancel, done := startDeviceTimeout()
+		opt := config.WithAuthor(author)
+
+		switch conf.Class {
+		case templates.Charger:
+			err = updateDevice(ctx, conf.ID, conf.Class, req, charger.NewFromConfig, config.Chargers(), false, opt)
+
+		case templates.Meter:
+			err = updateDevice(ctx, conf.ID, conf.Class, req, meter.NewFromConfig, config.Meters(), false, opt)
+
+		case templates.Vehicle:
+			err = updateDevice(ctx, conf.ID, conf.Class, req, vehicle.NewFromConfig, config.Vehicles(), false, opt)
+
+		case templates.Circuit:
+			err = updateDevice(ctx, conf.ID, conf.Class, req, circuit.NewFromConfig, config.Circuits(), false, opt)
+
+		case templates.Hems:
+			err = updateDevice(ctx, conf.ID, conf.Class, req, newHemsFactory(site), config.Hems(), false, opt)
+
+		case templates.Tariff:
+			err = updateDevice(ctx, conf.ID, conf.Class, req, tariff.NewFromConfig, config.Tariffs(), false, opt)
+
+		case templates.Messenger:
+			err = updateDevice(ctx, conf.ID, conf.Class, req, messenger.NewFromConfig, config.Messengers(), false, opt)
+
+		default:
+			// loadpoints are not reloaded from device configs
+			err = config.ErrNotFound
+		}
+
+		if errors.Is(err, config.ErrNotFound) {
+			cancel()
+			setConfigDirty()
+			return config.Restore(author, name, value)
+		}
+
+		if err != nil {
+			cancel()
+			return err
+		}
+
+		// prevent context from being cancelled
+		close(done)
+
+		if requiresRestart(conf.Class) {
+			setConfigDirty()
+		}
+
+		return nil
+	}
+}
@@ -40,6 +40,7 @@ func getLoadpointDynamicConfig(lp loadpoint.API) loadpoint.DynamicConfig {
 		Thresholds:               lp.GetThresholds(),
 		Soc:                      lp.GetSocConfig(),
 		UI:                       lp.GetUI(),
+		Watchdog:                 lp.GetWatchdog(),
 		PlanEnergy:               planEnergy,
 		PlanTime:                 planTime,
 		PlanStrategy:             lp.GetPlanStrategy(),
@@ -159,7 +160,7 @@ func newLoadpointHandler() http.HandlerFunc {
 		name := "lp-" + strconv.Itoa(id+1)
 		log := util.NewLoggerWithLoadpoint(name, id+1)
 
-		conf, err := config.AddConfig(templates.Loadpoint, static)
+		conf, err := config.AddConfig(templates.Loadpoint, static, config.WithAuthor(requestSource(r).Actor))
 		if err != nil {
 			jsonError(w, http.StatusBadRequest, err)
 			return
@@ -237,7 +238,7 @@ func updateLoadpointHandler() http.HandlerFunc {
 
 		instance := dev.Instance()
 
-		if err := configurable.Update(other, instance); err != nil {
+		if err := configurable.Update(other, instance, config.WithAuthor(requestSource(r).Actor)); err != nil {
 			jsonError(w, http.StatusBadRequest, err)
 			return
 		}
@@ -275,9 +276,10 @@ func deleteLoadpointHandler() http.HandlerFunc {
 		}
 
 		instance := lp.Instance()
+		author := config.WithAuthor(requestSource(r).Actor)
 
 		if dev, err := configurableDevice(instance.GetChargerRef(), config.Chargers()); err == nil {
-			if err := deleteDevice(dev.ID(), config.Chargers()); err != nil {
+			if err := deleteDevice(dev.ID(), config.Chargers(), author); err != nil {
 				jsonError(w, http.StatusBadRequest, err)
 				return
 			}
@@ -286,7 +288,7 @@ func deleteLoadpointHandler() http.HandlerFunc {
 		}
 
 		if dev, err := configurableDevice(instance.GetMeterRef(), config.Meters()); err == nil {
-			if err := deleteDevice(dev.ID(), config.Meters()); err != nil {
+			if err := deleteDevice(dev.ID(), config.Meters(), author); err != nil {
 				jsonError(w, http.StatusBadRequest, err)
 				return
 			}
@@ -296,7 +298,7 @@ func deleteLoadpointHandler() http.HandlerFunc {
 
 		setConfigDirty()
 
-		if err := deleteDevice(id, h); err != nil {
+		if err := deleteDevice(id, h, author); err != nil {
 			jsonError(w, http.StatusBadRequest, err)
 			return
 		}
@@ -3,22 +3,26 @@ package server
 import (
 	"net/http"
 
+	"github.com/evcc-io/evcc/core/shedding"
 	"github.com/evcc-io/evcc/core/site"
 	"github.com/evcc-io/evcc/server/db/settings"
 	"github.com/evcc-io/evcc/util/config"
+	"github.com/evcc-io/evcc/util/geo"
 )
 
 // siteHandler returns a device configurations by class
 func siteHandler(site site.API) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		res := struct {
-			Title    string   `json:"title"`
-			Grid     string   `json:"grid"`
-			PV       []string `json:"pv"`
-			Battery  []string `json:"battery"`
-			Aux      []string `json:"aux"`
-			Ext      []string `json:"ext"`
-			Consumer []string `json:"consumer"`
+			Title        string           `json:"title"`
+			Home         *geo.Home        `json:"home,omitempty"`
+			LoadShedding *shedding.Config `json:"loadShedding,omitempty"`
+			Grid         string           `json:"grid"`
+			PV           []string         `json:"pv"`
+			Battery      []string         `json:"battery"`
+			Aux          []string         `json:"aux"`
+			Ext          []string         `json:"ext"`
+			Consumer     []string         `json:"consumer"`
 		}{
 			Title:    site.GetTitle(),
 			Grid:     site.GetGridMeterRef(),
@@ -29,6 +33,14 @@ func siteHandler(site site.API) http.HandlerFunc {
 			Consumer: site.GetConsumerMeterRefs(),
 		}
 
+		if home := site.GetHome(); home.Configured() {
+			res.Home = &home
+		}
+
+		if c := site.GetLoadShedding(); c.Configured() {
+			res.LoadShedding = &c
+		}
+
 		jsonWrite(w, res)
 	}
 }
@@ -47,13 +59,15 @@ func validateRefs(w http.ResponseWriter, refs []string) bool {
 func updateSiteHandler(site site.API) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var payload struct {
-			Title    *string
-			Grid     *string
-			PV       *[]string
-			Battery  *[]string
-			Aux      *[]string
-			Ext      *[]string
-			Consumer *[]string
+			Title        *string
+			Home         *geo.Home
+			LoadShedding *shedding.Config
+			Grid         *string
+			PV           *[]string
+			Battery      *[]string
+			Aux          *[]string
+			Ext          *[]string
+			Consumer     *[]string
 		}
 
 		if err := jsonDecoder(r.Body).Decode(&payload); err != nil {
@@ -65,13 +79,26 @@ func updateSiteHandler(site site.API) http.HandlerFunc {
 			site.SetTitle(*payload.Title)
 		}
 
+		if payload.Home != nil {
+			if err := site.SetHome(*payload.Home); err != nil {
+				jsonError(w, http.StatusBadRequest, err)
+				return
+			}
+		}
+
+		if payload.LoadShedding != nil {
+			if err := site.SetLoadShedding(*payload.LoadShedding); err != nil {
+				jsonError(w, http.StatusBadRequest, err)
+				return
+			}
+		}
+
 		if payload.Grid != nil {
 			if *payload.Grid != "" && !validateRefs(w, []string{*payload.Grid}) {
 				return
 			}
 
 			site.SetGridMeterRef(*payload.Grid)
-			setConfigDirty()
 		}
 
 		if payload.PV != nil {
@@ -80,7 +107,6 @@ func updateSiteHandler(site site.API) http.HandlerFunc {
 			}
 
 			site.SetPVMeterRefs(*payload.PV)
-			setConfigDirty()
 		}
 
 		if payload.Battery != nil {
@@ -89,7 +115,6 @@ func updateSiteHandler(site site.API) http.HandlerFunc {
 			}
 
 			site.SetBatteryMeterRefs(*payload.Battery)
-			setConfigDirty()
 		}
 
 		if payload.Aux != nil {
@@ -98,7 +123,6 @@ func updateSiteHandler(site site.API) http.HandlerFunc {
 			}
 
 			site.SetAuxMeterRefs(*payload.Aux)
-			setConfigDirty()
 		}
 
 		if payload.Ext != nil {
@@ -107,7 +131,6 @@ func updateSiteHandler(site site.API) http.HandlerFunc {
 			}
 
 			site.SetExtMeterRefs(*payload.Ext)
-			setConfigDirty()
 		}
 
 		if payload.Consumer != nil {
@@ -116,7 +139,6 @@ func updateSiteHandler(site site.API) http.HandlerFunc {
 			}
 
 			site.SetConsumerMeterRefs(*payload.Consumer)
-			setConfigDirty()
 		}
 
 		// persist immediately to keep meter refs consistent with device config on unclean shutdown
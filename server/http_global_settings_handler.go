@@ -32,7 +32,10 @@ func settingsGetStringHandler(key string) http.HandlerFunc {
 
 func settingsDeleteHandler(key string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		settings.SetString(key, "")
+		persistSettings(r, func() {
+			settings.SetString(key, "")
+		})
+
 		jsonWrite(w, true)
 	}
 }
@@ -47,7 +50,9 @@ func settingsSetDurationHandler(key string, pub publisher) http.HandlerFunc {
 			return
 		}
 
-		settings.SetInt(key, int64(time.Second*time.Duration(val)))
+		persistSettings(r, func() {
+			settings.SetInt(key, int64(time.Second*time.Duration(val)))
+		})
 		setConfigDirty()
 
 		pub(key, val)
@@ -80,7 +85,9 @@ func settingsSetYamlHandler(key string, other, struc any, authObject auth.Auth)
 		}
 
 		val := strings.TrimSpace(string(b))
-		settings.SetString(key, val)
+		persistSettings(r, func() {
+			settings.SetString(key, val)
+		})
 		setConfigDirty()
 
 		jsonWrite(w, val)
@@ -114,7 +121,9 @@ func settingsSetJsonHandler(key string, pub publisher, newStruc func() any) http
 			}
 		}
 
-		settings.SetJson(key, struc)
+		persistSettings(r, func() {
+			settings.SetJson(key, struc)
+		})
 		setConfigDirty()
 
 		if allowPub(key) {
@@ -127,7 +136,9 @@ func settingsSetJsonHandler(key string, pub publisher, newStruc func() any) http
 
 func settingsDeleteJsonHandler(key string, pub publisher, struc any) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		settings.SetString(key, "")
+		persistSettings(r, func() {
+			settings.SetString(key, "")
+		})
 		setConfigDirty()
 
 		if allowPub(key) {
@@ -190,6 +190,24 @@ func vehicleDetectHandler(lp loadpoint.API) http.HandlerFunc {
 	}
 }
 
+// schedulesHandler updates the loadpoint schedules
+func schedulesHandler(lp loadpoint.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var res loadpoint.Schedules
+		if err := jsonDecoder(r.Body).Decode(&res); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := lp.SetSchedules(res); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		jsonWrite(w, lp.GetSchedules())
+	}
+}
+
 // planStrategyHandler updates plan strategy for loadpoint
 func planStrategyHandler(lp loadpoint.API) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
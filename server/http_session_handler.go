@@ -2,15 +2,21 @@ package server
 
 import (
 	"context"
+	"crypto/ed25519"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/evcc-io/evcc/core/keys"
 	"github.com/evcc-io/evcc/core/session"
 	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/server/db/settings"
 	"github.com/evcc-io/evcc/util/export"
 	"github.com/evcc-io/evcc/util/export/csv"
 	"github.com/evcc-io/evcc/util/export/xlsx"
@@ -25,6 +31,20 @@ func jsonAttachment(w http.ResponseWriter, res any, filename string) {
 	jsonWrite(w, res)
 }
 
+// localeContext returns a context with the requested or the request's language
+func localeContext(r *http.Request) context.Context {
+	lang := r.URL.Query().Get("lang")
+	if lang == "" {
+		// get request language
+		lang = r.Header.Get("Accept-Language")
+		if tags, _, err := language.ParseAcceptLanguage(lang); err == nil && len(tags) > 0 {
+			lang = tags[0].String()
+		}
+	}
+
+	return context.WithValue(context.Background(), locale.Locale, lang)
+}
+
 // exportResult writes res to w as format (csv|xlsx) with download headers.
 func exportResult(ctx context.Context, w http.ResponseWriter, format string, res export.Writer, filename string) {
 	if format == "xlsx" {
@@ -102,21 +122,97 @@ func sessionHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if format == "csv" || format == "xlsx" {
-		lang := r.URL.Query().Get("lang")
-		if lang == "" {
-			// get request language
-			lang = r.Header.Get("Accept-Language")
-			if tags, _, err := language.ParseAcceptLanguage(lang); err == nil && len(tags) > 0 {
-				lang = tags[0].String()
-			}
+		exportResult(localeContext(r), w, format, &res, filename)
+		return
+	}
+
+	jsonWrite(w, res)
+}
+
+// reimbursementHandler returns the company-car reimbursement report of a month
+func reimbursementHandler(w http.ResponseWriter, r *http.Request) {
+	if db.Instance == nil {
+		jsonError(w, http.StatusBadRequest, errors.New("database offline"))
+		return
+	}
+
+	query := r.URL.Query()
+
+	month, err := time.ParseInLocation("2006-01", query.Get("year")+"-"+fmt.Sprintf("%02s", query.Get("month")), time.Local)
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	q := session.ReimbursementQuery{
+		Vehicle:    query.Get("vehicle"),
+		Identifier: query.Get("identifier"),
+		Month:      month,
+	}
+
+	if rate := query.Get("rate"); rate != "" {
+		f, err := strconv.ParseFloat(rate, 64)
+		if err != nil || f < 0 {
+			jsonError(w, http.StatusBadRequest, fmt.Errorf("invalid rate: %s", rate))
+			return
+		}
+		q.Rate = &f
+	}
+
+	res, err := q.Reimbursements(db.Instance)
+	if err != nil {
+		jsonError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	filename := "reimbursement-" + month.Format("2006-01")
+	for _, s := range []string{q.Vehicle, q.Identifier} {
+		if s != "" {
+			filename += "-" + strings.ReplaceAll(s, " ", "_")
 		}
+	}
 
-		ctx := context.WithValue(context.Background(), locale.Locale, lang)
-		exportResult(ctx, w, format, &res, filename)
+	if format := query.Get("format"); format == "csv" || format == "xlsx" {
+		exportResult(localeContext(r), w, format, &res, filename)
 		return
 	}
 
-	jsonWrite(w, res)
+	currency, _ := settings.String(keys.Currency)
+
+	summary := q.Summary(res, currency)
+
+	key, err := session.SigningKey()
+	if err == nil {
+		err = summary.Sign(key)
+	}
+	if err != nil {
+		jsonError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	jsonAttachment(w, summary, filename)
+}
+
+// reimbursementKeyHandler returns the public key for verifying reimbursement report signatures
+func reimbursementKeyHandler(w http.ResponseWriter, r *http.Request) {
+	if db.Instance == nil {
+		jsonError(w, http.StatusBadRequest, errors.New("database offline"))
+		return
+	}
+
+	key, err := session.SigningKey()
+	if err != nil {
+		jsonError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	jsonWrite(w, struct {
+		Algorithm string `json:"algorithm"`
+		PublicKey string `json:"publicKey"`
+	}{
+		Algorithm: "ed25519",
+		PublicKey: base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey)),
+	})
 }
 
 // deleteSessionHandler removes session in sessions table with given id
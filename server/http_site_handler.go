@@ -239,10 +239,10 @@ func updateBatteryMode(site site.API) http.HandlerFunc {
 	}
 }
 
-// stateHandler returns the combined state
-func stateHandler(cache *util.ParamCache) http.HandlerFunc {
+// stateHandler returns the combined state of the given site, empty for the default site
+func stateHandler(cache *util.ParamCache, site string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		res := cache.State(encode.NewEncoder(encode.WithDuration()))
+		res := cache.SiteState(site, encode.NewEncoder(encode.WithDuration()))
 		for _, k := range ignoreState {
 			delete(res, k)
 		}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/core/vehicle"
 	"github.com/gorilla/mux"
 )
 
@@ -196,6 +197,40 @@ func addRepeatingPlansHandler(site site.API) http.HandlerFunc {
 	}
 }
 
+// vehicleSettingHandler sets a vehicle setting from the request body or removes it for DELETE requests
+func vehicleSettingHandler[T any](site site.API, set func(vehicle.API, *T) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+
+		v, err := site.Vehicles().ByName(vars["name"])
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		var res *T
+		if r.Method != http.MethodDelete {
+			res = new(T)
+			if err := json.NewDecoder(r.Body).Decode(res); err != nil {
+				jsonError(w, http.StatusBadRequest, err)
+				return
+			}
+		}
+
+		if err := set(v, res); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		if res == nil {
+			jsonWrite(w, struct{}{})
+			return
+		}
+
+		jsonWrite(w, res)
+	}
+}
+
 // planSocRemoveHandler removes plan soc and time
 func planSocRemoveHandler(site site.API) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
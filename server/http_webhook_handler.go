@@ -0,0 +1,38 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/evcc-io/evcc/messenger"
+	"github.com/evcc-io/evcc/server/db"
+)
+
+// webhookDeliveriesHandler returns the most recent webhook deliveries
+func webhookDeliveriesHandler(w http.ResponseWriter, r *http.Request) {
+	if db.Instance == nil {
+		jsonError(w, http.StatusBadRequest, errors.New("database offline"))
+		return
+	}
+
+	limit := 100
+	if s := r.URL.Query().Get("limit"); s != "" {
+		l, err := strconv.Atoi(s)
+		if err != nil || l <= 0 {
+			jsonError(w, http.StatusBadRequest, errors.New("invalid limit"))
+			return
+		}
+		limit = l
+	}
+
+	status := messenger.DeliveryStatus(r.URL.Query().Get("status"))
+
+	res, err := messenger.WebhookDeliveries(status, limit)
+	if err != nil {
+		jsonError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	jsonWrite(w, res)
+}
@@ -168,8 +168,8 @@ func (m *Influx) writeComplexPoint(writer pointWriter, key string, val any, tags
 	m.writePoint(writer, key, fields, tags)
 }
 
-// Run Influx publisher
-func (m *Influx) Run(site site.API, in <-chan util.Param) {
+// Run Influx publisher. Loadpoints are resolved from the sites by id, the default site has an empty id.
+func (m *Influx) Run(sites map[string]site.API, in <-chan util.Param) {
 	writer := m.client.WriteAPI(m.org, m.database)
 
 	// log errors
@@ -183,7 +183,11 @@ func (m *Influx) Run(site site.API, in <-chan util.Param) {
 	// add points to batch for async writing
 	for param := range in {
 		tags := make(map[string]string)
-		if param.Loadpoint != nil {
+		if param.Site != "" {
+			tags["site"] = param.Site
+		}
+
+		if site, ok := sites[param.Site]; ok && param.Loadpoint != nil {
 			lp := site.Loadpoints()[*param.Loadpoint]
 
 			tags["loadpoint"] = lp.GetTitle()
@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"net/http/httputil"
 
+	"github.com/evcc-io/evcc/core/audit"
 	"github.com/evcc-io/evcc/util"
 	openapi2mcp "github.com/evcc-io/openapi-mcp"
 	"github.com/getkin/kin-openapi/openapi3"
@@ -64,6 +65,8 @@ func NewHandler(host http.Handler) (http.Handler, error) {
 
 func requestHandler(log *util.Logger, handler http.Handler) func(req *http.Request) (*http.Response, error) {
 	return func(req *http.Request) (*http.Response, error) {
+		req = req.WithContext(audit.WithSource(req.Context(), audit.Source{Kind: audit.MCP}))
+
 		if r, err := httputil.DumpRequest(req, true); err == nil {
 			log.TRACE.Println(string(r))
 		}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/cmd/shutdown"
+	"github.com/evcc-io/evcc/core/audit"
 	"github.com/evcc-io/evcc/core/loadpoint"
 	"github.com/evcc-io/evcc/core/site"
 	"github.com/evcc-io/evcc/core/vehicle"
@@ -25,8 +26,8 @@ type MQTT struct {
 	publisher func(topic string, retained bool, payload string)
 }
 
-// NewMQTT creates MQTT server
-func NewMQTT(root string, site site.API) (*MQTT, error) {
+// NewMQTT creates MQTT server. Sites are keyed by id, the default site has an empty id.
+func NewMQTT(root string, sites map[string]site.API) (*MQTT, error) {
 	m := &MQTT{
 		log:     util.NewLogger("mqtt"),
 		Handler: mqtt.Instance,
@@ -36,7 +37,7 @@ func NewMQTT(root string, site site.API) (*MQTT, error) {
 
 	err := m.Handler.Cleanup(m.root, true)
 	if err == nil {
-		err = m.Listen(site)
+		err = m.Listen(sites)
 	}
 	if err != nil {
 		err = fmt.Errorf("mqtt: %w", err)
@@ -186,23 +187,54 @@ func (m *MQTT) publish(topic string, retained bool, payload any) {
 	m.publishComplex(topic, retained, payload)
 }
 
-func (m *MQTT) Listen(site site.API) error {
-	if err := m.listenSiteSetters(m.root+"/site", site); err != nil {
-		return err
+// siteRoot returns the topic root of the site, additional sites are nested below the root topic
+func (m *MQTT) siteRoot(id string) string {
+	if id == "" {
+		return m.root
+	}
+	return m.root + "/" + id
+}
+
+func (m *MQTT) Listen(sites map[string]site.API) error {
+	source := func(topic string) audit.Source {
+		return audit.Source{Kind: audit.MQTT, Actor: topic}
 	}
 
-	// loadpoint setters
-	for id, lp := range site.Loadpoints() {
-		topic := fmt.Sprintf("%s/loadpoints/%d", m.root, id+1)
-		if err := m.listenLoadpointSetters(topic, site, lp); err != nil {
+	for siteID, instance := range sites {
+		root := m.siteRoot(siteID)
+
+		auditSite := func(src audit.Source) site.API {
+			if siteID == "" {
+				return audit.Site(instance, src)
+			}
+			return audit.NamedSite(instance, siteID, src)
+		}
+
+		topic := root + "/site"
+		if err := m.listenSiteSetters(topic, auditSite(source(topic))); err != nil {
 			return err
 		}
+
+		// loadpoint setters
+		for id := range instance.Loadpoints() {
+			topic := fmt.Sprintf("%s/loadpoints/%d", root, id+1)
+			site := auditSite(source(topic))
+			if err := m.listenLoadpointSetters(topic, site, site.Loadpoints()[id]); err != nil {
+				return err
+			}
+		}
+	}
+
+	// vehicles are shared by all sites
+	site, ok := sites[""]
+	if !ok {
+		return nil
 	}
 
 	// vehicle setters
 	for _, vehicle := range site.Vehicles().Settings() {
 		topic := fmt.Sprintf("%s/vehicles/%s", m.root, vehicle.Name())
-		if err := m.listenVehicleSetters(topic, vehicle); err != nil {
+		if err := m.listenVehicleSetters(topic, audit.Vehicle(vehicle, source(topic))); err != nil {
 			return err
 		}
 	}
@@ -303,19 +335,23 @@ func (m *MQTT) listenVehicleSetters(topic string, v vehicle.API) error {
 }
 
 // Run starts the MQTT publisher for the MQTT API
-func (m *MQTT) Run(site site.API, in <-chan util.Param) {
-	// number of loadpoints
-	topic := fmt.Sprintf("%s/loadpoints", m.root)
-	m.publish(topic, true, len(site.Loadpoints()))
-
-	// number of vehicles
-	topic = fmt.Sprintf("%s/vehicles", m.root)
-	m.publish(topic, true, len(site.Vehicles().Settings()))
-
-	for i := range 10 {
-		m.publish(fmt.Sprintf("%s/site/pv/%d", m.root, i), true, nil)
-		m.publish(fmt.Sprintf("%s/site/battery/%d", m.root, i), true, nil)
-		m.publish(fmt.Sprintf("%s/site/vehicles/%d", m.root, i), true, nil)
+func (m *MQTT) Run(sites map[string]site.API, in <-chan util.Param) {
+	for id, site := range sites {
+		root := m.siteRoot(id)
+
+		// number of loadpoints
+		m.publish(fmt.Sprintf("%s/loadpoints", root), true, len(site.Loadpoints()))
+
+		// number of vehicles
+		if id == "" {
+			m.publish(fmt.Sprintf("%s/vehicles", root), true, len(site.Vehicles().Settings()))
+		}
+
+		for i := range 10 {
+			m.publish(fmt.Sprintf("%s/site/pv/%d", root, i), true, nil)
+			m.publish(fmt.Sprintf("%s/site/battery/%d", root, i), true, nil)
+			m.publish(fmt.Sprintf("%s/site/vehicles/%d", root, i), true, nil)
+		}
 	}
 
 	// alive indicator
@@ -323,14 +359,17 @@ func (m *MQTT) Run(site site.API, in <-chan util.Param) {
 
 	// publish
 	for p := range in {
+		var topic string
+		root := m.siteRoot(p.Site)
+
 		switch {
 		case p.Loadpoint != nil:
 			id := *p.Loadpoint + 1
-			topic = fmt.Sprintf("%s/loadpoints/%d/%s", m.root, id, p.Key)
+			topic = fmt.Sprintf("%s/loadpoints/%d/%s", root, id, p.Key)
 		case p.Key == "vehicles":
-			topic = fmt.Sprintf("%s/vehicles", m.root)
+			topic = fmt.Sprintf("%s/vehicles", root)
 		default:
-			topic = fmt.Sprintf("%s/site/%s", m.root, p.Key)
+			topic = fmt.Sprintf("%s/site/%s", root, p.Key)
 		}
 
 		// alive indicator
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -112,15 +113,26 @@ func (h *SocketHub) deleteSubscriber(s *socketSubscriber) {
 	h.mu.Unlock()
 }
 
+// socketKey returns the ui state path of the parameter
+func socketKey(p util.Param) string {
+	k := p.Key
+	if p.Loadpoint != nil {
+		k = "loadpoints." + strconv.Itoa(*p.Loadpoint) + "." + k
+	}
+
+	if p.Site != "" {
+		k = "sites." + p.Site + "." + k
+	}
+
+	return k
+}
+
 func (h *SocketHub) welcome(subscriber *socketSubscriber, params []util.Param) {
 	msg := make(map[string]json.RawMessage, len(params))
 	sharders := make(map[string]util.Sharder)
 
 	for _, p := range params {
-		k := p.Key
-		if p.Loadpoint != nil {
-			k = "loadpoints." + p.UniqueID()
-		}
+		k := socketKey(p)
 
 		// Sharder values are split into shards and sent as a separate message
 		if sharder, ok := (p.Val).(util.Sharder); ok {
@@ -156,10 +168,7 @@ func (h *SocketHub) broadcast(p util.Param) {
 
 	msg := make(map[string]json.RawMessage)
 
-	k := p.Key
-	if p.Loadpoint != nil {
-		k = "loadpoints." + p.UniqueID()
-	}
+	k := socketKey(p)
 
 	// Sharder splits data into chunks
 	if sp, ok := (p.Val).(util.Sharder); ok {
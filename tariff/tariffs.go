@@ -1,6 +1,7 @@
 package tariff
 
 import (
+	"slices"
 	"time"
 
 	"github.com/evcc-io/evcc/api"
@@ -70,6 +71,29 @@ func AverageRate(t api.Tariff, d time.Duration) *float64 {
 	return new(sum / float64(count))
 }
 
+// Replace swaps a reconfigured tariff in all roles. A nil tariff removes the previous one.
+func (t *Tariffs) Replace(previous, tariff api.Tariff) {
+	for _, role := range []*api.Tariff{&t.Grid, &t.FeedIn, &t.Co2, &t.Planner, &t.Solar, &t.Temperature} {
+		if *role == previous {
+			*role = tariff
+		}
+	}
+
+	// combined solar tariffs are immutable, replace with a new combination
+	if c, ok := t.Solar.(*combined); ok && slices.Contains(c.tariffs, previous) {
+		var tt []api.Tariff
+		for _, ct := range c.tariffs {
+			if ct == previous {
+				ct = tariff
+			}
+			if ct != nil {
+				tt = append(tt, ct)
+			}
+		}
+		t.Solar = NewCombined(tt)
+	}
+}
+
 func (t *Tariffs) Get(u api.TariffUsage) api.Tariff {
 	// ensure tariff is not a wrapper
 	exists := func(t api.Tariff) bool {
@@ -0,0 +1,32 @@
+package tariff
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTariffsReplace(t *testing.T) {
+	a, b, c := new(tariff), new(tariff), new(tariff)
+
+	tt := &Tariffs{
+		Grid:    a,
+		Planner: a,
+		FeedIn:  b,
+		Solar:   NewCombined([]api.Tariff{a, b}),
+	}
+
+	tt.Replace(a, c)
+
+	assert.Same(t, c, tt.Grid)
+	assert.Same(t, c, tt.Planner)
+	assert.Same(t, b, tt.FeedIn)
+	assert.Equal(t, []api.Tariff{c, b}, tt.Solar.(*combined).tariffs)
+
+	// removed tariffs are dropped
+	tt.Replace(b, nil)
+
+	assert.Nil(t, tt.FeedIn)
+	assert.Equal(t, []api.Tariff{c}, tt.Solar.(*combined).tariffs)
+}
@@ -4,3 +4,6 @@ import "embed"
 
 //go:embed charger/*.yaml meter/*.yaml vehicle/*.yaml tariff/*.yaml messenger/*.yaml circuit/*.yaml hems/*.yaml
 var YamlTemplates embed.FS
+
+//go:embed devices-schema.json common-schema.json
+var Schemas embed.FS
@@ -1,12 +1,14 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"maps"
 	"strconv"
 	"strings"
 
 	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/server/db/history"
 	"github.com/evcc-io/evcc/util/templates"
 	"gorm.io/gorm"
 )
@@ -20,6 +22,8 @@ type Config struct {
 	Class      templates.Class
 	Properties `gorm:"embedded"`
 	Data       map[string]any `gorm:"column:value;type:string;serializer:json"`
+	author     string         // author of the change for revision history
+	tx         *gorm.DB       // transaction to apply the change in
 }
 
 type Properties struct {
@@ -54,32 +58,141 @@ func WithProperties(p Properties) func(*Config) {
 	}
 }
 
+// WithAuthor attributes the change to author in the revision history
+func WithAuthor(author string) func(*Config) {
+	return func(d *Config) {
+		d.author = author
+	}
+}
+
+// WithTx applies the change as part of the database transaction
+func WithTx(tx *gorm.DB) func(*Config) {
+	return func(d *Config) {
+		d.tx = tx
+	}
+}
+
+// db returns the database or the transaction to apply the change in
+func (d *Config) db() *gorm.DB {
+	if d.tx != nil {
+		return d.tx
+	}
+	return db.Instance
+}
+
+// revision returns the config's json representation for the revision history
+func (d *Config) revision() *string {
+	b, err := json.Marshal(d)
+	if err != nil {
+		return nil
+	}
+	return new(string(b))
+}
+
 // Update updates a config's details to the database
 func (d *Config) Update(conf map[string]any, opt ...func(*Config)) error {
-	return db.Instance.Transaction(func(tx *gorm.DB) error {
-		var config Config
-		if err := tx.Where(Config{Class: d.Class, ID: d.ID}).First(&config).Error; err != nil {
+	next := *d
+	next.Data = conf
+	for _, o := range opt {
+		o(&next)
+	}
+
+	if err := next.db().Transaction(func(tx *gorm.DB) error {
+		var prev Config
+		if err := tx.Where(Config{Class: d.Class, ID: d.ID}).First(&prev).Error; err != nil {
 			return err
 		}
 
-		d.Data = conf
-		for _, o := range opt {
-			o(d)
+		if err := tx.Save(&next).Error; err != nil {
+			return err
 		}
 
-		return tx.Save(&d).Error
-	})
+		history.RecordTx(tx, next.author, history.Device, NameForID(d.ID), prev.revision(), next.revision())
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	next.author, next.tx = "", nil
+	*d = next
+
+	return nil
 }
 
 // Delete deletes a config from the database
-func (d *Config) Delete() error {
-	return db.Instance.Delete(Config{ID: d.ID}).Error
+func (d *Config) Delete(opt ...func(*Config)) error {
+	for _, o := range opt {
+		o(d)
+	}
+
+	if err := d.db().Delete(Config{ID: d.ID}).Error; err != nil {
+		return err
+	}
+
+	history.RecordTx(d.db(), d.author, history.Device, NameForID(d.ID), d.revision(), nil)
+
+	return nil
 }
 
 func init() {
 	db.Register(func(db *gorm.DB) error {
 		return db.AutoMigrate(new(Config))
 	})
+
+	history.Register(history.Device, Restore)
+}
+
+// Revision decodes a device revision. Redacted secrets are taken from the stored config.
+func Revision(name, value string) (Config, error) {
+	id, err := IDForName(name)
+	if err != nil {
+		return Config{}, err
+	}
+
+	if config, err := ConfigByID(id); err == nil {
+		if cur := config.revision(); cur != nil {
+			value = history.Unredact(value, *cur)
+		}
+	}
+
+	var res Config
+	if err := json.Unmarshal([]byte(value), &res); err != nil {
+		return Config{}, err
+	}
+	res.ID = id
+
+	return res, nil
+}
+
+// Restore restores a config from the revision history. Running devices are not affected.
+func Restore(author, name string, value *string) error {
+	id, err := IDForName(name)
+	if err != nil {
+		return err
+	}
+
+	if value == nil {
+		return (&Config{ID: id}).Delete(WithAuthor(author))
+	}
+
+	res, err := Revision(name, *value)
+	if err != nil {
+		return err
+	}
+
+	var prev *string
+	if config, err := ConfigByID(id); err == nil {
+		prev = config.revision()
+	}
+
+	if err := db.Instance.Save(&res).Error; err != nil {
+		return err
+	}
+
+	history.Record(author, history.Device, name, prev, res.revision())
+
+	return nil
 }
 
 // NameForID returns a unique config name for the given id
@@ -94,8 +207,13 @@ func IDForName(name string) (int, error) {
 
 // ConfigurationsByClass returns devices by class from the database
 func ConfigurationsByClass(class templates.Class) ([]Config, error) {
+	return ConfigurationsByClassTx(db.Instance, class)
+}
+
+// ConfigurationsByClassTx returns devices by class as part of a database transaction
+func ConfigurationsByClassTx(tx *gorm.DB, class templates.Class) ([]Config, error) {
 	var devices []Config
-	tx := db.Instance.Where(&Config{Class: class}).Find(&devices)
+	tx = tx.Where(&Config{Class: class}).Find(&devices)
 
 	// remove devices without details
 	res := make([]Config, 0, len(devices))
@@ -120,8 +238,13 @@ func ConfigurationByClass(class templates.Class) (*Config, error) {
 
 // ConfigByID returns device by id from the database
 func ConfigByID(id int) (Config, error) {
+	return ConfigByIDTx(db.Instance, id)
+}
+
+// ConfigByIDTx returns device by id as part of a database transaction
+func ConfigByIDTx(tx *gorm.DB, id int) (Config, error) {
 	var config Config
-	tx := db.Instance.Where(&Config{ID: id}).First(&config)
+	tx = tx.Where(&Config{ID: id}).First(&config)
 	return config, tx.Error
 }
 
@@ -136,9 +259,11 @@ func AddConfig(class templates.Class, conf map[string]any, opt ...func(*Config))
 		o(&config)
 	}
 
-	if err := db.Instance.Create(&config).Error; err != nil {
+	if err := config.db().Create(&config).Error; err != nil {
 		return Config{}, err
 	}
 
+	history.RecordTx(config.db(), config.author, history.Device, NameForID(config.ID), nil, config.revision())
+
 	return config, nil
 }
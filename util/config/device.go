@@ -12,7 +12,7 @@ type ConfigurableDevice[T any] interface {
 	ID() int
 	Properties() Properties
 	Update(map[string]any, T, ...func(*Config)) error
-	Delete() error
+	Delete(...func(*Config)) error
 }
 
 var _ Device[any] = (*staticDevice[any])(nil)
@@ -37,6 +37,22 @@ func (d *staticDevice[T]) Instance() T {
 	return d.instance
 }
 
+// updatedDevice is published with OpUpdate and keeps the replaced instance
+type updatedDevice[T any] struct {
+	Device[T]
+	previous T
+}
+
+// Previous returns the instance replaced by an OpUpdate operation
+func Previous[T any](dev Device[T]) (T, bool) {
+	if d, ok := dev.(*updatedDevice[T]); ok {
+		return d.previous, true
+	}
+
+	var zero T
+	return zero, false
+}
+
 var _ ConfigurableDevice[any] = (*configurableDevice[any])(nil)
 
 type configurableDevice[T any] struct {
@@ -86,8 +102,8 @@ func (d *configurableDevice[T]) Update(config map[string]any, instance T, opt ..
 	return nil
 }
 
-func (d *configurableDevice[T]) Delete() error {
+func (d *configurableDevice[T]) Delete(opt ...func(*Config)) error {
 	d.mu.Lock()
 	defer d.mu.Unlock()
-	return d.config.Delete()
+	return d.config.Delete(opt...)
 }
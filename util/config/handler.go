@@ -16,6 +16,7 @@ type Operation string
 
 const (
 	OpAdd    Operation = "add"
+	OpUpdate Operation = "update"
 	OpDelete Operation = "del"
 )
 
@@ -56,6 +57,35 @@ func (cp *handler[T]) Add(dev Device[T]) error {
 	return nil
 }
 
+// Update updates configurable device config and instance.
+// Subscribers receive the updated device, the replaced instance is available via Previous.
+// The replaced instance is shut down once subscribers no longer retain it.
+func (cp *handler[T]) Update(name string, conf map[string]any, instance T, opt ...func(*Config)) error {
+	dev, err := cp.ByName(name)
+	if err != nil {
+		return err
+	}
+
+	configurable, ok := dev.(ConfigurableDevice[T])
+	if !ok {
+		return errors.New("not configurable")
+	}
+
+	previous := configurable.Instance()
+
+	if err := configurable.Update(conf, instance, opt...); err != nil {
+		return err
+	}
+
+	bus.Publish(cp.topic, OpUpdate, Device[T](&updatedDevice[T]{Device: dev, previous: previous}))
+
+	if !sameInstance(previous, instance) {
+		retire(previous)
+	}
+
+	return nil
+}
+
 // Delete deletes device
 func (cp *handler[T]) Delete(name string) error {
 	cp.mu.Lock()
@@ -66,6 +96,8 @@ func (cp *handler[T]) Delete(name string) error {
 			cp.mu.Unlock()
 
 			bus.Publish(cp.topic, OpDelete, dev)
+			retire(dev.Instance())
+
 			return nil
 		}
 	}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/util/templates"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerOperations(t *testing.T) {
+	require.NoError(t, db.NewInstance("sqlite", ":memory:"))
+
+	h := &handler[string]{topic: t.Name()}
+
+	type event struct {
+		op       Operation
+		instance string
+		previous string
+	}
+
+	var events []event
+	h.Subscribe(func(op Operation, dev Device[string]) {
+		prev, _ := Previous(dev)
+		events = append(events, event{op, dev.Instance(), prev})
+	})
+
+	conf, err := AddConfig(templates.Meter, map[string]any{"foo": "bar"})
+	require.NoError(t, err)
+
+	name := NameForID(conf.ID)
+
+	require.NoError(t, h.Add(NewConfigurableDevice(&conf, "old")))
+	require.NoError(t, h.Update(name, map[string]any{"foo": "baz"}, "new"))
+
+	dev, err := h.ByName(name)
+	require.NoError(t, err)
+	assert.Equal(t, "new", dev.Instance())
+	assert.Equal(t, "baz", dev.Config().Other["foo"])
+
+	require.NoError(t, h.Delete(name))
+
+	assert.Equal(t, []event{
+		{OpAdd, "old", ""},
+		{OpUpdate, "new", "old"},
+		{OpDelete, "new", ""},
+	}, events)
+
+	// static devices are not updatable
+	require.NoError(t, h.Add(NewStaticDevice(Named{Name: "static"}, "static")))
+	assert.Error(t, h.Update("static", nil, "new"))
+	assert.ErrorIs(t, h.Update("missing", nil, "new"), ErrNotFound)
+}
+
+type closer struct{ closed bool }
+
+func (c *closer) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestHandlerLifetime(t *testing.T) {
+	require.NoError(t, db.NewInstance("sqlite", ":memory:"))
+
+	h := &handler[*closer]{topic: t.Name()}
+
+	conf, err := AddConfig(templates.Meter, map[string]any{"foo": "bar"})
+	require.NoError(t, err)
+
+	name := NameForID(conf.ID)
+
+	owned := func() (*closer, context.Context) {
+		ctx, cancel := context.WithCancel(t.Context())
+		c := new(closer)
+		Own(c, cancel)
+		return c, ctx
+	}
+
+	first, firstCtx := owned()
+	require.NoError(t, h.Add(NewConfigurableDevice(&conf, first)))
+
+	// replaced instance is shut down
+	second, secondCtx := owned()
+	require.NoError(t, h.Update(name, conf.Data, second))
+	assert.True(t, first.closed)
+	assert.Error(t, firstCtx.Err())
+
+	// retained instance is shut down once released
+	Retain(second)
+
+	third, thirdCtx := owned()
+	require.NoError(t, h.Update(name, conf.Data, third))
+	assert.False(t, second.closed)
+	assert.NoError(t, secondCtx.Err())
+
+	Release(second)
+	assert.True(t, second.closed)
+	assert.Error(t, secondCtx.Err())
+
+	// deleted instance is shut down
+	require.NoError(t, h.Delete(name))
+	assert.True(t, third.closed)
+	assert.Error(t, thirdCtx.Err())
+}
@@ -39,6 +39,7 @@ type Handler[T any] interface {
 	Subscribe(fn func(Operation, Device[T]))
 	Devices() []Device[T]
 	Add(dev Device[T]) error
+	Update(name string, conf map[string]any, instance T, opt ...func(*Config)) error
 	Delete(name string) error
 	ByName(name string) (Device[T], error)
 }
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"sync"
+)
+
+// lifetime is the context an owned device instance was created with
+type lifetime struct {
+	cancel  context.CancelFunc
+	users   int
+	retired bool
+}
+
+var lifetimes = struct {
+	mu      sync.Mutex
+	devices map[any]*lifetime
+}{
+	devices: make(map[any]*lifetime),
+}
+
+// lifetimeKey returns the instance as map key. Nil and non-comparable instances are not tracked.
+func lifetimeKey(instance any) (any, bool) {
+	if instance == nil {
+		return nil, false
+	}
+	return instance, reflect.ValueOf(instance).Comparable()
+}
+
+// sameInstance checks if both instances are identical
+func sameInstance(a, b any) bool {
+	ka, ok := lifetimeKey(a)
+	if !ok {
+		return false
+	}
+	kb, ok := lifetimeKey(b)
+	return ok && ka == kb
+}
+
+// Own registers the cancel func of the context the instance was created with.
+// Once the instance is replaced or deleted by its handler and no longer retained,
+// the context is cancelled and the instance is closed if it implements io.Closer.
+func Own(instance any, cancel context.CancelFunc) {
+	key, ok := lifetimeKey(instance)
+	if !ok {
+		return
+	}
+
+	lifetimes.mu.Lock()
+	defer lifetimes.mu.Unlock()
+
+	lifetimes.devices[key] = &lifetime{cancel: cancel}
+}
+
+// Retain marks an owned instance as in use outside of its handler, e.g. by a loadpoint
+func Retain(instance any) {
+	key, ok := lifetimeKey(instance)
+	if !ok {
+		return
+	}
+
+	lifetimes.mu.Lock()
+	defer lifetimes.mu.Unlock()
+
+	if l, ok := lifetimes.devices[key]; ok {
+		l.users++
+	}
+}
+
+// Release ends the use of an instance marked by Retain and shuts down retired instances no longer in use
+func Release(instance any) {
+	key, ok := lifetimeKey(instance)
+	if !ok {
+		return
+	}
+
+	lifetimes.mu.Lock()
+	l, ok := lifetimes.devices[key]
+	if ok {
+		l.users--
+	}
+	shutdown := ok && l.retired && l.users <= 0
+	if shutdown {
+		delete(lifetimes.devices, key)
+	}
+	lifetimes.mu.Unlock()
+
+	if shutdown {
+		l.shutdown(instance)
+	}
+}
+
+// retire shuts down an owned instance replaced or deleted by its handler once it is no longer retained
+func retire(instance any) {
+	key, ok := lifetimeKey(instance)
+	if !ok {
+		return
+	}
+
+	lifetimes.mu.Lock()
+	l, ok := lifetimes.devices[key]
+	if ok {
+		l.retired = true
+	}
+	shutdown := ok && l.users <= 0
+	if shutdown {
+		delete(lifetimes.devices, key)
+	}
+	lifetimes.mu.Unlock()
+
+	if shutdown {
+		l.shutdown(instance)
+	}
+}
+
+func (l *lifetime) shutdown(instance any) {
+	l.cancel()
+
+	if c, ok := instance.(io.Closer); ok {
+		_ = c.Close()
+	}
+}
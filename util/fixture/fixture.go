@@ -0,0 +1,181 @@
+// Package fixture records and replays the modbus, http and mqtt traffic of template devices.
+package fixture
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/plugin/mqtt"
+	"github.com/evcc-io/evcc/util/modbus"
+	"github.com/evcc-io/evcc/util/request"
+	"github.com/spf13/cast"
+	"go.yaml.in/yaml/v4"
+)
+
+// ErrNotRecorded is returned for requests without recorded response
+var ErrNotRecorded = errors.New("not recorded")
+
+// Masked replaces secret configuration values. It requires neither yaml quoting nor url encoding
+// to keep rendered requests identical to the masked recording.
+const Masked = "masked"
+
+// active serializes recorders and replayers since the taps intercept the traffic of the entire process
+var active sync.Mutex
+
+func tap(m modbus.TapFunc, h request.TapFunc, q mqtt.TapFunc) {
+	modbus.SetTap(m)
+	request.SetTap(h)
+	mqtt.SetTap(q)
+}
+
+// Fixture is the recorded traffic of a template device and its expected readings
+type Fixture struct {
+	Config map[string]any // device configuration including template name
+	Modbus []Modbus       `yaml:",omitempty"`
+	HTTP   []HTTP         `yaml:"http,omitempty"`
+	MQTT   []MQTT         `yaml:"mqtt,omitempty"`
+	Expect Values
+}
+
+// Modbus is a recorded modbus exchange
+type Modbus struct {
+	ID       uint8
+	Func     string
+	Address  uint16
+	Quantity uint16 `yaml:",omitempty"`
+	Value    string `yaml:",omitempty"` // hex
+	Result   string `yaml:",omitempty"` // hex
+	Error    string `yaml:",omitempty"`
+}
+
+// HTTP is a recorded http exchange
+type HTTP struct {
+	Method   string
+	URL      string `yaml:"url"`
+	Status   int    `yaml:",omitempty"`
+	Type     string `yaml:",omitempty"` // response content type
+	Response string `yaml:",omitempty"`
+	Error    string `yaml:",omitempty"`
+}
+
+// MQTT is a received mqtt message
+type MQTT struct {
+	Topic   string
+	Payload string
+}
+
+// Values are the device readings
+type Values struct {
+	Power  *float64 `yaml:",omitempty"` // W
+	Energy *float64 `yaml:",omitempty"` // kWh
+	Status string   `yaml:",omitempty"` // charger status
+	Soc    *float64 `yaml:",omitempty"` // %
+}
+
+// Load reads a fixture file
+func Load(file string) (Fixture, error) {
+	var res Fixture
+
+	b, err := os.ReadFile(file)
+	if err == nil {
+		err = yaml.Unmarshal(b, &res)
+	}
+
+	return res, err
+}
+
+// Save writes a fixture file
+func (f Fixture) Save(file string) error {
+	b, err := yaml.Marshal(f)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, b, 0o644)
+}
+
+// Mask replaces the values of the secret configuration keys with Masked,
+// including their occurrences in the recorded traffic
+func (f *Fixture) Mask(keys ...string) {
+	var replace []string
+
+	for _, key := range keys {
+		s := cast.ToString(f.Config[key])
+		if s == "" || s == Masked {
+			continue
+		}
+
+		f.Config[key] = Masked
+
+		for _, enc := range []func(string) string{
+			func(s string) string { return s },
+			url.QueryEscape,
+			url.PathEscape,
+		} {
+			replace = append(replace, enc(s), enc(Masked))
+		}
+	}
+
+	if len(replace) == 0 {
+		return
+	}
+
+	r := strings.NewReplacer(replace...)
+
+	for i, h := range f.HTTP {
+		f.HTTP[i].URL = r.Replace(h.URL)
+		f.HTTP[i].Response = r.Replace(h.Response)
+	}
+
+	for i, m := range f.MQTT {
+		f.MQTT[i].Topic = r.Replace(m.Topic)
+		f.MQTT[i].Payload = r.Replace(m.Payload)
+	}
+}
+
+// Read reads the values of all supported device interfaces
+func Read(dev any) (Values, error) {
+	var (
+		res  Values
+		errs []error
+	)
+
+	if m, ok := api.Cap[api.Meter](dev); ok {
+		if f, err := m.CurrentPower(); err == nil {
+			res.Power = &f
+		} else {
+			errs = append(errs, fmt.Errorf("power: %w", err))
+		}
+	}
+
+	if m, ok := api.Cap[api.MeterEnergy](dev); ok {
+		if f, err := m.TotalEnergy(); err == nil {
+			res.Energy = &f
+		} else {
+			errs = append(errs, fmt.Errorf("energy: %w", err))
+		}
+	}
+
+	if c, ok := api.Cap[api.Charger](dev); ok {
+		if s, err := c.Status(); err == nil {
+			res.Status = s.String()
+		} else {
+			errs = append(errs, fmt.Errorf("status: %w", err))
+		}
+	}
+
+	if b, ok := api.Cap[api.Battery](dev); ok {
+		if f, err := b.Soc(); err == nil {
+			res.Soc = &f
+		} else {
+			errs = append(errs, fmt.Errorf("soc: %w", err))
+		}
+	}
+
+	return res, errors.Join(errs...)
+}
@@ -0,0 +1,139 @@
+package fixture
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/evcc-io/evcc/plugin/mqtt"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/modbus"
+	"github.com/evcc-io/evcc/util/request"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordReplayHttp(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"power":1500}`))
+	}))
+
+	helper := request.NewHelper(util.NewLogger("foo"))
+
+	r := Record()
+	b, err := helper.GetBody(srv.URL + "/status")
+	f := r.Stop()
+	srv.Close()
+
+	require.NoError(t, err)
+	require.Equal(t, `{"power":1500}`, string(b))
+	require.Equal(t, []HTTP{{Method: http.MethodGet, URL: srv.URL + "/status", Status: http.StatusOK, Type: "application/json", Response: `{"power":1500}`}}, f.HTTP)
+
+	file := filepath.Join(t.TempDir(), "fixture.yaml")
+	require.NoError(t, f.Save(file))
+	f, err = Load(file)
+	require.NoError(t, err)
+
+	rp, err := Replay(f)
+	require.NoError(t, err)
+	defer rp.Stop()
+
+	b, err = helper.GetBody(srv.URL + "/status")
+	require.NoError(t, err)
+	require.Equal(t, `{"power":1500}`, string(b))
+
+	_, err = helper.GetBody(srv.URL + "/other")
+	require.ErrorIs(t, err, ErrNotRecorded)
+}
+
+func TestReplayModbus(t *testing.T) {
+	rp, err := Replay(Fixture{
+		Modbus: []Modbus{
+			{ID: 1, Func: "ReadHoldingRegisters", Address: 100, Quantity: 1, Result: "0001"},
+			{ID: 1, Func: "ReadHoldingRegisters", Address: 100, Quantity: 1, Result: "0002"},
+			{ID: 1, Func: "WriteSingleRegister", Address: 200, Value: "000a", Result: "000a"},
+		},
+	})
+	require.NoError(t, err)
+	defer rp.Stop()
+
+	conn, err := modbus.NewConnection(t.Context(), "localhost:1502", "", "", 0, modbus.Tcp, 1)
+	require.NoError(t, err)
+
+	// recorded responses in order, last one repeated
+	for _, expect := range [][]byte{{0, 1}, {0, 2}, {0, 2}} {
+		b, err := conn.ReadHoldingRegisters(100, 1)
+		require.NoError(t, err)
+		require.Equal(t, expect, b)
+	}
+
+	_, err = conn.WriteSingleRegister(200, 10)
+	require.NoError(t, err)
+
+	_, err = conn.WriteSingleRegister(200, 11)
+	require.ErrorIs(t, err, ErrNotRecorded)
+}
+
+func TestReplayMqtt(t *testing.T) {
+	rp, err := Replay(Fixture{
+		MQTT: []MQTT{{Topic: "foo/power", Payload: "42"}},
+	})
+	require.NoError(t, err)
+	defer rp.Stop()
+
+	var payload string
+	require.NoError(t, mqtt.Instance.Listen("foo/power", func(s string) {
+		payload = s
+	}))
+	require.Equal(t, "42", payload)
+}
+
+type httpMeter struct {
+	*request.Helper
+	uri string
+}
+
+func (m *httpMeter) CurrentPower() (float64, error) {
+	b, err := m.GetBody(m.uri)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(string(b), 64)
+}
+
+func TestFixture(t *testing.T) {
+	f := Fixture{
+		Config: map[string]any{"uri": "http://meter/power"},
+		HTTP:   []HTTP{{Method: http.MethodGet, URL: "http://meter/power", Status: http.StatusOK, Response: "1500"}},
+		Expect: Values{Power: new(1500.0)},
+	}
+
+	Test(t, f, func(_ context.Context, other map[string]any) (any, error) {
+		return &httpMeter{
+			Helper: request.NewHelper(util.NewLogger("foo")),
+			uri:    other["uri"].(string),
+		}, nil
+	})
+}
+
+func TestMask(t *testing.T) {
+	f := Fixture{
+		Config: map[string]any{"user": "foo", "password": "s3cret/+", "empty": ""},
+		HTTP: []HTTP{{
+			Method:   http.MethodGet,
+			URL:      "http://meter/s3cret%2F+/power?password=s3cret%2F%2B",
+			Response: `{"password":"s3cret/+"}`,
+		}},
+		MQTT: []MQTT{{Topic: "foo/s3cret/+/power", Payload: "s3cret/+"}},
+	}
+
+	f.Mask("password", "empty", "missing")
+
+	require.Equal(t, map[string]any{"user": "foo", "password": Masked, "empty": ""}, f.Config)
+	require.Equal(t, "http://meter/"+Masked+"/power?password="+Masked, f.HTTP[0].URL)
+	require.Equal(t, `{"password":"`+Masked+`"}`, f.HTTP[0].Response)
+	require.Equal(t, MQTT{Topic: "foo/" + Masked + "/power", Payload: Masked}, f.MQTT[0])
+}
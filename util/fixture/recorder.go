@@ -0,0 +1,118 @@
+package fixture
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"sync"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/evcc-io/evcc/util/modbus"
+)
+
+// Recorder records device traffic
+type Recorder struct {
+	mu     sync.Mutex
+	modbus []Modbus
+	http   []HTTP
+	mqtt   []MQTT
+}
+
+// Record records all device traffic until the recorder is stopped.
+// It waits for other recorders or replayers to be stopped.
+func Record() *Recorder {
+	active.Lock()
+
+	r := new(Recorder)
+	tap(r.modbusTap, r.httpTap, r.mqttTap)
+
+	return r
+}
+
+// Stop stops recording and returns the recorded traffic
+func (r *Recorder) Stop() Fixture {
+	tap(nil, nil, nil)
+	active.Unlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return Fixture{
+		Modbus: r.modbus,
+		HTTP:   r.http,
+		MQTT:   r.mqtt,
+	}
+}
+
+func (r *Recorder) modbusTap(req modbus.Request, exec func() ([]byte, error)) ([]byte, error) {
+	b, err := exec()
+
+	res := Modbus{
+		ID:       req.ID,
+		Func:     req.Func,
+		Address:  req.Address,
+		Quantity: req.Quantity,
+		Value:    hex.EncodeToString(req.Value),
+		Result:   hex.EncodeToString(b),
+	}
+	if err != nil {
+		res.Error = err.Error()
+	}
+
+	r.mu.Lock()
+	r.modbus = append(r.modbus, res)
+	r.mu.Unlock()
+
+	return b, err
+}
+
+func (r *Recorder) httpTap(req *http.Request, base http.RoundTripper) (*http.Response, error) {
+	res := HTTP{
+		Method: req.Method,
+		URL:    req.URL.String(),
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err == nil {
+		var b []byte
+		if b, err = io.ReadAll(resp.Body); err == nil {
+			resp.Body.Close()
+			resp.Body = io.NopCloser(bytes.NewReader(b))
+
+			res.Status = resp.StatusCode
+			res.Type = resp.Header.Get("Content-Type")
+			res.Response = string(b)
+		}
+	}
+
+	if err != nil {
+		res.Error = err.Error()
+	}
+
+	r.mu.Lock()
+	r.http = append(r.http, res)
+	r.mu.Unlock()
+
+	return resp, err
+}
+
+func (r *Recorder) mqttTap(client paho.Client) paho.Client {
+	return &recordingClient{Client: client, r: r}
+}
+
+// recordingClient records all received messages
+type recordingClient struct {
+	paho.Client
+	r *Recorder
+}
+
+func (c *recordingClient) Subscribe(topic string, qos byte, callback paho.MessageHandler) paho.Token {
+	return c.Client.Subscribe(topic, qos, func(client paho.Client, msg paho.Message) {
+		c.r.mu.Lock()
+		c.r.mqtt = append(c.r.mqtt, MQTT{Topic: msg.Topic(), Payload: string(msg.Payload())})
+		c.r.mu.Unlock()
+
+		callback(client, msg)
+	})
+}
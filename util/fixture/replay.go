@@ -0,0 +1,211 @@
+package fixture
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/evcc-io/evcc/plugin/mqtt"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/modbus"
+)
+
+// Replayer answers device requests with recorded traffic. Repeated requests receive
+// the recorded responses in order, the last response is repeated.
+type Replayer struct {
+	mu       sync.Mutex
+	modbus   map[string][]Modbus
+	http     map[string][]HTTP
+	mqtt     []MQTT
+	instance *mqtt.Client
+}
+
+// Replay replays the fixture's traffic until the replayer is stopped.
+// It waits for other recorders or replayers to be stopped.
+func Replay(f Fixture) (*Replayer, error) {
+	active.Lock()
+
+	r := &Replayer{
+		modbus:   make(map[string][]Modbus),
+		http:     make(map[string][]HTTP),
+		mqtt:     f.MQTT,
+		instance: mqtt.Instance,
+	}
+
+	for _, m := range f.Modbus {
+		key := modbusKey(m.ID, m.Func, m.Address, m.Quantity, m.Value)
+		r.modbus[key] = append(r.modbus[key], m)
+	}
+
+	for _, h := range f.HTTP {
+		key := h.Method + " " + h.URL
+		r.http[key] = append(r.http[key], h)
+	}
+
+	tap(r.modbusTap, r.httpTap, r.mqttTap)
+
+	// default broker for templates without broker configuration
+	if len(f.MQTT) > 0 {
+		instance, err := mqtt.NewClient(util.NewLogger("mqtt"), "replay", "", "", mqtt.ClientID(), 0, false, "", "", "")
+		if err != nil {
+			r.Stop()
+			return nil, err
+		}
+
+		mqtt.Instance = instance
+	}
+
+	return r, nil
+}
+
+// Stop stops replaying
+func (r *Replayer) Stop() {
+	tap(nil, nil, nil)
+	mqtt.Instance = r.instance
+	active.Unlock()
+}
+
+func modbusKey(id uint8, fun string, address, quantity uint16, value string) string {
+	return fmt.Sprintf("%d:%s:%d:%d:%s", id, fun, address, quantity, value)
+}
+
+// next returns the next recorded exchange for key, repeating the last one
+func next[T any](m map[string][]T, key string) (T, bool) {
+	q := m[key]
+	if len(q) == 0 {
+		var zero T
+		return zero, false
+	}
+
+	if len(q) > 1 {
+		m[key] = q[1:]
+	}
+
+	return q[0], true
+}
+
+func (r *Replayer) modbusTap(req modbus.Request, _ func() ([]byte, error)) ([]byte, error) {
+	r.mu.Lock()
+	res, ok := next(r.modbus, modbusKey(req.ID, req.Func, req.Address, req.Quantity, hex.EncodeToString(req.Value)))
+	r.mu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("modbus %d %s %d/%d: %w", req.ID, req.Func, req.Address, req.Quantity, ErrNotRecorded)
+	}
+
+	if res.Error != "" {
+		return nil, errors.New(res.Error)
+	}
+
+	return hex.DecodeString(res.Result)
+}
+
+func (r *Replayer) httpTap(req *http.Request, _ http.RoundTripper) (*http.Response, error) {
+	r.mu.Lock()
+	res, ok := next(r.http, req.Method+" "+req.URL.String())
+	r.mu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%s %s: %w", req.Method, req.URL, ErrNotRecorded)
+	}
+
+	if res.Error != "" {
+		return nil, errors.New(res.Error)
+	}
+
+	resp := &http.Response{
+		Status:        fmt.Sprintf("%d %s", res.Status, http.StatusText(res.Status)),
+		StatusCode:    res.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        make(http.Header),
+		Body:          io.NopCloser(strings.NewReader(res.Response)),
+		ContentLength: int64(len(res.Response)),
+		Request:       req,
+	}
+
+	if res.Type != "" {
+		resp.Header.Set("Content-Type", res.Type)
+	}
+
+	return resp, nil
+}
+
+func (r *Replayer) mqttTap(client paho.Client) paho.Client {
+	return &replayClient{Client: client, r: r}
+}
+
+// replayClient delivers the recorded messages on subscription without connecting to the broker
+type replayClient struct {
+	paho.Client
+	r *Replayer
+}
+
+func (c *replayClient) IsConnected() bool {
+	return true
+}
+
+func (c *replayClient) IsConnectionOpen() bool {
+	return true
+}
+
+func (c *replayClient) Connect() paho.Token {
+	return newToken()
+}
+
+func (c *replayClient) Disconnect(uint) {}
+
+func (c *replayClient) Publish(string, byte, bool, any) paho.Token {
+	return newToken()
+}
+
+func (c *replayClient) Subscribe(topic string, _ byte, callback paho.MessageHandler) paho.Token {
+	for _, m := range c.r.mqtt {
+		if m.Topic == topic {
+			callback(c, &message{topic: m.Topic, payload: []byte(m.Payload)})
+		}
+	}
+
+	return newToken()
+}
+
+func (c *replayClient) Unsubscribe(...string) paho.Token {
+	return newToken()
+}
+
+// token is a completed token
+type token struct {
+	done chan struct{}
+}
+
+func newToken() *token {
+	done := make(chan struct{})
+	close(done)
+	return &token{done: done}
+}
+
+func (t *token) Wait() bool                     { return true }
+func (t *token) WaitTimeout(time.Duration) bool { return true }
+func (t *token) Done() <-chan struct{}          { return t.done }
+func (t *token) Error() error                   { return nil }
+
+// message is a replayed message
+type message struct {
+	topic   string
+	payload []byte
+}
+
+func (m *message) Duplicate() bool   { return false }
+func (m *message) Qos() byte         { return 0 }
+func (m *message) Retained() bool    { return true }
+func (m *message) Topic() string     { return m.topic }
+func (m *message) MessageID() uint16 { return 0 }
+func (m *message) Payload() []byte   { return m.payload }
+func (m *message) Ack()              {}
@@ -0,0 +1,73 @@
+package fixture
+
+import (
+	"context"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/evcc-io/evcc/util/templates"
+)
+
+// TestClass replays all fixtures of the class subdirectory of dir against the rendered templates
+func TestClass(t *testing.T, class templates.Class, dir string, instantiate func(ctx context.Context, other map[string]any) (any, error)) {
+	files, err := filepath.Glob(filepath.Join(dir, class.String(), "*.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			f, err := Load(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			Test(t, f, instantiate)
+		})
+	}
+}
+
+// Test replays the fixture's traffic and asserts the expected device readings.
+// Replaying intercepts the device traffic of the entire test binary, fixture tests
+// must not run in parallel to other device tests.
+func Test(t *testing.T, f Fixture, instantiate func(ctx context.Context, other map[string]any) (any, error)) {
+	t.Helper()
+
+	r, err := Replay(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Stop()
+
+	dev, err := instantiate(t.Context(), f.Config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Read(dev)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range []struct {
+		name          string
+		expect, value *float64
+	}{
+		{"power", f.Expect.Power, res.Power},
+		{"energy", f.Expect.Energy, res.Energy},
+		{"soc", f.Expect.Soc, res.Soc},
+	} {
+		switch {
+		case v.expect == nil:
+		case v.value == nil:
+			t.Errorf("%s: expected %g, got none", v.name, *v.expect)
+		case math.Abs(*v.expect-*v.value) > 1e-6:
+			t.Errorf("%s: expected %g, got %g", v.name, *v.expect, *v.value)
+		}
+	}
+
+	if f.Expect.Status != "" && f.Expect.Status != res.Status {
+		t.Errorf("status: expected %s, got %s", f.Expect.Status, res.Status)
+	}
+}
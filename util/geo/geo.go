@@ -0,0 +1,94 @@
+// Package geo provides distance calculation and geofencing for vehicle positions.
+package geo
+
+import (
+	"errors"
+	"math"
+)
+
+// earthRadius is the mean earth radius in m
+const earthRadius = 6371e3
+
+const (
+	// DefaultRadius is the fence radius in m if not configured
+	DefaultRadius = 200
+
+	// DefaultArrival is the arrival radius in m if not configured
+	DefaultArrival = 5000
+)
+
+// Distance returns the great-circle distance between two positions in m
+func Distance(lat1, lon1, lat2, lon2 float64) float64 {
+	rad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := rad(lat2 - lat1)
+	dLon := rad(lon2 - lon1)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(rad(lat1))*math.Cos(rad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * earthRadius * math.Asin(math.Sqrt(math.Min(1, a)))
+}
+
+// Fence is a circular area around a location
+type Fence struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Radius    float64 `json:"radius,omitempty"` // m
+}
+
+// Configured returns true if the fence has a location
+func (f Fence) Configured() bool {
+	return f.Latitude != 0 || f.Longitude != 0
+}
+
+// Validate checks the fence's location and radius
+func (f Fence) Validate() error {
+	if math.Abs(f.Latitude) > 90 || math.Abs(f.Longitude) > 180 {
+		return errors.New("invalid location")
+	}
+	if f.Radius < 0 {
+		return errors.New("invalid radius")
+	}
+	return nil
+}
+
+// Distance returns the distance of a position from the fence's center in m
+func (f Fence) Distance(lat, lon float64) float64 {
+	return Distance(f.Latitude, f.Longitude, lat, lon)
+}
+
+// Contains returns true if the position is inside the fence
+func (f Fence) Contains(lat, lon float64) bool {
+	radius := f.Radius
+	if radius == 0 {
+		radius = DefaultRadius
+	}
+
+	return f.Distance(lat, lon) <= radius
+}
+
+// Home is the home location. Vehicles inside the fence are home,
+// vehicles entering the arrival radius are arriving.
+type Home struct {
+	Fence
+	Arrival float64 `json:"arrival,omitempty"` // m
+}
+
+// Validate checks the home's fence and arrival radius
+func (h Home) Validate() error {
+	if h.Arrival < 0 {
+		return errors.New("invalid arrival radius")
+	}
+	return h.Fence.Validate()
+}
+
+// Arriving returns true if the position is inside the arrival radius
+func (h Home) Arriving(lat, lon float64) bool {
+	arrival := h.Arrival
+	if arrival == 0 {
+		arrival = DefaultArrival
+	}
+
+	return h.Distance(lat, lon) <= arrival
+}
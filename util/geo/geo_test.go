@@ -0,0 +1,76 @@
+package geo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDistance(t *testing.T) {
+	// Berlin Brandenburger Tor to Hamburg Rathaus
+	assert.InDelta(t, 254e3, Distance(52.5163, 13.3777, 53.5503, 9.9923), 1e3)
+	assert.Zero(t, Distance(52.5163, 13.3777, 52.5163, 13.3777))
+}
+
+func TestFence(t *testing.T) {
+	f := Fence{Latitude: 52.5163, Longitude: 13.3777}
+
+	assert.True(t, f.Configured())
+	assert.False(t, Fence{}.Configured())
+
+	assert.True(t, f.Contains(52.5170, 13.3777), "~80m")
+	assert.False(t, f.Contains(52.5200, 13.3777), "~400m")
+
+	f.Radius = 500
+	assert.True(t, f.Contains(52.5200, 13.3777), "~400m")
+
+	assert.NoError(t, f.Validate())
+	assert.Error(t, Fence{Latitude: 91}.Validate())
+	assert.Error(t, Fence{Radius: -1}.Validate())
+}
+
+func TestHome(t *testing.T) {
+	h := Home{Fence: Fence{Latitude: 52.5163, Longitude: 13.3777}}
+
+	assert.True(t, h.Arriving(52.5400, 13.3777), "~2.6km")
+	assert.False(t, h.Arriving(52.6000, 13.3777), "~9.3km")
+
+	h.Arrival = 10e3
+	assert.True(t, h.Arriving(52.6000, 13.3777), "~9.3km")
+
+	assert.NoError(t, h.Validate())
+	assert.Error(t, Home{Arrival: -1}.Validate())
+}
+
+func TestGeocoder(t *testing.T) {
+	var requests int
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Query().Get("q") == "Rathausmarkt 1, Hamburg" {
+			_, _ = w.Write([]byte(`[{"lat":"53.5503","lon":"9.9923"}]`))
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	g := NewGeocoder(util.NewLogger("foo"), srv.URL)
+
+	lat, lon, err := g.Locate("Rathausmarkt 1, Hamburg")
+	require.NoError(t, err)
+	assert.Equal(t, 53.5503, lat)
+	assert.Equal(t, 9.9923, lon)
+
+	_, _, err = g.Locate("Room 4")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// cached
+	_, _, err = g.Locate("Room 4")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Equal(t, 2, requests)
+}
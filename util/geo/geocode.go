@@ -0,0 +1,80 @@
+package geo
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+)
+
+// NominatimURI is the OpenStreetMap geocoding service
+const NominatimURI = "https://nominatim.openstreetmap.org/search"
+
+// ErrNotFound is returned for addresses without position
+var ErrNotFound = errors.New("address not found")
+
+type position struct {
+	lat, lon float64
+	err      error
+}
+
+// Geocoder resolves addresses to positions. Results are cached and
+// requests are limited to one per second as required by the service's usage policy.
+type Geocoder struct {
+	*request.Helper
+	uri   string
+	mu    sync.Mutex
+	last  time.Time
+	cache map[string]position
+}
+
+// NewGeocoder creates a geocoder using the given Nominatim search uri
+func NewGeocoder(log *util.Logger, uri string) *Geocoder {
+	return &Geocoder{
+		Helper: request.NewHelper(log),
+		uri:    uri,
+		cache:  make(map[string]position),
+	}
+}
+
+// Locate returns the position of an address
+func (g *Geocoder) Locate(address string) (float64, float64, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if p, ok := g.cache[address]; ok {
+		return p.lat, p.lon, p.err
+	}
+
+	time.Sleep(time.Until(g.last.Add(time.Second)))
+	g.last = time.Now()
+
+	var res []struct {
+		Lat string `json:"lat"`
+		Lon string `json:"lon"`
+	}
+
+	uri := g.uri + "?" + url.Values{"q": {address}, "format": {"json"}, "limit": {"1"}}.Encode()
+	if err := g.GetJSON(uri, &res); err != nil {
+		// temporary errors are not cached
+		return 0, 0, err
+	}
+
+	var p position
+	if len(res) == 0 {
+		p.err = ErrNotFound
+	} else {
+		var err1, err2 error
+		p.lat, err1 = strconv.ParseFloat(res[0].Lat, 64)
+		p.lon, err2 = strconv.ParseFloat(res[0].Lon, 64)
+		p.err = errors.Join(err1, err2)
+	}
+
+	g.cache[address] = p
+
+	return p.lat, p.lon, p.err
+}
@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/evcc-io/evcc/api"
@@ -21,6 +22,7 @@ import (
 type Connection struct {
 	*request.Helper
 	instance *proxyInstance
+	socket   func() *socket // shared push connection, nil if not available
 }
 
 // NewConnection creates a new Home Assistant connection
@@ -53,6 +55,13 @@ func NewConnection(log *util.Logger, uri, home string, insecure bool) (*Connecti
 		Source: c.instance,
 	}
 
+	// connect the instance's websocket on first use
+	if uri != "" {
+		c.socket = sync.OnceValue(func() *socket {
+			return instanceSocket(c.instance.URI(), c.instance, c.Client)
+		})
+	}
+
 	return c, nil
 }
 
@@ -77,13 +86,23 @@ func (c *Connection) GetServices() ([]ServiceDomainResponse, error) {
 	return res, err
 }
 
-// GetState retrieves the state of an entity
+// GetState retrieves the state of an entity from the websocket cache or the REST API
 func (c *Connection) GetState(entity string) (StateResponse, error) {
-	var res StateResponse
-	uri := fmt.Sprintf("%s/api/states/%s", c.instance.URI(), url.PathEscape(entity))
+	var (
+		res StateResponse
+		ok  bool
+	)
+
+	if c.socket != nil {
+		res, ok = c.socket().state(entity)
+	}
 
-	if err := c.GetJSON(uri, &res); err != nil {
-		return res, err
+	if !ok {
+		uri := fmt.Sprintf("%s/api/states/%s", c.instance.URI(), url.PathEscape(entity))
+
+		if err := c.GetJSON(uri, &res); err != nil {
+			return res, err
+		}
 	}
 
 	if res.State == "unknown" || res.State == "unavailable" {
@@ -93,6 +112,14 @@ func (c *Connection) GetState(entity string) (StateResponse, error) {
 	return res, nil
 }
 
+// Listen registers a function called when the entity's state changes.
+// It requires the websocket connection and is a no-op otherwise.
+func (c *Connection) Listen(entity string, fn func()) {
+	if c.socket != nil {
+		c.socket().listen(entity, fn)
+	}
+}
+
 // GetIntState retrieves the state of an entity as int64
 func (c *Connection) GetIntState(entity string) (int64, error) {
 	state, err := c.GetState(entity)
@@ -235,8 +262,14 @@ func (c *Connection) GetChargeStatus(entity string, states StatusMap) (api.Charg
 	return api.StatusNone, fmt.Errorf("unknown charge status '%s' for entity %s", state.State, entity)
 }
 
-// CallService calls a Home Assistant service
+// CallService calls a Home Assistant service via websocket or the REST API
 func (c *Connection) CallService(domain, service string, data map[string]any) error {
+	if c.socket != nil {
+		if err := c.socket().callService(domain, service, data); !errors.Is(err, errDisconnected) {
+			return err
+		}
+	}
+
 	uri := fmt.Sprintf("%s/api/services/%s/%s", c.instance.URI(), domain, service)
 
 	req, err := request.New(http.MethodPost, uri, request.MarshalJSON(data), request.JSONEncoding)
@@ -0,0 +1,410 @@
+package homeassistant
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/evcc-io/evcc/util/request"
+	"golang.org/x/oauth2"
+)
+
+// https://developers.home-assistant.io/docs/api/websocket
+
+const (
+	wsPingInterval = 30 * time.Second
+	wsReadTimeout  = 2 * wsPingInterval // no message including pong means the connection is dead
+	wsReadLimit    = 32 << 20           // state list of large instances
+)
+
+var errDisconnected = errors.New("websocket disconnected")
+
+var (
+	socketsMu sync.Mutex
+	sockets   = make(map[string]*socket)
+)
+
+type wsMessage struct {
+	ID      int             `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Message string          `json:"message,omitempty"` // auth_invalid
+	Success bool            `json:"success,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+	Event *struct {
+		EventType string `json:"event_type"`
+		Data      struct {
+			EntityId string         `json:"entity_id"`
+			NewState *StateResponse `json:"new_state"` // nil if removed
+		} `json:"data"`
+	} `json:"event,omitempty"`
+}
+
+// socket is the WebSocket connection shared by all connections to a Home Assistant instance.
+// It keeps a live cache of entity states from state_changed events and executes service calls.
+// The cache is only available while connected, callers fall back to the REST API otherwise.
+type socket struct {
+	uri    string
+	client *http.Client
+	token  oauth2.TokenSource
+
+	mu        sync.Mutex
+	conn      *websocket.Conn
+	id        int
+	pending   map[int]chan wsMessage
+	states    map[string]StateResponse // nil until loaded
+	listeners map[string][]func()
+}
+
+// instanceSocket returns the instance's shared socket, connecting in background on first use
+func instanceSocket(uri string, token oauth2.TokenSource, client *http.Client) *socket {
+	socketsMu.Lock()
+	defer socketsMu.Unlock()
+
+	if s, ok := sockets[uri]; ok {
+		return s
+	}
+
+	s := newSocket(uri, token, client)
+	sockets[uri] = s
+
+	go s.run(context.Background())
+
+	return s
+}
+
+func newSocket(uri string, token oauth2.TokenSource, client *http.Client) *socket {
+	// http -> ws, https -> wss
+	if u, ok := strings.CutPrefix(uri, "http"); ok {
+		uri = "ws" + u
+	}
+
+	return &socket{
+		uri:       uri + "/api/websocket",
+		client:    client,
+		token:     token,
+		pending:   make(map[int]chan wsMessage),
+		listeners: make(map[string][]func()),
+	}
+}
+
+// run connects and reconnects with backoff until the context is cancelled
+func (s *socket) run(ctx context.Context) {
+	bo := backoff.NewExponentialBackOff(
+		backoff.WithMaxElapsedTime(0),
+		backoff.WithMaxInterval(5*time.Minute),
+	)
+
+	for ctx.Err() == nil {
+		connected, err := s.connect(ctx)
+		if connected {
+			log.WARN.Printf("websocket: %v", err)
+			bo.Reset()
+		} else {
+			log.DEBUG.Printf("websocket: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(bo.NextBackOff()):
+		}
+	}
+}
+
+func (s *socket) read(ctx context.Context, conn *websocket.Conn) (wsMessage, error) {
+	ctx, cancel := context.WithTimeout(ctx, wsReadTimeout)
+	defer cancel()
+
+	var res wsMessage
+	err := wsjson.Read(ctx, conn, &res)
+
+	return res, err
+}
+
+func (s *socket) auth(ctx context.Context, conn *websocket.Conn) error {
+	token, err := s.token.Token()
+	if err != nil {
+		return err
+	}
+
+	if msg, err := s.read(ctx, conn); err != nil {
+		return err
+	} else if msg.Type != "auth_required" {
+		return fmt.Errorf("unexpected message: %s", msg.Type)
+	}
+
+	if err := wsjson.Write(ctx, conn, map[string]any{
+		"type":         "auth",
+		"access_token": token.AccessToken,
+	}); err != nil {
+		return err
+	}
+
+	msg, err := s.read(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	if msg.Type != "auth_ok" {
+		return fmt.Errorf("auth failed: %s", msg.Message)
+	}
+
+	return nil
+}
+
+// connect serves a single connection and returns when it is closed.
+// It returns true if the connection had been authenticated.
+func (s *socket) connect(ctx context.Context) (bool, error) {
+	dialCtx, cancel := context.WithTimeout(ctx, request.Timeout)
+	defer cancel()
+
+	conn, _, err := websocket.Dial(dialCtx, s.uri, &websocket.DialOptions{HTTPClient: s.client})
+	if err != nil {
+		return false, err
+	}
+	defer conn.CloseNow()
+
+	conn.SetReadLimit(wsReadLimit)
+
+	if err := s.auth(dialCtx, conn); err != nil {
+		return false, err
+	}
+
+	ctx, cancel = context.WithCancel(ctx)
+	defer cancel()
+
+	s.mu.Lock()
+	s.conn = conn
+	s.mu.Unlock()
+
+	defer s.disconnect()
+
+	go s.ping(ctx)
+
+	// subscribe before loading states to not miss any change
+	if _, _, err := s.send(ctx, map[string]any{"type": "subscribe_events", "event_type": "state_changed"}, false); err != nil {
+		return true, err
+	}
+
+	statesID, _, err := s.send(ctx, map[string]any{"type": "get_states"}, false)
+	if err != nil {
+		return true, err
+	}
+
+	for {
+		msg, err := s.read(ctx, conn)
+		if err != nil {
+			return true, err
+		}
+
+		switch {
+		case msg.Type == "event" && msg.Event != nil:
+			s.update(msg)
+
+		case msg.Type == "result" && msg.ID == statesID:
+			if err := s.load(msg); err != nil {
+				return true, err
+			}
+
+		case msg.Type == "result":
+			s.resolve(msg)
+		}
+	}
+}
+
+func (s *socket) ping(ctx context.Context) {
+	ticker := time.NewTicker(wsPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, _, err := s.send(ctx, map[string]any{"type": "ping"}, false); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// disconnect invalidates the cache and fails pending calls
+func (s *socket) disconnect() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.conn = nil
+	s.states = nil
+
+	for id, ch := range s.pending {
+		close(ch)
+		delete(s.pending, id)
+	}
+}
+
+// send sends a message with the next id. If wait is true, the result is delivered to the returned channel.
+func (s *socket) send(ctx context.Context, msg map[string]any, wait bool) (int, chan wsMessage, error) {
+	s.mu.Lock()
+
+	conn := s.conn
+	if conn == nil {
+		s.mu.Unlock()
+		return 0, nil, errDisconnected
+	}
+
+	s.id++
+	id := s.id
+	msg["id"] = id
+
+	var ch chan wsMessage
+	if wait {
+		ch = make(chan wsMessage, 1)
+		s.pending[id] = ch
+	}
+
+	s.mu.Unlock()
+
+	if err := wsjson.Write(ctx, conn, msg); err != nil {
+		s.forget(id)
+		return 0, nil, err
+	}
+
+	return id, ch, nil
+}
+
+func (s *socket) forget(id int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.pending, id)
+}
+
+func (s *socket) resolve(msg wsMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if ch, ok := s.pending[msg.ID]; ok {
+		ch <- msg
+		delete(s.pending, msg.ID)
+	}
+}
+
+func (s *socket) load(msg wsMessage) error {
+	var res []StateResponse
+	if err := json.Unmarshal(msg.Result, &res); err != nil {
+		return err
+	}
+
+	states := make(map[string]StateResponse, len(res))
+	for _, state := range res {
+		states[state.EntityId] = state
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.states = states
+
+	return nil
+}
+
+func (s *socket) update(msg wsMessage) {
+	if msg.Event.EventType != "state_changed" {
+		return
+	}
+
+	data := msg.Event.Data
+
+	s.mu.Lock()
+
+	if s.states == nil {
+		s.mu.Unlock()
+		return
+	}
+
+	old, ok := s.states[data.EntityId]
+
+	if data.NewState == nil {
+		delete(s.states, data.EntityId)
+	} else {
+		s.states[data.EntityId] = *data.NewState
+	}
+
+	listeners := s.listeners[data.EntityId]
+
+	s.mu.Unlock()
+
+	// attribute-only changes are not relevant
+	if data.NewState != nil && ok && old.State == data.NewState.State {
+		return
+	}
+
+	for _, fn := range listeners {
+		fn()
+	}
+}
+
+// listen registers a function called when the entity's state changes
+func (s *socket) listen(entity string, fn func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.listeners[entity] = append(s.listeners[entity], fn)
+}
+
+// state returns the cached entity state if connected
+func (s *socket) state(entity string) (StateResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res, ok := s.states[entity]
+	return res, ok
+}
+
+// callService calls a service and waits for the result.
+// It returns errDisconnected if the call has not been sent.
+func (s *socket) callService(domain, service string, data map[string]any) error {
+	ctx, cancel := context.WithTimeout(context.Background(), request.Timeout)
+	defer cancel()
+
+	id, ch, err := s.send(ctx, map[string]any{
+		"type":         "call_service",
+		"domain":       domain,
+		"service":      service,
+		"service_data": data,
+	}, true)
+	if err != nil {
+		return err
+	}
+	defer s.forget(id)
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+
+	case res, ok := <-ch:
+		if !ok {
+			return errors.New("connection lost")
+		}
+
+		if !res.Success {
+			if res.Error != nil {
+				return fmt.Errorf("%s: %s", res.Error.Code, res.Error.Message)
+			}
+			return errors.New("service call failed")
+		}
+
+		return nil
+	}
+}
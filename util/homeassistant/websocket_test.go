@@ -0,0 +1,147 @@
+package homeassistant
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+// haServer is a minimal Home Assistant websocket server
+func haServer(t *testing.T, calls chan<- map[string]any, events <-chan map[string]any) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/websocket" {
+			http.Error(w, "rest api disabled", http.StatusInternalServerError)
+			return
+		}
+
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.CloseNow()
+
+		ctx := r.Context()
+
+		var msg map[string]any
+		if wsjson.Write(ctx, conn, map[string]any{"type": "auth_required"}) != nil ||
+			wsjson.Read(ctx, conn, &msg) != nil || msg["access_token"] != "token" {
+			wsjson.Write(ctx, conn, map[string]any{"type": "auth_invalid", "message": "invalid token"})
+			return
+		}
+		wsjson.Write(ctx, conn, map[string]any{"type": "auth_ok"})
+
+		go func() {
+			for ev := range events {
+				wsjson.Write(ctx, conn, map[string]any{"type": "event", "event": ev})
+			}
+		}()
+
+		for {
+			var msg map[string]any
+			if err := wsjson.Read(ctx, conn, &msg); err != nil {
+				return
+			}
+
+			res := map[string]any{"id": msg["id"], "type": "result", "success": true}
+
+			switch msg["type"] {
+			case "get_states":
+				res["result"] = []map[string]any{
+					{"entity_id": "sensor.power", "state": "1.5", "attributes": map[string]any{"unit_of_measurement": "kW"}},
+					{"entity_id": "switch.socket", "state": "off"},
+				}
+			case "call_service":
+				calls <- msg
+				if msg["domain"] == "light" {
+					res["success"] = false
+					res["error"] = map[string]any{"code": "not_found", "message": "service not found"}
+				}
+			}
+
+			wsjson.Write(ctx, conn, res)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestWebsocket(t *testing.T) {
+	calls := make(chan map[string]any, 1)
+	events := make(chan map[string]any)
+	srv := haServer(t, calls, events)
+
+	s := newSocket(srv.URL, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"}), http.DefaultClient)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go s.run(ctx)
+
+	c := newTestConnection(srv.URL)
+	c.socket = func() *socket { return s }
+
+	require.Eventually(t, func() bool {
+		_, ok := s.state("sensor.power")
+		return ok
+	}, time.Second, 10*time.Millisecond)
+
+	// served from cache, rest api fails
+	power, err := c.GetFloatState("sensor.power")
+	require.NoError(t, err)
+	assert.Equal(t, 1500.0, power)
+
+	events <- map[string]any{
+		"event_type": "state_changed",
+		"data": map[string]any{
+			"entity_id": "switch.socket",
+			"new_state": map[string]any{"entity_id": "switch.socket", "state": "on"},
+		},
+	}
+
+	assert.Eventually(t, func() bool {
+		on, err := c.GetBoolState("switch.socket")
+		return err == nil && on
+	}, time.Second, 10*time.Millisecond)
+
+	// removed entity falls back to rest api
+	events <- map[string]any{
+		"event_type": "state_changed",
+		"data":       map[string]any{"entity_id": "switch.socket", "new_state": nil},
+	}
+
+	assert.Eventually(t, func() bool {
+		_, err := c.GetBoolState("switch.socket")
+		return err != nil
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, c.CallSwitchService("switch.socket", true))
+	call := <-calls
+	assert.Equal(t, "switch", call["domain"])
+	assert.Equal(t, "turn_on", call["service"])
+	assert.Equal(t, map[string]any{"entity_id": "switch.socket"}, call["service_data"])
+
+	assert.ErrorContains(t, c.CallSwitchService("light.kitchen", true), "service not found")
+	<-calls
+}
+
+func TestWebsocketAuthFailed(t *testing.T) {
+	srv := haServer(t, nil, nil)
+
+	s := newSocket(srv.URL, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "invalid"}), http.DefaultClient)
+
+	connected, err := s.connect(context.Background())
+	assert.False(t, connected)
+	assert.ErrorContains(t, err, "invalid token")
+
+	_, ok := s.state("sensor.power")
+	assert.False(t, ok)
+	assert.ErrorIs(t, s.callService("switch", "turn_on", nil), errDisconnected)
+}
@@ -0,0 +1,221 @@
+// Package ical parses the events of iCalendar (RFC 5545) documents.
+// Recurring events are returned with their first occurrence and expanded by Expand.
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event is a calendar event
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	Status      string
+	Start, End  time.Time
+	AllDay      bool
+	Geo         *Position
+
+	Rrule        string      // recurrence rule
+	ExDates      []time.Time // excluded occurrences
+	RecurrenceID time.Time   // occurrence modified by this event
+}
+
+// Position is a geographic position
+type Position struct {
+	Latitude, Longitude float64
+}
+
+// Cancelled returns true if the event has been cancelled
+func (e Event) Cancelled() bool {
+	return strings.EqualFold(e.Status, "CANCELLED")
+}
+
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// lines returns the unfolded content lines
+func lines(r io.Reader) ([]string, error) {
+	var res []string
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		if len(line) > 0 && (line[0] == ' ' || line[0] == '\t') && len(res) > 0 {
+			res[len(res)-1] += line[1:]
+			continue
+		}
+
+		if line != "" {
+			res = append(res, line)
+		}
+	}
+
+	return res, scanner.Err()
+}
+
+// parseProperty splits a content line into name, parameters and value
+func parseProperty(line string) (property, error) {
+	var quoted bool
+	idx := -1
+
+	for i, c := range line {
+		if c == '"' {
+			quoted = !quoted
+		} else if c == ':' && !quoted {
+			idx = i
+			break
+		}
+	}
+
+	if idx < 0 {
+		return property{}, fmt.Errorf("invalid line: %s", line)
+	}
+
+	segments := strings.Split(line[:idx], ";")
+	res := property{
+		name:   strings.ToUpper(segments[0]),
+		params: make(map[string]string),
+		value:  line[idx+1:],
+	}
+
+	for _, p := range segments[1:] {
+		if k, v, ok := strings.Cut(p, "="); ok {
+			res.params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+		}
+	}
+
+	return res, nil
+}
+
+var unescaper = strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`)
+
+// parseTime parses DATE and DATE-TIME values
+func parseTime(p property) (time.Time, bool, error) {
+	if p.params["VALUE"] == "DATE" || len(p.value) == len("20060102") {
+		t, err := time.ParseInLocation("20060102", p.value, time.Local)
+		return t, true, err
+	}
+
+	if strings.HasSuffix(p.value, "Z") {
+		t, err := time.Parse("20060102T150405Z", p.value)
+		return t, false, err
+	}
+
+	loc := time.Local
+	if tzid := p.params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+
+	t, err := time.ParseInLocation("20060102T150405", p.value, loc)
+	return t, false, err
+}
+
+// Parse returns the events of an iCalendar document
+func Parse(r io.Reader) ([]Event, error) {
+	lines, err := lines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		res   []Event
+		ev    *Event
+		depth int // nesting inside event, e.g. VALARM
+	)
+
+	for _, line := range lines {
+		p, err := parseProperty(line)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case p.name == "BEGIN" && strings.EqualFold(p.value, "VEVENT"):
+			ev = new(Event)
+			continue
+
+		case ev == nil:
+			continue
+
+		case p.name == "BEGIN":
+			depth++
+			continue
+
+		case p.name == "END" && depth > 0:
+			depth--
+			continue
+
+		case p.name == "END" && strings.EqualFold(p.value, "VEVENT"):
+			if ev.End.IsZero() {
+				ev.End = ev.Start
+			}
+			res = append(res, *ev)
+			ev = nil
+			continue
+
+		case depth > 0:
+			continue
+		}
+
+		switch p.name {
+		case "UID":
+			ev.UID = p.value
+		case "SUMMARY":
+			ev.Summary = unescaper.Replace(p.value)
+		case "DESCRIPTION":
+			ev.Description = unescaper.Replace(p.value)
+		case "LOCATION":
+			ev.Location = unescaper.Replace(p.value)
+		case "STATUS":
+			ev.Status = p.value
+		case "DTSTART":
+			if ev.Start, ev.AllDay, err = parseTime(p); err != nil {
+				return nil, fmt.Errorf("%s: %w", ev.UID, err)
+			}
+		case "DTEND":
+			if ev.End, _, err = parseTime(p); err != nil {
+				return nil, fmt.Errorf("%s: %w", ev.UID, err)
+			}
+		case "RRULE":
+			ev.Rrule = p.value
+		case "EXDATE":
+			for v := range strings.SplitSeq(p.value, ",") {
+				p.value = v
+				ts, _, err := parseTime(p)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", ev.UID, err)
+				}
+				ev.ExDates = append(ev.ExDates, ts)
+			}
+		case "RECURRENCE-ID":
+			if ev.RecurrenceID, _, err = parseTime(p); err != nil {
+				return nil, fmt.Errorf("%s: %w", ev.UID, err)
+			}
+		case "GEO":
+			if lat, lon, ok := strings.Cut(p.value, ";"); ok {
+				la, err1 := strconv.ParseFloat(lat, 64)
+				lo, err2 := strconv.ParseFloat(lon, 64)
+				if err1 == nil && err2 == nil {
+					ev.Geo = &Position{Latitude: la, Longitude: lo}
+				}
+			}
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,129 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const calendar = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VTIMEZONE\r\n" +
+	"TZID:Europe/Berlin\r\n" +
+	"END:VTIMEZONE\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:trip-1\r\n" +
+	"DTSTART;TZID=Europe/Berlin:20261020T080000\r\n" +
+	"DTEND;TZID=Europe/Berlin:20261020T170000\r\n" +
+	"SUMMARY:Customer visit\\, Hamburg\r\n" +
+	"DESCRIPTION:distance: 280km\\nsoc: 90\r\n" +
+	"LOCATION:Rathausmarkt 1\\, Hamburg\r\n" +
+	"GEO:53.5503;9.9923\r\n" +
+	"BEGIN:VALARM\r\n" +
+	"DESCRIPTION:Reminder\r\n" +
+	"END:VALARM\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:trip-2\r\n" +
+	"DTSTART:20261021T060000Z\r\n" +
+	"SUMMARY:A very long summary that is folded\r\n" +
+	"  across lines\r\n" +
+	"STATUS:CANCELLED\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:holiday\r\n" +
+	"DTSTART;VALUE=DATE:20261022\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParse(t *testing.T) {
+	res, err := Parse(strings.NewReader(calendar))
+	require.NoError(t, err)
+	require.Len(t, res, 3)
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	ev := res[0]
+	assert.Equal(t, "trip-1", ev.UID)
+	assert.Equal(t, "Customer visit, Hamburg", ev.Summary)
+	assert.Equal(t, "distance: 280km\nsoc: 90", ev.Description)
+	assert.Equal(t, "Rathausmarkt 1, Hamburg", ev.Location)
+	assert.True(t, ev.Start.Equal(time.Date(2026, 10, 20, 8, 0, 0, 0, berlin)))
+	assert.True(t, ev.End.Equal(time.Date(2026, 10, 20, 17, 0, 0, 0, berlin)))
+	assert.Equal(t, &Position{53.5503, 9.9923}, ev.Geo)
+	assert.False(t, ev.Cancelled())
+
+	ev = res[1]
+	assert.Equal(t, "A very long summary that is folded across lines", ev.Summary)
+	assert.True(t, ev.Start.Equal(time.Date(2026, 10, 21, 6, 0, 0, 0, time.UTC)))
+	assert.Equal(t, ev.Start, ev.End)
+	assert.True(t, ev.Cancelled())
+
+	ev = res[2]
+	assert.True(t, ev.AllDay)
+	assert.Equal(t, time.Date(2026, 10, 22, 0, 0, 0, 0, time.Local), ev.Start)
+}
+
+func TestExpand(t *testing.T) {
+	const recurring = "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:commute\r\n" +
+		"DTSTART;TZID=Europe/Berlin:20261019T070000\r\n" +
+		"DTEND;TZID=Europe/Berlin:20261019T080000\r\n" +
+		"RRULE:FREQ=WEEKLY;BYDAY=MO,WE,FR;COUNT=6\r\n" +
+		"EXDATE;TZID=Europe/Berlin:20261021T070000\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:commute\r\n" +
+		"RECURRENCE-ID;TZID=Europe/Berlin:20261026T070000\r\n" +
+		"DTSTART;TZID=Europe/Berlin:20261026T090000\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:monthly\r\n" +
+		"DTSTART:20260131T060000Z\r\n" +
+		"RRULE:FREQ=MONTHLY;UNTIL=20261231T235959Z\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:unsupported\r\n" +
+		"DTSTART:20260101T060000Z\r\n" +
+		"RRULE:FREQ=MONTHLY;BYSETPOS=-1;BYDAY=FR\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	events, err := Parse(strings.NewReader(recurring))
+	require.NoError(t, err)
+	require.Len(t, events, 4)
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	res := Expand(events, from, from.AddDate(0, 3, 0))
+
+	var starts []time.Time
+	for _, ev := range res {
+		starts = append(starts, ev.Start)
+	}
+
+	assert.Equal(t, []time.Time{
+		// exdate and modified occurrence skipped, dst change keeps local time
+		time.Date(2026, 10, 19, 7, 0, 0, 0, berlin),
+		time.Date(2026, 10, 23, 7, 0, 0, 0, berlin),
+		time.Date(2026, 10, 28, 7, 0, 0, 0, berlin),
+		time.Date(2026, 10, 30, 7, 0, 0, 0, berlin),
+		// modification
+		time.Date(2026, 10, 26, 9, 0, 0, 0, berlin),
+		// months without 31st skipped
+		time.Date(2026, 10, 31, 6, 0, 0, 0, time.UTC),
+		time.Date(2026, 12, 31, 6, 0, 0, 0, time.UTC),
+		// unsupported rule kept unexpanded
+		time.Date(2026, 1, 1, 6, 0, 0, 0, time.UTC),
+	}, starts)
+
+	assert.Equal(t, time.Hour, res[0].End.Sub(res[0].Start))
+	assert.Empty(t, res[0].Rrule)
+}
@@ -0,0 +1,177 @@
+package ical
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxPeriods limits the expansion of unbounded recurrence rules
+const maxPeriods = 10000
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+// rule is a recurrence rule. Only FREQ, INTERVAL, COUNT, UNTIL and plain weekly BYDAY are supported.
+type rule struct {
+	freq     string
+	interval int
+	count    int
+	until    time.Time
+	byDay    []time.Weekday
+}
+
+func parseRule(s string) (rule, error) {
+	res := rule{interval: 1}
+
+	for part := range strings.SplitSeq(s, ";") {
+		k, v, _ := strings.Cut(part, "=")
+
+		var err error
+		switch strings.ToUpper(k) {
+		case "FREQ":
+			res.freq = strings.ToUpper(v)
+		case "INTERVAL":
+			res.interval, err = strconv.Atoi(v)
+		case "COUNT":
+			res.count, err = strconv.Atoi(v)
+		case "UNTIL":
+			res.until, _, err = parseTime(property{value: v, params: map[string]string{}})
+		case "BYDAY":
+			for d := range strings.SplitSeq(v, ",") {
+				wd, ok := weekdays[strings.ToUpper(d)]
+				if !ok {
+					return rule{}, fmt.Errorf("unsupported weekday: %s", d)
+				}
+				res.byDay = append(res.byDay, wd)
+			}
+		case "WKST":
+		default:
+			return rule{}, fmt.Errorf("unsupported rule: %s", part)
+		}
+
+		if err != nil {
+			return rule{}, fmt.Errorf("invalid rule: %s", part)
+		}
+	}
+
+	switch {
+	case res.interval < 1:
+		return rule{}, fmt.Errorf("invalid interval: %d", res.interval)
+	case len(res.byDay) > 0 && res.freq != "WEEKLY":
+		return rule{}, fmt.Errorf("unsupported rule: BYDAY with FREQ=%s", res.freq)
+	case !slices.Contains([]string{"DAILY", "WEEKLY", "MONTHLY", "YEARLY"}, res.freq):
+		return rule{}, fmt.Errorf("unsupported frequency: %s", res.freq)
+	}
+
+	// monday-based week order
+	slices.SortFunc(res.byDay, func(a, b time.Weekday) int {
+		return (int(a)+6)%7 - (int(b)+6)%7
+	})
+
+	return res, nil
+}
+
+// period returns the occurrences of the n-th period of the rule
+func (r rule) period(start time.Time, n int) []time.Time {
+	switch r.freq {
+	case "DAILY":
+		return []time.Time{start.AddDate(0, 0, n*r.interval)}
+
+	case "WEEKLY":
+		week := start.AddDate(0, 0, 7*n*r.interval)
+		if len(r.byDay) == 0 {
+			return []time.Time{week}
+		}
+
+		monday := week.AddDate(0, 0, -(int(week.Weekday())+6)%7)
+
+		var res []time.Time
+		for _, wd := range r.byDay {
+			if ts := monday.AddDate(0, 0, (int(wd)+6)%7); !ts.Before(start) {
+				res = append(res, ts)
+			}
+		}
+		return res
+
+	case "MONTHLY":
+		// months without the start day are skipped
+		if ts := start.AddDate(0, n*r.interval, 0); ts.Day() == start.Day() {
+			return []time.Time{ts}
+		}
+
+	case "YEARLY":
+		if ts := start.AddDate(n*r.interval, 0, 0); ts.Day() == start.Day() {
+			return []time.Time{ts}
+		}
+	}
+
+	return nil
+}
+
+// occurrences returns the starts of the rule's occurrences until the given time
+func (r rule) occurrences(start, to time.Time) []time.Time {
+	var (
+		res   []time.Time
+		count int
+	)
+
+	for n := range maxPeriods {
+		for _, ts := range r.period(start, n) {
+			if ts.After(to) || !r.until.IsZero() && ts.After(r.until) || r.count > 0 && count >= r.count {
+				return res
+			}
+
+			count++
+			res = append(res, ts)
+		}
+	}
+
+	return res
+}
+
+// Expand replaces recurring events by their occurrences starting between from and to.
+// Occurrences modified by another event or excluded are skipped. Events with unsupported rules are kept unexpanded.
+func Expand(events []Event, from, to time.Time) []Event {
+	modified := make(map[string]bool)
+	for _, ev := range events {
+		if !ev.RecurrenceID.IsZero() {
+			modified[ev.UID+ev.RecurrenceID.UTC().String()] = true
+		}
+	}
+
+	var res []Event
+
+	for _, ev := range events {
+		if ev.Rrule == "" || !ev.RecurrenceID.IsZero() {
+			res = append(res, ev)
+			continue
+		}
+
+		r, err := parseRule(ev.Rrule)
+		if err != nil {
+			res = append(res, ev)
+			continue
+		}
+
+		duration := ev.End.Sub(ev.Start)
+
+		for _, ts := range r.occurrences(ev.Start, to) {
+			if ts.Before(from) || modified[ev.UID+ts.UTC().String()] || slices.ContainsFunc(ev.ExDates, ts.Equal) {
+				continue
+			}
+
+			occ := ev
+			occ.Start, occ.End = ts, ts.Add(duration)
+			occ.Rrule, occ.ExDates = "", nil
+
+			res = append(res, occ)
+		}
+	}
+
+	return res
+}
@@ -1,12 +1,38 @@
 package modbus
 
 import (
+	"encoding/binary"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/volkszaehler/mbmd/meters"
 )
 
+// Request is a modbus request
+type Request struct {
+	ID       uint8
+	Func     string
+	Address  uint16
+	Quantity uint16
+	Value    []byte
+}
+
+// TapFunc intercepts a modbus request, exec executes the request on the device
+type TapFunc func(req Request, exec func() ([]byte, error)) ([]byte, error)
+
+var tap atomic.Pointer[TapFunc]
+
+// SetTap intercepts all modbus requests, e.g. for recording or replaying device traffic.
+// A nil tap restores direct device access.
+func SetTap(fun TapFunc) {
+	if fun == nil {
+		tap.Store(nil)
+		return
+	}
+	tap.Store(&fun)
+}
+
 // Connection is a logical modbus connection per slave ID sharing a physical connection
 type Connection struct {
 	*logger
@@ -50,80 +76,90 @@ func (c *Connection) Timeout(timeout time.Duration) {
 	}
 }
 
-func (c *Connection) exec(fun func() ([]byte, error)) ([]byte, error) {
-	return c.WithLogger(c.logical, func() ([]byte, error) {
-		time.Sleep(c.delay)
+func (c *Connection) exec(req Request, fun func() ([]byte, error)) ([]byte, error) {
+	req.ID = c.slaveID
 
-		b, err := fun()
-		if err != nil {
-			c.Connection.Close()
-		}
-		return b, err
-	})
+	exec := func() ([]byte, error) {
+		return c.WithLogger(c.logical, func() ([]byte, error) {
+			time.Sleep(c.delay)
+
+			b, err := fun()
+			if err != nil {
+				c.Connection.Close()
+			}
+			return b, err
+		})
+	}
+
+	if fun := tap.Load(); fun != nil {
+		return (*fun)(req, exec)
+	}
+
+	return exec()
 }
 
 func (c *Connection) ReadCoils(address, quantity uint16) ([]byte, error) {
-	return c.exec(func() ([]byte, error) {
+	return c.exec(Request{Func: "ReadCoils", Address: address, Quantity: quantity}, func() ([]byte, error) {
 		return c.ModbusClient().ReadCoils(address, quantity)
 	})
 }
 
 func (c *Connection) WriteSingleCoil(address, value uint16) ([]byte, error) {
-	return c.exec(func() ([]byte, error) {
+	return c.exec(Request{Func: "WriteSingleCoil", Address: address, Value: binary.BigEndian.AppendUint16(nil, value)}, func() ([]byte, error) {
 		return c.ModbusClient().WriteSingleCoil(address, value)
 	})
 }
 
 func (c *Connection) ReadInputRegisters(address, quantity uint16) ([]byte, error) {
-	return c.exec(func() ([]byte, error) {
+	return c.exec(Request{Func: "ReadInputRegisters", Address: address, Quantity: quantity}, func() ([]byte, error) {
 		return c.ModbusClient().ReadInputRegisters(address, quantity)
 	})
 }
 
 func (c *Connection) ReadHoldingRegisters(address, quantity uint16) ([]byte, error) {
-	return c.exec(func() ([]byte, error) {
+	return c.exec(Request{Func: "ReadHoldingRegisters", Address: address, Quantity: quantity}, func() ([]byte, error) {
 		return c.ModbusClient().ReadHoldingRegisters(address, quantity)
 	})
 }
 
 func (c *Connection) WriteSingleRegister(address, value uint16) ([]byte, error) {
-	return c.exec(func() ([]byte, error) {
+	return c.exec(Request{Func: "WriteSingleRegister", Address: address, Value: binary.BigEndian.AppendUint16(nil, value)}, func() ([]byte, error) {
 		return c.ModbusClient().WriteSingleRegister(address, value)
 	})
 }
 
 func (c *Connection) WriteMultipleRegisters(address, quantity uint16, value []byte) ([]byte, error) {
-	return c.exec(func() ([]byte, error) {
+	return c.exec(Request{Func: "WriteMultipleRegisters", Address: address, Quantity: quantity, Value: value}, func() ([]byte, error) {
 		return c.ModbusClient().WriteMultipleRegisters(address, quantity, value)
 	})
 }
 
 func (c *Connection) ReadDiscreteInputs(address, quantity uint16) (results []byte, err error) {
-	return c.exec(func() ([]byte, error) {
+	return c.exec(Request{Func: "ReadDiscreteInputs", Address: address, Quantity: quantity}, func() ([]byte, error) {
 		return c.ModbusClient().ReadDiscreteInputs(address, quantity)
 	})
 }
 
 func (c *Connection) WriteMultipleCoils(address, quantity uint16, value []byte) (results []byte, err error) {
-	return c.exec(func() ([]byte, error) {
+	return c.exec(Request{Func: "WriteMultipleCoils", Address: address, Quantity: quantity, Value: value}, func() ([]byte, error) {
 		return c.ModbusClient().WriteMultipleCoils(address, quantity, value)
 	})
 }
 
 func (c *Connection) ReadWriteMultipleRegisters(readAddress, readQuantity, writeAddress, writeQuantity uint16, value []byte) (results []byte, err error) {
-	return c.exec(func() ([]byte, error) {
+	return c.exec(Request{Func: "ReadWriteMultipleRegisters", Address: readAddress, Quantity: readQuantity, Value: value}, func() ([]byte, error) {
 		return c.ModbusClient().ReadWriteMultipleRegisters(readAddress, readQuantity, writeAddress, writeQuantity, value)
 	})
 }
 
 func (c *Connection) MaskWriteRegister(address, andMask, orMask uint16) (results []byte, err error) {
-	return c.exec(func() ([]byte, error) {
+	return c.exec(Request{Func: "MaskWriteRegister", Address: address, Value: binary.BigEndian.AppendUint16(binary.BigEndian.AppendUint16(nil, andMask), orMask)}, func() ([]byte, error) {
 		return c.ModbusClient().MaskWriteRegister(address, andMask, orMask)
 	})
 }
 
 func (c *Connection) ReadFIFOQueue(address uint16) (results []byte, err error) {
-	return c.exec(func() ([]byte, error) {
+	return c.exec(Request{Func: "ReadFIFOQueue", Address: address}, func() ([]byte, error) {
 		return c.ModbusClient().ReadFIFOQueue(address)
 	})
 }
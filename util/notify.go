@@ -0,0 +1,53 @@
+package util
+
+import (
+	"context"
+	"sync"
+)
+
+type notifyKey struct{}
+
+var (
+	notifyMu   sync.Mutex
+	notifySubs []chan<- string
+)
+
+// WithNotifier returns a context whose sources notify about pushed values of the named device
+func WithNotifier(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, notifyKey{}, name)
+}
+
+// Notify signals that a source of the context's device has pushed a new value.
+// Notifications are dropped if subscribers are busy.
+func Notify(ctx context.Context) {
+	if ctx == nil {
+		return
+	}
+
+	name, ok := ctx.Value(notifyKey{}).(string)
+	if !ok {
+		return
+	}
+
+	notifyMu.Lock()
+	defer notifyMu.Unlock()
+
+	for _, ch := range notifySubs {
+		select {
+		case ch <- name:
+		default:
+		}
+	}
+}
+
+// Notifications returns a channel receiving the names of devices that have pushed new values
+func Notifications() <-chan string {
+	ch := make(chan string, 16)
+
+	notifyMu.Lock()
+	defer notifyMu.Unlock()
+
+	notifySubs = append(notifySubs, ch)
+
+	return ch
+}
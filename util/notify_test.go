@@ -0,0 +1,18 @@
+package util
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotify(t *testing.T) {
+	ch := Notifications()
+
+	Notify(context.Background())
+	Notify(WithNotifier(context.Background(), "grid"))
+
+	assert.Equal(t, "grid", <-ch)
+	assert.Empty(t, ch)
+}
@@ -11,18 +11,24 @@ import (
 
 // Param is the broadcast channel data type
 type Param struct {
+	Site      string // site id, empty for the default site
 	Loadpoint *int
 	Key       string
 	Val       any
 }
 
-// UniqueID returns unique identifier for parameter Loadpoint/Key combination
+// UniqueID returns unique identifier for parameter Site/Loadpoint/Key combination
 func (p Param) UniqueID() string {
+	id := p.Key
 	if p.Loadpoint != nil {
-		return strconv.Itoa(*p.Loadpoint) + "." + p.Key
+		id = strconv.Itoa(*p.Loadpoint) + "." + id
 	}
 
-	return p.Key
+	if p.Site != "" {
+		id = "sites." + p.Site + "." + id
+	}
+
+	return id
 }
 
 // ParamCache is a data store
@@ -54,10 +60,15 @@ func (c *ParamCache) Run(in <-chan Param) {
 	}
 }
 
-// State provides a structured copy of the cached values.
+// State provides a structured copy of the default site's cached values.
 // Loadpoints are aggregated as loadpoints array.
 // Result values are formatted using encoder.
 func (c *ParamCache) State(enc encode.Encoder) map[string]any {
+	return c.SiteState("", enc)
+}
+
+// SiteState provides a structured copy of the given site's cached values
+func (c *ParamCache) SiteState(site string, enc encode.Encoder) map[string]any {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
@@ -65,6 +76,10 @@ func (c *ParamCache) State(enc encode.Encoder) map[string]any {
 	lps := make(map[int]map[string]any)
 
 	for _, param := range c.val {
+		if param.Site != site {
+			continue
+		}
+
 		if param.Loadpoint == nil {
 			res[param.Key] = enc.Encode(param.Val)
 		} else {
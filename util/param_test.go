@@ -3,6 +3,7 @@ package util
 import (
 	"testing"
 
+	"github.com/evcc-io/evcc/util/encode"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -16,6 +17,9 @@ func TestParam(t *testing.T) {
 
 	p.Loadpoint = &lp
 	assert.Equal(t, "2.power", p.UniqueID())
+
+	p.Site = "garage"
+	assert.Equal(t, "sites.garage.2.power", p.UniqueID())
 }
 
 func TestParamCache(t *testing.T) {
@@ -38,3 +42,29 @@ func TestParamCacheSnapshot(t *testing.T) {
 	assert.Len(t, state, 1)
 	assert.Equal(t, "before", state[0].Key)
 }
+
+func TestParamCacheSiteState(t *testing.T) {
+	lp := 0
+	c := NewParamCache()
+
+	for _, p := range []Param{
+		{Key: "gridPower", Val: 1000},
+		{Loadpoint: &lp, Key: "chargePower", Val: 2000},
+		{Site: "garage", Key: "gridPower", Val: 3000},
+		{Site: "garage", Loadpoint: &lp, Key: "chargePower", Val: 4000},
+	} {
+		c.Add(p.UniqueID(), p)
+	}
+
+	enc := encode.NewEncoder()
+
+	assert.Equal(t, map[string]any{
+		"gridPower":  1000,
+		"loadpoints": []map[string]any{{"chargePower": 2000}},
+	}, c.State(enc))
+
+	assert.Equal(t, map[string]any{
+		"gridPower":  3000,
+		"loadpoints": []map[string]any{{"chargePower": 4000}},
+	}, c.SiteState("garage", enc))
+}
@@ -20,6 +20,7 @@ func init() {
 	// fields that are not covered by template params (yet)
 	additional := []string{
 		"sponsortoken", "plant", // global settings
+		"private",                    // eebus certificate key
 		"app", "chats", "recipients", // push messaging
 	}
 
@@ -50,13 +51,18 @@ func String(src string) string {
 	return configRedactRegex.ReplaceAllString(src, "$1: *****")
 }
 
+// IsSecret checks if a configuration key holds sensitive data
+func IsSecret(key string) bool {
+	return slices.ContainsFunc(configRedactSecrets, func(s string) bool {
+		return strings.EqualFold(key, s)
+	})
+}
+
 // Map redacts sensitive keys in a configuration map
 func Map(src map[string]any) map[string]any {
 	res := maps.Clone(src)
 	for k := range res {
-		if slices.ContainsFunc(configRedactSecrets, func(s string) bool {
-			return strings.EqualFold(k, s)
-		}) {
+		if IsSecret(k) {
 			res[k] = "*****"
 		}
 	}
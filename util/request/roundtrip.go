@@ -8,6 +8,7 @@ import (
 	"net/http/httputil"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/evcc-io/evcc/util"
@@ -25,8 +26,23 @@ var (
 	LogMaxLen  = 1024 * 8
 	reqMetric  *prometheus.SummaryVec
 	resMetric  *prometheus.CounterVec
+
+	tap atomic.Pointer[TapFunc]
 )
 
+// TapFunc intercepts a http request, base executes the request
+type TapFunc func(req *http.Request, base http.RoundTripper) (*http.Response, error)
+
+// SetTap intercepts all http requests, e.g. for recording or replaying device traffic.
+// A nil tap restores direct access.
+func SetTap(fun TapFunc) {
+	if fun == nil {
+		tap.Store(nil)
+		return
+	}
+	tap.Store(&fun)
+}
+
 func init() {
 	labels := []string{"host"}
 
@@ -176,7 +192,12 @@ func (r *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
 	}
 
 	startTime := time.Now()
-	resp, err := r.base.RoundTrip(req)
+	var resp *http.Response
+	if fun := tap.Load(); fun != nil {
+		resp, err = (*fun)(req, r.base)
+	} else {
+		resp, err = r.base.RoundTrip(req)
+	}
 
 	reqMetric.WithLabelValues(req.URL.Hostname()).Observe(time.Since(startTime).Seconds())
 
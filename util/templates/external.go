@@ -0,0 +1,134 @@
+package templates
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"strings"
+)
+
+// LoadDir merges the templates of a local directory into the registry. Templates are organized
+// in class subdirectories like the embedded templates and replace embedded templates of the same name.
+func LoadDir(dir string) error {
+	return loadFS(os.DirFS(dir), dir)
+}
+
+// LoadBundle merges the templates of a zip bundle into the registry after verifying the bundle's
+// ed25519 signature against the base64 encoded public key. The signature is read from <file>.sig.
+func LoadBundle(file, publicKey string) error {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	sig, err := os.ReadFile(file + ".sig")
+	if err != nil {
+		return err
+	}
+
+	if err := verifyBundle(b, sig, publicKey); err != nil {
+		return fmt.Errorf("template bundle '%s': %w", file, err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return err
+	}
+
+	return loadFS(zr, file)
+}
+
+func verifyBundle(b, sig []byte, publicKey string) error {
+	if publicKey == "" {
+		return errors.New("missing public key")
+	}
+
+	key, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return errors.New("invalid public key")
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+	if err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+
+	if !ed25519.Verify(ed25519.PublicKey(key), b, signature) {
+		return errors.New("signature mismatch")
+	}
+
+	return nil
+}
+
+// loadFS validates all templates of fsys before merging them into the registry
+func loadFS(fsys fs.FS, source string) error {
+	res := make(map[Class][]Template)
+
+	for _, class := range classes {
+		if _, err := fs.Stat(fsys, class.String()); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+
+		err := fs.WalkDir(fsys, class.String(), func(filepath string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || path.Ext(filepath) != ".yaml" {
+				return nil
+			}
+
+			b, err := fs.ReadFile(fsys, filepath)
+			if err != nil {
+				return err
+			}
+
+			if err := validateSchema(b); err != nil {
+				return fmt.Errorf("template '%s' invalid: %w", path.Join(source, filepath), err)
+			}
+
+			tmpl, err := fromBytes(b)
+			if err != nil {
+				return fmt.Errorf("processing template '%s' failed: %w", path.Join(source, filepath), err)
+			}
+
+			if slices.ContainsFunc(res[class], func(t Template) bool { return t.Template == tmpl.Template }) {
+				return fmt.Errorf("duplicate template name: %s", tmpl.Template)
+			}
+
+			res[class] = append(res[class], tmpl)
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for class, tmpls := range res {
+		for _, tmpl := range tmpls {
+			override(class, tmpl)
+		}
+	}
+
+	return nil
+}
+
+// override adds a template to the registry, replacing an existing template of the same name
+func override(class Class, tmpl Template) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if i := slices.IndexFunc(templates[class], func(t Template) bool { return t.Template == tmpl.Template }); i >= 0 {
+		templates[class][i] = tmpl
+		return
+	}
+
+	templates[class] = append(templates[class], tmpl)
+}
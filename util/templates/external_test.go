@@ -0,0 +1,129 @@
+package templates
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/evcc-io/evcc/templates/definition"
+	"github.com/stretchr/testify/require"
+)
+
+const demoTemplate = `
+template: %s
+products:
+  - brand: Demo
+params:
+  - name: host
+    required: true
+render: |
+  type: demo
+`
+
+func restoreTemplates(t *testing.T) {
+	saved := make(map[Class][]Template)
+	for class, tmpls := range templates {
+		saved[class] = slices.Clone(tmpls)
+	}
+
+	t.Cleanup(func() {
+		templates = saved
+	})
+}
+
+func TestSchemaEmbedded(t *testing.T) {
+	// templates not conforming to the schema
+	exceptions := []string{
+		"meter/kaco-blueplanet.yaml", // no template name and products
+	}
+
+	err := fs.WalkDir(definition.YamlTemplates, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || slices.Contains(exceptions, path) {
+			return err
+		}
+
+		b, err := fs.ReadFile(definition.YamlTemplates, path)
+		require.NoError(t, err)
+		require.NoError(t, validateSchema(b), path)
+
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestLoadDir(t *testing.T) {
+	restoreTemplates(t)
+
+	name := ByClass(Meter)[0].Template
+
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "meter"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "meter", "override.yaml"), fmt.Appendf(nil, demoTemplate, name), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "meter", "local.yaml"), fmt.Appendf(nil, demoTemplate, "local-demo"), 0o644))
+
+	count := len(ByClass(Meter, WithDeprecated()))
+	require.NoError(t, LoadDir(dir))
+	require.Len(t, ByClass(Meter, WithDeprecated()), count+1)
+
+	tmpl, err := ByName(Meter, name)
+	require.NoError(t, err)
+	require.Equal(t, "Demo", tmpl.Products[0].Brand)
+
+	_, err = ByName(Meter, "local-demo")
+	require.NoError(t, err)
+}
+
+func TestLoadDirInvalid(t *testing.T) {
+	restoreTemplates(t)
+
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "charger"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "charger", "valid.yaml"), fmt.Appendf(nil, demoTemplate, "valid-demo"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "charger", "invalid.yaml"), []byte("template: invalid-demo\nfoo: bar\n"), 0o644))
+
+	require.Error(t, LoadDir(dir))
+
+	// nothing merged
+	_, err := ByName(Charger, "valid-demo")
+	require.Error(t, err)
+}
+
+func TestLoadBundle(t *testing.T) {
+	restoreTemplates(t)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("vehicle/demo.yaml")
+	require.NoError(t, err)
+	_, err = fmt.Fprintf(w, demoTemplate, "bundle-demo")
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	publicKey := base64.StdEncoding.EncodeToString(pub)
+
+	file := filepath.Join(t.TempDir(), "bundle.zip")
+	require.NoError(t, os.WriteFile(file, buf.Bytes(), 0o644))
+
+	// missing signature
+	require.Error(t, LoadBundle(file, publicKey))
+
+	// wrong signature
+	require.NoError(t, os.WriteFile(file+".sig", []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(priv, []byte("foo")))), 0o644))
+	require.Error(t, LoadBundle(file, publicKey))
+
+	require.NoError(t, os.WriteFile(file+".sig", []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(priv, buf.Bytes()))), 0o644))
+	require.Error(t, LoadBundle(file, ""))
+	require.NoError(t, LoadBundle(file, publicKey))
+
+	_, err = ByName(Vehicle, "bundle-demo")
+	require.NoError(t, err)
+}
@@ -26,6 +26,9 @@ var (
 	ConfigDefaults  configDefaults
 	mu              sync.Mutex
 	encoderLanguage string
+
+	// classes with templates
+	classes = []Class{Charger, Meter, Vehicle, Tariff, Messenger, Circuit, Hems}
 )
 
 func init() {
@@ -33,7 +36,7 @@ func init() {
 
 	baseTmpl = template.Must(FuncMap(template.New("base")).ParseFS(includeFS, "includes/*.tpl"))
 
-	for _, class := range []Class{Charger, Meter, Vehicle, Tariff, Messenger, Circuit, Hems} {
+	for _, class := range classes {
 		load(class)
 	}
 }
@@ -139,7 +142,10 @@ func WithDeprecated() filterFunc {
 
 // ByClass returns templates for class excluding deprecated templates
 func ByClass(class Class, opt ...filterFunc) []Template {
-	res := templates[class]
+	mu.Lock()
+	res := slices.Clone(templates[class])
+	mu.Unlock()
+
 	if len(opt) == 0 {
 		opt = append(opt, func(t []Template) []Template {
 			return lo.Filter(t, func(t Template, _ int) bool {
@@ -155,6 +161,9 @@ func ByClass(class Class, opt ...filterFunc) []Template {
 
 // ByClass returns templates for class and name including deprecated templates
 func ByName(class Class, name string) (Template, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
 	for _, tmpl := range templates[class] {
 		if tmpl.Template == name || slices.Contains(tmpl.Covers, name) {
 			return tmpl, nil
@@ -0,0 +1,63 @@
+package templates
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/fs"
+	"sync"
+
+	"github.com/evcc-io/evcc/templates/definition"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"go.yaml.in/yaml/v4"
+)
+
+const schemaBase = "https://evcc.io/templates/definition/"
+
+// deviceSchema is the compiled devices-schema.json
+var deviceSchema = sync.OnceValues(func() (*jsonschema.Schema, error) {
+	c := jsonschema.NewCompiler()
+
+	for _, name := range []string{"devices-schema.json", "common-schema.json"} {
+		b, err := fs.ReadFile(definition.Schemas, name)
+		if err != nil {
+			return nil, err
+		}
+
+		doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(b))
+		if err != nil {
+			return nil, err
+		}
+
+		if err := c.AddResource(schemaBase+name, doc); err != nil {
+			return nil, err
+		}
+	}
+
+	return c.Compile(schemaBase + "devices-schema.json")
+})
+
+// validateSchema validates a yaml template against the devices schema
+func validateSchema(b []byte) error {
+	schema, err := deviceSchema()
+	if err != nil {
+		return err
+	}
+
+	var doc any
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return err
+	}
+
+	// convert to json types
+	jb, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	inst, err := jsonschema.UnmarshalJSON(bytes.NewReader(jb))
+	if err != nil {
+		return err
+	}
+
+	return schema.Validate(inst)
+}
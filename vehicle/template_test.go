@@ -1,11 +1,13 @@
 package vehicle
 
 import (
+	"context"
 	"slices"
 	"strings"
 	"testing"
 
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util/fixture"
 	"github.com/evcc-io/evcc/util/templates"
 	"github.com/evcc-io/evcc/util/test"
 )
@@ -43,6 +45,12 @@ func TestTemplates(t *testing.T) {
 	})
 }
 
+func TestFixtures(t *testing.T) {
+	fixture.TestClass(t, templates.Vehicle, "../templates/fixtures", func(ctx context.Context, other map[string]any) (any, error) {
+		return NewFromConfig(ctx, "template", other)
+	})
+}
+
 // onlineVehicleFeatures render via the shared vehicle-features include, so a
 // stored config may carry them; dropping the param breaks reload (discussion #31291).
 var onlineVehicleFeatures = []string{"streaming", "coarsecurrent", "welcomecharge", "climaterdisabled", "autodetectdisabled", "wakeupdisabled"}